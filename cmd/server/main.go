@@ -85,16 +85,33 @@ func main() {
 		&model.User{},
 		&model.DataSource{},
 		&model.Query{},
+		&model.QueryFolder{},
+		&model.QueryRevision{},
 		&model.QueryExecution{},
+		&model.ConsoleStatement{},
+		&model.QueryResult{},
+		&model.QueryResultChunk{},
+		&model.QuerySchedule{},
+		&model.QuerySnapshot{},
+		&model.SchedulerLease{},
+		&model.ResultCacheEntry{},
+		&model.ResultCachePurge{},
 		&model.Tool{},
 		&model.McpServer{},
+		&model.McpServerRelease{},
 		&model.McpLog{},
+		&model.Tag{},
+		&model.Tagging{},
+		&model.Favorite{},
 	); err != nil {
 		logger.Fatal("Failed to migrate database", zap.Error(err))
 	}
+	if err := database.MigrateData(); err != nil {
+		logger.Fatal("Failed to migrate data", zap.Error(err))
+	}
 
 	// Setup router
-	router := api.SetupRouter(cfg.Server.Mode)
+	router, stopWorkers := api.SetupRouter(cfg.Server.Mode)
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -132,5 +149,9 @@ func main() {
 		logger.Fatal("Server forced to shutdown", zap.Error(err))
 	}
 
+	// Return running jobs to the queue and hand the scheduler lease over so
+	// another instance can pick them up
+	stopWorkers()
+
 	logger.Info("Server exited gracefully")
 }
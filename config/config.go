@@ -13,6 +13,13 @@ type Config struct {
 	JWT        JWTConfig        `mapstructure:"jwt"`
 	Encryption EncryptionConfig `mapstructure:"encryption"`
 	Log        LogConfig        `mapstructure:"log"`
+	Query      QueryConfig      `mapstructure:"query"`
+	Lint       LintConfig       `mapstructure:"lint"`
+	Jobs       JobsConfig       `mapstructure:"jobs"`
+	Export     ExportConfig     `mapstructure:"export"`
+	Scheduler  SchedulerConfig  `mapstructure:"scheduler"`
+	Cache      CacheConfig      `mapstructure:"cache"`
+	History    HistoryConfig    `mapstructure:"history"`
 }
 
 type ServerConfig struct {
@@ -50,6 +57,70 @@ type LogConfig struct {
 	Compress   bool   `mapstructure:"compress"`
 }
 
+// QueryConfig bounds how much of a result set is held in memory per
+// execution and how long a synchronous execution may run, and lists the
+// stored procedures and functions queries may call
+type QueryConfig struct {
+	MaxResultRows   int      `mapstructure:"max_result_rows"`
+	MaxResultBytes  int64    `mapstructure:"max_result_bytes"`
+	TimeoutSeconds  int      `mapstructure:"timeout_seconds"`  // Negative disables the timeout
+	AllowedRoutines []string `mapstructure:"allowed_routines"` // Names as written in the SQL, e.g. dbo.usp_sales_report
+}
+
+// LintConfig tunes the SQL lint findings returned by query validation
+type LintConfig struct {
+	LargeTableRows int64                     `mapstructure:"large_table_rows"`
+	Rules          map[string]LintRuleConfig `mapstructure:"rules"` // Keyed by rule ID
+}
+
+// LintRuleConfig enables or disables a lint rule and overrides its severity
+type LintRuleConfig struct {
+	Enabled  *bool  `mapstructure:"enabled"`
+	Severity string `mapstructure:"severity"` // info, warning or error
+}
+
+// JobsConfig sizes the worker pool that runs asynchronous query jobs and
+// bounds the results it keeps
+type JobsConfig struct {
+	Workers        int   `mapstructure:"workers"`          // Jobs run at once per server instance
+	MaxQueued      int   `mapstructure:"max_queued"`       // Queued jobs accepted before submissions are refused
+	ResultTTL      int   `mapstructure:"result_ttl"`       // Seconds results are kept after a job finishes
+	MaxResultRows  int   `mapstructure:"max_result_rows"`  // Rows kept per job
+	MaxResultBytes int64 `mapstructure:"max_result_bytes"` // Approximate bytes kept per job
+	Timeout        int   `mapstructure:"timeout"`          // Seconds a job may run before it fails
+}
+
+// ExportConfig bounds the results streamed by a query export. Exports run
+// under the query timeout.
+type ExportConfig struct {
+	MaxRows  int   `mapstructure:"max_rows"`  // Rows written per export
+	MaxBytes int64 `mapstructure:"max_bytes"` // Approximate bytes of row data written per export
+}
+
+// SchedulerConfig controls the runner of scheduled queries. Every instance
+// may run it; a lease in the metadata database elects the one that does.
+type SchedulerConfig struct {
+	Enabled         *bool `mapstructure:"enabled"`          // Whether this instance takes part in the election; defaults to true
+	Workers         int   `mapstructure:"workers"`          // Schedules run at once by the leader
+	LeaseTTL        int   `mapstructure:"lease_ttl"`        // Seconds a leader keeps the lease without renewing it
+	RetainSnapshots int   `mapstructure:"retain_snapshots"` // Snapshots kept per schedule unless the schedule sets its own
+}
+
+// CacheConfig sizes the result cache used by MCP servers with caching
+// enabled and by executions that ask for a cached result
+type CacheConfig struct {
+	DefaultTTL     int   `mapstructure:"default_ttl"`      // Seconds a result is kept unless its tool sets its own TTL
+	MaxMemoryBytes int64 `mapstructure:"max_memory_bytes"` // Size of the in-memory tier of each server instance
+	MaxEntryBytes  int64 `mapstructure:"max_entry_bytes"`  // Larger results are not cached
+	Shared         bool  `mapstructure:"shared"`           // Also keep results in the metadata database, shared by all instances
+}
+
+// HistoryConfig controls how long query execution records and SQL console
+// history are kept
+type HistoryConfig struct {
+	RetentionDays int `mapstructure:"retention_days"` // Days execution records and console history are kept; negative keeps them forever
+}
+
 func (d *DatabaseConfig) DSN() string {
 	return fmt.Sprintf(
 		"host=%s user=%s password=%s dbname=%s port=%d sslmode=%s TimeZone=Asia/Shanghai",
@@ -107,6 +178,64 @@ func LoadConfig(configPath string) (*Config, error) {
 	if config.Log.MaxAge == 0 {
 		config.Log.MaxAge = 28
 	}
+	if config.Query.MaxResultRows == 0 {
+		config.Query.MaxResultRows = 10000
+	}
+	if config.Query.MaxResultBytes == 0 {
+		config.Query.MaxResultBytes = 32 << 20
+	}
+	if config.Query.TimeoutSeconds == 0 {
+		config.Query.TimeoutSeconds = 300
+	}
+	if config.Jobs.Workers == 0 {
+		config.Jobs.Workers = 4
+	}
+	if config.Jobs.MaxQueued == 0 {
+		config.Jobs.MaxQueued = 100
+	}
+	if config.Jobs.ResultTTL == 0 {
+		config.Jobs.ResultTTL = 86400
+	}
+	if config.Jobs.MaxResultRows == 0 {
+		config.Jobs.MaxResultRows = 1000000
+	}
+	if config.Jobs.MaxResultBytes == 0 {
+		config.Jobs.MaxResultBytes = 256 << 20
+	}
+	if config.Jobs.Timeout == 0 {
+		config.Jobs.Timeout = 3600
+	}
+	if config.Export.MaxRows == 0 {
+		config.Export.MaxRows = 1000000
+	}
+	if config.Export.MaxBytes == 0 {
+		config.Export.MaxBytes = 1 << 30
+	}
+	if config.Scheduler.Enabled == nil {
+		enabled := true
+		config.Scheduler.Enabled = &enabled
+	}
+	if config.Scheduler.Workers == 0 {
+		config.Scheduler.Workers = 2
+	}
+	if config.Scheduler.LeaseTTL == 0 {
+		config.Scheduler.LeaseTTL = 30
+	}
+	if config.Scheduler.RetainSnapshots == 0 {
+		config.Scheduler.RetainSnapshots = 100
+	}
+	if config.Cache.DefaultTTL == 0 {
+		config.Cache.DefaultTTL = 300
+	}
+	if config.Cache.MaxMemoryBytes == 0 {
+		config.Cache.MaxMemoryBytes = 64 << 20
+	}
+	if config.Cache.MaxEntryBytes == 0 {
+		config.Cache.MaxEntryBytes = 4 << 20
+	}
+	if config.History.RetentionDays == 0 {
+		config.History.RetentionDays = 90
+	}
 
 	AppConfig = &config
 	return &config, nil
@@ -0,0 +1,225 @@
+package console
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/internal/repository"
+	"github.com/yourusername/dataweaver/internal/response"
+	"github.com/yourusername/dataweaver/internal/service"
+)
+
+// Handler handles SQL console API requests
+type Handler struct {
+	service service.ConsoleService
+}
+
+// NewHandler creates a new Handler
+func NewHandler(svc service.ConsoleService) *Handler {
+	return &Handler{service: svc}
+}
+
+// getUserID extracts user ID from context (set by JWT middleware)
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+	if id, ok := userID.(uint); ok {
+		return id
+	}
+	if id, ok := userID.(float64); ok {
+		return uint(id)
+	}
+	return 0
+}
+
+// Execute godoc
+// @Summary Run console SQL
+// @Description Run ad-hoc read-only SQL against a data source, under the same checks, row caps and timeout as saved queries. The statement is recorded in the session history and the first page of its result returned.
+// @Tags Console
+// @Accept json
+// @Produce json
+// @Param request body model.ConsoleExecuteRequest true "Statement"
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.ConsoleResultResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/console/execute [post]
+func (h *Handler) Execute(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	var req model.ConsoleExecuteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.service.Execute(userID, &req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// GetResults godoc
+// @Summary Get console results
+// @Description Get a page of the result of a console statement; results no longer cached are produced by running the statement again
+// @Tags Console
+// @Accept json
+// @Produce json
+// @Param id path string true "Statement ID"
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Rows per page" default(100)
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.ConsoleResultResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/console/statements/{id}/results [get]
+func (h *Handler) GetResults(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "100"))
+
+	result, err := h.service.GetResults(c.Param("id"), userID, page, size)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// GetHistory godoc
+// @Summary Get console session history
+// @Description Get the statements run in a console session, newest first
+// @Tags Console
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size" default(20)
+// @Security BearerAuth
+// @Success 200 {object} response.PagedResponse{data=[]model.ConsoleStatementResponse}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/console/sessions/{sessionId}/history [get]
+func (h *Handler) GetHistory(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	stmts, total, err := h.service.GetHistory(userID, c.Param("sessionId"), page, size)
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.SuccessPaged(c, stmts, total, page, size)
+}
+
+// ClearHistory godoc
+// @Summary Clear console session history
+// @Description Delete the statements run in a console session
+// @Tags Console
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/console/sessions/{sessionId}/history [delete]
+func (h *Handler) ClearHistory(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	if err := h.service.ClearHistory(userID, c.Param("sessionId")); err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// Promote godoc
+// @Summary Save console statement as query
+// @Description Create a saved query from a console statement; its parameters are extracted from the SQL
+// @Tags Console
+// @Accept json
+// @Produce json
+// @Param id path string true "Statement ID"
+// @Param request body model.PromoteStatementRequest true "Query info"
+// @Security BearerAuth
+// @Success 201 {object} response.Response{data=model.QueryResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/console/statements/{id}/promote [post]
+func (h *Handler) Promote(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	var req model.PromoteStatementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	query, err := h.service.Promote(c.Param("id"), userID, &req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	response.Created(c, query)
+}
+
+// handleError maps console errors to responses
+func handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrConsoleStatementNotFound):
+		response.NotFound(c, "console statement not found")
+	case errors.Is(err, service.ErrDataSourceNotFound):
+		response.NotFound(c, "data source not found")
+	case errors.Is(err, repository.ErrFolderNotFound):
+		response.NotFound(c, "folder not found")
+	case errors.Is(err, service.ErrInvalidSQL),
+		errors.Is(err, service.ErrNonReadOnlySQL),
+		errors.Is(err, service.ErrMissingParameters),
+		errors.Is(err, service.ErrQueryExecution),
+		errors.Is(err, service.ErrConsoleStatementFailed):
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalError(c, err.Error())
+	}
+}
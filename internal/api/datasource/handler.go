@@ -358,3 +358,188 @@ func (h *Handler) GetTables(c *gin.Context) {
 
 	response.Success(c, tables)
 }
+
+// ListSchemas godoc
+// @Summary List datasource schemas
+// @Description Get the schemas visible in a datasource
+// @Tags DataSources
+// @Accept json
+// @Produce json
+// @Param id path string true "Datasource ID"
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]model.SchemaInfoResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/datasources/{id}/schemas [get]
+func (h *Handler) ListSchemas(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		response.BadRequest(c, "datasource id is required")
+		return
+	}
+
+	schemas, err := h.service.ListSchemas(id, userID)
+	if err != nil {
+		if err == repository.ErrDataSourceNotFound {
+			response.NotFound(c, "datasource not found")
+			return
+		}
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, schemas)
+}
+
+// ListSchemaTables godoc
+// @Summary List tables in a schema
+// @Description Get a paginated list of tables, views and materialized views in a schema
+// @Tags DataSources
+// @Accept json
+// @Produce json
+// @Param id path string true "Datasource ID"
+// @Param schema path string true "Schema name"
+// @Param keyword query string false "Table name filter"
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size" default(20)
+// @Param include_columns query bool false "Load columns for the returned tables"
+// @Security BearerAuth
+// @Success 200 {object} response.PagedResponse{data=[]model.TableInfoResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/datasources/{id}/schemas/{schema}/tables [get]
+func (h *Handler) ListSchemaTables(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		response.BadRequest(c, "datasource id is required")
+		return
+	}
+
+	var req model.ListTablesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	tables, total, err := h.service.ListSchemaTables(id, userID, c.Param("schema"), &req)
+	if err != nil {
+		if err == repository.ErrDataSourceNotFound {
+			response.NotFound(c, "datasource not found")
+			return
+		}
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	size := req.Size
+	if size < 1 || size > 100 {
+		size = 20
+	}
+
+	response.SuccessPaged(c, tables, total, page, size)
+}
+
+// GetTableDetail godoc
+// @Summary Get table detail
+// @Description Get a table's columns, indexes and foreign keys
+// @Tags DataSources
+// @Accept json
+// @Produce json
+// @Param id path string true "Datasource ID"
+// @Param schema path string true "Schema name"
+// @Param table path string true "Table name"
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.TableInfoResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/datasources/{id}/schemas/{schema}/tables/{table} [get]
+func (h *Handler) GetTableDetail(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		response.BadRequest(c, "datasource id is required")
+		return
+	}
+
+	table, err := h.service.GetTableDetail(id, userID, c.Param("schema"), c.Param("table"))
+	if err != nil {
+		if err == repository.ErrDataSourceNotFound {
+			response.NotFound(c, "datasource not found")
+			return
+		}
+		if err == service.ErrTableNotFound {
+			response.NotFound(c, "table not found")
+			return
+		}
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, table)
+}
+
+// RefreshMetadata godoc
+// @Summary Refresh datasource metadata
+// @Description Discard cached schema metadata so it is reloaded from the database
+// @Tags DataSources
+// @Accept json
+// @Produce json
+// @Param id path string true "Datasource ID"
+// @Security BearerAuth
+// @Success 204
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/datasources/{id}/metadata/refresh [post]
+func (h *Handler) RefreshMetadata(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	id := c.Param("id")
+	if id == "" {
+		response.BadRequest(c, "datasource id is required")
+		return
+	}
+
+	if err := h.service.RefreshMetadata(id, userID); err != nil {
+		if err == repository.ErrDataSourceNotFound {
+			response.NotFound(c, "datasource not found")
+			return
+		}
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.NoContent(c)
+}
@@ -89,6 +89,35 @@ func (m *MockDataSourceService) GetTables(id string, userID uint) ([]model.Table
 	return args.Get(0).([]model.TableInfoResponse), args.Error(1)
 }
 
+func (m *MockDataSourceService) ListSchemas(id string, userID uint) ([]model.SchemaInfoResponse, error) {
+	args := m.Called(id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.SchemaInfoResponse), args.Error(1)
+}
+
+func (m *MockDataSourceService) ListSchemaTables(id string, userID uint, schema string, req *model.ListTablesRequest) ([]model.TableInfoResponse, int64, error) {
+	args := m.Called(id, userID, schema, req)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]model.TableInfoResponse), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockDataSourceService) GetTableDetail(id string, userID uint, schema, table string) (*model.TableInfoResponse, error) {
+	args := m.Called(id, userID, schema, table)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TableInfoResponse), args.Error(1)
+}
+
+func (m *MockDataSourceService) RefreshMetadata(id string, userID uint) error {
+	args := m.Called(id, userID)
+	return args.Error(0)
+}
+
 func setupRouter(handler *Handler) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -107,6 +136,10 @@ func setupRouter(handler *Handler) *gin.Engine {
 	r.DELETE("/datasources/:id", handler.Delete)
 	r.POST("/datasources/:id/test", handler.TestConnection)
 	r.GET("/datasources/:id/tables", handler.GetTables)
+	r.GET("/datasources/:id/schemas", handler.ListSchemas)
+	r.GET("/datasources/:id/schemas/:schema/tables", handler.ListSchemaTables)
+	r.GET("/datasources/:id/schemas/:schema/tables/:table", handler.GetTableDetail)
+	r.POST("/datasources/:id/metadata/refresh", handler.RefreshMetadata)
 
 	return r
 }
@@ -399,3 +432,63 @@ func TestHandler_GetTables(t *testing.T) {
 
 	mockSvc.AssertExpectations(t)
 }
+
+func TestHandler_ListSchemaTables(t *testing.T) {
+	mockSvc := new(MockDataSourceService)
+	handler := NewHandler(mockSvc)
+	router := setupRouter(handler)
+
+	rowCount := int64(1200)
+	tables := []model.TableInfoResponse{
+		{Name: "orders", Schema: "sales", Type: "table", RowCount: &rowCount},
+	}
+
+	expectedReq := &model.ListTablesRequest{Keyword: "ord", Page: 2, Size: 10, IncludeColumns: true}
+	mockSvc.On("ListSchemaTables", "uuid-1", uint(1), "sales", expectedReq).Return(tables, int64(11), nil)
+
+	req, _ := http.NewRequest("GET", "/datasources/uuid-1/schemas/sales/tables?keyword=ord&page=2&size=10&include_columns=true", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(11), response["total"])
+	assert.Equal(t, float64(2), response["page"])
+
+	mockSvc.AssertExpectations(t)
+}
+
+func TestHandler_GetTableDetail_NotFound(t *testing.T) {
+	mockSvc := new(MockDataSourceService)
+	handler := NewHandler(mockSvc)
+	router := setupRouter(handler)
+
+	mockSvc.On("GetTableDetail", "uuid-1", uint(1), "public", "missing").Return(nil, service.ErrTableNotFound)
+
+	req, _ := http.NewRequest("GET", "/datasources/uuid-1/schemas/public/tables/missing", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockSvc.AssertExpectations(t)
+}
+
+func TestHandler_RefreshMetadata(t *testing.T) {
+	mockSvc := new(MockDataSourceService)
+	handler := NewHandler(mockSvc)
+	router := setupRouter(handler)
+
+	mockSvc.On("RefreshMetadata", "uuid-1", uint(1)).Return(nil)
+
+	req, _ := http.NewRequest("POST", "/datasources/uuid-1/metadata/refresh", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	mockSvc.AssertExpectations(t)
+}
@@ -0,0 +1,181 @@
+package folder
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/internal/repository"
+	"github.com/yourusername/dataweaver/internal/response"
+	"github.com/yourusername/dataweaver/internal/service"
+)
+
+// Handler handles query folder API requests
+type Handler struct {
+	service service.FolderService
+}
+
+// NewHandler creates a new Handler
+func NewHandler(svc service.FolderService) *Handler {
+	return &Handler{service: svc}
+}
+
+// getUserID extracts user ID from context (set by JWT middleware)
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+	if id, ok := userID.(uint); ok {
+		return id
+	}
+	if id, ok := userID.(float64); ok {
+		return uint(id)
+	}
+	return 0
+}
+
+// List godoc
+// @Summary List query folders
+// @Description Get the query folders of the current user, ordered by path so subfolders follow their parent
+// @Tags Folders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]model.FolderResponse}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/folders [get]
+func (h *Handler) List(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	folders, err := h.service.List(userID)
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, folders)
+}
+
+// Create godoc
+// @Summary Create query folder
+// @Description Create a query folder, at the top level or under a parent folder
+// @Tags Folders
+// @Accept json
+// @Produce json
+// @Param request body model.CreateFolderRequest true "Folder info"
+// @Security BearerAuth
+// @Success 201 {object} response.Response{data=model.FolderResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/folders [post]
+func (h *Handler) Create(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	var req model.CreateFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	folder, err := h.service.Create(userID, &req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	response.Created(c, folder)
+}
+
+// Update godoc
+// @Summary Update query folder
+// @Description Rename a query folder or move it under another folder
+// @Tags Folders
+// @Accept json
+// @Produce json
+// @Param id path string true "Folder ID"
+// @Param request body model.UpdateFolderRequest true "Folder info"
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.FolderResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/folders/{id} [put]
+func (h *Handler) Update(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	var req model.UpdateFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	folder, err := h.service.Update(c.Param("id"), userID, &req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	response.Success(c, folder)
+}
+
+// Delete godoc
+// @Summary Delete query folder
+// @Description Delete a query folder; its subfolders and queries move to its parent
+// @Tags Folders
+// @Accept json
+// @Produce json
+// @Param id path string true "Folder ID"
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/folders/{id} [delete]
+func (h *Handler) Delete(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	if err := h.service.Delete(c.Param("id"), userID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// handleError maps folder errors to responses
+func handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrFolderNotFound):
+		response.NotFound(c, "folder not found")
+	case errors.Is(err, service.ErrFolderNameExists):
+		response.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrFolderCycle):
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalError(c, err.Error())
+	}
+}
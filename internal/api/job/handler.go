@@ -0,0 +1,194 @@
+package job
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/internal/repository"
+	"github.com/yourusername/dataweaver/internal/response"
+	"github.com/yourusername/dataweaver/internal/service"
+)
+
+// Handler handles asynchronous query job API requests
+type Handler struct {
+	service service.QueryJobService
+}
+
+// NewHandler creates a new Handler
+func NewHandler(svc service.QueryJobService) *Handler {
+	return &Handler{service: svc}
+}
+
+// getUserID extracts user ID from context (set by JWT middleware)
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+	if id, ok := userID.(uint); ok {
+		return id
+	}
+	if id, ok := userID.(float64); ok {
+		return uint(id)
+	}
+	return 0
+}
+
+// Submit godoc
+// @Summary Submit query job
+// @Description Queue a query to run in the background. Poll the job for its status, then fetch its results page by page.
+// @Tags Jobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Query ID"
+// @Param request body model.ExecuteQueryRequest true "Execution parameters"
+// @Security BearerAuth
+// @Success 202 {object} response.Response{data=model.QueryJobResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 429 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/queries/{id}/jobs [post]
+func (h *Handler) Submit(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	var req model.ExecuteQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		// Allow empty body (no parameters required for some queries)
+		req = model.ExecuteQueryRequest{Parameters: make(map[string]interface{})}
+	}
+
+	job, err := h.service.Submit(c.Param("id"), userID, &req)
+	if err != nil {
+		handleJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response.Response{
+		Code:    0,
+		Message: "success",
+		Data:    job,
+	})
+}
+
+// Get godoc
+// @Summary Get query job
+// @Description Get the status of a query job
+// @Tags Jobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.QueryJobResponse}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/jobs/{id} [get]
+func (h *Handler) Get(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	job, err := h.service.Get(c.Param("id"), userID)
+	if err != nil {
+		handleJobError(c, err)
+		return
+	}
+
+	response.Success(c, job)
+}
+
+// GetResults godoc
+// @Summary Get query job results
+// @Description Get a page of the result of a succeeded query job. Results are kept until the job's result_expires_at.
+// @Tags Jobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size (max 1000)" default(100)
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.QueryJobResults}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 410 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/jobs/{id}/results [get]
+func (h *Handler) GetResults(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "100"))
+
+	results, err := h.service.GetResults(c.Param("id"), userID, page, size)
+	if err != nil {
+		handleJobError(c, err)
+		return
+	}
+
+	response.Success(c, results)
+}
+
+// Cancel godoc
+// @Summary Cancel query job
+// @Description Cancel a queued or running query job
+// @Tags Jobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.QueryJobResponse}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/jobs/{id}/cancel [post]
+func (h *Handler) Cancel(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	job, err := h.service.Cancel(c.Param("id"), userID)
+	if err != nil {
+		handleJobError(c, err)
+		return
+	}
+
+	response.Success(c, job)
+}
+
+func handleJobError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrQueryNotFound):
+		response.NotFound(c, "query not found")
+	case errors.Is(err, repository.ErrQueryJobNotFound):
+		response.NotFound(c, "job not found")
+	case errors.Is(err, service.ErrMissingParameters):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, service.ErrJobQueueFull):
+		response.Error(c, http.StatusTooManyRequests, err.Error())
+	case errors.Is(err, service.ErrJobNotFinished), errors.Is(err, service.ErrJobFinished), errors.Is(err, service.ErrJobFailed):
+		response.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrJobResultExpired):
+		response.Error(c, http.StatusGone, err.Error())
+	default:
+		response.InternalError(c, err.Error())
+	}
+}
@@ -85,7 +85,10 @@ func (h *Handler) Create(c *gin.Context) {
 // @Param page query int false "Page number" default(1)
 // @Param size query int false "Page size" default(20)
 // @Param keyword query string false "Search keyword"
+// @Param tag query []string false "Only servers with every tag" collectionFormat(multi)
+// @Param favorite query bool false "Only the user's favorites"
 // @Success 200 {object} response.PagedResponse{data=[]model.McpServerResponse}
+// @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Router /mcp-servers [get]
 func (h *Handler) List(c *gin.Context) {
@@ -97,9 +100,14 @@ func (h *Handler) List(c *gin.Context) {
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
-	keyword := c.Query("keyword")
 
-	servers, total, err := h.mcpService.List(userID, page, size, keyword)
+	var filter model.ListFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	servers, total, err := h.mcpService.List(userID, page, size, &filter)
 	if err != nil {
 		response.InternalError(c, err.Error())
 		return
@@ -355,11 +363,40 @@ func (h *Handler) GetStatistics(c *gin.Context) {
 	response.Success(c, stats)
 }
 
+// PurgeCache removes the cached results of an MCP server's tools
+// @Summary Purge MCP server cache
+// @Description Remove the cached results of the queries run by the server's tools, in its draft and its active release
+// @Tags mcp-servers
+// @Produce json
+// @Security Bearer
+// @Param id path string true "MCP Server ID"
+// @Success 200 {object} response.Response{data=model.CachePurgeResponse}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /mcp-servers/{id}/cache [delete]
+func (h *Handler) PurgeCache(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	result, err := h.mcpService.PurgeCache(c.Param("id"), userID)
+	if err != nil {
+		handleMcpServerError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
 // handleMcpServerError handles MCP server-specific errors
 func handleMcpServerError(c *gin.Context, err error) {
 	switch {
 	case errors.Is(err, repository.ErrMcpServerNotFound):
 		response.NotFound(c, "MCP server not found")
+	case errors.Is(err, repository.ErrMcpReleaseNotFound):
+		response.NotFound(c, "MCP server release not found")
 	case errors.Is(err, repository.ErrMcpServerNameExists):
 		response.Error(c, http.StatusConflict, "MCP server name already exists")
 	case errors.Is(err, service.ErrMcpServerNameExists):
@@ -370,6 +407,8 @@ func handleMcpServerError(c *gin.Context, err error) {
 		response.BadRequest(c, "At least one tool is required to publish")
 	case errors.Is(err, service.ErrInvalidApiKey):
 		response.Unauthorized(c, "Invalid API key")
+	case errors.Is(err, service.ErrBulkTagsRequired), errors.Is(err, service.ErrBulkActionNotSupported):
+		response.BadRequest(c, err.Error())
 	default:
 		response.InternalError(c, err.Error())
 	}
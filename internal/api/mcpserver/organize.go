@@ -0,0 +1,122 @@
+package mcpserver
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/internal/response"
+)
+
+// SetTags replaces the tags of an MCP server
+// @Summary Set MCP server tags
+// @Description Replace the tags of an MCP server; tags are created on first use
+// @Tags mcp-servers
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "MCP Server ID"
+// @Param request body model.SetTagsRequest true "Tags"
+// @Success 200 {object} response.Response{data=model.McpServerResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /mcp-servers/{id}/tags [put]
+func (h *Handler) SetTags(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	var req model.SetTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	server, err := h.mcpService.SetTags(c.Param("id"), userID, req.Tags)
+	if err != nil {
+		handleMcpServerError(c, err)
+		return
+	}
+
+	response.Success(c, server)
+}
+
+// AddFavorite marks an MCP server as a favorite
+// @Summary Favorite MCP server
+// @Description Mark an MCP server as a favorite of the current user
+// @Tags mcp-servers
+// @Produce json
+// @Security Bearer
+// @Param id path string true "MCP Server ID"
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /mcp-servers/{id}/favorite [put]
+func (h *Handler) AddFavorite(c *gin.Context) {
+	h.setFavorite(c, true)
+}
+
+// RemoveFavorite removes an MCP server from the favorites
+// @Summary Unfavorite MCP server
+// @Description Remove an MCP server from the favorites of the current user
+// @Tags mcp-servers
+// @Produce json
+// @Security Bearer
+// @Param id path string true "MCP Server ID"
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /mcp-servers/{id}/favorite [delete]
+func (h *Handler) RemoveFavorite(c *gin.Context) {
+	h.setFavorite(c, false)
+}
+
+func (h *Handler) setFavorite(c *gin.Context, favorite bool) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	if err := h.mcpService.SetFavorite(c.Param("id"), userID, favorite); err != nil {
+		handleMcpServerError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Bulk applies one action to many MCP servers
+// @Summary Bulk update MCP servers
+// @Description Tag, untag or delete many MCP servers; reports the servers each action failed on
+// @Tags mcp-servers
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body model.BulkRequest true "Action and MCP server IDs"
+// @Success 200 {object} response.Response{data=model.BulkResult}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /mcp-servers/bulk [post]
+func (h *Handler) Bulk(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	var req model.BulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.mcpService.Bulk(userID, &req)
+	if err != nil {
+		handleMcpServerError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
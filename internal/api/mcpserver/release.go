@@ -0,0 +1,133 @@
+package mcpserver
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/dataweaver/internal/response"
+)
+
+// ListReleases lists the releases of an MCP server
+// @Summary List MCP server releases
+// @Description Get the releases recorded each time an MCP server was published, newest first
+// @Tags mcp-servers
+// @Produce json
+// @Security Bearer
+// @Param id path string true "MCP Server ID"
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size" default(20)
+// @Success 200 {object} response.PagedResponse{data=[]model.McpServerReleaseResponse}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /mcp-servers/{id}/releases [get]
+func (h *Handler) ListReleases(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	id := c.Param("id")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	releases, total, err := h.mcpService.ListReleases(id, userID, page, size)
+	if err != nil {
+		handleMcpServerError(c, err)
+		return
+	}
+
+	response.SuccessPaged(c, releases, total, page, size)
+}
+
+// GetRelease returns one release of an MCP server
+// @Summary Get MCP server release
+// @Description Get the tool, query and parameter definitions captured by a release
+// @Tags mcp-servers
+// @Produce json
+// @Security Bearer
+// @Param id path string true "MCP Server ID"
+// @Param releaseId path string true "Release ID"
+// @Success 200 {object} response.Response{data=model.McpServerReleaseResponse}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /mcp-servers/{id}/releases/{releaseId} [get]
+func (h *Handler) GetRelease(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	release, err := h.mcpService.GetRelease(c.Param("id"), userID, c.Param("releaseId"))
+	if err != nil {
+		handleMcpServerError(c, err)
+		return
+	}
+
+	response.Success(c, release)
+}
+
+// CompareReleases compares two releases of an MCP server
+// @Summary Compare MCP server releases
+// @Description List the tools added, removed and changed between two releases
+// @Tags mcp-servers
+// @Produce json
+// @Security Bearer
+// @Param id path string true "MCP Server ID"
+// @Param from query string true "Release ID to compare from"
+// @Param to query string false "Release ID to compare to (defaults to the active release)"
+// @Success 200 {object} response.Response{data=model.McpServerReleaseDiff}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /mcp-servers/{id}/releases/compare [get]
+func (h *Handler) CompareReleases(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	from := c.Query("from")
+	if from == "" {
+		response.BadRequest(c, "'from' release is required")
+		return
+	}
+
+	diff, err := h.mcpService.CompareReleases(c.Param("id"), userID, from, c.Query("to"))
+	if err != nil {
+		handleMcpServerError(c, err)
+		return
+	}
+
+	response.Success(c, diff)
+}
+
+// Rollback makes an earlier release the active one
+// @Summary Roll back MCP server
+// @Description Serve an earlier release of an MCP server. Tools and queries are left unchanged.
+// @Tags mcp-servers
+// @Produce json
+// @Security Bearer
+// @Param id path string true "MCP Server ID"
+// @Param releaseId path string true "Release ID"
+// @Success 200 {object} response.Response{data=model.McpServerResponse}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /mcp-servers/{id}/releases/{releaseId}/rollback [post]
+func (h *Handler) Rollback(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	server, err := h.mcpService.Rollback(c.Param("id"), userID, c.Param("releaseId"))
+	if err != nil {
+		handleMcpServerError(c, err)
+		return
+	}
+
+	response.Success(c, server)
+}
@@ -0,0 +1,106 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/internal/repository"
+	"github.com/yourusername/dataweaver/internal/response"
+	"github.com/yourusername/dataweaver/internal/service"
+)
+
+// Trailers sent after the last chunk of an export
+const (
+	exportTruncatedTrailer = "X-Export-Truncated" // "true" when the export limits stopped the export early
+	exportErrorTrailer     = "X-Export-Error"     // Why the export failed after streaming started
+)
+
+// exportChunkTimeout is how long writing one chunk of an export may take.
+// The server's write timeout covers a whole response, so each chunk pushes
+// the deadline forward instead.
+const exportChunkTimeout = time.Minute
+
+// deadlineWriter extends the write deadline of the response every time a
+// chunk is flushed
+type deadlineWriter struct {
+	gin.ResponseWriter
+	controller *http.ResponseController
+}
+
+func newDeadlineWriter(w gin.ResponseWriter) *deadlineWriter {
+	dw := &deadlineWriter{ResponseWriter: w, controller: http.NewResponseController(w)}
+	dw.extend()
+	return dw
+}
+
+func (w *deadlineWriter) Flush() {
+	w.ResponseWriter.Flush()
+	w.extend()
+}
+
+func (w *deadlineWriter) extend() {
+	_ = w.controller.SetWriteDeadline(time.Now().Add(exportChunkTimeout))
+}
+
+// Export godoc
+// @Summary Export query results
+// @Description Run a query and stream its results as CSV, XLSX, Parquet or JSON Lines, up to the configured export limits and under the query timeout. The response uses chunked transfer and ends with trailers: X-Export-Truncated is "true" when the limits stopped the export early, and X-Export-Error holds the failure if the query fails after streaming started. Both are also recorded in the execution history.
+// @Tags Queries
+// @Accept json
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/vnd.apache.parquet,application/x-ndjson
+// @Param id path string true "Query ID"
+// @Param request body model.ExportQueryRequest true "Export format and parameters"
+// @Security BearerAuth
+// @Success 200 {file} file
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/queries/{id}/export [post]
+func (h *Handler) Export(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	var req model.ExportQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	exp, err := h.service.Export(c.Request.Context(), c.Param("id"), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrQueryNotFound):
+			response.NotFound(c, "query not found")
+		case errors.Is(err, service.ErrMissingParameters), errors.Is(err, service.ErrQueryExecution):
+			response.BadRequest(c, err.Error())
+		default:
+			response.InternalError(c, err.Error())
+		}
+		return
+	}
+
+	c.Header("Content-Type", exp.Format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", exp.FileName))
+	c.Header("Trailer", exportTruncatedTrailer+", "+exportErrorTrailer)
+	c.Status(http.StatusOK)
+
+	// The status is sent with the first chunk, so a failure from here on
+	// can only be reported in the trailers
+	_, truncated, err := exp.Stream(newDeadlineWriter(c.Writer))
+	if truncated {
+		c.Writer.Header().Set(exportTruncatedTrailer, "true")
+	}
+	if err != nil {
+		c.Writer.Header().Set(exportErrorTrailer, err.Error())
+		_ = c.Error(err)
+		c.Abort()
+	}
+}
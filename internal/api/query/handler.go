@@ -45,8 +45,13 @@ func getUserID(c *gin.Context) uint {
 // @Param page query int false "Page number" default(1)
 // @Param size query int false "Page size" default(20)
 // @Param keyword query string false "Search keyword"
+// @Param tag query []string false "Only queries with every tag" collectionFormat(multi)
+// @Param favorite query bool false "Only the user's favorites"
+// @Param folderId query string false "Folder ID, or root for queries in no folder"
+// @Param recursive query bool false "Include the folder's subfolders"
 // @Security BearerAuth
 // @Success 200 {object} response.PagedResponse{data=[]model.QueryResponse}
+// @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/queries [get]
@@ -59,9 +64,14 @@ func (h *Handler) List(c *gin.Context) {
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
-	keyword := c.Query("keyword")
 
-	queries, total, err := h.service.List(userID, page, size, keyword)
+	var filter model.ListFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	queries, total, err := h.service.List(userID, page, size, &filter)
 	if err != nil {
 		response.InternalError(c, err.Error())
 		return
@@ -102,6 +112,10 @@ func (h *Handler) Create(c *gin.Context) {
 			response.NotFound(c, "data source not found")
 			return
 		}
+		if errors.Is(err, repository.ErrFolderNotFound) {
+			response.NotFound(c, "folder not found")
+			return
+		}
 		if errors.Is(err, service.ErrInvalidSQL) || errors.Is(err, service.ErrNonReadOnlySQL) {
 			response.BadRequest(c, err.Error())
 			return
@@ -197,6 +211,10 @@ func (h *Handler) Update(c *gin.Context) {
 			response.NotFound(c, "data source not found")
 			return
 		}
+		if errors.Is(err, repository.ErrFolderNotFound) {
+			response.NotFound(c, "folder not found")
+			return
+		}
 		if errors.Is(err, service.ErrInvalidSQL) || errors.Is(err, service.ErrNonReadOnlySQL) {
 			response.BadRequest(c, err.Error())
 			return
@@ -248,6 +266,39 @@ func (h *Handler) Delete(c *gin.Context) {
 	response.NoContent(c)
 }
 
+// PurgeCache godoc
+// @Summary Purge cached query results
+// @Description Remove the cached results of a query, for every revision and parameter set
+// @Tags Queries
+// @Accept json
+// @Produce json
+// @Param id path string true "Query ID"
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.CachePurgeResponse}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/queries/{id}/cache [delete]
+func (h *Handler) PurgeCache(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	result, err := h.service.PurgeCache(c.Param("id"), userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrQueryNotFound) {
+			response.NotFound(c, "query not found")
+			return
+		}
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
 // Execute godoc
 // @Summary Execute query
 // @Description Execute a query with parameters
@@ -342,7 +393,11 @@ func (h *Handler) Validate(c *gin.Context) {
 	}
 
 	// Validate the SQL
-	result, err := h.service.ValidateSQL(query.SQLTemplate)
+	result, err := h.service.ValidateSQL(userID, &model.ValidateSQLRequest{
+		SQLTemplate:  query.SQLTemplate,
+		DataSourceID: query.DataSourceID,
+		Parameters:   query.Parameters,
+	})
 	if err != nil {
 		response.InternalError(c, err.Error())
 		return
@@ -353,7 +408,7 @@ func (h *Handler) Validate(c *gin.Context) {
 
 // ValidateSQL godoc
 // @Summary Validate SQL directly
-// @Description Validate SQL template syntax and check if it's read-only (without saving)
+// @Description Validate SQL template syntax, check if it's read-only and report lint findings (without saving). With data_source_id, parameter types are inferred from the schema.
 // @Tags Queries
 // @Accept json
 // @Produce json
@@ -362,6 +417,7 @@ func (h *Handler) Validate(c *gin.Context) {
 // @Success 200 {object} response.Response{data=model.ValidateSQLResponse}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/queries/validate [post]
 func (h *Handler) ValidateSQL(c *gin.Context) {
@@ -377,8 +433,12 @@ func (h *Handler) ValidateSQL(c *gin.Context) {
 		return
 	}
 
-	result, err := h.service.ValidateSQL(req.SQLTemplate)
+	result, err := h.service.ValidateSQL(userID, &req)
 	if err != nil {
+		if errors.Is(err, service.ErrDataSourceNotFound) {
+			response.NotFound(c, "data source not found")
+			return
+		}
 		response.InternalError(c, err.Error())
 		return
 	}
@@ -428,15 +488,25 @@ func (h *Handler) GetParameters(c *gin.Context) {
 
 // GetHistory godoc
 // @Summary Get query execution history
-// @Description Get execution history for all queries or a specific query
+// @Description Get execution history for all queries or a specific query, optionally filtered and searched
 // @Tags Queries
 // @Accept json
 // @Produce json
 // @Param queryId query string false "Query ID (optional, if not provided returns all history)"
+// @Param dataSourceId query string false "Only executions of queries on this data source"
+// @Param status query []string false "Execution statuses, repeat for several" collectionFormat(multi)
+// @Param from query string false "Executions created at or after this time (RFC 3339)"
+// @Param to query string false "Executions created before this time (RFC 3339)"
+// @Param minDurationMs query int false "Minimum execution time in milliseconds"
+// @Param maxDurationMs query int false "Maximum execution time in milliseconds"
+// @Param minRows query int false "Minimum row count"
+// @Param maxRows query int false "Maximum row count"
+// @Param search query string false "Words to find in parameters and error messages"
 // @Param page query int false "Page number" default(1)
 // @Param pageSize query int false "Page size" default(20)
 // @Security BearerAuth
 // @Success 200 {object} response.PagedResponse{data=[]model.QueryExecutionResponse}
+// @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/queries/history [get]
@@ -447,11 +517,15 @@ func (h *Handler) GetHistory(c *gin.Context) {
 		return
 	}
 
-	queryID := c.Query("queryId")
+	var filter model.ExecutionHistoryFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
 
-	history, total, err := h.service.GetExecutionHistory(userID, queryID, page, pageSize)
+	history, total, err := h.service.GetExecutionHistory(userID, &filter, page, pageSize)
 	if err != nil {
 		response.InternalError(c, err.Error())
 		return
@@ -0,0 +1,91 @@
+package query
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/dataweaver/internal/repository"
+	"github.com/yourusername/dataweaver/internal/response"
+	"github.com/yourusername/dataweaver/internal/service"
+)
+
+// RerunExecution godoc
+// @Summary Re-run execution
+// @Description Execute the query of an earlier execution again, at its current revision, with the same parameters
+// @Tags Queries
+// @Accept json
+// @Produce json
+// @Param executionId path string true "Execution ID"
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.ExecuteQueryResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/queries/history/{executionId}/rerun [post]
+func (h *Handler) RerunExecution(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	result, err := h.service.RerunExecution(c.Param("executionId"), userID)
+	if err != nil {
+		handleHistoryError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// CompareExecutions godoc
+// @Summary Compare executions
+// @Description Compare the query revision, parameters, status, row count and duration of two executions
+// @Tags Queries
+// @Accept json
+// @Produce json
+// @Param from query string true "Earlier execution ID"
+// @Param to query string true "Later execution ID"
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.ExecutionComparison}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/queries/history/compare [get]
+func (h *Handler) CompareExecutions(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	from, to := c.Query("from"), c.Query("to")
+	if from == "" || to == "" {
+		response.BadRequest(c, "from and to execution ids are required")
+		return
+	}
+
+	comparison, err := h.service.CompareExecutions(userID, from, to)
+	if err != nil {
+		handleHistoryError(c, err)
+		return
+	}
+
+	response.Success(c, comparison)
+}
+
+// handleHistoryError maps execution history errors to responses
+func handleHistoryError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrExecutionNotFound):
+		response.NotFound(c, "execution not found")
+	case errors.Is(err, repository.ErrQueryNotFound):
+		response.NotFound(c, "query not found")
+	case errors.Is(err, service.ErrMissingParameters), errors.Is(err, service.ErrQueryExecution):
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalError(c, err.Error())
+	}
+}
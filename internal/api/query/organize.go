@@ -0,0 +1,147 @@
+package query
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/internal/repository"
+	"github.com/yourusername/dataweaver/internal/response"
+	"github.com/yourusername/dataweaver/internal/service"
+)
+
+// SetTags godoc
+// @Summary Set query tags
+// @Description Replace the tags of a query; tags are created on first use
+// @Tags Queries
+// @Accept json
+// @Produce json
+// @Param id path string true "Query ID"
+// @Param request body model.SetTagsRequest true "Tags"
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.QueryResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/queries/{id}/tags [put]
+func (h *Handler) SetTags(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	var req model.SetTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	query, err := h.service.SetTags(c.Param("id"), userID, req.Tags)
+	if err != nil {
+		handleOrganizeError(c, err)
+		return
+	}
+
+	response.Success(c, query)
+}
+
+// AddFavorite godoc
+// @Summary Favorite query
+// @Description Mark a query as a favorite of the current user
+// @Tags Queries
+// @Accept json
+// @Produce json
+// @Param id path string true "Query ID"
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/queries/{id}/favorite [put]
+func (h *Handler) AddFavorite(c *gin.Context) {
+	h.setFavorite(c, true)
+}
+
+// RemoveFavorite godoc
+// @Summary Unfavorite query
+// @Description Remove a query from the favorites of the current user
+// @Tags Queries
+// @Accept json
+// @Produce json
+// @Param id path string true "Query ID"
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/queries/{id}/favorite [delete]
+func (h *Handler) RemoveFavorite(c *gin.Context) {
+	h.setFavorite(c, false)
+}
+
+func (h *Handler) setFavorite(c *gin.Context, favorite bool) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	if err := h.service.SetFavorite(c.Param("id"), userID, favorite); err != nil {
+		handleOrganizeError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// Bulk godoc
+// @Summary Bulk update queries
+// @Description Tag, untag, move to a folder or delete many queries; reports the queries each action failed on
+// @Tags Queries
+// @Accept json
+// @Produce json
+// @Param request body model.BulkRequest true "Action and query IDs"
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.BulkResult}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/queries/bulk [post]
+func (h *Handler) Bulk(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	var req model.BulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.service.Bulk(userID, &req)
+	if err != nil {
+		handleOrganizeError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// handleOrganizeError maps tag, favorite and bulk errors to responses
+func handleOrganizeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrQueryNotFound):
+		response.NotFound(c, "query not found")
+	case errors.Is(err, repository.ErrFolderNotFound):
+		response.NotFound(c, "folder not found")
+	case errors.Is(err, service.ErrBulkTagsRequired), errors.Is(err, service.ErrBulkActionNotSupported):
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalError(c, err.Error())
+	}
+}
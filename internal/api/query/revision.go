@@ -0,0 +1,177 @@
+package query
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/dataweaver/internal/repository"
+	"github.com/yourusername/dataweaver/internal/response"
+	"github.com/yourusername/dataweaver/internal/service"
+)
+
+// ListRevisions godoc
+// @Summary List query revisions
+// @Description Get the revision history of a query, newest first
+// @Tags Queries
+// @Accept json
+// @Produce json
+// @Param id path string true "Query ID"
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size" default(20)
+// @Security BearerAuth
+// @Success 200 {object} response.PagedResponse{data=[]model.QueryRevisionResponse}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/queries/{id}/revisions [get]
+func (h *Handler) ListRevisions(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	revisions, total, err := h.service.ListRevisions(c.Param("id"), userID, page, size)
+	if err != nil {
+		handleRevisionError(c, err)
+		return
+	}
+
+	response.SuccessPaged(c, revisions, total, page, size)
+}
+
+// GetRevision godoc
+// @Summary Get query revision
+// @Description Get one revision of a query
+// @Tags Queries
+// @Accept json
+// @Produce json
+// @Param id path string true "Query ID"
+// @Param revision path int true "Revision number"
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.QueryRevisionResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/queries/{id}/revisions/{revision} [get]
+func (h *Handler) GetRevision(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		response.BadRequest(c, "invalid revision number")
+		return
+	}
+
+	rev, err := h.service.GetRevision(c.Param("id"), userID, revision)
+	if err != nil {
+		handleRevisionError(c, err)
+		return
+	}
+
+	response.Success(c, rev)
+}
+
+// DiffRevisions godoc
+// @Summary Diff query revisions
+// @Description Compare the SQL, parameters and fields of two revisions of a query
+// @Tags Queries
+// @Accept json
+// @Produce json
+// @Param id path string true "Query ID"
+// @Param from query int false "Revision to compare from (defaults to the revision before 'to')"
+// @Param to query int false "Revision to compare to (defaults to the current revision)"
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.QueryRevisionDiff}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/queries/{id}/revisions/diff [get]
+func (h *Handler) DiffRevisions(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	from, err := strconv.Atoi(c.DefaultQuery("from", "0"))
+	if err != nil {
+		response.BadRequest(c, "invalid 'from' revision")
+		return
+	}
+	to, err := strconv.Atoi(c.DefaultQuery("to", "0"))
+	if err != nil {
+		response.BadRequest(c, "invalid 'to' revision")
+		return
+	}
+
+	diff, err := h.service.DiffRevisions(c.Param("id"), userID, from, to)
+	if err != nil {
+		handleRevisionError(c, err)
+		return
+	}
+
+	response.Success(c, diff)
+}
+
+// RestoreRevision godoc
+// @Summary Restore query revision
+// @Description Make an earlier revision of a query current again. The restore is recorded as a new revision.
+// @Tags Queries
+// @Accept json
+// @Produce json
+// @Param id path string true "Query ID"
+// @Param revision path int true "Revision number"
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.QueryResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/queries/{id}/revisions/{revision}/restore [post]
+func (h *Handler) RestoreRevision(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		response.BadRequest(c, "invalid revision number")
+		return
+	}
+
+	query, err := h.service.RestoreRevision(c.Param("id"), userID, revision)
+	if err != nil {
+		handleRevisionError(c, err)
+		return
+	}
+
+	response.Success(c, query)
+}
+
+func handleRevisionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrQueryNotFound):
+		response.NotFound(c, "query not found")
+	case errors.Is(err, repository.ErrQueryRevisionNotFound):
+		response.NotFound(c, "query revision not found")
+	case errors.Is(err, service.ErrDataSourceNotFound):
+		response.NotFound(c, "data source not found")
+	case errors.Is(err, service.ErrInvalidRevision):
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalError(c, err.Error())
+	}
+}
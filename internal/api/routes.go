@@ -9,10 +9,15 @@ import (
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"github.com/yourusername/dataweaver/internal/api/auth"
+	"github.com/yourusername/dataweaver/internal/api/console"
 	"github.com/yourusername/dataweaver/internal/api/datasource"
+	"github.com/yourusername/dataweaver/internal/api/folder"
+	"github.com/yourusername/dataweaver/internal/api/job"
 	"github.com/yourusername/dataweaver/internal/api/mcp"
 	"github.com/yourusername/dataweaver/internal/api/mcpserver"
 	"github.com/yourusername/dataweaver/internal/api/query"
+	"github.com/yourusername/dataweaver/internal/api/schedule"
+	"github.com/yourusername/dataweaver/internal/api/tag"
 	"github.com/yourusername/dataweaver/internal/api/tool"
 	"github.com/yourusername/dataweaver/internal/database"
 	"github.com/yourusername/dataweaver/internal/middleware"
@@ -21,7 +26,9 @@ import (
 	"github.com/yourusername/dataweaver/internal/service"
 )
 
-func SetupRouter(mode string) *gin.Engine {
+// SetupRouter builds the router and starts the background workers. The
+// returned function stops the workers.
+func SetupRouter(mode string) (*gin.Engine, func()) {
 	gin.SetMode(mode)
 
 	r := gin.New()
@@ -49,18 +56,36 @@ func SetupRouter(mode string) *gin.Engine {
 	queryRepo := repository.NewQueryRepository(database.DB)
 	toolRepo := repository.NewToolRepository(database.DB)
 	mcpRepo := repository.NewMcpServerRepository(database.DB)
+	jobRepo := repository.NewQueryJobRepository(database.DB)
+	scheduleRepo := repository.NewQueryScheduleRepository(database.DB)
+	cacheRepo := repository.NewResultCacheRepository(database.DB)
+	tagRepo := repository.NewTagRepository(database.DB)
+	folderRepo := repository.NewFolderRepository(database.DB)
+	consoleRepo := repository.NewConsoleRepository(database.DB)
 
 	// Initialize services
+	resultCache := service.NewResultCache(cacheRepo)
+	retention := service.NewExecutionRetention(queryRepo, consoleRepo)
 	authSvc := service.NewAuthService(userRepo)
 	dsSvc := service.NewDataSourceService(dsRepo)
-	querySvc := service.NewQueryService(queryRepo, dsRepo)
-	toolSvc := service.NewToolService(toolRepo, queryRepo, dsRepo)
-	mcpSvc := service.NewMcpServerService(mcpRepo, toolRepo, queryRepo, dsRepo)
+	querySvc := service.NewQueryService(queryRepo, dsRepo, resultCache, tagRepo, folderRepo)
+	toolSvc := service.NewToolService(toolRepo, queryRepo, dsRepo, scheduleRepo, tagRepo)
+	mcpSvc := service.NewMcpServerService(mcpRepo, toolRepo, queryRepo, dsRepo, scheduleRepo, resultCache, tagRepo)
+	tagSvc := service.NewTagService(tagRepo)
+	folderSvc := service.NewFolderService(folderRepo)
+	consoleSvc := service.NewConsoleService(consoleRepo, querySvc, resultCache)
+	jobSvc := service.NewQueryJobService(jobRepo, queryRepo, dsRepo)
+	scheduleSvc := service.NewQueryScheduleService(scheduleRepo, queryRepo, dsRepo)
 
 	// Initialize handlers
 	authHandler := auth.NewHandler(authSvc)
 	dsHandler := datasource.NewHandler(dsSvc)
 	queryHandler := query.NewHandler(querySvc)
+	tagHandler := tag.NewHandler(tagSvc)
+	folderHandler := folder.NewHandler(folderSvc)
+	consoleHandler := console.NewHandler(consoleSvc)
+	jobHandler := job.NewHandler(jobSvc)
+	scheduleHandler := schedule.NewHandler(scheduleSvc)
 	toolHandler := tool.NewHandler(toolSvc)
 	mcpServerHandler := mcpserver.NewHandler(mcpSvc, baseURL)
 	mcpRuntimeHandler := mcp.NewRuntimeHandler(mcpSvc)
@@ -106,6 +131,10 @@ func SetupRouter(mode string) *gin.Engine {
 				datasources.DELETE("/:id", dsHandler.Delete)
 				datasources.POST("/:id/test", dsHandler.TestConnection)
 				datasources.GET("/:id/tables", dsHandler.GetTables)
+				datasources.GET("/:id/schemas", dsHandler.ListSchemas)
+				datasources.GET("/:id/schemas/:schema/tables", dsHandler.ListSchemaTables)
+				datasources.GET("/:id/schemas/:schema/tables/:table", dsHandler.GetTableDetail)
+				datasources.POST("/:id/metadata/refresh", dsHandler.RefreshMetadata)
 			}
 
 			// Query routes
@@ -114,13 +143,76 @@ func SetupRouter(mode string) *gin.Engine {
 				queries.GET("", queryHandler.List)
 				queries.POST("", queryHandler.Create)
 				queries.POST("/validate", queryHandler.ValidateSQL)
-				queries.GET("/history", queryHandler.GetHistory) // Must be before /:id
+				queries.POST("/bulk", queryHandler.Bulk)
+				queries.GET("/history", queryHandler.GetHistory)                // Must be before /:id
+				queries.GET("/history/compare", queryHandler.CompareExecutions) // Must be before /:id
+				queries.POST("/history/:executionId/rerun", queryHandler.RerunExecution)
 				queries.GET("/:id", queryHandler.Get)
 				queries.PUT("/:id", queryHandler.Update)
 				queries.DELETE("/:id", queryHandler.Delete)
+				queries.PUT("/:id/tags", queryHandler.SetTags)
+				queries.PUT("/:id/favorite", queryHandler.AddFavorite)
+				queries.DELETE("/:id/favorite", queryHandler.RemoveFavorite)
 				queries.POST("/:id/execute", queryHandler.Execute)
+				queries.DELETE("/:id/cache", queryHandler.PurgeCache)
+				queries.POST("/:id/export", queryHandler.Export)
+				queries.POST("/:id/jobs", jobHandler.Submit)
 				queries.POST("/:id/validate", queryHandler.Validate)
 				queries.GET("/:id/parameters", queryHandler.GetParameters)
+				queries.GET("/:id/revisions", queryHandler.ListRevisions)
+				queries.GET("/:id/revisions/diff", queryHandler.DiffRevisions) // Must be before /:revision
+				queries.GET("/:id/revisions/:revision", queryHandler.GetRevision)
+				queries.POST("/:id/revisions/:revision/restore", queryHandler.RestoreRevision)
+			}
+
+			// SQL console routes
+			sqlConsole := protected.Group("/console")
+			{
+				sqlConsole.POST("/execute", consoleHandler.Execute)
+				sqlConsole.GET("/sessions/:sessionId/history", consoleHandler.GetHistory)
+				sqlConsole.DELETE("/sessions/:sessionId/history", consoleHandler.ClearHistory)
+				sqlConsole.GET("/statements/:id/results", consoleHandler.GetResults)
+				sqlConsole.POST("/statements/:id/promote", consoleHandler.Promote)
+			}
+
+			// Query folder routes
+			folders := protected.Group("/folders")
+			{
+				folders.GET("", folderHandler.List)
+				folders.POST("", folderHandler.Create)
+				folders.PUT("/:id", folderHandler.Update)
+				folders.DELETE("/:id", folderHandler.Delete)
+			}
+
+			// Tag routes, shared by queries, tools and MCP servers
+			tags := protected.Group("/tags")
+			{
+				tags.GET("", tagHandler.List)
+				tags.PUT("/:id", tagHandler.Rename)
+				tags.DELETE("/:id", tagHandler.Delete)
+			}
+
+			// Query job routes
+			jobs := protected.Group("/jobs")
+			{
+				jobs.GET("/:id", jobHandler.Get)
+				jobs.GET("/:id/results", jobHandler.GetResults)
+				jobs.POST("/:id/cancel", jobHandler.Cancel)
+			}
+
+			// Query schedule routes
+			schedules := protected.Group("/schedules")
+			{
+				schedules.GET("", scheduleHandler.List)
+				schedules.POST("", scheduleHandler.Create)
+				schedules.GET("/:id", scheduleHandler.Get)
+				schedules.PUT("/:id", scheduleHandler.Update)
+				schedules.DELETE("/:id", scheduleHandler.Delete)
+				schedules.POST("/:id/run", scheduleHandler.RunNow)
+				schedules.GET("/:id/snapshots", scheduleHandler.ListSnapshots)
+				schedules.GET("/:id/snapshots/latest", scheduleHandler.GetLatestSnapshot) // Must be before /:snapshotId
+				schedules.GET("/:id/snapshots/:snapshotId", scheduleHandler.GetSnapshot)
+				schedules.GET("/:id/snapshots/:snapshotId/diff", scheduleHandler.DiffSnapshot)
 			}
 
 			// Tool routes
@@ -128,11 +220,16 @@ func SetupRouter(mode string) *gin.Engine {
 			{
 				tools.GET("", toolHandler.List)
 				tools.POST("", toolHandler.Create)
-				tools.GET("/export", toolHandler.ExportAll) // Must be before /:id
+				tools.POST("/bulk", toolHandler.Bulk)
+				tools.GET("/export", toolHandler.ExportAll)       // Must be before /:id
+				tools.GET("/by-column", toolHandler.FindByColumn) // Must be before /:id
 				tools.POST("/from-query/:query_id", toolHandler.CreateFromQuery)
 				tools.GET("/:id", toolHandler.Get)
 				tools.PUT("/:id", toolHandler.Update)
 				tools.DELETE("/:id", toolHandler.Delete)
+				tools.PUT("/:id/tags", toolHandler.SetTags)
+				tools.PUT("/:id/favorite", toolHandler.AddFavorite)
+				tools.DELETE("/:id/favorite", toolHandler.RemoveFavorite)
 				tools.POST("/:id/test", toolHandler.TestTool)
 				tools.GET("/:id/export", toolHandler.Export)
 				tools.POST("/:id/generate-description", toolHandler.GenerateDescription)
@@ -143,19 +240,39 @@ func SetupRouter(mode string) *gin.Engine {
 			{
 				mcpServers.GET("", mcpServerHandler.List)
 				mcpServers.POST("", mcpServerHandler.Create)
+				mcpServers.POST("/bulk", mcpServerHandler.Bulk)
 				mcpServers.GET("/:id", mcpServerHandler.Get)
 				mcpServers.PUT("/:id", mcpServerHandler.Update)
 				mcpServers.DELETE("/:id", mcpServerHandler.Delete)
+				mcpServers.PUT("/:id/tags", mcpServerHandler.SetTags)
+				mcpServers.PUT("/:id/favorite", mcpServerHandler.AddFavorite)
+				mcpServers.DELETE("/:id/favorite", mcpServerHandler.RemoveFavorite)
 				mcpServers.POST("/:id/publish", mcpServerHandler.Publish)
 				mcpServers.POST("/:id/unpublish", mcpServerHandler.Unpublish)
 				mcpServers.GET("/:id/config", mcpServerHandler.GetConfig)
 				mcpServers.GET("/:id/logs", mcpServerHandler.GetLogs)
 				mcpServers.GET("/:id/statistics", mcpServerHandler.GetStatistics)
+				mcpServers.DELETE("/:id/cache", mcpServerHandler.PurgeCache)
+				mcpServers.GET("/:id/releases", mcpServerHandler.ListReleases)
+				mcpServers.GET("/:id/releases/compare", mcpServerHandler.CompareReleases) // Must be before /:releaseId
+				mcpServers.GET("/:id/releases/:releaseId", mcpServerHandler.GetRelease)
+				mcpServers.POST("/:id/releases/:releaseId/rollback", mcpServerHandler.Rollback)
 			}
 		}
 	}
 
-	return r
+	jobSvc.Start()
+	scheduleSvc.Start()
+	resultCache.Start()
+	retention.Start()
+
+	stop := func() {
+		retention.Stop()
+		resultCache.Stop()
+		scheduleSvc.Stop()
+		jobSvc.Stop()
+	}
+	return r, stop
 }
 
 func corsMiddleware() gin.HandlerFunc {
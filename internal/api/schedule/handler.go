@@ -0,0 +1,383 @@
+package schedule
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/internal/repository"
+	"github.com/yourusername/dataweaver/internal/response"
+	"github.com/yourusername/dataweaver/internal/service"
+)
+
+// Handler handles query schedule API requests
+type Handler struct {
+	service service.QueryScheduleService
+}
+
+// NewHandler creates a new Handler
+func NewHandler(svc service.QueryScheduleService) *Handler {
+	return &Handler{service: svc}
+}
+
+// getUserID extracts user ID from context (set by JWT middleware)
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+	if id, ok := userID.(uint); ok {
+		return id
+	}
+	if id, ok := userID.(float64); ok {
+		return uint(id)
+	}
+	return 0
+}
+
+// List godoc
+// @Summary List query schedules
+// @Description Get the query schedules of the current user
+// @Tags Schedules
+// @Accept json
+// @Produce json
+// @Param query_id query string false "Only schedules of this query"
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size" default(20)
+// @Security BearerAuth
+// @Success 200 {object} response.PagedResponse{data=[]model.QueryScheduleResponse}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/schedules [get]
+func (h *Handler) List(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	schedules, total, err := h.service.List(userID, c.Query("query_id"), page, size)
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.SuccessPaged(c, schedules, total, page, size)
+}
+
+// Create godoc
+// @Summary Create query schedule
+// @Description Run a query with fixed parameters on a cron schedule, keeping a snapshot of each result
+// @Tags Schedules
+// @Accept json
+// @Produce json
+// @Param request body model.CreateQueryScheduleRequest true "Schedule info"
+// @Security BearerAuth
+// @Success 201 {object} response.Response{data=model.QueryScheduleResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/schedules [post]
+func (h *Handler) Create(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	var req model.CreateQueryScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	schedule, err := h.service.Create(userID, &req)
+	if err != nil {
+		handleScheduleError(c, err)
+		return
+	}
+
+	response.Created(c, schedule)
+}
+
+// Get godoc
+// @Summary Get query schedule
+// @Description Get a query schedule by ID
+// @Tags Schedules
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.QueryScheduleResponse}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/schedules/{id} [get]
+func (h *Handler) Get(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	schedule, err := h.service.Get(c.Param("id"), userID)
+	if err != nil {
+		handleScheduleError(c, err)
+		return
+	}
+
+	response.Success(c, schedule)
+}
+
+// Update godoc
+// @Summary Update query schedule
+// @Description Update a query schedule. Its next run is worked out again from now.
+// @Tags Schedules
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Param request body model.UpdateQueryScheduleRequest true "Schedule info"
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.QueryScheduleResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/schedules/{id} [put]
+func (h *Handler) Update(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	var req model.UpdateQueryScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	schedule, err := h.service.Update(c.Param("id"), userID, &req)
+	if err != nil {
+		handleScheduleError(c, err)
+		return
+	}
+
+	response.Success(c, schedule)
+}
+
+// Delete godoc
+// @Summary Delete query schedule
+// @Description Delete a query schedule and its snapshots
+// @Tags Schedules
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/schedules/{id} [delete]
+func (h *Handler) Delete(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	if err := h.service.Delete(c.Param("id"), userID); err != nil {
+		handleScheduleError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// RunNow godoc
+// @Summary Run query schedule now
+// @Description Run a query schedule as soon as a worker is free, outside its cron times. A disabled schedule runs once, unless a run of it is already in flight.
+// @Tags Schedules
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Security BearerAuth
+// @Success 202 {object} response.Response{data=model.QueryScheduleResponse}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/schedules/{id}/run [post]
+func (h *Handler) RunNow(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	schedule, err := h.service.RunNow(c.Param("id"), userID)
+	if err != nil {
+		handleScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response.Response{
+		Code:    0,
+		Message: "success",
+		Data:    schedule,
+	})
+}
+
+// ListSnapshots godoc
+// @Summary List query snapshots
+// @Description Get the snapshots of a schedule, newest first, without their rows
+// @Tags Schedules
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Param changed query bool false "Only snapshots that differ from the previous one"
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size" default(20)
+// @Security BearerAuth
+// @Success 200 {object} response.PagedResponse{data=[]model.QuerySnapshotResponse}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/schedules/{id}/snapshots [get]
+func (h *Handler) ListSnapshots(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+	changed, _ := strconv.ParseBool(c.DefaultQuery("changed", "false"))
+
+	snapshots, total, err := h.service.ListSnapshots(c.Param("id"), userID, changed, page, size)
+	if err != nil {
+		handleScheduleError(c, err)
+		return
+	}
+
+	response.SuccessPaged(c, snapshots, total, page, size)
+}
+
+// GetLatestSnapshot godoc
+// @Summary Get latest query snapshot
+// @Description Get the newest successful snapshot of a schedule with its rows
+// @Tags Schedules
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.QuerySnapshotResponse}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/schedules/{id}/snapshots/latest [get]
+func (h *Handler) GetLatestSnapshot(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	snapshot, err := h.service.GetLatestSnapshot(c.Param("id"), userID)
+	if err != nil {
+		handleScheduleError(c, err)
+		return
+	}
+
+	response.Success(c, snapshot)
+}
+
+// GetSnapshot godoc
+// @Summary Get query snapshot
+// @Description Get a snapshot of a schedule with its rows
+// @Tags Schedules
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Param snapshotId path string true "Snapshot ID"
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.QuerySnapshotResponse}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/schedules/{id}/snapshots/{snapshotId} [get]
+func (h *Handler) GetSnapshot(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	snapshot, err := h.service.GetSnapshot(c.Param("id"), userID, c.Param("snapshotId"))
+	if err != nil {
+		handleScheduleError(c, err)
+		return
+	}
+
+	response.Success(c, snapshot)
+}
+
+// DiffSnapshot godoc
+// @Summary Diff query snapshot
+// @Description List the rows added, removed and changed since the previous snapshot, matched by the schedule's key columns
+// @Tags Schedules
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Param snapshotId path string true "Snapshot ID"
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.SnapshotDiff}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/schedules/{id}/snapshots/{snapshotId}/diff [get]
+func (h *Handler) DiffSnapshot(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	diff, err := h.service.DiffSnapshot(c.Param("id"), userID, c.Param("snapshotId"))
+	if err != nil {
+		handleScheduleError(c, err)
+		return
+	}
+
+	response.Success(c, diff)
+}
+
+func handleScheduleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrQueryScheduleNotFound):
+		response.NotFound(c, "schedule not found")
+	case errors.Is(err, repository.ErrQuerySnapshotNotFound):
+		response.NotFound(c, "snapshot not found")
+	case errors.Is(err, repository.ErrQueryNotFound):
+		response.NotFound(c, "query not found")
+	case errors.Is(err, service.ErrNoPreviousSnapshot):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrMissingParameters),
+		errors.Is(err, service.ErrInvalidCron),
+		errors.Is(err, service.ErrInvalidTimezone),
+		errors.Is(err, service.ErrKeyColumnNotInResult):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, service.ErrScheduleRunning):
+		response.Error(c, http.StatusConflict, err.Error())
+	default:
+		response.InternalError(c, err.Error())
+	}
+}
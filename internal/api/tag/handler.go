@@ -0,0 +1,144 @@
+package tag
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/internal/repository"
+	"github.com/yourusername/dataweaver/internal/response"
+	"github.com/yourusername/dataweaver/internal/service"
+)
+
+// Handler handles tag API requests
+type Handler struct {
+	service service.TagService
+}
+
+// NewHandler creates a new Handler
+func NewHandler(svc service.TagService) *Handler {
+	return &Handler{service: svc}
+}
+
+// getUserID extracts user ID from context (set by JWT middleware)
+func getUserID(c *gin.Context) uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return 0
+	}
+	if id, ok := userID.(uint); ok {
+		return id
+	}
+	if id, ok := userID.(float64); ok {
+		return uint(id)
+	}
+	return 0
+}
+
+// List godoc
+// @Summary List tags
+// @Description Get the tags of the current user with how many queries, tools and MCP servers carry each
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]model.TagResponse}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/tags [get]
+func (h *Handler) List(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	tags, err := h.service.List(userID)
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, tags)
+}
+
+// Rename godoc
+// @Summary Rename tag
+// @Description Rename a tag on every query, tool and MCP server carrying it
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Param request body model.RenameTagRequest true "New name"
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=model.TagResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/tags/{id} [put]
+func (h *Handler) Rename(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	var req model.RenameTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	tag, err := h.service.Rename(c.Param("id"), userID, &req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	response.Success(c, tag)
+}
+
+// Delete godoc
+// @Summary Delete tag
+// @Description Delete a tag, removing it from every query, tool and MCP server
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/tags/{id} [delete]
+func (h *Handler) Delete(c *gin.Context) {
+	userID := getUserID(c)
+	if userID == 0 {
+		response.Unauthorized(c, "unauthorized")
+		return
+	}
+
+	if err := h.service.Delete(c.Param("id"), userID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// handleError maps tag errors to responses
+func handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrTagNotFound):
+		response.NotFound(c, "tag not found")
+	case errors.Is(err, repository.ErrTagNameExists):
+		response.Error(c, http.StatusConflict, "tag name already exists")
+	case errors.Is(err, service.ErrInvalidTagName):
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalError(c, err.Error())
+	}
+}
@@ -113,7 +113,10 @@ func (h *Handler) CreateFromQuery(c *gin.Context) {
 // @Param page query int false "Page number" default(1)
 // @Param size query int false "Page size" default(20)
 // @Param keyword query string false "Search keyword"
+// @Param tag query []string false "Only tools with every tag" collectionFormat(multi)
+// @Param favorite query bool false "Only the user's favorites"
 // @Success 200 {object} response.Response{data=response.PaginatedData{items=[]model.ToolResponse}}
+// @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Router /tools [get]
 func (h *Handler) List(c *gin.Context) {
@@ -121,9 +124,14 @@ func (h *Handler) List(c *gin.Context) {
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
-	keyword := c.Query("keyword")
 
-	tools, total, err := h.toolService.List(userID, page, size, keyword)
+	var filter model.ListFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	tools, total, err := h.toolService.List(userID, page, size, &filter)
 	if err != nil {
 		response.InternalError(c, err.Error())
 		return
@@ -292,6 +300,38 @@ func (h *Handler) ExportAll(c *gin.Context) {
 	response.Success(c, definitions)
 }
 
+// FindByColumn lists the tools that read a column
+// @Summary Find tools by column
+// @Description List the tools whose query returns or filters on a column, based on the column lineage of each query
+// @Tags tools
+// @Produce json
+// @Security Bearer
+// @Param column query string true "Column name"
+// @Param table query string false "Table name"
+// @Param schema query string false "Schema name"
+// @Param data_source_id query string false "Data source ID"
+// @Success 200 {object} response.Response{data=[]model.ToolColumnUsage}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /tools/by-column [get]
+func (h *Handler) FindByColumn(c *gin.Context) {
+	userID := getUserID(c)
+
+	var req model.ColumnUsageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	usages, err := h.toolService.FindByColumn(userID, &req)
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, usages)
+}
+
 // GenerateDescription generates a description for a tool
 // @Summary Generate description
 // @Description Generate a description for a tool based on its query
@@ -334,8 +374,16 @@ func handleToolError(c *gin.Context, err error) {
 		response.Error(c, http.StatusConflict, "Tool name already exists")
 	case errors.Is(err, service.ErrQueryRequired):
 		response.BadRequest(c, "Query not found")
+	case errors.Is(err, repository.ErrQueryRevisionNotFound):
+		response.BadRequest(c, "Query revision not found")
+	case errors.Is(err, repository.ErrQueryScheduleNotFound):
+		response.BadRequest(c, "Schedule not found")
+	case errors.Is(err, service.ErrScheduleQuery):
+		response.BadRequest(c, "Schedule must run the tool's query")
 	case errors.Is(err, service.ErrInvalidToolName):
 		response.BadRequest(c, "Invalid tool name format. Must be snake_case (lowercase letters, numbers, underscores)")
+	case errors.Is(err, service.ErrBulkTagsRequired), errors.Is(err, service.ErrBulkActionNotSupported):
+		response.BadRequest(c, err.Error())
 	default:
 		response.InternalError(c, err.Error())
 	}
@@ -0,0 +1,110 @@
+package tool
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/internal/response"
+)
+
+// SetTags replaces the tags of a tool
+// @Summary Set tool tags
+// @Description Replace the tags of a tool; tags are created on first use
+// @Tags tools
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Tool ID"
+// @Param request body model.SetTagsRequest true "Tags"
+// @Success 200 {object} response.Response{data=model.ToolResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /tools/{id}/tags [put]
+func (h *Handler) SetTags(c *gin.Context) {
+	userID := getUserID(c)
+
+	var req model.SetTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	tool, err := h.toolService.SetTags(c.Param("id"), userID, req.Tags)
+	if err != nil {
+		handleToolError(c, err)
+		return
+	}
+
+	response.Success(c, tool)
+}
+
+// AddFavorite marks a tool as a favorite
+// @Summary Favorite tool
+// @Description Mark a tool as a favorite of the current user
+// @Tags tools
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Tool ID"
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /tools/{id}/favorite [put]
+func (h *Handler) AddFavorite(c *gin.Context) {
+	h.setFavorite(c, true)
+}
+
+// RemoveFavorite removes a tool from the favorites
+// @Summary Unfavorite tool
+// @Description Remove a tool from the favorites of the current user
+// @Tags tools
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Tool ID"
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /tools/{id}/favorite [delete]
+func (h *Handler) RemoveFavorite(c *gin.Context) {
+	h.setFavorite(c, false)
+}
+
+func (h *Handler) setFavorite(c *gin.Context, favorite bool) {
+	userID := getUserID(c)
+
+	if err := h.toolService.SetFavorite(c.Param("id"), userID, favorite); err != nil {
+		handleToolError(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// Bulk applies one action to many tools
+// @Summary Bulk update tools
+// @Description Tag, untag or delete many tools; reports the tools each action failed on
+// @Tags tools
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body model.BulkRequest true "Action and tool IDs"
+// @Success 200 {object} response.Response{data=model.BulkResult}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /tools/bulk [post]
+func (h *Handler) Bulk(c *gin.Context) {
+	userID := getUserID(c)
+
+	var req model.BulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.toolService.Bulk(userID, &req)
+	if err != nil {
+		handleToolError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// legacyStatuses maps the statuses synchronous executions, exports, console
+// statements and schedule snapshots used to record to the lifecycle statuses
+var legacyStatuses = map[string]string{
+	"success": model.ExecutionStatusSucceeded,
+	"error":   model.ExecutionStatusFailed,
+}
+
+// statusColumns are the columns holding execution statuses
+var statusColumns = []struct{ table, column string }{
+	{"query_executions", "status"},
+	{"console_statements", "status"},
+	{"query_snapshots", "status"},
+	{"query_schedules", "last_status"},
+}
+
+// MigrateData rewrites data kept in an older format. It runs after
+// AutoMigrate and does nothing once the data is current.
+func MigrateData() error {
+	for _, c := range statusColumns {
+		for from, to := range legacyStatuses {
+			result := DB.Table(c.table).Where(c.column+" = ?", from).Update(c.column, to)
+			if result.Error != nil {
+				return fmt.Errorf("failed to migrate %s.%s: %w", c.table, c.column, result.Error)
+			}
+			if result.RowsAffected > 0 {
+				logger.Info("Migrated execution statuses",
+					zap.String("table", c.table),
+					zap.String("from", from),
+					zap.String("to", to),
+					zap.Int64("rows", result.RowsAffected),
+				)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package model
+
+import "time"
+
+// ConsoleStatement is an ad-hoc SQL statement run from the SQL console,
+// kept in the history of the console session it was run in
+type ConsoleStatement struct {
+	ID              string    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID          uint      `gorm:"not null;index:idx_console_user_session" json:"user_id"`
+	SessionID       string    `gorm:"size:64;not null;index:idx_console_user_session" json:"session_id"` // Chosen by the client, e.g. one per editor tab
+	DataSourceID    string    `gorm:"type:uuid;not null" json:"data_source_id"`
+	SQL             string    `gorm:"type:text;not null" json:"sql"` // As entered
+	Parameters      string    `gorm:"type:jsonb" json:"parameters"`
+	Status          string    `gorm:"size:20;not null" json:"status"` // ExecutionStatusSucceeded or ExecutionStatusFailed
+	RowCount        int       `json:"row_count"`
+	Truncated       bool      `gorm:"not null;default:false" json:"truncated"`
+	ExecutionTimeMs int64     `json:"execution_time_ms"`
+	ErrorMessage    string    `gorm:"type:text" json:"error_message,omitempty"`
+	QueryID         *string   `gorm:"type:uuid" json:"query_id,omitempty"` // Saved query the statement was promoted to
+	CreatedAt       time.Time `gorm:"index" json:"created_at"`
+}
+
+func (ConsoleStatement) TableName() string {
+	return "console_statements"
+}
+
+// Request/Response DTOs
+
+// ConsoleExecuteRequest represents the request body for running SQL in the console
+type ConsoleExecuteRequest struct {
+	SessionID    string                 `json:"session_id" binding:"required,max=64"`
+	DataSourceID string                 `json:"data_source_id" binding:"required,uuid"`
+	SQL          string                 `json:"sql" binding:"required"`
+	Parameters   map[string]interface{} `json:"parameters"`
+	Size         int                    `json:"size"` // Rows in the first page
+}
+
+// ConsoleResultResponse is one page of the result of a console statement
+type ConsoleResultResponse struct {
+	StatementID     string                   `json:"statement_id"`
+	Columns         []string                 `json:"columns"`
+	ColumnTypes     []ResultColumn           `json:"column_types,omitempty"`
+	Data            []map[string]interface{} `json:"data"`
+	Total           int                      `json:"total"` // Rows in the whole result
+	Page            int                      `json:"page"`
+	Size            int                      `json:"size"`
+	Truncated       bool                     `json:"truncated"` // Whether the statement stopped at the row or size limit
+	ExecutionTimeMs int64                    `json:"execution_time_ms"`
+	ResultSets      []ResultSetResponse      `json:"result_sets,omitempty"` // Result sets after the first, unpaged and only with the first page
+}
+
+// ConsoleStatementResponse represents a console history item
+type ConsoleStatementResponse struct {
+	ID              string                 `json:"id"`
+	SessionID       string                 `json:"session_id"`
+	DataSourceID    string                 `json:"data_source_id"`
+	SQL             string                 `json:"sql"`
+	Parameters      map[string]interface{} `json:"parameters"`
+	Status          string                 `json:"status"`
+	RowCount        int                    `json:"row_count"`
+	Truncated       bool                   `json:"truncated"`
+	ExecutionTimeMs int64                  `json:"execution_time_ms"`
+	ErrorMessage    string                 `json:"error_message,omitempty"`
+	QueryID         *string                `json:"query_id,omitempty"`
+	CreatedAt       time.Time              `json:"created_at"`
+}
+
+// PromoteStatementRequest represents the request body for saving a console
+// statement as a query
+type PromoteStatementRequest struct {
+	Name        string  `json:"name" binding:"required,min=1,max=100"`
+	Description string  `json:"description" binding:"max=500"`
+	FolderID    *string `json:"folder_id" binding:"omitempty,uuid"`
+}
@@ -29,6 +29,7 @@ type DataSource struct {
 	Username    string         `gorm:"size:100;not null" json:"username" binding:"required"`
 	Password    string         `gorm:"size:500;not null" json:"-"` // encrypted, not returned in JSON
 	SSLMode     string         `gorm:"size:20;default:'disable'" json:"ssl_mode"`
+	ReadOnly    *bool          `gorm:"not null;default:true" json:"read_only"` // nil until persisted; see IsReadOnly
 	Status      string         `gorm:"size:20;default:'active'" json:"status"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
@@ -41,6 +42,12 @@ func (DataSource) TableName() string {
 	return "data_sources"
 }
 
+// IsReadOnly reports whether queries must run in read-only sessions.
+// Data sources are read-only unless explicitly turned off.
+func (ds *DataSource) IsReadOnly() bool {
+	return ds.ReadOnly == nil || *ds.ReadOnly
+}
+
 // CreateDataSourceRequest represents the request body for creating a datasource
 type CreateDataSourceRequest struct {
 	Name        string `json:"name" binding:"required,min=1,max=100"`
@@ -52,6 +59,7 @@ type CreateDataSourceRequest struct {
 	Username    string `json:"username" binding:"required"`
 	Password    string `json:"password" binding:"required"`
 	SSLMode     string `json:"ssl_mode"`
+	ReadOnly    *bool  `json:"read_only"` // Defaults to true
 }
 
 // UpdateDataSourceRequest represents the request body for updating a datasource
@@ -65,6 +73,7 @@ type UpdateDataSourceRequest struct {
 	Username    *string `json:"username"`
 	Password    *string `json:"password"`
 	SSLMode     *string `json:"ssl_mode"`
+	ReadOnly    *bool   `json:"read_only"`
 	Status      *string `json:"status" binding:"omitempty,oneof=active inactive"`
 }
 
@@ -80,6 +89,7 @@ type DataSourceResponse struct {
 	Database    string    `json:"database"`
 	Username    string    `json:"username"`
 	SSLMode     string    `json:"ssl_mode"`
+	ReadOnly    bool      `json:"read_only"`
 	Status      string    `json:"status"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
@@ -98,6 +108,7 @@ func (ds *DataSource) ToResponse() *DataSourceResponse {
 		Database:    ds.Database,
 		Username:    ds.Username,
 		SSLMode:     ds.SSLMode,
+		ReadOnly:    ds.IsReadOnly(),
 		Status:      ds.Status,
 		CreatedAt:   ds.CreatedAt,
 		UpdatedAt:   ds.UpdatedAt,
@@ -111,18 +122,54 @@ type TestConnectionResult struct {
 	Latency int64  `json:"latency_ms"`
 }
 
+// SchemaInfoResponse represents a schema in a datasource
+type SchemaInfoResponse struct {
+	Name string `json:"name"`
+}
+
+// ListTablesRequest represents the query parameters for browsing tables in a schema
+type ListTablesRequest struct {
+	Keyword        string `form:"keyword"`
+	Page           int    `form:"page"`
+	Size           int    `form:"size"`
+	IncludeColumns bool   `form:"include_columns"`
+}
+
 // TableInfoResponse represents table information from a datasource
 type TableInfoResponse struct {
-	Name     string               `json:"name"`
-	Schema   string               `json:"schema"`
-	RowCount int64                `json:"row_count,omitempty"`
-	Columns  []ColumnInfoResponse `json:"columns,omitempty"`
+	Name        string                   `json:"name"`
+	Schema      string                   `json:"schema"`
+	Type        string                   `json:"type"` // table, view, materialized_view
+	Comment     string                   `json:"comment,omitempty"`
+	RowCount    *int64                   `json:"row_count,omitempty"` // approximate; omitted when unknown
+	Columns     []ColumnInfoResponse     `json:"columns,omitempty"`
+	Indexes     []IndexInfoResponse      `json:"indexes,omitempty"`
+	ForeignKeys []ForeignKeyInfoResponse `json:"foreign_keys,omitempty"`
 }
 
 // ColumnInfoResponse represents column information
 type ColumnInfoResponse struct {
-	Name       string `json:"name"`
-	Type       string `json:"type"`
-	Nullable   bool   `json:"nullable"`
-	PrimaryKey bool   `json:"primary_key"`
+	Name       string  `json:"name"`
+	Type       string  `json:"type"`
+	Nullable   bool    `json:"nullable"`
+	PrimaryKey bool    `json:"primary_key"`
+	Default    *string `json:"default,omitempty"`
+	Comment    string  `json:"comment,omitempty"`
+}
+
+// IndexInfoResponse represents index information
+type IndexInfoResponse struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique"`
+	Primary bool     `json:"primary"`
+}
+
+// ForeignKeyInfoResponse represents a foreign key constraint
+type ForeignKeyInfoResponse struct {
+	Name              string   `json:"name"`
+	Columns           []string `json:"columns"`
+	ReferencedSchema  string   `json:"referenced_schema"`
+	ReferencedTable   string   `json:"referenced_table"`
+	ReferencedColumns []string `json:"referenced_columns"`
 }
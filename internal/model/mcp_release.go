@@ -0,0 +1,191 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ReleaseTool is a tool as it was resolved when its server was published:
+// the tool definition together with the query revision it runs
+type ReleaseTool struct {
+	ToolID          string                 `json:"tool_id"`
+	ToolVersion     int                    `json:"tool_version"`
+	Name            string                 `json:"name"`
+	DisplayName     string                 `json:"display_name"`
+	Description     string                 `json:"description"`
+	Parameters      []ToolParameter        `json:"parameters"`
+	OutputSchema    map[string]interface{} `json:"output_schema,omitempty"`
+	MaxRows         int                    `json:"max_rows"`
+	CacheTTL        int                    `json:"cache_ttl"`
+	CostGuard       CostGuard              `json:"cost_guard"`
+	ScheduleID      *string                `json:"schedule_id,omitempty"`
+	QueryID         string                 `json:"query_id"`
+	QueryRevision   int                    `json:"query_revision"`
+	DataSourceID    string                 `json:"data_source_id"`
+	SQLTemplate     string                 `json:"sql_template"`
+	QueryParameters []QueryParameter       `json:"query_parameters"`
+}
+
+// NewReleaseTool snapshots a tool and the query it runs
+func NewReleaseTool(t *Tool, q *Query) ReleaseTool {
+	return ReleaseTool{
+		ToolID:          t.ID,
+		ToolVersion:     t.Version,
+		Name:            t.Name,
+		DisplayName:     t.DisplayName,
+		Description:     t.Description,
+		Parameters:      []ToolParameter(t.Parameters),
+		OutputSchema:    map[string]interface{}(t.OutputSchema),
+		MaxRows:         t.MaxRows,
+		CacheTTL:        t.CacheTTL,
+		CostGuard:       t.CostGuard,
+		ScheduleID:      t.ScheduleID,
+		QueryID:         q.ID,
+		QueryRevision:   q.Revision,
+		DataSourceID:    q.DataSourceID,
+		SQLTemplate:     q.SQLTemplate,
+		QueryParameters: []QueryParameter(q.Parameters),
+	}
+}
+
+// Tool rebuilds the released tool definition
+func (r *ReleaseTool) Tool() *Tool {
+	revision := r.QueryRevision
+	return &Tool{
+		ID:            r.ToolID,
+		Name:          r.Name,
+		DisplayName:   r.DisplayName,
+		Description:   r.Description,
+		QueryID:       r.QueryID,
+		QueryRevision: &revision,
+		Parameters:    ToolParameters(r.Parameters),
+		OutputSchema:  OutputSchema(r.OutputSchema),
+		Version:       r.ToolVersion,
+		MaxRows:       r.MaxRows,
+		CacheTTL:      r.CacheTTL,
+		CostGuard:     r.CostGuard,
+		ScheduleID:    r.ScheduleID,
+		Status:        "active",
+	}
+}
+
+// Query rebuilds the released query definition
+func (r *ReleaseTool) Query() *Query {
+	return &Query{
+		ID:           r.QueryID,
+		DataSourceID: r.DataSourceID,
+		SQLTemplate:  r.SQLTemplate,
+		Parameters:   JSONParameters(r.QueryParameters),
+		Revision:     r.QueryRevision,
+		Status:       "active",
+	}
+}
+
+// ReleaseTools is a custom type for storing release tools in the database
+type ReleaseTools []ReleaseTool
+
+// Value implements driver.Valuer interface
+func (t ReleaseTools) Value() (driver.Value, error) {
+	if t == nil {
+		return nil, nil
+	}
+	return json.Marshal(t)
+}
+
+// Scan implements sql.Scanner interface
+func (t *ReleaseTools) Scan(value interface{}) error {
+	if value == nil {
+		*t = nil
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("failed to scan ReleaseTools")
+	}
+
+	if len(bytes) == 0 {
+		*t = nil
+		return nil
+	}
+
+	return json.Unmarshal(bytes, t)
+}
+
+// McpServerRelease is an immutable snapshot of a server's tools, taken each
+// time the server is published
+type McpServerRelease struct {
+	ID          string       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	McpServerID string       `gorm:"type:uuid;not null;uniqueIndex:idx_mcp_server_release" json:"mcp_server_id"`
+	Version     string       `gorm:"size:20;not null;uniqueIndex:idx_mcp_server_release" json:"version"`
+	UserID      uint         `gorm:"index;not null" json:"user_id"`
+	Tools       ReleaseTools `gorm:"type:jsonb" json:"tools"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+func (McpServerRelease) TableName() string {
+	return "mcp_server_releases"
+}
+
+// FindTool returns the released tool with a name, or nil
+func (r *McpServerRelease) FindTool(name string) *ReleaseTool {
+	for i := range r.Tools {
+		if r.Tools[i].Name == name {
+			return &r.Tools[i]
+		}
+	}
+	return nil
+}
+
+// McpServerReleaseResponse represents a release in API responses
+type McpServerReleaseResponse struct {
+	ID          string        `json:"id"`
+	McpServerID string        `json:"mcp_server_id"`
+	Version     string        `json:"version"`
+	Active      bool          `json:"active"` // Whether the runtime serves this release
+	UserID      uint          `json:"user_id"`
+	Tools       []ReleaseTool `json:"tools"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// ToResponse converts McpServerRelease to McpServerReleaseResponse
+func (r *McpServerRelease) ToResponse(activeReleaseID *string) *McpServerReleaseResponse {
+	tools := []ReleaseTool(r.Tools)
+	if tools == nil {
+		tools = []ReleaseTool{}
+	}
+	return &McpServerReleaseResponse{
+		ID:          r.ID,
+		McpServerID: r.McpServerID,
+		Version:     r.Version,
+		Active:      activeReleaseID != nil && *activeReleaseID == r.ID,
+		UserID:      r.UserID,
+		Tools:       tools,
+		CreatedAt:   r.CreatedAt,
+	}
+}
+
+// ReleaseToolChange is a tool added, removed or changed between two releases
+type ReleaseToolChange struct {
+	Name   string        `json:"name"`
+	Change string        `json:"change"` // added, removed, changed
+	Fields []FieldChange `json:"fields,omitempty"`
+	SQL    []DiffLine    `json:"sql,omitempty"` // Line diff of the SQL template, when it changed
+}
+
+// McpServerReleaseDiff compares two releases of a server
+type McpServerReleaseDiff struct {
+	McpServerID   string              `json:"mcp_server_id"`
+	FromReleaseID string              `json:"from_release_id"`
+	FromVersion   string              `json:"from_version"`
+	ToReleaseID   string              `json:"to_release_id"`
+	ToVersion     string              `json:"to_version"`
+	Tools         []ReleaseToolChange `json:"tools"`
+}
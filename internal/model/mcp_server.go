@@ -53,7 +53,8 @@ type ServerConfig struct {
 	TimeoutSeconds  int    `json:"timeout_seconds"`
 	RateLimitPerMin int    `json:"rate_limit_per_min"`
 	LogLevel        string `json:"log_level"`
-	EnableCaching   bool   `json:"enable_caching"`
+	EnableCaching   bool   `json:"enable_caching"` // Answer tool calls from the result cache while a result is fresh
+	MaxRows         int    `json:"max_rows"`       // Row cap for the server's tools; 0 uses the global limit
 }
 
 // ServerConfigJSON is a custom type for storing ServerConfig in the database
@@ -102,19 +103,20 @@ const (
 
 // McpServer represents an MCP server instance
 type McpServer struct {
-	ID          string           `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	UserID      uint             `gorm:"index;not null" json:"user_id"`
-	Name        string           `gorm:"size:100;not null" json:"name"`
-	Description string           `gorm:"type:text" json:"description"`
-	Version     string           `gorm:"size:20;default:'1.0.0'" json:"version"`
-	ToolIDs     StringArray      `gorm:"type:jsonb" json:"tool_ids"`
-	Config      ServerConfigJSON `gorm:"type:jsonb" json:"config"`
-	Status      string           `gorm:"size:20;default:'draft'" json:"status"`
-	Endpoint    string           `gorm:"size:500" json:"endpoint"`
-	ApiKey      string           `gorm:"size:100" json:"api_key"`
-	CreatedAt   time.Time        `json:"created_at"`
-	UpdatedAt   time.Time        `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt   `gorm:"index" json:"-"`
+	ID              string           `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID          uint             `gorm:"index;not null" json:"user_id"`
+	Name            string           `gorm:"size:100;not null" json:"name"`
+	Description     string           `gorm:"type:text" json:"description"`
+	Version         string           `gorm:"size:20;default:'1.0.0'" json:"version"`
+	ToolIDs         StringArray      `gorm:"type:jsonb" json:"tool_ids"`
+	ActiveReleaseID *string          `gorm:"type:uuid" json:"active_release_id,omitempty"` // Release served by the runtime
+	Config          ServerConfigJSON `gorm:"type:jsonb" json:"config"`
+	Status          string           `gorm:"size:20;default:'draft'" json:"status"`
+	Endpoint        string           `gorm:"size:500" json:"endpoint"`
+	ApiKey          string           `gorm:"size:100" json:"api_key"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt   `gorm:"index" json:"-"`
 
 	// Preloaded relationships
 	Tools []Tool `gorm:"-" json:"tools,omitempty"`
@@ -193,6 +195,7 @@ type McpLog struct {
 	Status         string           `gorm:"size:20" json:"status"`
 	ErrorMessage   string           `gorm:"type:text" json:"error_message"`
 	RowCount       int              `gorm:"default:0" json:"row_count"`
+	CacheHit       bool             `gorm:"default:false" json:"cache_hit"` // Answered from the result cache
 	Timestamp      time.Time        `gorm:"index" json:"timestamp"`
 }
 
@@ -204,10 +207,11 @@ func (McpLog) TableName() string {
 
 // CreateMcpServerRequest represents the request body for creating an MCP server
 type CreateMcpServerRequest struct {
-	Name        string       `json:"name" binding:"required,min=1,max=100"`
-	Description string       `json:"description"`
-	ToolIDs     []string     `json:"tool_ids"`
-	Config      ServerConfig `json:"config"`
+	Name            string       `json:"name" binding:"required,min=1,max=100"`
+	Description     string       `json:"description"`
+	ToolIDs         []string     `json:"tool_ids"`
+	ActiveReleaseID *string      `json:"active_release_id,omitempty"`
+	Config          ServerConfig `json:"config"`
 }
 
 // UpdateMcpServerRequest represents the request body for updating an MCP server
@@ -221,19 +225,22 @@ type UpdateMcpServerRequest struct {
 
 // McpServerResponse represents the response body for an MCP server
 type McpServerResponse struct {
-	ID          string       `json:"id"`
-	UserID      uint         `json:"user_id"`
-	Name        string       `json:"name"`
-	Description string       `json:"description"`
-	Version     string       `json:"version"`
-	ToolIDs     []string     `json:"tool_ids"`
-	Config      ServerConfig `json:"config"`
-	Status      string       `json:"status"`
-	Endpoint    string       `json:"endpoint,omitempty"`
-	ApiKey      string       `json:"api_key,omitempty"`
-	CreatedAt   time.Time    `json:"created_at"`
-	UpdatedAt   time.Time    `json:"updated_at"`
-	Tools       []ToolInfo   `json:"tools,omitempty"`
+	ID              string       `json:"id"`
+	UserID          uint         `json:"user_id"`
+	Name            string       `json:"name"`
+	Description     string       `json:"description"`
+	Version         string       `json:"version"`
+	ToolIDs         []string     `json:"tool_ids"`
+	ActiveReleaseID *string      `json:"active_release_id,omitempty"`
+	Config          ServerConfig `json:"config"`
+	Status          string       `json:"status"`
+	Endpoint        string       `json:"endpoint,omitempty"`
+	ApiKey          string       `json:"api_key,omitempty"`
+	Tags            []string     `json:"tags"`
+	Favorite        bool         `json:"favorite"` // Whether the server is a favorite of the current user
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+	Tools           []ToolInfo   `json:"tools,omitempty"`
 }
 
 // ToolInfo represents minimal tool info in MCP server response
@@ -252,17 +259,19 @@ func (s *McpServer) ToResponse() *McpServerResponse {
 	}
 
 	resp := &McpServerResponse{
-		ID:          s.ID,
-		UserID:      s.UserID,
-		Name:        s.Name,
-		Description: s.Description,
-		Version:     s.Version,
-		ToolIDs:     toolIDs,
-		Config:      s.Config.ServerConfig,
-		Status:      s.Status,
-		Endpoint:    s.Endpoint,
-		CreatedAt:   s.CreatedAt,
-		UpdatedAt:   s.UpdatedAt,
+		ID:              s.ID,
+		UserID:          s.UserID,
+		Name:            s.Name,
+		Description:     s.Description,
+		Version:         s.Version,
+		ToolIDs:         toolIDs,
+		ActiveReleaseID: s.ActiveReleaseID,
+		Config:          s.Config.ServerConfig,
+		Status:          s.Status,
+		Endpoint:        s.Endpoint,
+		Tags:            []string{},
+		CreatedAt:       s.CreatedAt,
+		UpdatedAt:       s.UpdatedAt,
 	}
 
 	// Only include API key if published
@@ -315,6 +324,7 @@ type McpLogResponse struct {
 	Status         string                 `json:"status"`
 	ErrorMessage   string                 `json:"error_message,omitempty"`
 	RowCount       int                    `json:"row_count"`
+	CacheHit       bool                   `json:"cache_hit"`
 	Timestamp      time.Time              `json:"timestamp"`
 }
 
@@ -335,6 +345,7 @@ func (l *McpLog) ToResponse() *McpLogResponse {
 		Status:         l.Status,
 		ErrorMessage:   l.ErrorMessage,
 		RowCount:       l.RowCount,
+		CacheHit:       l.CacheHit,
 		Timestamp:      l.Timestamp,
 	}
 }
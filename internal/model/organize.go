@@ -0,0 +1,142 @@
+package model
+
+import "time"
+
+// Resource types that can be tagged and favorited
+const (
+	ResourceQuery     = "query"
+	ResourceTool      = "tool"
+	ResourceMcpServer = "mcp_server"
+)
+
+// Tag is a user's label, shared by their queries, tools and MCP servers
+type Tag struct {
+	ID        string    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_tag_user_name" json:"user_id"`
+	Name      string    `gorm:"size:50;not null;uniqueIndex:idx_tag_user_name" json:"name"` // Lower case
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// Tagging attaches a tag to a query, tool or MCP server
+type Tagging struct {
+	TagID        string    `gorm:"type:uuid;primaryKey" json:"tag_id"`
+	ResourceType string    `gorm:"size:20;primaryKey;index:idx_tagging_resource" json:"resource_type"` // See ResourceQuery
+	ResourceID   string    `gorm:"type:uuid;primaryKey;index:idx_tagging_resource" json:"resource_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (Tagging) TableName() string {
+	return "taggings"
+}
+
+// Favorite marks a query, tool or MCP server as a favorite of a user
+type Favorite struct {
+	UserID       uint      `gorm:"primaryKey" json:"user_id"`
+	ResourceType string    `gorm:"size:20;primaryKey" json:"resource_type"` // See ResourceQuery
+	ResourceID   string    `gorm:"type:uuid;primaryKey" json:"resource_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (Favorite) TableName() string {
+	return "favorites"
+}
+
+// QueryFolder groups queries; folders nest under a parent folder
+type QueryFolder struct {
+	ID        string    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uint      `gorm:"index;not null" json:"user_id"`
+	ParentID  *string   `gorm:"type:uuid;index" json:"parent_id,omitempty"` // Nil for top-level folders
+	Name      string    `gorm:"size:100;not null" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (QueryFolder) TableName() string {
+	return "query_folders"
+}
+
+// Request/Response DTOs
+
+// ListFilter narrows the lists of queries, tools and MCP servers
+type ListFilter struct {
+	Keyword   string   `form:"keyword"`
+	Tags      []string `form:"tag" binding:"dive,min=1,max=50"`           // Repeat for several; items must carry every tag
+	Favorite  bool     `form:"favorite"`                                  // Only the user's favorites
+	FolderID  string   `form:"folderId" binding:"omitempty,uuid|eq=root"` // Queries only; "root" lists queries in no folder
+	Recursive bool     `form:"recursive"`                                 // Queries only; include the folder's subfolders
+}
+
+// SetTagsRequest replaces the tags of a query, tool or MCP server
+type SetTagsRequest struct {
+	Tags []string `json:"tags" binding:"dive,min=1,max=50"`
+}
+
+// RenameTagRequest represents the request body for renaming a tag
+type RenameTagRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=50"`
+}
+
+// TagResponse represents a tag and how many items carry it
+type TagResponse struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Queries    int64     `json:"queries"`
+	Tools      int64     `json:"tools"`
+	McpServers int64     `json:"mcp_servers"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CreateFolderRequest represents the request body for creating a folder
+type CreateFolderRequest struct {
+	Name     string  `json:"name" binding:"required,min=1,max=100"`
+	ParentID *string `json:"parent_id" binding:"omitempty,uuid"`
+}
+
+// UpdateFolderRequest represents the request body for renaming or moving a folder
+type UpdateFolderRequest struct {
+	Name     *string `json:"name" binding:"omitempty,min=1,max=100"`
+	ParentID *string `json:"parent_id" binding:"omitempty,uuid|eq="` // "" moves the folder to the top level
+}
+
+// FolderResponse represents a folder in API responses
+type FolderResponse struct {
+	ID         string    `json:"id"`
+	ParentID   *string   `json:"parent_id,omitempty"`
+	Name       string    `json:"name"`
+	Path       string    `json:"path"`        // Names from the top-level folder down, joined by "/"
+	QueryCount int64     `json:"query_count"` // Queries directly in the folder
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Bulk actions
+const (
+	BulkActionTag    = "tag"
+	BulkActionUntag  = "untag"
+	BulkActionMove   = "move"
+	BulkActionDelete = "delete"
+)
+
+// BulkRequest applies one action to many queries, tools or MCP servers
+type BulkRequest struct {
+	Action   string   `json:"action" binding:"required,oneof=tag untag move delete"`
+	IDs      []string `json:"ids" binding:"required,min=1,max=500,dive,uuid"`
+	Tags     []string `json:"tags" binding:"dive,min=1,max=50"`       // For tag and untag
+	FolderID *string  `json:"folder_id" binding:"omitempty,uuid|eq="` // For moving queries; "" moves them out of any folder
+}
+
+// BulkFailure is an item a bulk action could not be applied to
+type BulkFailure struct {
+	ID    string `json:"id"`
+	Error string `json:"error"`
+}
+
+// BulkResult reports the items a bulk action was and was not applied to
+type BulkResult struct {
+	Succeeded []string      `json:"succeeded"`
+	Failed    []BulkFailure `json:"failed"`
+}
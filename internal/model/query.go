@@ -6,6 +6,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/yourusername/dataweaver/pkg/sqlparser"
 	"gorm.io/gorm"
 )
 
@@ -45,6 +46,39 @@ func (p *JSONParameters) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, p)
 }
 
+// QueryLineage is the table and column lineage of a query's SQL template
+type QueryLineage sqlparser.Lineage
+
+// Value implements driver.Valuer interface
+func (l QueryLineage) Value() (driver.Value, error) {
+	return json.Marshal(l)
+}
+
+// Scan implements sql.Scanner interface
+func (l *QueryLineage) Scan(value interface{}) error {
+	if value == nil {
+		*l = QueryLineage{}
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("failed to scan QueryLineage")
+	}
+
+	if len(bytes) == 0 {
+		*l = QueryLineage{}
+		return nil
+	}
+
+	return json.Unmarshal(bytes, l)
+}
+
 // Query is the main Query model with UUID primary key
 type Query struct {
 	ID           string         `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
@@ -54,7 +88,10 @@ type Query struct {
 	DataSourceID string         `gorm:"type:uuid;not null" json:"data_source_id" binding:"required"`
 	SQLTemplate  string         `gorm:"type:text;not null" json:"sql_template" binding:"required"`
 	Parameters   JSONParameters `gorm:"type:jsonb" json:"parameters"`
+	Lineage      *QueryLineage  `gorm:"type:jsonb" json:"lineage,omitempty"` // Extracted from SQLTemplate on create and update
+	Revision     int            `gorm:"not null;default:1" json:"revision"`  // Number of the current QueryRevision
 	Status       string         `gorm:"size:20;default:'active'" json:"status"`
+	FolderID     *string        `gorm:"type:uuid;index" json:"folder_id,omitempty"` // Nil for queries in no folder
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
@@ -70,7 +107,10 @@ func (Query) TableName() string {
 // QueryParameter represents a parameter definition for a query
 type QueryParameter struct {
 	Name        string      `json:"name"`
-	Type        string      `json:"type"` // string, number, boolean, date
+	Type        string      `json:"type"`             // string, number, boolean, date, array
+	Items       string      `json:"items,omitempty"`  // Element type when Type is array
+	Format      string      `json:"format,omitempty"` // JSON Schema format, e.g. date-time or uuid
+	Source      string      `json:"source,omitempty"` // Column the type was inferred from, as schema.table.column
 	Required    bool        `json:"required"`
 	Default     interface{} `json:"default,omitempty"`
 	Description string      `json:"description"`
@@ -97,6 +137,7 @@ type CreateQueryRequest struct {
 	DataSourceID string           `json:"data_source_id" binding:"required,uuid"`
 	SQLTemplate  string           `json:"sql_template" binding:"required"`
 	Parameters   []QueryParameter `json:"parameters"`
+	FolderID     *string          `json:"folder_id" binding:"omitempty,uuid"`
 }
 
 // UpdateQueryRequest represents the request body for updating a query
@@ -107,6 +148,8 @@ type UpdateQueryRequest struct {
 	SQLTemplate  *string          `json:"sql_template"`
 	Parameters   []QueryParameter `json:"parameters"`
 	Status       *string          `json:"status" binding:"omitempty,oneof=active inactive"`
+	FolderID     *string          `json:"folder_id" binding:"omitempty,uuid|eq="` // "" moves the query out of its folder
+	ChangeNote   string           `json:"change_note" binding:"max=200"`          // Recorded on the revision this update creates
 }
 
 // QueryResponse represents the response body for a query
@@ -118,10 +161,18 @@ type QueryResponse struct {
 	DataSourceID string           `json:"data_source_id"`
 	SQLTemplate  string           `json:"sql_template"`
 	Parameters   []QueryParameter `json:"parameters"`
+	Lineage      *QueryLineage    `json:"lineage,omitempty"`
+	Revision     int              `json:"revision"`
 	Status       string           `json:"status"`
+	FolderID     *string          `json:"folder_id,omitempty"`
+	Tags         []string         `json:"tags"`
+	Favorite     bool             `json:"favorite"` // Whether the query is a favorite of the current user
 	CreatedAt    time.Time        `json:"created_at"`
 	UpdatedAt    time.Time        `json:"updated_at"`
 	DataSource   *DataSourceInfo  `json:"data_source,omitempty"`
+	// ParameterStyle is the parameter style detected in the submitted SQL
+	// template, set when a template is created or updated
+	ParameterStyle string `json:"parameter_style,omitempty"`
 }
 
 // DataSourceInfo represents minimal datasource info in query response
@@ -146,7 +197,11 @@ func (q *Query) ToResponse() *QueryResponse {
 		DataSourceID: q.DataSourceID,
 		SQLTemplate:  q.SQLTemplate,
 		Parameters:   params,
+		Lineage:      q.Lineage,
+		Revision:     q.Revision,
 		Status:       q.Status,
+		FolderID:     q.FolderID,
+		Tags:         []string{},
 		CreatedAt:    q.CreatedAt,
 		UpdatedAt:    q.UpdatedAt,
 	}
@@ -166,39 +221,102 @@ func (q *Query) ToResponse() *QueryResponse {
 // ExecuteQueryRequest represents the request body for executing a query
 type ExecuteQueryRequest struct {
 	Parameters map[string]interface{} `json:"parameters"`
+	UseCache   bool                   `json:"use_cache"` // Answer from the result cache when fresh, and cache the result
+}
+
+// ExportQueryRequest represents the request body for exporting query results
+type ExportQueryRequest struct {
+	Format     string                 `json:"format" binding:"required,oneof=csv xlsx parquet jsonl"`
+	Parameters map[string]interface{} `json:"parameters"`
+}
+
+// ResultColumn describes the database type of a result column
+type ResultColumn struct {
+	Name         string `json:"name"`
+	DatabaseType string `json:"database_type"`
+	Nullable     *bool  `json:"nullable,omitempty"`
+	Precision    *int64 `json:"precision,omitempty"`
+	Scale        *int64 `json:"scale,omitempty"`
+	Length       *int64 `json:"length,omitempty"`
 }
 
 // ExecuteQueryResponse represents the response of a query execution
 type ExecuteQueryResponse struct {
 	Columns         []string                 `json:"columns"`
+	ColumnTypes     []ResultColumn           `json:"column_types,omitempty"`
 	Data            []map[string]interface{} `json:"data"`
 	RowCount        int                      `json:"row_count"`
+	Truncated       bool                     `json:"truncated"`
 	ExecutionTimeMs int64                    `json:"execution_time_ms"`
+	Cached          bool                     `json:"cached,omitempty"`      // Answered from the result cache
+	CachedAt        *time.Time               `json:"cached_at,omitempty"`   // When the cached result was produced
+	ResultSets      []ResultSetResponse      `json:"result_sets,omitempty"` // Result sets after the first, from statements such as stored procedures that return several
+}
+
+// ResultSetResponse is one of several result sets returned by a statement
+type ResultSetResponse struct {
+	Columns     []string                 `json:"columns"`
+	ColumnTypes []ResultColumn           `json:"column_types,omitempty"`
+	Data        []map[string]interface{} `json:"data"`
+	RowCount    int                      `json:"row_count"`
+	Truncated   bool                     `json:"truncated"`
 }
 
 // ValidateSQLRequest represents the request body for SQL validation
 type ValidateSQLRequest struct {
-	SQLTemplate string `json:"sql_template" binding:"required"`
+	SQLTemplate  string           `json:"sql_template" binding:"required"`
+	DataSourceID string           `json:"data_source_id"` // Optional; enables schema-based parameter types and table size checks
+	Parameters   []QueryParameter `json:"parameters"`     // Optional declared parameters, checked against the template
+}
+
+// SQLPosition locates a validation problem in the SQL source
+type SQLPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Offset int `json:"offset"`
+}
+
+// LintFinding is a best-practice warning about a SQL template
+type LintFinding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"` // info, warning, error
+	Message  string `json:"message"`
+	Line     int    `json:"line,omitempty"`   // 1-based; omitted when the finding has no location
+	Column   int    `json:"column,omitempty"` // 1-based
+	Offset   int    `json:"offset"`
 }
 
 // ValidateSQLResponse represents the response of SQL validation
 type ValidateSQLResponse struct {
-	Valid      bool             `json:"valid"`
-	Message    string           `json:"message"`
-	Parameters []QueryParameter `json:"parameters,omitempty"`
+	Valid          bool             `json:"valid"`
+	Message        string           `json:"message"`
+	ParameterStyle string           `json:"parameter_style"`          // colon, at, dollar, question, mixed or none
+	NormalizedSQL  string           `json:"normalized_sql,omitempty"` // The template with :name parameters, if it was rewritten
+	Position       *SQLPosition     `json:"position,omitempty"`       // Where the validation failed, if known
+	Parameters     []QueryParameter `json:"parameters,omitempty"`
+	Findings       []LintFinding    `json:"findings"`
 }
 
-// QueryExecution represents a query execution history record
+// QueryExecution represents a query execution history record. Synchronous
+// executions and exports end as succeeded or failed; asynchronous jobs move
+// from queued through running to succeeded, failed or cancelled.
 type QueryExecution struct {
-	ID              string    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	UserID          uint      `gorm:"index;not null" json:"user_id"`
-	QueryID         string    `gorm:"type:uuid;not null;index" json:"query_id"`
-	Parameters      string    `gorm:"type:jsonb" json:"parameters"`
-	RowCount        int       `json:"row_count"`
-	ExecutionTimeMs int64     `json:"execution_time_ms"`
-	Status          string    `gorm:"size:20;not null" json:"status"` // success, error
-	ErrorMessage    string    `gorm:"type:text" json:"error_message,omitempty"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID              string     `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID          uint       `gorm:"index;not null" json:"user_id"`
+	QueryID         string     `gorm:"type:uuid;not null;index" json:"query_id"`
+	QueryRevision   int        `gorm:"not null;default:0" json:"query_revision,omitempty"` // Revision that ran, 0 if not recorded
+	Parameters      string     `gorm:"type:jsonb" json:"parameters"`
+	RowCount        int        `json:"row_count"`
+	Truncated       bool       `gorm:"not null;default:false" json:"truncated"`
+	ExecutionTimeMs int64      `json:"execution_time_ms"`
+	Status          string     `gorm:"size:20;not null;index" json:"status"`        // See ExecutionStatus
+	Format          string     `gorm:"size:10;not null;default:json" json:"format"` // json for API results, else the export format
+	ErrorMessage    string     `gorm:"type:text" json:"error_message,omitempty"`
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+	HeartbeatAt     *time.Time `json:"-"`                           // Last sign of life from the worker running a job
+	ResultExpiresAt *time.Time `json:"result_expires_at,omitempty"` // When a job's stored result is deleted
+	CreatedAt       time.Time  `json:"created_at"`
 
 	Query Query `gorm:"foreignKey:QueryID" json:"query,omitempty"`
 }
@@ -212,10 +330,48 @@ type QueryExecutionResponse struct {
 	ID              string                 `json:"id"`
 	QueryID         string                 `json:"query_id"`
 	QueryName       string                 `json:"query_name,omitempty"`
+	QueryRevision   int                    `json:"query_revision,omitempty"`
 	Parameters      map[string]interface{} `json:"parameters"`
 	RowCount        int                    `json:"row_count"`
 	ExecutionTimeMs int64                  `json:"execution_time_ms"`
 	Status          string                 `json:"status"`
+	Format          string                 `json:"format"`
+	Truncated       bool                   `json:"truncated"`
 	ErrorMessage    string                 `json:"error_message,omitempty"`
 	CreatedAt       time.Time              `json:"created_at"`
 }
+
+// ExecutionHistoryFilter narrows the execution history. Unset fields do not
+// filter; Search matches words in the parameters and error message.
+type ExecutionHistoryFilter struct {
+	QueryID       string     `form:"queryId" binding:"omitempty,uuid"`
+	DataSourceID  string     `form:"dataSourceId" binding:"omitempty,uuid"`
+	Status        []string   `form:"status" binding:"dive,oneof=queued running succeeded failed cancelled"`
+	From          *time.Time `form:"from"` // RFC 3339, inclusive
+	To            *time.Time `form:"to"`   // RFC 3339, exclusive
+	MinDurationMs *int64     `form:"minDurationMs" binding:"omitempty,min=0"`
+	MaxDurationMs *int64     `form:"maxDurationMs" binding:"omitempty,min=0"`
+	MinRows       *int       `form:"minRows" binding:"omitempty,min=0"`
+	MaxRows       *int       `form:"maxRows" binding:"omitempty,min=0"`
+	Search        string     `form:"search"`
+}
+
+// ParameterValueChange is a parameter whose value differs between two
+// executions; From or To is nil when the parameter was not given
+type ParameterValueChange struct {
+	Name string      `json:"name"`
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// ExecutionComparison compares two executions of the same or different queries
+type ExecutionComparison struct {
+	From                 QueryExecutionResponse `json:"from"`
+	To                   QueryExecutionResponse `json:"to"`
+	SameQuery            bool                   `json:"same_query"`
+	RevisionChanged      bool                   `json:"revision_changed"`
+	StatusChanged        bool                   `json:"status_changed"`
+	ParameterChanges     []ParameterValueChange `json:"parameter_changes"`
+	RowCountDelta        int                    `json:"row_count_delta"`         // To minus From
+	ExecutionTimeDeltaMs int64                  `json:"execution_time_delta_ms"` // To minus From
+}
@@ -0,0 +1,163 @@
+package model
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ExecutionStatus values of QueryExecution.Status. Synchronous executions,
+// exports, console statements and schedule snapshots end as succeeded or
+// failed; jobs also pass through queued and running and may be cancelled.
+const (
+	ExecutionStatusQueued    = "queued"
+	ExecutionStatusRunning   = "running"
+	ExecutionStatusSucceeded = "succeeded"
+	ExecutionStatusFailed    = "failed"
+	ExecutionStatusCancelled = "cancelled"
+)
+
+// IsJobFinished reports whether a job status is final
+func IsJobFinished(status string) bool {
+	switch status {
+	case ExecutionStatusSucceeded, ExecutionStatusFailed, ExecutionStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// ResultColumns is a custom type for storing result column types in the database
+type ResultColumns []ResultColumn
+
+// Value implements driver.Valuer interface
+func (c ResultColumns) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+	return json.Marshal(c)
+}
+
+// Scan implements sql.Scanner interface
+func (c *ResultColumns) Scan(value interface{}) error {
+	if value == nil {
+		*c = nil
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("failed to scan ResultColumns")
+	}
+
+	if len(bytes) == 0 {
+		*c = nil
+		return nil
+	}
+
+	return json.Unmarshal(bytes, c)
+}
+
+// ResultRows is a custom type for storing result rows in the database.
+// Numbers are read back as json.Number so decimals stay exact.
+type ResultRows []map[string]interface{}
+
+// Value implements driver.Valuer interface
+func (r ResultRows) Value() (driver.Value, error) {
+	if r == nil {
+		return nil, nil
+	}
+	return json.Marshal(r)
+}
+
+// Scan implements sql.Scanner interface
+func (r *ResultRows) Scan(value interface{}) error {
+	if value == nil {
+		*r = nil
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("failed to scan ResultRows")
+	}
+
+	if len(data) == 0 {
+		*r = nil
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(r)
+}
+
+// QueryResult is the stored result of a query job, kept until it expires.
+// The rows are stored in QueryResultChunks.
+type QueryResult struct {
+	ExecutionID string        `gorm:"type:uuid;primary_key" json:"execution_id"`
+	Columns     StringArray   `gorm:"type:jsonb" json:"columns"`
+	ColumnTypes ResultColumns `gorm:"type:jsonb" json:"column_types"`
+	RowCount    int           `json:"row_count"`
+	ChunkSize   int           `gorm:"not null" json:"chunk_size"` // Rows per chunk; the last chunk may hold fewer
+	ExpiresAt   time.Time     `gorm:"index;not null" json:"expires_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+func (QueryResult) TableName() string {
+	return "query_results"
+}
+
+// QueryResultChunk is a consecutive run of rows of a stored result
+type QueryResultChunk struct {
+	ExecutionID string     `gorm:"type:uuid;primaryKey" json:"execution_id"`
+	Chunk       int        `gorm:"primaryKey;autoIncrement:false" json:"chunk"` // 0-based
+	Rows        ResultRows `gorm:"type:jsonb" json:"rows"`
+}
+
+func (QueryResultChunk) TableName() string {
+	return "query_result_chunks"
+}
+
+// QueryJobResponse represents an asynchronous query job
+type QueryJobResponse struct {
+	ID              string                 `json:"id"`
+	QueryID         string                 `json:"query_id"`
+	QueryName       string                 `json:"query_name,omitempty"`
+	QueryRevision   int                    `json:"query_revision,omitempty"`
+	Parameters      map[string]interface{} `json:"parameters"`
+	Status          string                 `json:"status"` // queued, running, succeeded, failed, cancelled
+	RowCount        int                    `json:"row_count"`
+	Truncated       bool                   `json:"truncated"`
+	ExecutionTimeMs int64                  `json:"execution_time_ms"`
+	ErrorMessage    string                 `json:"error_message,omitempty"`
+	CreatedAt       time.Time              `json:"created_at"`
+	StartedAt       *time.Time             `json:"started_at,omitempty"`
+	FinishedAt      *time.Time             `json:"finished_at,omitempty"`
+	ResultExpiresAt *time.Time             `json:"result_expires_at,omitempty"`
+}
+
+// QueryJobResults is one page of the result of a succeeded job
+type QueryJobResults struct {
+	JobID       string                   `json:"job_id"`
+	Columns     []string                 `json:"columns"`
+	ColumnTypes []ResultColumn           `json:"column_types,omitempty"`
+	Data        []map[string]interface{} `json:"data"`
+	Total       int                      `json:"total"` // Rows in the whole result
+	Page        int                      `json:"page"`
+	Size        int                      `json:"size"`
+	Truncated   bool                     `json:"truncated"` // Whether the job stopped at the row or size limit
+	ExpiresAt   time.Time                `json:"expires_at"`
+}
@@ -0,0 +1,127 @@
+package model
+
+import "time"
+
+// QueryRevision is a snapshot of a query's definition, written on every change
+type QueryRevision struct {
+	ID           string         `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	QueryID      string         `gorm:"type:uuid;not null;uniqueIndex:idx_query_revision" json:"query_id"`
+	Revision     int            `gorm:"not null;uniqueIndex:idx_query_revision" json:"revision"`
+	UserID       uint           `gorm:"index;not null" json:"user_id"`
+	Name         string         `gorm:"size:100;not null" json:"name"`
+	Description  string         `gorm:"size:500" json:"description"`
+	DataSourceID string         `gorm:"type:uuid;not null" json:"data_source_id"`
+	SQLTemplate  string         `gorm:"type:text;not null" json:"sql_template"`
+	Parameters   JSONParameters `gorm:"type:jsonb" json:"parameters"`
+	Lineage      *QueryLineage  `gorm:"type:jsonb" json:"lineage,omitempty"`
+	ChangeNote   string         `gorm:"size:200" json:"change_note"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+func (QueryRevision) TableName() string {
+	return "query_revisions"
+}
+
+// NewQueryRevision snapshots the current definition of a query
+func NewQueryRevision(q *Query, note string) *QueryRevision {
+	return &QueryRevision{
+		QueryID:      q.ID,
+		Revision:     q.Revision,
+		UserID:       q.UserID,
+		Name:         q.Name,
+		Description:  q.Description,
+		DataSourceID: q.DataSourceID,
+		SQLTemplate:  q.SQLTemplate,
+		Parameters:   q.Parameters,
+		Lineage:      q.Lineage,
+		ChangeNote:   note,
+	}
+}
+
+// Apply returns a copy of the query with the definition of the revision
+func (r *QueryRevision) Apply(q *Query) *Query {
+	pinned := *q
+	pinned.Revision = r.Revision
+	pinned.Name = r.Name
+	pinned.Description = r.Description
+	pinned.DataSourceID = r.DataSourceID
+	pinned.SQLTemplate = r.SQLTemplate
+	pinned.Parameters = r.Parameters
+	pinned.Lineage = r.Lineage
+	if pinned.DataSource.ID != r.DataSourceID {
+		pinned.DataSource = DataSource{}
+	}
+	return &pinned
+}
+
+// QueryRevisionResponse represents a query revision in API responses
+type QueryRevisionResponse struct {
+	ID           string           `json:"id"`
+	QueryID      string           `json:"query_id"`
+	Revision     int              `json:"revision"`
+	Current      bool             `json:"current"` // Whether this is the query's current definition
+	UserID       uint             `json:"user_id"`
+	Name         string           `json:"name"`
+	Description  string           `json:"description"`
+	DataSourceID string           `json:"data_source_id"`
+	SQLTemplate  string           `json:"sql_template"`
+	Parameters   []QueryParameter `json:"parameters"`
+	Lineage      *QueryLineage    `json:"lineage,omitempty"`
+	ChangeNote   string           `json:"change_note"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
+
+// ToResponse converts QueryRevision to QueryRevisionResponse
+func (r *QueryRevision) ToResponse(currentRevision int) *QueryRevisionResponse {
+	params := []QueryParameter(r.Parameters)
+	if params == nil {
+		params = []QueryParameter{}
+	}
+	return &QueryRevisionResponse{
+		ID:           r.ID,
+		QueryID:      r.QueryID,
+		Revision:     r.Revision,
+		Current:      r.Revision == currentRevision,
+		UserID:       r.UserID,
+		Name:         r.Name,
+		Description:  r.Description,
+		DataSourceID: r.DataSourceID,
+		SQLTemplate:  r.SQLTemplate,
+		Parameters:   params,
+		Lineage:      r.Lineage,
+		ChangeNote:   r.ChangeNote,
+		CreatedAt:    r.CreatedAt,
+	}
+}
+
+// DiffLine is one line of a line-by-line diff
+type DiffLine struct {
+	Op   string `json:"op"` // " " unchanged, "-" removed, "+" added
+	Text string `json:"text"`
+}
+
+// FieldChange is a changed scalar field between two revisions
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// ParameterChange is a parameter added, removed or changed between two revisions
+type ParameterChange struct {
+	Name   string          `json:"name"`
+	Change string          `json:"change"` // added, removed, changed
+	From   *QueryParameter `json:"from,omitempty"`
+	To     *QueryParameter `json:"to,omitempty"`
+}
+
+// QueryRevisionDiff compares two revisions of a query
+type QueryRevisionDiff struct {
+	QueryID      string            `json:"query_id"`
+	FromRevision int               `json:"from_revision"`
+	ToRevision   int               `json:"to_revision"`
+	Fields       []FieldChange     `json:"fields"`
+	SQL          []DiffLine        `json:"sql"`
+	SQLChanged   bool              `json:"sql_changed"`
+	Parameters   []ParameterChange `json:"parameters"`
+}
@@ -0,0 +1,217 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ScheduleParameters is a custom type for storing the fixed parameters of a schedule
+type ScheduleParameters map[string]interface{}
+
+// Value implements driver.Valuer interface
+func (p ScheduleParameters) Value() (driver.Value, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return json.Marshal(p)
+}
+
+// Scan implements sql.Scanner interface
+func (p *ScheduleParameters) Scan(value interface{}) error {
+	if value == nil {
+		*p = nil
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("failed to scan ScheduleParameters")
+	}
+
+	if len(bytes) == 0 {
+		*p = nil
+		return nil
+	}
+
+	return json.Unmarshal(bytes, p)
+}
+
+// QuerySchedule runs a query with fixed parameters on a cron schedule and
+// keeps a snapshot of each result
+type QuerySchedule struct {
+	ID              string             `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID          uint               `gorm:"index;not null" json:"user_id"`
+	QueryID         string             `gorm:"type:uuid;index;not null" json:"query_id"`
+	Name            string             `gorm:"size:100;not null" json:"name"`
+	Cron            string             `gorm:"size:100;not null" json:"cron"`                  // Standard 5-field expression or descriptor such as @hourly
+	Timezone        string             `gorm:"size:64;not null;default:'UTC'" json:"timezone"` // IANA zone the cron expression is read in
+	Parameters      ScheduleParameters `gorm:"type:jsonb" json:"parameters"`
+	KeyColumns      StringArray        `gorm:"type:jsonb" json:"key_columns"`              // Columns identifying a row when diffing snapshots
+	RetainSnapshots int                `gorm:"not null;default:0" json:"retain_snapshots"` // Snapshots kept; 0 uses the configured default
+	Enabled         bool               `gorm:"not null" json:"enabled"`
+	NextRunAt       *time.Time         `gorm:"index" json:"next_run_at,omitempty"`
+	LastRunAt       *time.Time         `json:"last_run_at,omitempty"`
+	LastStatus      string             `gorm:"size:20" json:"last_status,omitempty"`
+	LastError       string             `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt     `gorm:"index" json:"-"`
+
+	Query Query `gorm:"foreignKey:QueryID" json:"-"`
+}
+
+func (QuerySchedule) TableName() string {
+	return "query_schedules"
+}
+
+// QuerySnapshot is the result of one run of a schedule, with how it differs
+// from the previous successful run
+type QuerySnapshot struct {
+	ID              string        `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ScheduleID      string        `gorm:"type:uuid;index;not null" json:"schedule_id"`
+	QueryID         string        `gorm:"type:uuid;not null" json:"query_id"`
+	QueryRevision   int           `gorm:"not null;default:0" json:"query_revision"`
+	Status          string        `gorm:"size:20;not null" json:"status"` // ExecutionStatusSucceeded or ExecutionStatusFailed
+	ErrorMessage    string        `gorm:"type:text" json:"error_message,omitempty"`
+	Columns         StringArray   `gorm:"type:jsonb" json:"columns"`
+	ColumnTypes     ResultColumns `gorm:"type:jsonb" json:"column_types"`
+	Rows            ResultRows    `gorm:"type:jsonb" json:"-"`
+	RowCount        int           `json:"row_count"`
+	Truncated       bool          `json:"truncated"`
+	PreviousID      *string       `gorm:"type:uuid" json:"previous_id,omitempty"` // Snapshot this one was diffed against
+	Added           int           `json:"added"`
+	Removed         int           `json:"removed"`
+	Changed         int           `json:"changed"`
+	ExecutionTimeMs int64         `json:"execution_time_ms"`
+	CreatedAt       time.Time     `gorm:"index" json:"created_at"`
+}
+
+func (QuerySnapshot) TableName() string {
+	return "query_snapshots"
+}
+
+// HasChanges reports whether the snapshot differs from the previous one
+func (s *QuerySnapshot) HasChanges() bool {
+	return s.Added > 0 || s.Removed > 0 || s.Changed > 0
+}
+
+// SchedulerLease elects the server instance that runs schedules. The holder
+// renews it while alive; another instance takes over once it expires.
+type SchedulerLease struct {
+	Name      string    `gorm:"size:50;primary_key" json:"name"`
+	Holder    string    `gorm:"size:200;not null" json:"holder"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+}
+
+func (SchedulerLease) TableName() string {
+	return "scheduler_leases"
+}
+
+// CreateQueryScheduleRequest represents the request body for creating a schedule
+type CreateQueryScheduleRequest struct {
+	QueryID         string                 `json:"query_id" binding:"required,uuid"`
+	Name            string                 `json:"name" binding:"required,min=1,max=100"`
+	Cron            string                 `json:"cron" binding:"required,max=100"`
+	Timezone        string                 `json:"timezone" binding:"max=64"` // Defaults to UTC
+	Parameters      map[string]interface{} `json:"parameters"`
+	KeyColumns      []string               `json:"key_columns"`
+	RetainSnapshots int                    `json:"retain_snapshots" binding:"min=0"`
+	Enabled         *bool                  `json:"enabled"` // Defaults to true
+}
+
+// UpdateQueryScheduleRequest represents the request body for updating a schedule
+type UpdateQueryScheduleRequest struct {
+	Name            *string                `json:"name" binding:"omitempty,min=1,max=100"`
+	Cron            *string                `json:"cron" binding:"omitempty,max=100"`
+	Timezone        *string                `json:"timezone" binding:"omitempty,max=64"`
+	Parameters      map[string]interface{} `json:"parameters"`
+	KeyColumns      []string               `json:"key_columns"`
+	RetainSnapshots *int                   `json:"retain_snapshots" binding:"omitempty,min=0"`
+	Enabled         *bool                  `json:"enabled"`
+}
+
+// QueryScheduleResponse represents the response body for a schedule
+type QueryScheduleResponse struct {
+	ID              string                 `json:"id"`
+	QueryID         string                 `json:"query_id"`
+	QueryName       string                 `json:"query_name,omitempty"`
+	Name            string                 `json:"name"`
+	Cron            string                 `json:"cron"`
+	Timezone        string                 `json:"timezone"`
+	Parameters      map[string]interface{} `json:"parameters"`
+	KeyColumns      []string               `json:"key_columns"`
+	RetainSnapshots int                    `json:"retain_snapshots"`
+	Enabled         bool                   `json:"enabled"`
+	NextRunAt       *time.Time             `json:"next_run_at,omitempty"`
+	LastRunAt       *time.Time             `json:"last_run_at,omitempty"`
+	LastStatus      string                 `json:"last_status,omitempty"`
+	LastError       string                 `json:"last_error,omitempty"`
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+}
+
+// ToResponse converts QuerySchedule to QueryScheduleResponse
+func (s *QuerySchedule) ToResponse() *QueryScheduleResponse {
+	params := map[string]interface{}(s.Parameters)
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	keys := []string(s.KeyColumns)
+	if keys == nil {
+		keys = []string{}
+	}
+
+	return &QueryScheduleResponse{
+		ID:              s.ID,
+		QueryID:         s.QueryID,
+		QueryName:       s.Query.Name,
+		Name:            s.Name,
+		Cron:            s.Cron,
+		Timezone:        s.Timezone,
+		Parameters:      params,
+		KeyColumns:      keys,
+		RetainSnapshots: s.RetainSnapshots,
+		Enabled:         s.Enabled,
+		NextRunAt:       s.NextRunAt,
+		LastRunAt:       s.LastRunAt,
+		LastStatus:      s.LastStatus,
+		LastError:       s.LastError,
+		CreatedAt:       s.CreatedAt,
+		UpdatedAt:       s.UpdatedAt,
+	}
+}
+
+// QuerySnapshotResponse represents a snapshot; Data is only filled in when a
+// single snapshot is requested
+type QuerySnapshotResponse struct {
+	QuerySnapshot
+	Data []map[string]interface{} `json:"data,omitempty"`
+}
+
+// SnapshotRowChange is a row whose key is in both snapshots but whose values differ
+type SnapshotRowChange struct {
+	Key     map[string]interface{} `json:"key"`
+	Columns []string               `json:"columns"` // Columns whose values differ
+	Before  map[string]interface{} `json:"before"`
+	After   map[string]interface{} `json:"after"`
+}
+
+// SnapshotDiff lists the rows added, removed and changed between two snapshots
+type SnapshotDiff struct {
+	FromSnapshotID string                   `json:"from_snapshot_id,omitempty"`
+	ToSnapshotID   string                   `json:"to_snapshot_id"`
+	KeyColumns     []string                 `json:"key_columns"` // Empty when rows are compared whole
+	Added          []map[string]interface{} `json:"added"`
+	Removed        []map[string]interface{} `json:"removed"`
+	Changed        []SnapshotRowChange      `json:"changed"`
+	Truncated      bool                     `json:"truncated"` // Whether either snapshot was truncated, leaving rows uncompared
+}
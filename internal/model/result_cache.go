@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// ResultCacheEntry is a cached query result in the shared cache tier, which
+// every server instance reads through its own memory tier
+type ResultCacheEntry struct {
+	Key       string    `gorm:"size:64;primary_key" json:"key"` // Hash of the query revision, parameters and row limits
+	QueryID   string    `gorm:"type:uuid;index;not null" json:"query_id"`
+	Value     []byte    `gorm:"type:bytea;not null" json:"-"`
+	ExpiresAt time.Time `gorm:"index;not null" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (ResultCacheEntry) TableName() string {
+	return "result_cache_entries"
+}
+
+// ResultCachePurge records that the cached results of a query were purged,
+// so that other instances drop them from their memory tier too
+type ResultCachePurge struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	QueryID   string    `gorm:"type:uuid;not null" json:"query_id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+func (ResultCachePurge) TableName() string {
+	return "result_cache_purges"
+}
+
+// CachePurgeResponse reports how many cached results a purge removed
+type CachePurgeResponse struct {
+	Queries int   `json:"queries"` // Queries whose results were purged
+	Memory  int   `json:"memory"`  // Entries removed from this instance's memory tier
+	Shared  int64 `json:"shared"`  // Entries removed from the shared tier
+}
@@ -4,8 +4,11 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/yourusername/dataweaver/pkg/sqlparser"
 	"gorm.io/gorm"
 )
 
@@ -81,22 +84,92 @@ func (s *OutputSchema) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, s)
 }
 
+// Cost guard modes
+const (
+	CostGuardOff    = "off"
+	CostGuardWarn   = "warn"
+	CostGuardReject = "reject"
+)
+
+// CostGuard sets thresholds on the optimizer's estimates for a tool's query.
+// Before the query runs, its plan is explained; a query over a threshold is
+// refused in reject mode and runs with a warning in warn mode. A zero
+// threshold is not checked.
+type CostGuard struct {
+	Mode             string  `json:"mode" binding:"omitempty,oneof=off warn reject"`
+	MaxEstimatedCost float64 `json:"max_estimated_cost" binding:"min=0"`
+	MaxEstimatedRows float64 `json:"max_estimated_rows" binding:"min=0"`
+}
+
+// Enabled reports whether the guard needs a query plan
+func (g CostGuard) Enabled() bool {
+	return (g.Mode == CostGuardWarn || g.Mode == CostGuardReject) &&
+		(g.MaxEstimatedCost > 0 || g.MaxEstimatedRows > 0)
+}
+
+// Check returns why the estimates exceed the thresholds, or "" if they don't
+func (g CostGuard) Check(cost, rows float64) string {
+	var reasons []string
+	if g.MaxEstimatedCost > 0 && cost > g.MaxEstimatedCost {
+		reasons = append(reasons, fmt.Sprintf("estimated cost %.0f exceeds the limit of %.0f", cost, g.MaxEstimatedCost))
+	}
+	if g.MaxEstimatedRows > 0 && rows > g.MaxEstimatedRows {
+		reasons = append(reasons, fmt.Sprintf("estimated %.0f rows scanned exceeds the limit of %.0f", rows, g.MaxEstimatedRows))
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// Value implements driver.Valuer interface
+func (g CostGuard) Value() (driver.Value, error) {
+	return json.Marshal(g)
+}
+
+// Scan implements sql.Scanner interface
+func (g *CostGuard) Scan(value interface{}) error {
+	if value == nil {
+		*g = CostGuard{}
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("failed to scan CostGuard")
+	}
+
+	if len(bytes) == 0 {
+		*g = CostGuard{}
+		return nil
+	}
+
+	return json.Unmarshal(bytes, g)
+}
+
 // Tool is the main Tool model with UUID primary key
 type Tool struct {
-	ID           string         `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	UserID       uint           `gorm:"index;not null" json:"user_id"`
-	Name         string         `gorm:"size:100;not null" json:"name" binding:"required"`
-	DisplayName  string         `gorm:"size:200;not null" json:"display_name" binding:"required"`
-	Description  string         `gorm:"type:text;not null" json:"description"`
-	QueryID      string         `gorm:"type:uuid;not null" json:"query_id"`
-	Parameters   ToolParameters `gorm:"type:jsonb" json:"parameters"`
-	OutputSchema OutputSchema   `gorm:"type:jsonb" json:"output_schema"`
-	Version      int            `gorm:"default:1" json:"version"`
-	McpServerID  *string        `gorm:"type:uuid" json:"mcp_server_id,omitempty"`
-	Status       string         `gorm:"size:20;default:'active'" json:"status"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	ID            string         `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID        uint           `gorm:"index;not null" json:"user_id"`
+	Name          string         `gorm:"size:100;not null" json:"name" binding:"required"`
+	DisplayName   string         `gorm:"size:200;not null" json:"display_name" binding:"required"`
+	Description   string         `gorm:"type:text;not null" json:"description"`
+	QueryID       string         `gorm:"type:uuid;not null" json:"query_id"`
+	QueryRevision *int           `json:"query_revision,omitempty"` // Pinned query revision; nil runs the current one
+	Parameters    ToolParameters `gorm:"type:jsonb" json:"parameters"`
+	OutputSchema  OutputSchema   `gorm:"type:jsonb" json:"output_schema"`
+	Version       int            `gorm:"default:1" json:"version"`
+	MaxRows       int            `gorm:"default:0" json:"max_rows"`  // Row cap for this tool; 0 uses the server or global limit
+	CacheTTL      int            `gorm:"default:0" json:"cache_ttl"` // Seconds results are cached on servers with caching; 0 uses the default, -1 never caches
+	CostGuard     CostGuard      `gorm:"type:jsonb" json:"cost_guard"`
+	ScheduleID    *string        `gorm:"type:uuid" json:"schedule_id,omitempty"` // Schedule whose latest snapshot answers MCP calls; nil runs the query
+	McpServerID   *string        `gorm:"type:uuid" json:"mcp_server_id,omitempty"`
+	Status        string         `gorm:"size:20;default:'active'" json:"status"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
 
 	Query Query `gorm:"foreignKey:QueryID" json:"query,omitempty"`
 }
@@ -105,10 +178,20 @@ func (Tool) TableName() string {
 	return "tools"
 }
 
+// PinnedRevision returns the pinned query revision, or 0 when the tool runs
+// the query's current revision
+func (t *Tool) PinnedRevision() int {
+	if t.QueryRevision == nil {
+		return 0
+	}
+	return *t.QueryRevision
+}
+
 // ToolParameter represents a parameter definition for a tool
 type ToolParameter struct {
 	Name        string      `json:"name"`
-	Type        string      `json:"type"` // string, number, boolean, date, integer
+	Type        string      `json:"type"`            // string, number, boolean, date, integer, array
+	Items       string      `json:"items,omitempty"` // Element type when Type is array
 	Required    bool        `json:"required"`
 	Default     interface{} `json:"default,omitempty"`
 	Description string      `json:"description"`
@@ -117,12 +200,17 @@ type ToolParameter struct {
 
 // CreateToolRequest represents the request body for creating a tool
 type CreateToolRequest struct {
-	Name         string                 `json:"name" binding:"required,min=1,max=100"`
-	DisplayName  string                 `json:"display_name" binding:"required,min=1,max=200"`
-	Description  string                 `json:"description" binding:"required"`
-	QueryID      string                 `json:"query_id" binding:"required,uuid"`
-	Parameters   []ToolParameter        `json:"parameters"`
-	OutputSchema map[string]interface{} `json:"output_schema"`
+	Name          string                 `json:"name" binding:"required,min=1,max=100"`
+	DisplayName   string                 `json:"display_name" binding:"required,min=1,max=200"`
+	Description   string                 `json:"description" binding:"required"`
+	QueryID       string                 `json:"query_id" binding:"required,uuid"`
+	QueryRevision *int                   `json:"query_revision" binding:"omitempty,min=1"` // Pin a query revision; omit to run the current one
+	Parameters    []ToolParameter        `json:"parameters"`
+	OutputSchema  map[string]interface{} `json:"output_schema"`
+	MaxRows       int                    `json:"max_rows" binding:"min=0"`
+	CacheTTL      int                    `json:"cache_ttl" binding:"min=-1"` // 0 uses the default cache TTL, -1 never caches
+	CostGuard     CostGuard              `json:"cost_guard"`
+	ScheduleID    *string                `json:"schedule_id" binding:"omitempty,uuid"` // Answer MCP calls from a snapshot of a schedule of the same query
 }
 
 // CreateToolFromQueryRequest represents the request body for creating a tool from a query
@@ -134,31 +222,43 @@ type CreateToolFromQueryRequest struct {
 
 // UpdateToolRequest represents the request body for updating a tool
 type UpdateToolRequest struct {
-	Name         *string                `json:"name" binding:"omitempty,min=1,max=100"`
-	DisplayName  *string                `json:"display_name" binding:"omitempty,min=1,max=200"`
-	Description  *string                `json:"description"`
-	QueryID      *string                `json:"query_id" binding:"omitempty,uuid"`
-	Parameters   []ToolParameter        `json:"parameters"`
-	OutputSchema map[string]interface{} `json:"output_schema"`
-	Status       *string                `json:"status" binding:"omitempty,oneof=active inactive"`
+	Name          *string                `json:"name" binding:"omitempty,min=1,max=100"`
+	DisplayName   *string                `json:"display_name" binding:"omitempty,min=1,max=200"`
+	Description   *string                `json:"description"`
+	QueryID       *string                `json:"query_id" binding:"omitempty,uuid"`
+	QueryRevision *int                   `json:"query_revision" binding:"omitempty,min=0"` // 0 unpins the query revision
+	Parameters    []ToolParameter        `json:"parameters"`
+	OutputSchema  map[string]interface{} `json:"output_schema"`
+	MaxRows       *int                   `json:"max_rows" binding:"omitempty,min=0"`
+	CacheTTL      *int                   `json:"cache_ttl" binding:"omitempty,min=-1"`
+	CostGuard     *CostGuard             `json:"cost_guard"`
+	ScheduleID    *string                `json:"schedule_id" binding:"omitempty,uuid|eq="` // "" runs the query for MCP calls again
+	Status        *string                `json:"status" binding:"omitempty,oneof=active inactive"`
 }
 
 // ToolResponse represents the response body for a tool
 type ToolResponse struct {
-	ID           string                 `json:"id"`
-	UserID       uint                   `json:"user_id"`
-	Name         string                 `json:"name"`
-	DisplayName  string                 `json:"display_name"`
-	Description  string                 `json:"description"`
-	QueryID      string                 `json:"query_id"`
-	Parameters   []ToolParameter        `json:"parameters"`
-	OutputSchema map[string]interface{} `json:"output_schema"`
-	Version      int                    `json:"version"`
-	McpServerID  *string                `json:"mcp_server_id,omitempty"`
-	Status       string                 `json:"status"`
-	CreatedAt    time.Time              `json:"created_at"`
-	UpdatedAt    time.Time              `json:"updated_at"`
-	Query        *QueryInfo             `json:"query,omitempty"`
+	ID            string                 `json:"id"`
+	UserID        uint                   `json:"user_id"`
+	Name          string                 `json:"name"`
+	DisplayName   string                 `json:"display_name"`
+	Description   string                 `json:"description"`
+	QueryID       string                 `json:"query_id"`
+	QueryRevision *int                   `json:"query_revision,omitempty"`
+	Parameters    []ToolParameter        `json:"parameters"`
+	OutputSchema  map[string]interface{} `json:"output_schema"`
+	Version       int                    `json:"version"`
+	MaxRows       int                    `json:"max_rows"`
+	CacheTTL      int                    `json:"cache_ttl"`
+	CostGuard     CostGuard              `json:"cost_guard"`
+	ScheduleID    *string                `json:"schedule_id,omitempty"`
+	McpServerID   *string                `json:"mcp_server_id,omitempty"`
+	Status        string                 `json:"status"`
+	Tags          []string               `json:"tags"`
+	Favorite      bool                   `json:"favorite"` // Whether the tool is a favorite of the current user
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+	Query         *QueryInfo             `json:"query,omitempty"`
 }
 
 // QueryInfo represents minimal query info in tool response
@@ -166,6 +266,7 @@ type QueryInfo struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	Revision    int    `json:"revision"` // Current revision of the query
 }
 
 // ToResponse converts Tool to ToolResponse
@@ -181,19 +282,25 @@ func (t *Tool) ToResponse() *ToolResponse {
 	}
 
 	resp := &ToolResponse{
-		ID:           t.ID,
-		UserID:       t.UserID,
-		Name:         t.Name,
-		DisplayName:  t.DisplayName,
-		Description:  t.Description,
-		QueryID:      t.QueryID,
-		Parameters:   params,
-		OutputSchema: outputSchema,
-		Version:      t.Version,
-		McpServerID:  t.McpServerID,
-		Status:       t.Status,
-		CreatedAt:    t.CreatedAt,
-		UpdatedAt:    t.UpdatedAt,
+		ID:            t.ID,
+		UserID:        t.UserID,
+		Name:          t.Name,
+		DisplayName:   t.DisplayName,
+		Description:   t.Description,
+		QueryID:       t.QueryID,
+		QueryRevision: t.QueryRevision,
+		Parameters:    params,
+		OutputSchema:  outputSchema,
+		Version:       t.Version,
+		MaxRows:       t.MaxRows,
+		CacheTTL:      t.CacheTTL,
+		CostGuard:     t.CostGuard,
+		ScheduleID:    t.ScheduleID,
+		McpServerID:   t.McpServerID,
+		Status:        t.Status,
+		Tags:          []string{},
+		CreatedAt:     t.CreatedAt,
+		UpdatedAt:     t.UpdatedAt,
 	}
 
 	// Include Query info if loaded
@@ -202,12 +309,34 @@ func (t *Tool) ToResponse() *ToolResponse {
 			ID:          t.Query.ID,
 			Name:        t.Query.Name,
 			Description: t.Query.Description,
+			Revision:    t.Query.Revision,
 		}
 	}
 
 	return resp
 }
 
+// ColumnUsageRequest selects a column for a lineage search; schema and
+// table narrow the search when given
+type ColumnUsageRequest struct {
+	DataSourceID string `form:"data_source_id"`
+	Schema       string `form:"schema"`
+	Table        string `form:"table"`
+	Column       string `form:"column" binding:"required"`
+}
+
+// ToolColumnUsage describes how a tool's query reads a column
+type ToolColumnUsage struct {
+	ToolID        string   `json:"tool_id"`
+	ToolName      string   `json:"tool_name"`
+	McpServerID   *string  `json:"mcp_server_id,omitempty"`
+	QueryID       string   `json:"query_id"`
+	QueryName     string   `json:"query_name"`
+	DataSourceID  string   `json:"data_source_id"`
+	ResultColumns []string `json:"result_columns"` // Result columns whose values come from the column
+	Parameters    []string `json:"parameters"`     // Parameters compared against the column
+}
+
 // TestToolRequest represents the request body for testing a tool
 type TestToolRequest struct {
 	Parameters map[string]interface{} `json:"parameters"`
@@ -219,8 +348,12 @@ type TestToolResponse struct {
 	Message         string                   `json:"message"`
 	ExecutionTimeMs int64                    `json:"execution_time_ms"`
 	RowCount        int                      `json:"row_count"`
+	Truncated       bool                     `json:"truncated"`
+	Warnings        []string                 `json:"warnings,omitempty"`
 	Data            []map[string]interface{} `json:"data,omitempty"`
 	Columns         []string                 `json:"columns,omitempty"`
+	ColumnTypes     []ResultColumn           `json:"column_types,omitempty"`
+	ResultSets      []ResultSetResponse      `json:"result_sets,omitempty"` // Result sets after the first
 }
 
 // MCPToolDefinition represents the MCP tool format for export
@@ -246,6 +379,14 @@ func (t *Tool) ToMCPDefinition() *MCPToolDefinition {
 			propDef["format"] = param.Format
 		}
 
+		if param.Type == "array" {
+			propDef["items"] = map[string]interface{}{
+				"type": convertToJSONSchemaType(param.Items),
+			}
+			propDef["minItems"] = 1
+			propDef["maxItems"] = sqlparser.MaxArrayParameterLength
+		}
+
 		if param.Default != nil {
 			propDef["default"] = param.Default
 		}
@@ -282,6 +423,8 @@ func convertToJSONSchemaType(internalType string) string {
 		return "integer"
 	case "boolean":
 		return "boolean"
+	case "array":
+		return "array"
 	case "date":
 		return "string" // with format: date
 	case "datetime":
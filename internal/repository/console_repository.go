@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/dataweaver/internal/model"
+	"gorm.io/gorm"
+)
+
+var ErrConsoleStatementNotFound = errors.New("console statement not found")
+
+// ConsoleRepository handles database operations for SQL console history
+type ConsoleRepository interface {
+	Create(stmt *model.ConsoleStatement) error
+	FindByIDAndUserID(id string, userID uint) (*model.ConsoleStatement, error)
+	FindBySession(userID uint, sessionID string, page, size int) ([]model.ConsoleStatement, int64, error)
+	SetQueryID(id, queryID string) error
+	DeleteSession(userID uint, sessionID string) (int64, error)
+	// DeleteBefore deletes up to limit statements run before cutoff
+	DeleteBefore(cutoff time.Time, limit int) (int64, error)
+}
+
+type consoleRepository struct {
+	db *gorm.DB
+}
+
+// NewConsoleRepository creates a new ConsoleRepository
+func NewConsoleRepository(db *gorm.DB) ConsoleRepository {
+	return &consoleRepository{db: db}
+}
+
+// Create records a console statement
+func (r *consoleRepository) Create(stmt *model.ConsoleStatement) error {
+	if err := r.db.Create(stmt).Error; err != nil {
+		return fmt.Errorf("failed to create console statement: %w", err)
+	}
+	return nil
+}
+
+// FindByIDAndUserID finds a console statement by ID and user ID
+func (r *consoleRepository) FindByIDAndUserID(id string, userID uint) (*model.ConsoleStatement, error) {
+	var stmt model.ConsoleStatement
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&stmt).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrConsoleStatementNotFound
+		}
+		return nil, fmt.Errorf("failed to find console statement: %w", err)
+	}
+	return &stmt, nil
+}
+
+// FindBySession returns the statements of a console session, newest first
+func (r *consoleRepository) FindBySession(userID uint, sessionID string, page, size int) ([]model.ConsoleStatement, int64, error) {
+	var stmts []model.ConsoleStatement
+	var total int64
+
+	offset := (page - 1) * size
+	query := r.db.Model(&model.ConsoleStatement{}).Where("user_id = ? AND session_id = ?", userID, sessionID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count console statements: %w", err)
+	}
+
+	if err := r.db.Where("user_id = ? AND session_id = ?", userID, sessionID).
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(size).
+		Find(&stmts).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to find console statements: %w", err)
+	}
+
+	return stmts, total, nil
+}
+
+// SetQueryID records the saved query a statement was promoted to
+func (r *consoleRepository) SetQueryID(id, queryID string) error {
+	if err := r.db.Model(&model.ConsoleStatement{}).Where("id = ?", id).Update("query_id", queryID).Error; err != nil {
+		return fmt.Errorf("failed to update console statement: %w", err)
+	}
+	return nil
+}
+
+// DeleteSession deletes the history of a console session
+func (r *consoleRepository) DeleteSession(userID uint, sessionID string) (int64, error) {
+	result := r.db.Where("user_id = ? AND session_id = ?", userID, sessionID).Delete(&model.ConsoleStatement{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete console session: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// DeleteBefore deletes up to limit statements created before cutoff
+func (r *consoleRepository) DeleteBefore(cutoff time.Time, limit int) (int64, error) {
+	ids := r.db.Model(&model.ConsoleStatement{}).
+		Select("id").
+		Where("created_at < ?", cutoff).
+		Limit(limit)
+
+	result := r.db.Where("id IN (?)", ids).Delete(&model.ConsoleStatement{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete console statements: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/dataweaver/internal/model"
+	"gorm.io/gorm"
+)
+
+var ErrFolderNotFound = errors.New("folder not found")
+
+// FolderRepository handles database operations for query folders
+type FolderRepository interface {
+	Create(folder *model.QueryFolder) error
+	FindAll(userID uint) ([]model.QueryFolder, error)
+	FindByIDAndUserID(id string, userID uint) (*model.QueryFolder, error)
+	Update(folder *model.QueryFolder) error
+	// Delete deletes a folder, moving its subfolders and queries to its parent
+	Delete(folder *model.QueryFolder) error
+	// CountQueries counts the queries directly in each of a user's folders
+	CountQueries(userID uint) (map[string]int64, error)
+	// MoveQuery moves a query into a folder, or out of any folder for nil
+	MoveQuery(queryID string, userID uint, folderID *string) error
+}
+
+type folderRepository struct {
+	db *gorm.DB
+}
+
+// NewFolderRepository creates a new FolderRepository
+func NewFolderRepository(db *gorm.DB) FolderRepository {
+	return &folderRepository{db: db}
+}
+
+// Create creates a new folder
+func (r *folderRepository) Create(folder *model.QueryFolder) error {
+	if err := r.db.Create(folder).Error; err != nil {
+		return fmt.Errorf("failed to create folder: %w", err)
+	}
+	return nil
+}
+
+// FindAll returns all folders of a user by name
+func (r *folderRepository) FindAll(userID uint) ([]model.QueryFolder, error) {
+	var folders []model.QueryFolder
+	if err := r.db.Where("user_id = ?", userID).Order("name").Find(&folders).Error; err != nil {
+		return nil, fmt.Errorf("failed to find folders: %w", err)
+	}
+	return folders, nil
+}
+
+// FindByIDAndUserID finds a folder by ID and user ID
+func (r *folderRepository) FindByIDAndUserID(id string, userID uint) (*model.QueryFolder, error) {
+	var folder model.QueryFolder
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&folder).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFolderNotFound
+		}
+		return nil, fmt.Errorf("failed to find folder: %w", err)
+	}
+	return &folder, nil
+}
+
+// Update updates a folder
+func (r *folderRepository) Update(folder *model.QueryFolder) error {
+	if err := r.db.Save(folder).Error; err != nil {
+		return fmt.Errorf("failed to update folder: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a folder, moving its contents up a level
+func (r *folderRepository) Delete(folder *model.QueryFolder) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.QueryFolder{}).
+			Where("parent_id = ? AND user_id = ?", folder.ID, folder.UserID).
+			Update("parent_id", folder.ParentID).Error; err != nil {
+			return fmt.Errorf("failed to move subfolders: %w", err)
+		}
+		if err := tx.Model(&model.Query{}).
+			Where("folder_id = ? AND user_id = ?", folder.ID, folder.UserID).
+			Update("folder_id", folder.ParentID).Error; err != nil {
+			return fmt.Errorf("failed to move queries: %w", err)
+		}
+		if err := tx.Delete(folder).Error; err != nil {
+			return fmt.Errorf("failed to delete folder: %w", err)
+		}
+		return nil
+	})
+}
+
+// CountQueries counts the queries directly in each folder
+func (r *folderRepository) CountQueries(userID uint) (map[string]int64, error) {
+	var rows []struct {
+		FolderID string
+		Count    int64
+	}
+	if err := r.db.Model(&model.Query{}).
+		Select("folder_id, COUNT(*) AS count").
+		Where("user_id = ? AND folder_id IS NOT NULL", userID).
+		Group("folder_id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count queries in folders: %w", err)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.FolderID] = row.Count
+	}
+	return counts, nil
+}
+
+// MoveQuery moves a query into a folder
+func (r *folderRepository) MoveQuery(queryID string, userID uint, folderID *string) error {
+	result := r.db.Model(&model.Query{}).
+		Where("id = ? AND user_id = ?", queryID, userID).
+		Update("folder_id", folderID)
+	if result.Error != nil {
+		return fmt.Errorf("failed to move query: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrQueryNotFound
+	}
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 var (
 	ErrMcpServerNotFound   = errors.New("mcp server not found")
 	ErrMcpServerNameExists = errors.New("mcp server name already exists")
+	ErrMcpReleaseNotFound  = errors.New("mcp server release not found")
 )
 
 // McpServerRepository handles database operations for MCP servers
@@ -24,7 +25,13 @@ type McpServerRepository interface {
 	FindByApiKey(apiKey string) (*model.McpServer, error)
 	Update(server *model.McpServer) error
 	Delete(id string, userID uint) error
-	Search(userID uint, keyword string, page, size int) ([]model.McpServer, int64, error)
+	List(userID uint, filter *model.ListFilter, page, size int) ([]model.McpServer, int64, error)
+
+	// Release operations
+	PublishRelease(server *model.McpServer, release *model.McpServerRelease) error
+	FindReleases(serverID string, page, size int) ([]model.McpServerRelease, int64, error)
+	FindRelease(serverID, releaseID string) (*model.McpServerRelease, error)
+	FindLatestRelease(serverID string) (*model.McpServerRelease, error)
 
 	// Log operations
 	CreateLog(log *model.McpLog) error
@@ -181,36 +188,91 @@ func (r *mcpServerRepository) Delete(id string, userID uint) error {
 	return nil
 }
 
-// Search searches MCP servers by keyword
-func (r *mcpServerRepository) Search(userID uint, keyword string, page, size int) ([]model.McpServer, int64, error) {
+// List returns a user's MCP servers matching a filter, with pagination
+func (r *mcpServerRepository) List(userID uint, filter *model.ListFilter, page, size int) ([]model.McpServer, int64, error) {
 	var servers []model.McpServer
 	var total int64
 
 	offset := (page - 1) * size
-	searchPattern := "%" + keyword + "%"
-
-	query := r.db.Model(&model.McpServer{}).
-		Where("user_id = ?", userID).
-		Where("name ILIKE ? OR description ILIKE ?", searchPattern, searchPattern)
+	scope := listFilter("mcp_servers", model.ResourceMcpServer, userID, filter, "name", "description")
 
 	// Count total records
-	if err := query.Count(&total).Error; err != nil {
+	if err := r.db.Model(&model.McpServer{}).Scopes(scope).Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count mcp servers: %w", err)
 	}
 
 	// Get paginated records
-	if err := r.db.Where("user_id = ?", userID).
-		Where("name ILIKE ? OR description ILIKE ?", searchPattern, searchPattern).
+	if err := r.db.Scopes(scope).
 		Order("created_at DESC").
 		Offset(offset).
 		Limit(size).
 		Find(&servers).Error; err != nil {
-		return nil, 0, fmt.Errorf("failed to search mcp servers: %w", err)
+		return nil, 0, fmt.Errorf("failed to list mcp servers: %w", err)
 	}
 
 	return servers, total, nil
 }
 
+// PublishRelease stores a release and makes it the server's active release
+func (r *mcpServerRepository) PublishRelease(server *model.McpServer, release *model.McpServerRelease) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(release).Error; err != nil {
+			return fmt.Errorf("failed to create mcp server release: %w", err)
+		}
+		server.ActiveReleaseID = &release.ID
+		if err := tx.Save(server).Error; err != nil {
+			return fmt.Errorf("failed to update mcp server: %w", err)
+		}
+		return nil
+	})
+}
+
+// FindReleases returns the releases of a server, newest first
+func (r *mcpServerRepository) FindReleases(serverID string, page, size int) ([]model.McpServerRelease, int64, error) {
+	var releases []model.McpServerRelease
+	var total int64
+
+	offset := (page - 1) * size
+
+	if err := r.db.Model(&model.McpServerRelease{}).Where("mcp_server_id = ?", serverID).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count mcp server releases: %w", err)
+	}
+
+	if err := r.db.Where("mcp_server_id = ?", serverID).
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(size).
+		Find(&releases).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to find mcp server releases: %w", err)
+	}
+
+	return releases, total, nil
+}
+
+// FindRelease finds a release of a server
+func (r *mcpServerRepository) FindRelease(serverID, releaseID string) (*model.McpServerRelease, error) {
+	var release model.McpServerRelease
+	if err := r.db.Where("id = ? AND mcp_server_id = ?", releaseID, serverID).First(&release).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMcpReleaseNotFound
+		}
+		return nil, fmt.Errorf("failed to find mcp server release: %w", err)
+	}
+	return &release, nil
+}
+
+// FindLatestRelease finds the most recently published release of a server
+func (r *mcpServerRepository) FindLatestRelease(serverID string) (*model.McpServerRelease, error) {
+	var release model.McpServerRelease
+	if err := r.db.Where("mcp_server_id = ?", serverID).Order("created_at DESC").First(&release).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMcpReleaseNotFound
+		}
+		return nil, fmt.Errorf("failed to find mcp server release: %w", err)
+	}
+	return &release, nil
+}
+
 // CreateLog creates a new MCP log entry
 func (r *mcpServerRepository) CreateLog(log *model.McpLog) error {
 	if err := r.db.Create(log).Error; err != nil {
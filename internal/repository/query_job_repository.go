@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/dataweaver/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrQueryJobNotFound    = errors.New("query job not found")
+	ErrQueryResultNotFound = errors.New("query result not found")
+)
+
+// QueryJobRepository handles database operations for asynchronous query jobs
+// and their stored results. Jobs are QueryExecution records, so every server
+// instance sharing the metadata database can claim them.
+type QueryJobRepository interface {
+	Create(job *model.QueryExecution) error
+	FindByIDAndUserID(id string, userID uint) (*model.QueryExecution, error)
+	CountQueued() (int64, error)
+	// ClaimNext marks the oldest queued job as running and returns it, or
+	// returns nil if no job is queued
+	ClaimNext() (*model.QueryExecution, error)
+	// Heartbeat records that a running job is alive and reports whether it
+	// is still running
+	Heartbeat(id string) (bool, error)
+	// Transition updates a job that is in the from status, and reports
+	// whether it was
+	Transition(id, from string, updates map[string]interface{}) (bool, error)
+	// FailStale fails running jobs that have not sent a heartbeat since before
+	FailStale(before time.Time, message string) (int64, error)
+
+	SaveResultChunk(chunk *model.QueryResultChunk) error
+	SaveResult(result *model.QueryResult) error
+	FindResult(executionID string) (*model.QueryResult, error)
+	FindResultChunks(executionID string, from, to int) ([]model.QueryResultChunk, error)
+	DeleteResult(executionID string) error
+	// DeleteExpiredResults deletes results that expired before now
+	DeleteExpiredResults(now time.Time) (int64, error)
+}
+
+type queryJobRepository struct {
+	db *gorm.DB
+}
+
+// NewQueryJobRepository creates a new QueryJobRepository
+func NewQueryJobRepository(db *gorm.DB) QueryJobRepository {
+	return &queryJobRepository{db: db}
+}
+
+// Create creates a queued job
+func (r *queryJobRepository) Create(job *model.QueryExecution) error {
+	if err := r.db.Create(job).Error; err != nil {
+		return fmt.Errorf("failed to create query job: %w", err)
+	}
+	return nil
+}
+
+// FindByIDAndUserID finds a job of a user, with its query
+func (r *queryJobRepository) FindByIDAndUserID(id string, userID uint) (*model.QueryExecution, error) {
+	var job model.QueryExecution
+	if err := r.db.Preload("Query").
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQueryJobNotFound
+		}
+		return nil, fmt.Errorf("failed to find query job: %w", err)
+	}
+	return &job, nil
+}
+
+// CountQueued counts jobs waiting for a worker
+func (r *queryJobRepository) CountQueued() (int64, error) {
+	var count int64
+	if err := r.db.Model(&model.QueryExecution{}).
+		Where("status = ?", model.ExecutionStatusQueued).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count queued jobs: %w", err)
+	}
+	return count, nil
+}
+
+// ClaimNext marks the oldest queued job as running. SKIP LOCKED lets workers
+// of several server instances claim jobs concurrently without blocking.
+func (r *queryJobRepository) ClaimNext() (*model.QueryExecution, error) {
+	var job model.QueryExecution
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", model.ExecutionStatusQueued).
+			Order("created_at").
+			First(&job).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		job.Status = model.ExecutionStatusRunning
+		job.StartedAt = &now
+		job.HeartbeatAt = &now
+		return tx.Model(&job).Updates(map[string]interface{}{
+			"status":       job.Status,
+			"started_at":   now,
+			"heartbeat_at": now,
+		}).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim query job: %w", err)
+	}
+	return &job, nil
+}
+
+// Heartbeat records that a running job is alive
+func (r *queryJobRepository) Heartbeat(id string) (bool, error) {
+	result := r.db.Model(&model.QueryExecution{}).
+		Where("id = ? AND status = ?", id, model.ExecutionStatusRunning).
+		Update("heartbeat_at", time.Now())
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update job heartbeat: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Transition updates a job that is in the from status
+func (r *queryJobRepository) Transition(id, from string, updates map[string]interface{}) (bool, error) {
+	result := r.db.Model(&model.QueryExecution{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(updates)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update query job: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FailStale fails running jobs whose worker stopped sending heartbeats
+func (r *queryJobRepository) FailStale(before time.Time, message string) (int64, error) {
+	result := r.db.Model(&model.QueryExecution{}).
+		Where("status = ? AND heartbeat_at < ?", model.ExecutionStatusRunning, before).
+		Updates(map[string]interface{}{
+			"status":        model.ExecutionStatusFailed,
+			"error_message": message,
+			"finished_at":   time.Now(),
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to fail stale jobs: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// SaveResultChunk stores a chunk of result rows
+func (r *queryJobRepository) SaveResultChunk(chunk *model.QueryResultChunk) error {
+	if err := r.db.Create(chunk).Error; err != nil {
+		return fmt.Errorf("failed to save result chunk: %w", err)
+	}
+	return nil
+}
+
+// SaveResult stores the header of a result once all its chunks are saved
+func (r *queryJobRepository) SaveResult(result *model.QueryResult) error {
+	if err := r.db.Create(result).Error; err != nil {
+		return fmt.Errorf("failed to save query result: %w", err)
+	}
+	return nil
+}
+
+// FindResult finds the stored result of a job
+func (r *queryJobRepository) FindResult(executionID string) (*model.QueryResult, error) {
+	var result model.QueryResult
+	if err := r.db.Where("execution_id = ?", executionID).First(&result).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQueryResultNotFound
+		}
+		return nil, fmt.Errorf("failed to find query result: %w", err)
+	}
+	return &result, nil
+}
+
+// FindResultChunks returns the chunks from..to (inclusive) of a result, in order
+func (r *queryJobRepository) FindResultChunks(executionID string, from, to int) ([]model.QueryResultChunk, error) {
+	var chunks []model.QueryResultChunk
+	if err := r.db.Where("execution_id = ? AND chunk BETWEEN ? AND ?", executionID, from, to).
+		Order("chunk").
+		Find(&chunks).Error; err != nil {
+		return nil, fmt.Errorf("failed to find result chunks: %w", err)
+	}
+	return chunks, nil
+}
+
+// DeleteResult deletes the stored result of a job, including partial results
+func (r *queryJobRepository) DeleteResult(executionID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("execution_id = ?", executionID).Delete(&model.QueryResultChunk{}).Error; err != nil {
+			return fmt.Errorf("failed to delete result chunks: %w", err)
+		}
+		if err := tx.Where("execution_id = ?", executionID).Delete(&model.QueryResult{}).Error; err != nil {
+			return fmt.Errorf("failed to delete query result: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteExpiredResults deletes results that expired before now, and the
+// partial results of failed and cancelled jobs
+func (r *queryJobRepository) DeleteExpiredResults(now time.Time) (int64, error) {
+	var deleted int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		expired := tx.Model(&model.QueryResult{}).Select("execution_id").Where("expires_at < ?", now)
+		if err := tx.Where("execution_id IN (?)", expired).Delete(&model.QueryResultChunk{}).Error; err != nil {
+			return fmt.Errorf("failed to delete expired result chunks: %w", err)
+		}
+		result := tx.Where("expires_at < ?", now).Delete(&model.QueryResult{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete expired results: %w", result.Error)
+		}
+		deleted = result.RowsAffected
+
+		// Partial results of jobs whose worker died before cleaning up
+		stored := tx.Model(&model.QueryResult{}).Select("execution_id")
+		ended := tx.Model(&model.QueryExecution{}).Select("id").
+			Where("status IN ?", []string{model.ExecutionStatusFailed, model.ExecutionStatusCancelled})
+		if err := tx.Where("execution_id NOT IN (?) AND execution_id IN (?)", stored, ended).
+			Delete(&model.QueryResultChunk{}).Error; err != nil {
+			return fmt.Errorf("failed to delete orphaned result chunks: %w", err)
+		}
+		return nil
+	})
+	return deleted, err
+}
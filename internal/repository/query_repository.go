@@ -3,13 +3,17 @@ package repository
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/yourusername/dataweaver/internal/model"
 	"gorm.io/gorm"
 )
 
 var (
-	ErrQueryNotFound = errors.New("query not found")
+	ErrQueryNotFound         = errors.New("query not found")
+	ErrQueryRevisionNotFound = errors.New("query revision not found")
+	ErrExecutionNotFound     = errors.New("execution not found")
 )
 
 // QueryRepository handles database operations for queries
@@ -21,13 +25,19 @@ type QueryRepository interface {
 	FindByIDWithDataSource(id string, userID uint) (*model.Query, error)
 	Update(q *model.Query) error
 	Delete(id string, userID uint) error
-	Search(userID uint, keyword string, page, size int) ([]model.Query, int64, error)
+	List(userID uint, filter *model.ListFilter, page, size int) ([]model.Query, int64, error)
 	FindByDataSourceID(dataSourceID string) ([]model.Query, error)
 	CountByDataSourceID(dataSourceID string) (int64, error)
+	// Revision history
+	CreateWithRevision(q *model.Query, note string) error
+	UpdateWithRevision(q *model.Query, baseline *model.QueryRevision, note string) error
+	FindRevisions(queryID string, page, size int) ([]model.QueryRevision, int64, error)
+	FindRevision(queryID string, revision int) (*model.QueryRevision, error)
 	// Execution history
 	CreateExecution(exec *model.QueryExecution) error
-	FindExecutionsByQueryID(queryID string, userID uint, page, size int) ([]model.QueryExecution, int64, error)
-	FindExecutionsByUserID(userID uint, page, size int) ([]model.QueryExecution, int64, error)
+	FindExecutions(userID uint, filter *model.ExecutionHistoryFilter, page, size int) ([]model.QueryExecution, int64, error)
+	FindExecution(id string, userID uint) (*model.QueryExecution, error)
+	DeleteExecutionsBefore(cutoff time.Time, limit int) (int64, error)
 }
 
 type queryRepository struct {
@@ -134,37 +144,55 @@ func (r *queryRepository) Delete(id string, userID uint) error {
 	return nil
 }
 
-// Search searches queries by keyword (name or description)
-func (r *queryRepository) Search(userID uint, keyword string, page, size int) ([]model.Query, int64, error) {
+// List returns a user's queries matching a filter, with pagination
+func (r *queryRepository) List(userID uint, filter *model.ListFilter, page, size int) ([]model.Query, int64, error) {
 	var queries []model.Query
 	var total int64
 
 	offset := (page - 1) * size
-	searchPattern := "%" + keyword + "%"
-
-	query := r.db.Model(&model.Query{}).
-		Where("user_id = ?", userID).
-		Where("name ILIKE ? OR description ILIKE ?", searchPattern, searchPattern)
+	scopes := []func(*gorm.DB) *gorm.DB{
+		listFilter("queries", model.ResourceQuery, userID, filter, "name", "description"),
+		folderFilter(filter),
+	}
 
 	// Count total records
-	if err := query.Count(&total).Error; err != nil {
+	if err := r.db.Model(&model.Query{}).Scopes(scopes...).Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count queries: %w", err)
 	}
 
 	// Get paginated records with DataSource preloaded
 	if err := r.db.Preload("DataSource").
-		Where("user_id = ?", userID).
-		Where("name ILIKE ? OR description ILIKE ?", searchPattern, searchPattern).
+		Scopes(scopes...).
 		Order("created_at DESC").
 		Offset(offset).
 		Limit(size).
 		Find(&queries).Error; err != nil {
-		return nil, 0, fmt.Errorf("failed to search queries: %w", err)
+		return nil, 0, fmt.Errorf("failed to list queries: %w", err)
 	}
 
 	return queries, total, nil
 }
 
+// folderFilter scopes queries to the folder of a filter, and with Recursive
+// to its subfolders too
+func folderFilter(filter *model.ListFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		switch {
+		case filter == nil || filter.FolderID == "":
+			return db
+		case filter.FolderID == "root":
+			return db.Where("queries.folder_id IS NULL")
+		case filter.Recursive:
+			return db.Where("queries.folder_id IN (WITH RECURSIVE subfolders AS ("+
+				"SELECT id FROM query_folders WHERE id = ?"+
+				" UNION ALL SELECT f.id FROM query_folders f JOIN subfolders ON f.parent_id = subfolders.id"+
+				") SELECT id FROM subfolders)", filter.FolderID)
+		default:
+			return db.Where("queries.folder_id = ?", filter.FolderID)
+		}
+	}
+}
+
 // FindByDataSourceID finds all queries associated with a data source
 func (r *queryRepository) FindByDataSourceID(dataSourceID string) ([]model.Query, error) {
 	var queries []model.Query
@@ -183,58 +211,112 @@ func (r *queryRepository) CountByDataSourceID(dataSourceID string) (int64, error
 	return count, nil
 }
 
-// CreateExecution creates a new query execution record
-func (r *queryRepository) CreateExecution(exec *model.QueryExecution) error {
-	if err := r.db.Create(exec).Error; err != nil {
-		return fmt.Errorf("failed to create execution record: %w", err)
-	}
-	return nil
+// CreateWithRevision creates a query together with its first revision
+func (r *queryRepository) CreateWithRevision(q *model.Query, note string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		q.Revision = 1
+		if err := tx.Create(q).Error; err != nil {
+			return fmt.Errorf("failed to create query: %w", err)
+		}
+		if err := tx.Create(model.NewQueryRevision(q, note)).Error; err != nil {
+			return fmt.Errorf("failed to create query revision: %w", err)
+		}
+		return nil
+	})
 }
 
-// FindExecutionsByQueryID finds execution history for a specific query
-func (r *queryRepository) FindExecutionsByQueryID(queryID string, userID uint, page, size int) ([]model.QueryExecution, int64, error) {
-	var executions []model.QueryExecution
+// UpdateWithRevision saves a changed query and records its new revision.
+// The baseline is the definition before the change; it is recorded first
+// when the query predates revision history.
+func (r *queryRepository) UpdateWithRevision(q *model.Query, baseline *model.QueryRevision, note string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&model.QueryRevision{}).
+			Where("query_id = ? AND revision = ?", baseline.QueryID, baseline.Revision).
+			Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check query revision: %w", err)
+		}
+		if count == 0 {
+			if err := tx.Create(baseline).Error; err != nil {
+				return fmt.Errorf("failed to create query revision: %w", err)
+			}
+		}
+
+		q.Revision = baseline.Revision + 1
+		result := tx.Save(q)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update query: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrQueryNotFound
+		}
+		if err := tx.Create(model.NewQueryRevision(q, note)).Error; err != nil {
+			return fmt.Errorf("failed to create query revision: %w", err)
+		}
+		return nil
+	})
+}
+
+// FindRevisions returns the revisions of a query, newest first
+func (r *queryRepository) FindRevisions(queryID string, page, size int) ([]model.QueryRevision, int64, error) {
+	var revisions []model.QueryRevision
 	var total int64
 
 	offset := (page - 1) * size
 
-	// Count total records
-	if err := r.db.Model(&model.QueryExecution{}).
-		Where("query_id = ? AND user_id = ?", queryID, userID).
-		Count(&total).Error; err != nil {
-		return nil, 0, fmt.Errorf("failed to count executions: %w", err)
+	if err := r.db.Model(&model.QueryRevision{}).Where("query_id = ?", queryID).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count query revisions: %w", err)
 	}
 
-	// Get paginated records
-	if err := r.db.Preload("Query").
-		Where("query_id = ? AND user_id = ?", queryID, userID).
-		Order("created_at DESC").
+	if err := r.db.Where("query_id = ?", queryID).
+		Order("revision DESC").
 		Offset(offset).
 		Limit(size).
-		Find(&executions).Error; err != nil {
-		return nil, 0, fmt.Errorf("failed to find executions: %w", err)
+		Find(&revisions).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to find query revisions: %w", err)
 	}
 
-	return executions, total, nil
+	return revisions, total, nil
+}
+
+// FindRevision finds one revision of a query
+func (r *queryRepository) FindRevision(queryID string, revision int) (*model.QueryRevision, error) {
+	var rev model.QueryRevision
+	if err := r.db.Where("query_id = ? AND revision = ?", queryID, revision).First(&rev).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQueryRevisionNotFound
+		}
+		return nil, fmt.Errorf("failed to find query revision: %w", err)
+	}
+	return &rev, nil
+}
+
+// CreateExecution creates a new query execution record
+func (r *queryRepository) CreateExecution(exec *model.QueryExecution) error {
+	if err := r.db.Create(exec).Error; err != nil {
+		return fmt.Errorf("failed to create execution record: %w", err)
+	}
+	return nil
 }
 
-// FindExecutionsByUserID finds all execution history for a user
-func (r *queryRepository) FindExecutionsByUserID(userID uint, page, size int) ([]model.QueryExecution, int64, error) {
+// FindExecutions finds execution history for a user, newest first
+func (r *queryRepository) FindExecutions(userID uint, filter *model.ExecutionHistoryFilter, page, size int) ([]model.QueryExecution, int64, error) {
 	var executions []model.QueryExecution
 	var total int64
 
 	offset := (page - 1) * size
+	scope := executionFilter(userID, filter)
 
 	// Count total records
 	if err := r.db.Model(&model.QueryExecution{}).
-		Where("user_id = ?", userID).
+		Scopes(scope).
 		Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count executions: %w", err)
 	}
 
 	// Get paginated records with Query preloaded
 	if err := r.db.Preload("Query").
-		Where("user_id = ?", userID).
+		Scopes(scope).
 		Order("created_at DESC").
 		Offset(offset).
 		Limit(size).
@@ -244,3 +326,75 @@ func (r *queryRepository) FindExecutionsByUserID(userID uint, page, size int) ([
 
 	return executions, total, nil
 }
+
+// executionFilter scopes execution history to a user and a filter
+func executionFilter(userID uint, filter *model.ExecutionHistoryFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("query_executions.user_id = ?", userID)
+		if filter == nil {
+			return db
+		}
+		if filter.QueryID != "" {
+			db = db.Where("query_executions.query_id = ?", filter.QueryID)
+		}
+		if filter.DataSourceID != "" {
+			db = db.Where("query_executions.query_id IN (?)",
+				db.Session(&gorm.Session{NewDB: true}).Model(&model.Query{}).Select("id").Where("data_source_id = ?", filter.DataSourceID))
+		}
+		if len(filter.Status) > 0 {
+			db = db.Where("query_executions.status IN ?", filter.Status)
+		}
+		if filter.From != nil {
+			db = db.Where("query_executions.created_at >= ?", *filter.From)
+		}
+		if filter.To != nil {
+			db = db.Where("query_executions.created_at < ?", *filter.To)
+		}
+		if filter.MinDurationMs != nil {
+			db = db.Where("query_executions.execution_time_ms >= ?", *filter.MinDurationMs)
+		}
+		if filter.MaxDurationMs != nil {
+			db = db.Where("query_executions.execution_time_ms <= ?", *filter.MaxDurationMs)
+		}
+		if filter.MinRows != nil {
+			db = db.Where("query_executions.row_count >= ?", *filter.MinRows)
+		}
+		if filter.MaxRows != nil {
+			db = db.Where("query_executions.row_count <= ?", *filter.MaxRows)
+		}
+		if search := strings.TrimSpace(filter.Search); search != "" {
+			db = db.Where("to_tsvector('simple', COALESCE(query_executions.parameters::text, '') || ' ' || COALESCE(query_executions.error_message, '')) @@ plainto_tsquery('simple', ?)", search)
+		}
+		return db
+	}
+}
+
+// FindExecution finds an execution record by ID and user ID
+func (r *queryRepository) FindExecution(id string, userID uint) (*model.QueryExecution, error) {
+	var exec model.QueryExecution
+	if err := r.db.Preload("Query").Where("id = ? AND user_id = ?", id, userID).First(&exec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExecutionNotFound
+		}
+		return nil, fmt.Errorf("failed to find execution: %w", err)
+	}
+	return &exec, nil
+}
+
+// DeleteExecutionsBefore deletes up to limit finished execution records
+// created before cutoff. Jobs still queued or running, or whose stored
+// result has not expired, are kept.
+func (r *queryRepository) DeleteExecutionsBefore(cutoff time.Time, limit int) (int64, error) {
+	ids := r.db.Model(&model.QueryExecution{}).
+		Select("id").
+		Where("created_at < ?", cutoff).
+		Where("status NOT IN ?", []string{model.ExecutionStatusQueued, model.ExecutionStatusRunning}).
+		Where("result_expires_at IS NULL OR result_expires_at < ?", time.Now()).
+		Limit(limit)
+
+	result := r.db.Where("id IN (?)", ids).Delete(&model.QueryExecution{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete old executions: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/dataweaver/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrQueryScheduleNotFound = errors.New("query schedule not found")
+	ErrQuerySnapshotNotFound = errors.New("query snapshot not found")
+)
+
+// QueryScheduleRepository handles database operations for query schedules,
+// their snapshots and the scheduler lease
+type QueryScheduleRepository interface {
+	Create(schedule *model.QuerySchedule) error
+	FindAll(userID uint, queryID string, page, size int) ([]model.QuerySchedule, int64, error)
+	FindByID(id string) (*model.QuerySchedule, error)
+	FindByIDAndUserID(id string, userID uint) (*model.QuerySchedule, error)
+	Update(schedule *model.QuerySchedule) error
+	Delete(id string, userID uint) error
+	// FindDue returns enabled schedules whose next run is at or before now,
+	// earliest first
+	FindDue(now time.Time, limit int) ([]model.QuerySchedule, error)
+	// Advance moves a schedule's next run from from to next, and reports
+	// whether it was still due at from. Only the caller that advances a
+	// schedule runs that occurrence.
+	Advance(id string, from time.Time, next *time.Time) (bool, error)
+	// RecordRun records the outcome of a run on the schedule
+	RecordRun(id string, runAt time.Time, status, errorMessage string) error
+
+	CreateSnapshot(snapshot *model.QuerySnapshot) error
+	// FindSnapshots lists snapshots newest first, without their rows
+	FindSnapshots(scheduleID string, changedOnly bool, page, size int) ([]model.QuerySnapshot, int64, error)
+	FindSnapshot(scheduleID, snapshotID string) (*model.QuerySnapshot, error)
+	// FindLatestSnapshot returns the newest successful snapshot
+	FindLatestSnapshot(scheduleID string) (*model.QuerySnapshot, error)
+	// PruneSnapshots deletes all but the newest keep snapshots
+	PruneSnapshots(scheduleID string, keep int) (int64, error)
+
+	// AcquireLease takes or renews the named lease for holder until
+	// expiresAt, and reports whether holder has it
+	AcquireLease(name, holder string, now, expiresAt time.Time) (bool, error)
+	ReleaseLease(name, holder string) error
+}
+
+type queryScheduleRepository struct {
+	db *gorm.DB
+}
+
+// NewQueryScheduleRepository creates a new QueryScheduleRepository
+func NewQueryScheduleRepository(db *gorm.DB) QueryScheduleRepository {
+	return &queryScheduleRepository{db: db}
+}
+
+// Create creates a new schedule
+func (r *queryScheduleRepository) Create(schedule *model.QuerySchedule) error {
+	if err := r.db.Create(schedule).Error; err != nil {
+		return fmt.Errorf("failed to create query schedule: %w", err)
+	}
+	return nil
+}
+
+// FindAll returns the schedules of a user, optionally of one query, with pagination
+func (r *queryScheduleRepository) FindAll(userID uint, queryID string, page, size int) ([]model.QuerySchedule, int64, error) {
+	var schedules []model.QuerySchedule
+	var total int64
+
+	offset := (page - 1) * size
+
+	filter := func(db *gorm.DB) *gorm.DB {
+		db = db.Where("user_id = ?", userID)
+		if queryID != "" {
+			db = db.Where("query_id = ?", queryID)
+		}
+		return db
+	}
+
+	if err := r.db.Model(&model.QuerySchedule{}).Scopes(filter).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count query schedules: %w", err)
+	}
+
+	if err := r.db.Scopes(filter).Preload("Query").
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(size).
+		Find(&schedules).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to find query schedules: %w", err)
+	}
+
+	return schedules, total, nil
+}
+
+// FindByID finds a schedule by ID, with its query
+func (r *queryScheduleRepository) FindByID(id string) (*model.QuerySchedule, error) {
+	var schedule model.QuerySchedule
+	if err := r.db.Preload("Query").Where("id = ?", id).First(&schedule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQueryScheduleNotFound
+		}
+		return nil, fmt.Errorf("failed to find query schedule: %w", err)
+	}
+	return &schedule, nil
+}
+
+// FindByIDAndUserID finds a schedule of a user, with its query
+func (r *queryScheduleRepository) FindByIDAndUserID(id string, userID uint) (*model.QuerySchedule, error) {
+	var schedule model.QuerySchedule
+	if err := r.db.Preload("Query").
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&schedule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQueryScheduleNotFound
+		}
+		return nil, fmt.Errorf("failed to find query schedule: %w", err)
+	}
+	return &schedule, nil
+}
+
+// Update updates a schedule
+func (r *queryScheduleRepository) Update(schedule *model.QuerySchedule) error {
+	if err := r.db.Omit("Query").Save(schedule).Error; err != nil {
+		return fmt.Errorf("failed to update query schedule: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a schedule and its snapshots
+func (r *queryScheduleRepository) Delete(id string, userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&model.QuerySchedule{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete query schedule: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrQueryScheduleNotFound
+		}
+		if err := tx.Where("schedule_id = ?", id).Delete(&model.QuerySnapshot{}).Error; err != nil {
+			return fmt.Errorf("failed to delete query snapshots: %w", err)
+		}
+		return nil
+	})
+}
+
+// FindDue returns enabled schedules whose next run is at or before now
+func (r *queryScheduleRepository) FindDue(now time.Time, limit int) ([]model.QuerySchedule, error) {
+	var schedules []model.QuerySchedule
+	if err := r.db.Preload("Query").
+		Where("enabled = ? AND next_run_at <= ?", true, now).
+		Order("next_run_at").
+		Limit(limit).
+		Find(&schedules).Error; err != nil {
+		return nil, fmt.Errorf("failed to find due query schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// Advance moves a schedule's next run if it is still due at from
+func (r *queryScheduleRepository) Advance(id string, from time.Time, next *time.Time) (bool, error) {
+	result := r.db.Model(&model.QuerySchedule{}).
+		Where("id = ? AND next_run_at = ?", id, from).
+		Update("next_run_at", next)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to advance query schedule: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RecordRun records the outcome of a run
+func (r *queryScheduleRepository) RecordRun(id string, runAt time.Time, status, errorMessage string) error {
+	if err := r.db.Model(&model.QuerySchedule{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"last_run_at": runAt,
+			"last_status": status,
+			"last_error":  errorMessage,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to record query schedule run: %w", err)
+	}
+	return nil
+}
+
+// CreateSnapshot stores a snapshot
+func (r *queryScheduleRepository) CreateSnapshot(snapshot *model.QuerySnapshot) error {
+	if err := r.db.Create(snapshot).Error; err != nil {
+		return fmt.Errorf("failed to create query snapshot: %w", err)
+	}
+	return nil
+}
+
+// FindSnapshots lists the snapshots of a schedule without their rows
+func (r *queryScheduleRepository) FindSnapshots(scheduleID string, changedOnly bool, page, size int) ([]model.QuerySnapshot, int64, error) {
+	var snapshots []model.QuerySnapshot
+	var total int64
+
+	offset := (page - 1) * size
+
+	filter := func(db *gorm.DB) *gorm.DB {
+		db = db.Where("schedule_id = ?", scheduleID)
+		if changedOnly {
+			db = db.Where("(added > 0 OR removed > 0 OR changed > 0)")
+		}
+		return db
+	}
+
+	if err := r.db.Model(&model.QuerySnapshot{}).Scopes(filter).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count query snapshots: %w", err)
+	}
+
+	if err := r.db.Scopes(filter).Omit("rows").
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(size).
+		Find(&snapshots).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to find query snapshots: %w", err)
+	}
+
+	return snapshots, total, nil
+}
+
+// FindSnapshot finds a snapshot of a schedule, with its rows
+func (r *queryScheduleRepository) FindSnapshot(scheduleID, snapshotID string) (*model.QuerySnapshot, error) {
+	var snapshot model.QuerySnapshot
+	if err := r.db.Where("id = ? AND schedule_id = ?", snapshotID, scheduleID).First(&snapshot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQuerySnapshotNotFound
+		}
+		return nil, fmt.Errorf("failed to find query snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// FindLatestSnapshot returns the newest successful snapshot, with its rows
+func (r *queryScheduleRepository) FindLatestSnapshot(scheduleID string) (*model.QuerySnapshot, error) {
+	var snapshot model.QuerySnapshot
+	if err := r.db.Where("schedule_id = ? AND status = ?", scheduleID, model.ExecutionStatusSucceeded).
+		Order("created_at DESC").
+		First(&snapshot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQuerySnapshotNotFound
+		}
+		return nil, fmt.Errorf("failed to find latest query snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// PruneSnapshots deletes all but the newest keep snapshots of a schedule
+func (r *queryScheduleRepository) PruneSnapshots(scheduleID string, keep int) (int64, error) {
+	newest := r.db.Model(&model.QuerySnapshot{}).
+		Select("id").
+		Where("schedule_id = ?", scheduleID).
+		Order("created_at DESC").
+		Limit(keep)
+	result := r.db.Where("schedule_id = ? AND id NOT IN (?)", scheduleID, newest).Delete(&model.QuerySnapshot{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to prune query snapshots: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// AcquireLease inserts the lease, or takes it over when holder already has
+// it or it has expired
+func (r *queryScheduleRepository) AcquireLease(name, holder string, now, expiresAt time.Time) (bool, error) {
+	lease := model.SchedulerLease{Name: name, Holder: holder, ExpiresAt: expiresAt}
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"holder", "expires_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Or(
+				clause.Eq{Column: clause.Column{Table: lease.TableName(), Name: "holder"}, Value: holder},
+				clause.Lt{Column: clause.Column{Table: lease.TableName(), Name: "expires_at"}, Value: now},
+			),
+		}},
+	}).Create(&lease)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to acquire scheduler lease: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReleaseLease gives up a lease held by holder so another instance can take
+// over without waiting for it to expire
+func (r *queryScheduleRepository) ReleaseLease(name, holder string) error {
+	if err := r.db.Where("name = ? AND holder = ?", name, holder).
+		Delete(&model.SchedulerLease{}).Error; err != nil {
+		return fmt.Errorf("failed to release scheduler lease: %w", err)
+	}
+	return nil
+}
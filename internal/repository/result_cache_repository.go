@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/dataweaver/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrResultCacheMiss = errors.New("result not cached")
+
+// ResultCacheRepository handles database operations for the shared tier of
+// the result cache
+type ResultCacheRepository interface {
+	// Get returns an entry that has not expired by now
+	Get(key string, now time.Time) (*model.ResultCacheEntry, error)
+	// Set stores an entry, replacing one with the same key
+	Set(entry *model.ResultCacheEntry) error
+	// Purge deletes the entries of queries and records the purge for other instances
+	Purge(queryIDs []string) (int64, error)
+	// FindPurgesAfter returns purges with an ID above id, oldest first
+	FindPurgesAfter(id uint) ([]model.ResultCachePurge, error)
+	// LatestPurgeID returns the ID of the newest purge, or 0
+	LatestPurgeID() (uint, error)
+	// DeleteExpired deletes entries that expired before now, and purge
+	// records older than purgesBefore
+	DeleteExpired(now, purgesBefore time.Time) (int64, error)
+}
+
+type resultCacheRepository struct {
+	db *gorm.DB
+}
+
+// NewResultCacheRepository creates a new ResultCacheRepository
+func NewResultCacheRepository(db *gorm.DB) ResultCacheRepository {
+	return &resultCacheRepository{db: db}
+}
+
+// Get returns an unexpired entry
+func (r *resultCacheRepository) Get(key string, now time.Time) (*model.ResultCacheEntry, error) {
+	var entry model.ResultCacheEntry
+	if err := r.db.Where("key = ? AND expires_at > ?", key, now).First(&entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrResultCacheMiss
+		}
+		return nil, fmt.Errorf("failed to get cached result: %w", err)
+	}
+	return &entry, nil
+}
+
+// Set stores an entry, replacing one with the same key
+func (r *resultCacheRepository) Set(entry *model.ResultCacheEntry) error {
+	if err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"query_id", "value", "expires_at", "created_at"}),
+	}).Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to cache result: %w", err)
+	}
+	return nil
+}
+
+// Purge deletes the entries of queries and records the purge
+func (r *resultCacheRepository) Purge(queryIDs []string) (int64, error) {
+	if len(queryIDs) == 0 {
+		return 0, nil
+	}
+
+	var deleted int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("query_id IN ?", queryIDs).Delete(&model.ResultCacheEntry{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to purge cached results: %w", result.Error)
+		}
+		deleted = result.RowsAffected
+
+		purges := make([]model.ResultCachePurge, len(queryIDs))
+		for i, id := range queryIDs {
+			purges[i] = model.ResultCachePurge{QueryID: id}
+		}
+		if err := tx.Create(&purges).Error; err != nil {
+			return fmt.Errorf("failed to record result cache purge: %w", err)
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+// FindPurgesAfter returns purges with an ID above id
+func (r *resultCacheRepository) FindPurgesAfter(id uint) ([]model.ResultCachePurge, error) {
+	var purges []model.ResultCachePurge
+	if err := r.db.Where("id > ?", id).Order("id").Find(&purges).Error; err != nil {
+		return nil, fmt.Errorf("failed to find result cache purges: %w", err)
+	}
+	return purges, nil
+}
+
+// LatestPurgeID returns the ID of the newest purge
+func (r *resultCacheRepository) LatestPurgeID() (uint, error) {
+	var id uint
+	if err := r.db.Model(&model.ResultCachePurge{}).
+		Select("COALESCE(MAX(id), 0)").
+		Scan(&id).Error; err != nil {
+		return 0, fmt.Errorf("failed to find latest result cache purge: %w", err)
+	}
+	return id, nil
+}
+
+// DeleteExpired deletes expired entries and old purge records
+func (r *resultCacheRepository) DeleteExpired(now, purgesBefore time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", now).Delete(&model.ResultCacheEntry{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired cached results: %w", result.Error)
+	}
+	if err := r.db.Where("created_at < ?", purgesBefore).Delete(&model.ResultCachePurge{}).Error; err != nil {
+		return result.RowsAffected, fmt.Errorf("failed to delete old result cache purges: %w", err)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/dataweaver/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTagNotFound   = errors.New("tag not found")
+	ErrTagNameExists = errors.New("tag name already exists")
+)
+
+// TagUsage counts the items of one type carrying a tag
+type TagUsage struct {
+	TagID        string
+	ResourceType string
+	Count        int64
+}
+
+// TagRepository handles database operations for tags and favorites of
+// queries, tools and MCP servers
+type TagRepository interface {
+	// Tags
+	FindAll(userID uint) ([]model.Tag, error)
+	FindByIDAndUserID(id string, userID uint) (*model.Tag, error)
+	CountUsage(userID uint) ([]TagUsage, error)
+	Rename(tag *model.Tag) error
+	Delete(id string, userID uint) error
+
+	// Taggings; tags are created on first use
+	SetTags(userID uint, resourceType, resourceID string, names []string) error
+	AddTags(userID uint, resourceType, resourceID string, names []string) error
+	RemoveTags(userID uint, resourceType, resourceID string, names []string) error
+	FindNames(resourceType string, resourceIDs []string) (map[string][]string, error)
+
+	// Favorites
+	SetFavorite(userID uint, resourceType, resourceID string, favorite bool) error
+	FindFavorites(userID uint, resourceType string, resourceIDs []string) (map[string]bool, error)
+
+	// DeleteResource removes the tags and favorites of a deleted item
+	DeleteResource(resourceType, resourceID string) error
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository creates a new TagRepository
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// FindAll returns a user's tags by name
+func (r *tagRepository) FindAll(userID uint) ([]model.Tag, error) {
+	var tags []model.Tag
+	if err := r.db.Where("user_id = ?", userID).Order("name").Find(&tags).Error; err != nil {
+		return nil, fmt.Errorf("failed to find tags: %w", err)
+	}
+	return tags, nil
+}
+
+// FindByIDAndUserID finds a tag by ID and user ID
+func (r *tagRepository) FindByIDAndUserID(id string, userID uint) (*model.Tag, error) {
+	var tag model.Tag
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&tag).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, fmt.Errorf("failed to find tag: %w", err)
+	}
+	return &tag, nil
+}
+
+// CountUsage counts the items of each type carrying each of a user's tags
+func (r *tagRepository) CountUsage(userID uint) ([]TagUsage, error) {
+	var usage []TagUsage
+	if err := r.db.Model(&model.Tagging{}).
+		Select("taggings.tag_id, taggings.resource_type, COUNT(*) AS count").
+		Joins("JOIN tags ON tags.id = taggings.tag_id").
+		Where("tags.user_id = ?", userID).
+		Group("taggings.tag_id, taggings.resource_type").
+		Scan(&usage).Error; err != nil {
+		return nil, fmt.Errorf("failed to count tag usage: %w", err)
+	}
+	return usage, nil
+}
+
+// Rename changes the name of a tag
+func (r *tagRepository) Rename(tag *model.Tag) error {
+	var count int64
+	if err := r.db.Model(&model.Tag{}).
+		Where("user_id = ? AND name = ? AND id <> ?", tag.UserID, tag.Name, tag.ID).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check tag name: %w", err)
+	}
+	if count > 0 {
+		return ErrTagNameExists
+	}
+	if err := r.db.Model(tag).Update("name", tag.Name).Error; err != nil {
+		return fmt.Errorf("failed to rename tag: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a tag, removing it from every item
+func (r *tagRepository) Delete(id string, userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&model.Tag{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete tag: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrTagNotFound
+		}
+		if err := tx.Where("tag_id = ?", id).Delete(&model.Tagging{}).Error; err != nil {
+			return fmt.Errorf("failed to delete taggings: %w", err)
+		}
+		return nil
+	})
+}
+
+// SetTags replaces the tags of an item
+func (r *tagRepository) SetTags(userID uint, resourceType, resourceID string, names []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("resource_type = ? AND resource_id = ?", resourceType, resourceID).
+			Delete(&model.Tagging{}).Error; err != nil {
+			return fmt.Errorf("failed to clear tags: %w", err)
+		}
+		return addTags(tx, userID, resourceType, resourceID, names)
+	})
+}
+
+// AddTags adds tags to an item, keeping the ones it has
+func (r *tagRepository) AddTags(userID uint, resourceType, resourceID string, names []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return addTags(tx, userID, resourceType, resourceID, names)
+	})
+}
+
+// addTags creates missing tags and attaches them to an item
+func addTags(tx *gorm.DB, userID uint, resourceType, resourceID string, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	tags := make([]model.Tag, len(names))
+	for i, name := range names {
+		tags[i] = model.Tag{UserID: userID, Name: name}
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error; err != nil {
+		return fmt.Errorf("failed to create tags: %w", err)
+	}
+
+	var ids []string
+	if err := tx.Model(&model.Tag{}).
+		Where("user_id = ? AND name IN ?", userID, names).
+		Pluck("id", &ids).Error; err != nil {
+		return fmt.Errorf("failed to find tags: %w", err)
+	}
+
+	taggings := make([]model.Tagging, len(ids))
+	for i, id := range ids {
+		taggings[i] = model.Tagging{TagID: id, ResourceType: resourceType, ResourceID: resourceID}
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&taggings).Error; err != nil {
+		return fmt.Errorf("failed to tag %s: %w", resourceType, err)
+	}
+	return nil
+}
+
+// RemoveTags removes tags from an item
+func (r *tagRepository) RemoveTags(userID uint, resourceType, resourceID string, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	tagIDs := r.db.Model(&model.Tag{}).Select("id").Where("user_id = ? AND name IN ?", userID, names)
+	if err := r.db.Where("resource_type = ? AND resource_id = ? AND tag_id IN (?)", resourceType, resourceID, tagIDs).
+		Delete(&model.Tagging{}).Error; err != nil {
+		return fmt.Errorf("failed to untag %s: %w", resourceType, err)
+	}
+	return nil
+}
+
+// FindNames returns the tag names of items, sorted, keyed by item ID
+func (r *tagRepository) FindNames(resourceType string, resourceIDs []string) (map[string][]string, error) {
+	names := make(map[string][]string)
+	if len(resourceIDs) == 0 {
+		return names, nil
+	}
+
+	var rows []struct {
+		ResourceID string
+		Name       string
+	}
+	if err := r.db.Model(&model.Tagging{}).
+		Select("taggings.resource_id, tags.name").
+		Joins("JOIN tags ON tags.id = taggings.tag_id").
+		Where("taggings.resource_type = ? AND taggings.resource_id IN ?", resourceType, resourceIDs).
+		Order("tags.name").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to find tags: %w", err)
+	}
+	for _, row := range rows {
+		names[row.ResourceID] = append(names[row.ResourceID], row.Name)
+	}
+	return names, nil
+}
+
+// SetFavorite marks or unmarks an item as a favorite of a user
+func (r *tagRepository) SetFavorite(userID uint, resourceType, resourceID string, favorite bool) error {
+	if !favorite {
+		if err := r.db.Where("user_id = ? AND resource_type = ? AND resource_id = ?", userID, resourceType, resourceID).
+			Delete(&model.Favorite{}).Error; err != nil {
+			return fmt.Errorf("failed to remove favorite: %w", err)
+		}
+		return nil
+	}
+
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.Favorite{UserID: userID, ResourceType: resourceType, ResourceID: resourceID}).Error; err != nil {
+		return fmt.Errorf("failed to add favorite: %w", err)
+	}
+	return nil
+}
+
+// FindFavorites returns which of the items are favorites of a user
+func (r *tagRepository) FindFavorites(userID uint, resourceType string, resourceIDs []string) (map[string]bool, error) {
+	favorites := make(map[string]bool)
+	if len(resourceIDs) == 0 {
+		return favorites, nil
+	}
+
+	var ids []string
+	if err := r.db.Model(&model.Favorite{}).
+		Where("user_id = ? AND resource_type = ? AND resource_id IN ?", userID, resourceType, resourceIDs).
+		Pluck("resource_id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to find favorites: %w", err)
+	}
+	for _, id := range ids {
+		favorites[id] = true
+	}
+	return favorites, nil
+}
+
+// DeleteResource removes the tags and favorites of an item
+func (r *tagRepository) DeleteResource(resourceType, resourceID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("resource_type = ? AND resource_id = ?", resourceType, resourceID).
+			Delete(&model.Tagging{}).Error; err != nil {
+			return fmt.Errorf("failed to delete taggings: %w", err)
+		}
+		if err := tx.Where("resource_type = ? AND resource_id = ?", resourceType, resourceID).
+			Delete(&model.Favorite{}).Error; err != nil {
+			return fmt.Errorf("failed to delete favorites: %w", err)
+		}
+		return nil
+	})
+}
+
+// listFilter scopes a list of a user's items of one type, in table, to a
+// keyword over columns and to the tags and favorites of the filter
+func listFilter(table, resourceType string, userID uint, filter *model.ListFilter, keywordColumns ...string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where(table+".user_id = ?", userID)
+		if filter == nil {
+			return db
+		}
+
+		if filter.Keyword != "" {
+			pattern := "%" + filter.Keyword + "%"
+			conditions := make([]string, len(keywordColumns))
+			args := make([]interface{}, len(keywordColumns))
+			for i, column := range keywordColumns {
+				conditions[i] = table + "." + column + " ILIKE ?"
+				args[i] = pattern
+			}
+			db = db.Where("("+strings.Join(conditions, " OR ")+")", args...)
+		}
+		if len(filter.Tags) > 0 {
+			db = db.Where(table+".id IN (SELECT taggings.resource_id FROM taggings JOIN tags ON tags.id = taggings.tag_id"+
+				" WHERE taggings.resource_type = ? AND tags.user_id = ? AND tags.name IN ?"+
+				" GROUP BY taggings.resource_id HAVING COUNT(DISTINCT tags.id) = ?)",
+				resourceType, userID, filter.Tags, len(filter.Tags))
+		}
+		if filter.Favorite {
+			db = db.Where(table+".id IN (SELECT resource_id FROM favorites WHERE user_id = ? AND resource_type = ?)",
+				userID, resourceType)
+		}
+		return db
+	}
+}
@@ -23,9 +23,10 @@ type ToolRepository interface {
 	FindByName(name string, userID uint) (*model.Tool, error)
 	Update(t *model.Tool) error
 	Delete(id string, userID uint) error
-	Search(userID uint, keyword string, page, size int) ([]model.Tool, int64, error)
+	List(userID uint, filter *model.ListFilter, page, size int) ([]model.Tool, int64, error)
 	FindByQueryID(queryID string) ([]model.Tool, error)
 	FindByMcpServerID(mcpServerID string) ([]model.Tool, error)
+	FindAllWithQuery(userID uint) ([]model.Tool, error)
 	CountByQueryID(queryID string) (int64, error)
 	IncrementVersion(id string, userID uint) error
 }
@@ -158,32 +159,27 @@ func (r *toolRepository) Delete(id string, userID uint) error {
 	return nil
 }
 
-// Search searches tools by keyword (name, display_name, or description)
-func (r *toolRepository) Search(userID uint, keyword string, page, size int) ([]model.Tool, int64, error) {
+// List returns a user's tools matching a filter, with pagination
+func (r *toolRepository) List(userID uint, filter *model.ListFilter, page, size int) ([]model.Tool, int64, error) {
 	var tools []model.Tool
 	var total int64
 
 	offset := (page - 1) * size
-	searchPattern := "%" + keyword + "%"
-
-	query := r.db.Model(&model.Tool{}).
-		Where("user_id = ?", userID).
-		Where("name ILIKE ? OR display_name ILIKE ? OR description ILIKE ?", searchPattern, searchPattern, searchPattern)
+	scope := listFilter("tools", model.ResourceTool, userID, filter, "name", "display_name", "description")
 
 	// Count total records
-	if err := query.Count(&total).Error; err != nil {
+	if err := r.db.Model(&model.Tool{}).Scopes(scope).Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count tools: %w", err)
 	}
 
 	// Get paginated records with Query preloaded
 	if err := r.db.Preload("Query").
-		Where("user_id = ?", userID).
-		Where("name ILIKE ? OR display_name ILIKE ? OR description ILIKE ?", searchPattern, searchPattern, searchPattern).
+		Scopes(scope).
 		Order("created_at DESC").
 		Offset(offset).
 		Limit(size).
 		Find(&tools).Error; err != nil {
-		return nil, 0, fmt.Errorf("failed to search tools: %w", err)
+		return nil, 0, fmt.Errorf("failed to list tools: %w", err)
 	}
 
 	return tools, total, nil
@@ -207,6 +203,20 @@ func (r *toolRepository) FindByMcpServerID(mcpServerID string) ([]model.Tool, er
 	return tools, nil
 }
 
+// FindAllWithQuery returns all tools for a user with Query and its
+// DataSource preloaded
+func (r *toolRepository) FindAllWithQuery(userID uint) ([]model.Tool, error) {
+	var tools []model.Tool
+	if err := r.db.Preload("Query").
+		Preload("Query.DataSource").
+		Where("user_id = ?", userID).
+		Order("name ASC").
+		Find(&tools).Error; err != nil {
+		return nil, fmt.Errorf("failed to find tools: %w", err)
+	}
+	return tools, nil
+}
+
 // CountByQueryID counts tools associated with a query
 func (r *toolRepository) CountByQueryID(queryID string) (int64, error) {
 	var count int64
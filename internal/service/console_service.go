@@ -0,0 +1,247 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/internal/repository"
+	"github.com/yourusername/dataweaver/pkg/dbconnector"
+)
+
+var ErrConsoleStatementFailed = errors.New("console statement failed and has no results")
+
+// ConsoleService handles business logic for the SQL console: ad-hoc
+// read-only statements, their per-session history and saving them as queries
+type ConsoleService interface {
+	Execute(userID uint, req *model.ConsoleExecuteRequest) (*model.ConsoleResultResponse, error)
+	GetResults(statementID string, userID uint, page, size int) (*model.ConsoleResultResponse, error)
+	GetHistory(userID uint, sessionID string, page, size int) ([]model.ConsoleStatementResponse, int64, error)
+	ClearHistory(userID uint, sessionID string) error
+	Promote(statementID string, userID uint, req *model.PromoteStatementRequest) (*model.QueryResponse, error)
+}
+
+type consoleService struct {
+	consoleRepo repository.ConsoleRepository
+	querySvc    QueryService
+	cache       *ResultCache
+}
+
+// NewConsoleService creates a new ConsoleService. Statements run through
+// querySvc, and their results are kept in cache for paging.
+func NewConsoleService(consoleRepo repository.ConsoleRepository, querySvc QueryService, cache *ResultCache) ConsoleService {
+	return &consoleService{
+		consoleRepo: consoleRepo,
+		querySvc:    querySvc,
+		cache:       cache,
+	}
+}
+
+// Execute runs a statement, records it in the session history and returns
+// the first page of its result
+func (s *consoleService) Execute(userID uint, req *model.ConsoleExecuteRequest) (*model.ConsoleResultResponse, error) {
+	result, execErr := s.querySvc.ExecuteRawQuery(userID, req.DataSourceID, req.SQL, req.Parameters)
+	if errors.Is(execErr, ErrDataSourceNotFound) {
+		return nil, execErr
+	}
+
+	paramsJSON, _ := serializeParams(req.Parameters)
+	stmt := &model.ConsoleStatement{
+		UserID:       userID,
+		SessionID:    req.SessionID,
+		DataSourceID: req.DataSourceID,
+		SQL:          req.SQL,
+		Parameters:   paramsJSON,
+	}
+	if execErr != nil {
+		stmt.Status = model.ExecutionStatusFailed
+		stmt.ErrorMessage = execErr.Error()
+	} else {
+		stmt.Status = model.ExecutionStatusSucceeded
+		stmt.RowCount = len(result.Data)
+		stmt.Truncated = result.Truncated
+		stmt.ExecutionTimeMs = result.ExecutionTimeMs
+	}
+
+	// Failed statements are recorded too, so they can be fixed from history
+	if err := s.consoleRepo.Create(stmt); err != nil && execErr == nil {
+		return nil, err
+	}
+	if execErr != nil {
+		return nil, execErr
+	}
+
+	s.cache.Set(consoleResultKey(stmt.ID), "", toConnectorResult(result), s.cache.TTL(0))
+	return consolePage(stmt, result, 1, req.Size), nil
+}
+
+// GetResults returns a page of the result of a statement. Results no longer
+// cached are produced by running the statement again.
+func (s *consoleService) GetResults(statementID string, userID uint, page, size int) (*model.ConsoleResultResponse, error) {
+	stmt, err := s.consoleRepo.FindByIDAndUserID(statementID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if stmt.Status != model.ExecutionStatusSucceeded {
+		return nil, ErrConsoleStatementFailed
+	}
+
+	key := consoleResultKey(stmt.ID)
+	if cached, ok := s.cache.Get(key); ok {
+		result := &model.ExecuteQueryResponse{
+			Columns:         cached.Columns,
+			ColumnTypes:     toResultColumns(cached.ColumnTypes),
+			Data:            cached.Data,
+			RowCount:        len(cached.Data),
+			Truncated:       cached.Truncated,
+			ExecutionTimeMs: stmt.ExecutionTimeMs,
+			ResultSets:      toResultSetResponses(cached.QueryResult().ResultSets),
+		}
+		return consolePage(stmt, result, page, size), nil
+	}
+
+	result, err := s.querySvc.ExecuteRawQuery(userID, stmt.DataSourceID, stmt.SQL, deserializeParams(stmt.Parameters))
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Set(key, "", toConnectorResult(result), s.cache.TTL(0))
+	return consolePage(stmt, result, page, size), nil
+}
+
+// GetHistory returns the statements of a console session, newest first
+func (s *consoleService) GetHistory(userID uint, sessionID string, page, size int) ([]model.ConsoleStatementResponse, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 20
+	}
+
+	stmts, total, err := s.consoleRepo.FindBySession(userID, sessionID, page, size)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]model.ConsoleStatementResponse, len(stmts))
+	for i := range stmts {
+		responses[i] = *toConsoleStatementResponse(&stmts[i])
+	}
+	return responses, total, nil
+}
+
+// ClearHistory deletes the history of a console session
+func (s *consoleService) ClearHistory(userID uint, sessionID string) error {
+	_, err := s.consoleRepo.DeleteSession(userID, sessionID)
+	return err
+}
+
+// Promote saves a statement as a query, validated like any new query
+func (s *consoleService) Promote(statementID string, userID uint, req *model.PromoteStatementRequest) (*model.QueryResponse, error) {
+	stmt, err := s.consoleRepo.FindByIDAndUserID(statementID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := s.querySvc.Create(userID, &model.CreateQueryRequest{
+		Name:         req.Name,
+		Description:  req.Description,
+		DataSourceID: stmt.DataSourceID,
+		SQLTemplate:  stmt.SQL,
+		FolderID:     req.FolderID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.consoleRepo.SetQueryID(stmt.ID, query.ID); err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+// consoleResultKey identifies the cached result of a console statement
+func consoleResultKey(statementID string) string {
+	return "console:" + statementID
+}
+
+// consolePage cuts a page out of the first result set of a statement. Any
+// further result sets come whole with the first page.
+func consolePage(stmt *model.ConsoleStatement, result *model.ExecuteQueryResponse, page, size int) *model.ConsoleResultResponse {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 1000 {
+		size = 100
+	}
+
+	data := []map[string]interface{}{}
+	if offset := (page - 1) * size; offset < len(result.Data) {
+		end := offset + size
+		if end > len(result.Data) {
+			end = len(result.Data)
+		}
+		data = result.Data[offset:end]
+	}
+
+	response := &model.ConsoleResultResponse{
+		StatementID:     stmt.ID,
+		Columns:         result.Columns,
+		ColumnTypes:     result.ColumnTypes,
+		Data:            data,
+		Total:           len(result.Data),
+		Page:            page,
+		Size:            size,
+		Truncated:       result.Truncated,
+		ExecutionTimeMs: result.ExecutionTimeMs,
+	}
+	if page == 1 {
+		response.ResultSets = result.ResultSets
+	}
+	return response
+}
+
+// toConnectorResult converts an execution response back to connector form
+// for caching
+func toConnectorResult(result *model.ExecuteQueryResponse) *dbconnector.QueryResult {
+	converted := &dbconnector.QueryResult{
+		Columns:     result.Columns,
+		ColumnTypes: toColumnMeta(result.ColumnTypes),
+		Data:        result.Data,
+		Truncated:   result.Truncated,
+	}
+	for _, set := range result.ResultSets {
+		converted.ResultSets = append(converted.ResultSets, dbconnector.ResultSet{
+			Columns:     set.Columns,
+			ColumnTypes: toColumnMeta(set.ColumnTypes),
+			Data:        set.Data,
+			Truncated:   set.Truncated,
+		})
+	}
+	return converted
+}
+
+// toColumnMeta converts API column metadata back to connector form
+func toColumnMeta(columns []model.ResultColumn) []dbconnector.ColumnMeta {
+	meta := make([]dbconnector.ColumnMeta, len(columns))
+	for i, c := range columns {
+		meta[i] = dbconnector.ColumnMeta(c)
+	}
+	return meta
+}
+
+// toConsoleStatementResponse converts a console statement to a history item
+func toConsoleStatementResponse(stmt *model.ConsoleStatement) *model.ConsoleStatementResponse {
+	return &model.ConsoleStatementResponse{
+		ID:              stmt.ID,
+		SessionID:       stmt.SessionID,
+		DataSourceID:    stmt.DataSourceID,
+		SQL:             stmt.SQL,
+		Parameters:      deserializeParams(stmt.Parameters),
+		Status:          stmt.Status,
+		RowCount:        stmt.RowCount,
+		Truncated:       stmt.Truncated,
+		ExecutionTimeMs: stmt.ExecutionTimeMs,
+		ErrorMessage:    stmt.ErrorMessage,
+		QueryID:         stmt.QueryID,
+		CreatedAt:       stmt.CreatedAt,
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/dataweaver/internal/model"
+)
+
+func TestConsolePage(t *testing.T) {
+	stmt := &model.ConsoleStatement{ID: "s1"}
+	result := &model.ExecuteQueryResponse{
+		Columns:   []string{"id"},
+		Data:      make([]map[string]interface{}, 25),
+		Truncated: true,
+	}
+	for i := range result.Data {
+		result.Data[i] = map[string]interface{}{"id": i}
+	}
+
+	result.ResultSets = []model.ResultSetResponse{{Columns: []string{"total"}}}
+	page := consolePage(stmt, result, 1, 10)
+	assert.Len(t, page.ResultSets, 1)
+
+	page = consolePage(stmt, result, 3, 10)
+	assert.Empty(t, page.ResultSets)
+	assert.Equal(t, "s1", page.StatementID)
+	assert.Equal(t, 25, page.Total)
+	assert.True(t, page.Truncated)
+	assert.Len(t, page.Data, 5)
+	assert.Equal(t, 20, page.Data[0]["id"])
+
+	// Past the last row
+	page = consolePage(stmt, result, 4, 10)
+	assert.NotNil(t, page.Data)
+	assert.Empty(t, page.Data)
+
+	// Out of range sizes fall back to the default
+	page = consolePage(stmt, result, 0, 5000)
+	assert.Equal(t, 1, page.Page)
+	assert.Equal(t, 100, page.Size)
+	assert.Len(t, page.Data, 25)
+}
+
+func TestToConnectorResult(t *testing.T) {
+	nullable := true
+	result := &model.ExecuteQueryResponse{
+		Columns:     []string{"amount"},
+		ColumnTypes: []model.ResultColumn{{Name: "amount", DatabaseType: "NUMERIC", Nullable: &nullable}},
+		Data:        []map[string]interface{}{{"amount": "1.50"}},
+		ResultSets: []model.ResultSetResponse{
+			{Columns: []string{"total"}, ColumnTypes: []model.ResultColumn{{Name: "total"}}, Data: []map[string]interface{}{{"total": 1}}, RowCount: 1},
+		},
+	}
+
+	converted := toConnectorResult(result)
+	assert.Equal(t, result.Columns, converted.Columns)
+	assert.Equal(t, result.Data, converted.Data)
+	assert.Equal(t, result.ColumnTypes, toResultColumns(converted.ColumnTypes))
+	assert.Equal(t, result.ResultSets, toResultSetResponses(converted.ResultSets))
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/yourusername/dataweaver/internal/model"
@@ -15,6 +16,7 @@ var (
 	ErrInvalidDataSourceType = errors.New("invalid datasource type")
 	ErrDataSourceInUse       = errors.New("datasource is in use by queries")
 	ErrConnectionFailed      = errors.New("connection test failed")
+	ErrTableNotFound         = errors.New("table not found")
 )
 
 // DataSourceService handles business logic for datasources
@@ -28,15 +30,24 @@ type DataSourceService interface {
 	TestConnection(id string, userID uint) (*model.TestConnectionResult, error)
 	TestConnectionDirect(req *model.CreateDataSourceRequest) (*model.TestConnectionResult, error)
 	GetTables(id string, userID uint) ([]model.TableInfoResponse, error)
+	// Lazy schema browsing
+	ListSchemas(id string, userID uint) ([]model.SchemaInfoResponse, error)
+	ListSchemaTables(id string, userID uint, schema string, req *model.ListTablesRequest) ([]model.TableInfoResponse, int64, error)
+	GetTableDetail(id string, userID uint, schema, table string) (*model.TableInfoResponse, error)
+	RefreshMetadata(id string, userID uint) error
 }
 
 type dataSourceService struct {
-	repo repository.DataSourceRepository
+	repo  repository.DataSourceRepository
+	cache *metadataCache
 }
 
 // NewDataSourceService creates a new DataSourceService
 func NewDataSourceService(repo repository.DataSourceRepository) DataSourceService {
-	return &dataSourceService{repo: repo}
+	return &dataSourceService{
+		repo:  repo,
+		cache: newMetadataCache(metadataCacheTTL),
+	}
 }
 
 // Create creates a new datasource
@@ -58,6 +69,12 @@ func (s *dataSourceService) Create(userID uint, req *model.CreateDataSourceReque
 		sslMode = "disable"
 	}
 
+	// Data sources are read-only unless explicitly turned off
+	readOnly := true
+	if req.ReadOnly != nil {
+		readOnly = *req.ReadOnly
+	}
+
 	ds := &model.DataSource{
 		UserID:      userID,
 		Name:        req.Name,
@@ -69,6 +86,7 @@ func (s *dataSourceService) Create(userID uint, req *model.CreateDataSourceReque
 		Username:    req.Username,
 		Password:    encryptedPassword,
 		SSLMode:     sslMode,
+		ReadOnly:    &readOnly,
 		Status:      "active",
 	}
 
@@ -179,6 +197,9 @@ func (s *dataSourceService) Update(id string, userID uint, req *model.UpdateData
 	if req.SSLMode != nil {
 		ds.SSLMode = *req.SSLMode
 	}
+	if req.ReadOnly != nil {
+		ds.ReadOnly = req.ReadOnly
+	}
 	if req.Status != nil {
 		ds.Status = *req.Status
 	}
@@ -187,6 +208,9 @@ func (s *dataSourceService) Update(id string, userID uint, req *model.UpdateData
 		return nil, err
 	}
 
+	// Connection details may have changed
+	s.cache.invalidate(id)
+
 	return ds.ToResponse(), nil
 }
 
@@ -201,7 +225,12 @@ func (s *dataSourceService) Delete(id string, userID uint) error {
 		return ErrDataSourceInUse
 	}
 
-	return s.repo.Delete(id, userID)
+	if err := s.repo.Delete(id, userID); err != nil {
+		return err
+	}
+
+	s.cache.invalidate(id)
+	return nil
 }
 
 // TestConnection tests the connection to a datasource
@@ -261,6 +290,169 @@ func (s *dataSourceService) testConnection(dbType, host string, port int, userna
 
 // GetTables returns the list of tables in a datasource
 func (s *dataSourceService) GetTables(id string, userID uint) ([]model.TableInfoResponse, error) {
+	connector, err := s.connect(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer connector.Close()
+
+	tables, err := connector.GetSchema()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema: %w", err)
+	}
+
+	responses := make([]model.TableInfoResponse, len(tables))
+	for i := range tables {
+		responses[i] = toTableInfoResponse(&tables[i])
+	}
+
+	return responses, nil
+}
+
+// ListSchemas returns the schemas of a datasource
+func (s *dataSourceService) ListSchemas(id string, userID uint) ([]model.SchemaInfoResponse, error) {
+	// Verify ownership before serving anything from the cache
+	if _, err := s.repo.FindByIDAndUserID(id, userID); err != nil {
+		return nil, err
+	}
+
+	var schemas []string
+	if cached, ok := s.cache.get(id, "schemas"); ok {
+		schemas = cached.([]string)
+	} else {
+		connector, err := s.connect(id, userID)
+		if err != nil {
+			return nil, err
+		}
+		defer connector.Close()
+
+		schemas, err = connector.ListSchemas()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list schemas: %w", err)
+		}
+		s.cache.set(id, "schemas", schemas)
+	}
+
+	responses := make([]model.SchemaInfoResponse, len(schemas))
+	for i, name := range schemas {
+		responses[i] = model.SchemaInfoResponse{Name: name}
+	}
+
+	return responses, nil
+}
+
+// ListSchemaTables returns a page of the tables in a schema, optionally
+// filtered by name and with columns loaded for the returned page only
+func (s *dataSourceService) ListSchemaTables(id string, userID uint, schema string, req *model.ListTablesRequest) ([]model.TableInfoResponse, int64, error) {
+	if _, err := s.repo.FindByIDAndUserID(id, userID); err != nil {
+		return nil, 0, err
+	}
+
+	page, size := req.Page, req.Size
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 20
+	}
+
+	var connector *dbconnector.Connector
+	defer func() {
+		if connector != nil {
+			connector.Close()
+		}
+	}()
+
+	var tables []dbconnector.TableInfo
+	cacheKey := "tables:" + schema
+	if cached, ok := s.cache.get(id, cacheKey); ok {
+		tables = cached.([]dbconnector.TableInfo)
+	} else {
+		var err error
+		if connector, err = s.connect(id, userID); err != nil {
+			return nil, 0, err
+		}
+		tables, err = connector.ListTables(schema)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list tables: %w", err)
+		}
+		s.cache.set(id, cacheKey, tables)
+	}
+
+	pageTables, total := pageTables(filterTables(tables, req.Keyword), page, size)
+
+	if req.IncludeColumns && len(pageTables) > 0 {
+		if connector == nil {
+			var err error
+			if connector, err = s.connect(id, userID); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		names := make([]string, len(pageTables))
+		for i, t := range pageTables {
+			names[i] = t.Name
+		}
+		columns, err := connector.GetColumns(schema, names)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get columns: %w", err)
+		}
+		for i := range pageTables {
+			pageTables[i].Columns = columns[pageTables[i].Name]
+		}
+	}
+
+	responses := make([]model.TableInfoResponse, len(pageTables))
+	for i := range pageTables {
+		responses[i] = toTableInfoResponse(&pageTables[i])
+	}
+
+	return responses, total, nil
+}
+
+// GetTableDetail returns one table with its columns, indexes and foreign keys
+func (s *dataSourceService) GetTableDetail(id string, userID uint, schema, table string) (*model.TableInfoResponse, error) {
+	if _, err := s.repo.FindByIDAndUserID(id, userID); err != nil {
+		return nil, err
+	}
+
+	cacheKey := "table:" + schema + "." + table
+	if cached, ok := s.cache.get(id, cacheKey); ok {
+		resp := toTableInfoResponse(cached.(*dbconnector.TableInfo))
+		return &resp, nil
+	}
+
+	connector, err := s.connect(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer connector.Close()
+
+	detail, err := connector.GetTableDetail(schema, table)
+	if err != nil {
+		if errors.Is(err, dbconnector.ErrTableNotFound) {
+			return nil, ErrTableNotFound
+		}
+		return nil, fmt.Errorf("failed to get table: %w", err)
+	}
+	s.cache.set(id, cacheKey, detail)
+
+	resp := toTableInfoResponse(detail)
+	return &resp, nil
+}
+
+// RefreshMetadata discards cached schema metadata for a datasource
+func (s *dataSourceService) RefreshMetadata(id string, userID uint) error {
+	if _, err := s.repo.FindByIDAndUserID(id, userID); err != nil {
+		return err
+	}
+
+	s.cache.invalidate(id)
+	return nil
+}
+
+// connect opens a connection to a datasource owned by the user
+func (s *dataSourceService) connect(id string, userID uint) (*dbconnector.Connector, error) {
 	ds, err := s.GetWithPassword(id, userID)
 	if err != nil {
 		return nil, err
@@ -274,38 +466,96 @@ func (s *dataSourceService) GetTables(id string, userID uint) ([]model.TableInfo
 		Password: ds.Password,
 		Database: ds.Database,
 		SSLMode:  ds.SSLMode,
+		ReadOnly: ds.IsReadOnly(),
 	}
 
 	connector := dbconnector.NewConnector(config)
 	if err := connector.Connect(); err != nil {
 		return nil, fmt.Errorf("failed to connect: %w", err)
 	}
-	defer connector.Close()
 
-	tables, err := connector.GetSchema()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get schema: %w", err)
+	return connector, nil
+}
+
+// filterTables returns the tables whose name contains keyword, case-insensitively
+func filterTables(tables []dbconnector.TableInfo, keyword string) []dbconnector.TableInfo {
+	if keyword == "" {
+		return tables
 	}
 
-	responses := make([]model.TableInfoResponse, len(tables))
-	for i, t := range tables {
-		columns := make([]model.ColumnInfoResponse, len(t.Columns))
-		for j, c := range t.Columns {
-			columns[j] = model.ColumnInfoResponse{
-				Name:       c.Name,
-				Type:       c.Type,
-				Nullable:   c.Nullable,
-				PrimaryKey: c.PrimaryKey,
-			}
+	keyword = strings.ToLower(keyword)
+	filtered := make([]dbconnector.TableInfo, 0)
+	for _, t := range tables {
+		if strings.Contains(strings.ToLower(t.Name), keyword) {
+			filtered = append(filtered, t)
 		}
-		responses[i] = model.TableInfoResponse{
-			Name:    t.Name,
-			Schema:  t.Schema,
-			Columns: columns,
+	}
+	return filtered
+}
+
+// pageTables returns a copy of one page of tables and the total count
+func pageTables(tables []dbconnector.TableInfo, page, size int) ([]dbconnector.TableInfo, int64) {
+	total := int64(len(tables))
+	start := (page - 1) * size
+	if start >= len(tables) {
+		return []dbconnector.TableInfo{}, total
+	}
+	end := start + size
+	if end > len(tables) {
+		end = len(tables)
+	}
+
+	// Copy so that callers may fill in columns without mutating cached entries
+	result := make([]dbconnector.TableInfo, end-start)
+	copy(result, tables[start:end])
+	return result, total
+}
+
+// toTableInfoResponse converts connector table metadata to its API representation
+func toTableInfoResponse(t *dbconnector.TableInfo) model.TableInfoResponse {
+	columns := make([]model.ColumnInfoResponse, len(t.Columns))
+	for i, c := range t.Columns {
+		columns[i] = model.ColumnInfoResponse{
+			Name:       c.Name,
+			Type:       c.Type,
+			Nullable:   c.Nullable,
+			PrimaryKey: c.PrimaryKey,
+			Default:    c.Default,
+			Comment:    c.Comment,
 		}
 	}
 
-	return responses, nil
+	indexes := make([]model.IndexInfoResponse, len(t.Indexes))
+	for i, idx := range t.Indexes {
+		indexes[i] = model.IndexInfoResponse{
+			Name:    idx.Name,
+			Columns: idx.Columns,
+			Unique:  idx.Unique,
+			Primary: idx.Primary,
+		}
+	}
+
+	foreignKeys := make([]model.ForeignKeyInfoResponse, len(t.ForeignKeys))
+	for i, fk := range t.ForeignKeys {
+		foreignKeys[i] = model.ForeignKeyInfoResponse{
+			Name:              fk.Name,
+			Columns:           fk.Columns,
+			ReferencedSchema:  fk.ReferencedSchema,
+			ReferencedTable:   fk.ReferencedTable,
+			ReferencedColumns: fk.ReferencedColumns,
+		}
+	}
+
+	return model.TableInfoResponse{
+		Name:        t.Name,
+		Schema:      t.Schema,
+		Type:        string(t.Type),
+		Comment:     t.Comment,
+		RowCount:    t.RowCount,
+		Columns:     columns,
+		Indexes:     indexes,
+		ForeignKeys: foreignKeys,
+	}
 }
 
 func isValidType(t string) bool {
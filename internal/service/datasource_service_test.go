@@ -8,6 +8,7 @@ import (
 	"github.com/yourusername/dataweaver/internal/model"
 	"github.com/yourusername/dataweaver/internal/repository"
 	"github.com/yourusername/dataweaver/pkg/crypto"
+	"github.com/yourusername/dataweaver/pkg/dbconnector"
 )
 
 func init() {
@@ -41,6 +42,7 @@ func TestDataSourceService_Create(t *testing.T) {
 	assert.Equal(t, 5432, result.Port)
 	assert.Equal(t, "testdb", result.Database)
 	assert.Equal(t, "active", result.Status)
+	assert.True(t, result.ReadOnly)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -243,6 +245,55 @@ func TestDataSourceService_Delete_WithAssociatedQueries(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestDataSourceService_ListSchemas_Cached(t *testing.T) {
+	mockRepo := new(repository.MockDataSourceRepository)
+	svc := NewDataSourceService(mockRepo).(*dataSourceService)
+
+	mockRepo.On("FindByIDAndUserID", "uuid-1", uint(1)).Return(&model.DataSource{ID: "uuid-1", UserID: 1}, nil)
+	svc.cache.set("uuid-1", "schemas", []string{"public", "sales"})
+
+	// Served from the cache, so no connection is attempted
+	result, err := svc.ListSchemas("uuid-1", 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.SchemaInfoResponse{{Name: "public"}, {Name: "sales"}}, result)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDataSourceService_RefreshMetadata(t *testing.T) {
+	mockRepo := new(repository.MockDataSourceRepository)
+	svc := NewDataSourceService(mockRepo).(*dataSourceService)
+
+	mockRepo.On("FindByIDAndUserID", "uuid-1", uint(1)).Return(&model.DataSource{ID: "uuid-1", UserID: 1}, nil)
+	svc.cache.set("uuid-1", "schemas", []string{"public"})
+
+	err := svc.RefreshMetadata("uuid-1", 1)
+
+	assert.NoError(t, err)
+	_, ok := svc.cache.get("uuid-1", "schemas")
+	assert.False(t, ok)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestFilterAndPageTables(t *testing.T) {
+	tables := []dbconnector.TableInfo{
+		{Name: "customers"}, {Name: "orders"}, {Name: "order_items"}, {Name: "Order_Archive"},
+	}
+
+	filtered := filterTables(tables, "ORDER")
+	assert.Len(t, filtered, 3)
+
+	page, total := pageTables(filtered, 2, 2)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, []dbconnector.TableInfo{{Name: "Order_Archive"}}, page)
+
+	page, total = pageTables(filtered, 3, 2)
+	assert.Equal(t, int64(3), total)
+	assert.Empty(t, page)
+}
+
 func TestIsValidType(t *testing.T) {
 	tests := []struct {
 		name     string
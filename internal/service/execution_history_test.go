@@ -0,0 +1,55 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/dataweaver/internal/model"
+)
+
+func TestCompareExecutions(t *testing.T) {
+	from := &model.QueryExecution{
+		ID:              "e1",
+		QueryID:         "q1",
+		QueryRevision:   2,
+		Parameters:      `{"region":"eu","limit":10,"active":true}`,
+		RowCount:        40,
+		ExecutionTimeMs: 120,
+		Status:          model.ExecutionStatusSucceeded,
+	}
+	to := &model.QueryExecution{
+		ID:              "e2",
+		QueryID:         "q1",
+		QueryRevision:   3,
+		Parameters:      `{"region":"us","limit":10,"since":"2024-01-01"}`,
+		RowCount:        55,
+		ExecutionTimeMs: 100,
+		Status:          model.ExecutionStatusFailed,
+	}
+
+	cmp := compareExecutions(from, to)
+
+	assert.True(t, cmp.SameQuery)
+	assert.True(t, cmp.RevisionChanged)
+	assert.True(t, cmp.StatusChanged)
+	assert.Equal(t, 15, cmp.RowCountDelta)
+	assert.Equal(t, int64(-20), cmp.ExecutionTimeDeltaMs)
+
+	require.Len(t, cmp.ParameterChanges, 3)
+	assert.Equal(t, model.ParameterValueChange{Name: "active", From: true, To: nil}, cmp.ParameterChanges[0])
+	assert.Equal(t, model.ParameterValueChange{Name: "region", From: "eu", To: "us"}, cmp.ParameterChanges[1])
+	assert.Equal(t, model.ParameterValueChange{Name: "since", From: nil, To: "2024-01-01"}, cmp.ParameterChanges[2])
+}
+
+func TestCompareExecutions_DifferentQueries(t *testing.T) {
+	from := &model.QueryExecution{ID: "e1", QueryID: "q1", QueryRevision: 1, Status: model.ExecutionStatusSucceeded}
+	to := &model.QueryExecution{ID: "e2", QueryID: "q2", QueryRevision: 4, Status: model.ExecutionStatusSucceeded}
+
+	cmp := compareExecutions(from, to)
+
+	assert.False(t, cmp.SameQuery)
+	assert.False(t, cmp.RevisionChanged)
+	assert.False(t, cmp.StatusChanged)
+	assert.Empty(t, cmp.ParameterChanges)
+}
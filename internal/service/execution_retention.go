@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yourusername/dataweaver/config"
+	"github.com/yourusername/dataweaver/internal/repository"
+	"github.com/yourusername/dataweaver/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Execution retention runtime settings
+const (
+	retentionInterval  = time.Hour // How often old execution records are pruned
+	retentionBatchSize = 1000      // Records deleted per statement, to keep locks short
+)
+
+// defaultRetentionDays is used when no configuration has been loaded
+const defaultRetentionDays = 90
+
+// ExecutionRetention prunes query execution records and SQL console history
+// older than the configured retention. Every instance may run it; deletes
+// are idempotent.
+type ExecutionRetention struct {
+	queryRepo   repository.QueryRepository
+	consoleRepo repository.ConsoleRepository
+
+	mu     sync.Mutex
+	ctx    context.Context
+	stop   context.CancelFunc
+	wg     sync.WaitGroup
+	closed bool
+}
+
+// NewExecutionRetention creates the execution history pruner
+func NewExecutionRetention(queryRepo repository.QueryRepository, consoleRepo repository.ConsoleRepository) *ExecutionRetention {
+	ctx, stop := context.WithCancel(context.Background())
+	return &ExecutionRetention{
+		queryRepo:   queryRepo,
+		consoleRepo: consoleRepo,
+		ctx:         ctx,
+		stop:        stop,
+	}
+}
+
+// Start prunes old records now and then hourly until Stop is called. It
+// does nothing when history is kept forever.
+func (r *ExecutionRetention) Start() {
+	if retentionDays() < 0 {
+		return
+	}
+	r.wg.Add(1)
+	go r.loop()
+}
+
+// Stop stops pruning and waits for a running prune to finish
+func (r *ExecutionRetention) Stop() {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return
+	}
+	r.closed = true
+	r.mu.Unlock()
+
+	r.stop()
+	r.wg.Wait()
+}
+
+// loop prunes on every tick
+func (r *ExecutionRetention) loop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+
+	for {
+		r.prune(time.Now().AddDate(0, 0, -retentionDays()))
+
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// prune deletes records created before cutoff
+func (r *ExecutionRetention) prune(cutoff time.Time) {
+	r.pruneRecords("query executions", cutoff, r.queryRepo.DeleteExecutionsBefore)
+	r.pruneRecords("console statements", cutoff, r.consoleRepo.DeleteBefore)
+}
+
+// pruneRecords deletes one kind of record, a batch at a time
+func (r *ExecutionRetention) pruneRecords(kind string, cutoff time.Time, deleteBefore func(time.Time, int) (int64, error)) {
+	var total int64
+	for r.ctx.Err() == nil {
+		deleted, err := deleteBefore(cutoff, retentionBatchSize)
+		if err != nil {
+			logger.Warn("Failed to prune "+kind, zap.Error(err))
+			return
+		}
+		total += deleted
+		if deleted < retentionBatchSize {
+			break
+		}
+	}
+	if total > 0 {
+		logger.Info("Pruned "+kind, zap.Int64("deleted", total), zap.Time("before", cutoff))
+	}
+}
+
+// retentionDays returns the configured execution retention in days
+func retentionDays() int {
+	if config.AppConfig == nil {
+		return defaultRetentionDays
+	}
+	return config.AppConfig.History.RetentionDays
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/internal/repository"
+)
+
+// FolderService handles business logic for query folders
+type FolderService interface {
+	List(userID uint) ([]model.FolderResponse, error)
+	Create(userID uint, req *model.CreateFolderRequest) (*model.FolderResponse, error)
+	Update(id string, userID uint, req *model.UpdateFolderRequest) (*model.FolderResponse, error)
+	// Delete deletes a folder, moving its subfolders and queries to its parent
+	Delete(id string, userID uint) error
+}
+
+type folderService struct {
+	folderRepo repository.FolderRepository
+}
+
+// NewFolderService creates a new FolderService
+func NewFolderService(folderRepo repository.FolderRepository) FolderService {
+	return &folderService{folderRepo: folderRepo}
+}
+
+// List returns all folders of a user, ordered by path
+func (s *folderService) List(userID uint) ([]model.FolderResponse, error) {
+	folders, err := s.folderRepo.FindAll(userID)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.folderRepo.CountQueries(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := folderIndex(folders)
+	responses := make([]model.FolderResponse, len(folders))
+	for i := range folders {
+		responses[i] = *toFolderResponse(&folders[i], byID)
+		responses[i].QueryCount = counts[folders[i].ID]
+	}
+	sortFolders(responses)
+	return responses, nil
+}
+
+// Create creates a folder
+func (s *folderService) Create(userID uint, req *model.CreateFolderRequest) (*model.FolderResponse, error) {
+	folders, err := s.folderRepo.FindAll(userID)
+	if err != nil {
+		return nil, err
+	}
+	byID := folderIndex(folders)
+
+	parentID := req.ParentID
+	if parentID != nil && *parentID == "" {
+		parentID = nil
+	}
+	if parentID != nil && byID[*parentID] == nil {
+		return nil, repository.ErrFolderNotFound
+	}
+	name := strings.TrimSpace(req.Name)
+	if siblingNamed(folders, parentID, name, "") {
+		return nil, ErrFolderNameExists
+	}
+
+	folder := &model.QueryFolder{
+		UserID:   userID,
+		ParentID: parentID,
+		Name:     name,
+	}
+	if err := s.folderRepo.Create(folder); err != nil {
+		return nil, err
+	}
+	byID[folder.ID] = folder
+	return toFolderResponse(folder, byID), nil
+}
+
+// Update renames a folder or moves it under another folder
+func (s *folderService) Update(id string, userID uint, req *model.UpdateFolderRequest) (*model.FolderResponse, error) {
+	folders, err := s.folderRepo.FindAll(userID)
+	if err != nil {
+		return nil, err
+	}
+	byID := folderIndex(folders)
+
+	folder := byID[id]
+	if folder == nil {
+		return nil, repository.ErrFolderNotFound
+	}
+
+	if req.Name != nil {
+		folder.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.ParentID != nil {
+		if *req.ParentID == "" {
+			folder.ParentID = nil
+		} else {
+			if byID[*req.ParentID] == nil {
+				return nil, repository.ErrFolderNotFound
+			}
+			if isFolderWithin(byID, *req.ParentID, folder.ID) {
+				return nil, ErrFolderCycle
+			}
+			parentID := *req.ParentID
+			folder.ParentID = &parentID
+		}
+	}
+	if siblingNamed(folders, folder.ParentID, folder.Name, folder.ID) {
+		return nil, ErrFolderNameExists
+	}
+
+	if err := s.folderRepo.Update(folder); err != nil {
+		return nil, err
+	}
+	return toFolderResponse(folder, byID), nil
+}
+
+// Delete deletes a folder
+func (s *folderService) Delete(id string, userID uint) error {
+	folder, err := s.folderRepo.FindByIDAndUserID(id, userID)
+	if err != nil {
+		return err
+	}
+	return s.folderRepo.Delete(folder)
+}
+
+// folderIndex indexes folders by ID
+func folderIndex(folders []model.QueryFolder) map[string]*model.QueryFolder {
+	byID := make(map[string]*model.QueryFolder, len(folders))
+	for i := range folders {
+		byID[folders[i].ID] = &folders[i]
+	}
+	return byID
+}
+
+// isFolderWithin reports whether folder id is ancestor or one of its
+// subfolders
+func isFolderWithin(byID map[string]*model.QueryFolder, id, ancestor string) bool {
+	// Bounded by the number of folders in case stored parents already loop
+	for i := 0; i <= len(byID); i++ {
+		if id == ancestor {
+			return true
+		}
+		folder := byID[id]
+		if folder == nil || folder.ParentID == nil {
+			return false
+		}
+		id = *folder.ParentID
+	}
+	return false
+}
+
+// folderPath joins the names of a folder and its parents from the top level
+func folderPath(byID map[string]*model.QueryFolder, folder *model.QueryFolder) string {
+	names := []string{folder.Name}
+	for i := 0; folder.ParentID != nil && i < len(byID); i++ {
+		folder = byID[*folder.ParentID]
+		if folder == nil {
+			break
+		}
+		names = append([]string{folder.Name}, names...)
+	}
+	return strings.Join(names, "/")
+}
+
+// siblingNamed reports whether another folder under parentID has the name
+func siblingNamed(folders []model.QueryFolder, parentID *string, name, exceptID string) bool {
+	for _, f := range folders {
+		if f.ID == exceptID || !strings.EqualFold(f.Name, name) {
+			continue
+		}
+		if (f.ParentID == nil && parentID == nil) ||
+			(f.ParentID != nil && parentID != nil && *f.ParentID == *parentID) {
+			return true
+		}
+	}
+	return false
+}
+
+// toFolderResponse converts a folder to a response with its path
+func toFolderResponse(folder *model.QueryFolder, byID map[string]*model.QueryFolder) *model.FolderResponse {
+	return &model.FolderResponse{
+		ID:        folder.ID,
+		ParentID:  folder.ParentID,
+		Name:      folder.Name,
+		Path:      folderPath(byID, folder),
+		CreatedAt: folder.CreatedAt,
+		UpdatedAt: folder.UpdatedAt,
+	}
+}
+
+// sortFolders orders folders by path, so subfolders follow their parent
+func sortFolders(folders []model.FolderResponse) {
+	sort.SliceStable(folders, func(i, j int) bool {
+		return strings.ToLower(folders[i].Path) < strings.ToLower(folders[j].Path)
+	})
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/internal/repository"
+)
+
+// ListReleases returns the releases of a server, newest first
+func (s *mcpServerService) ListReleases(serverID string, userID uint, page, size int) ([]model.McpServerReleaseResponse, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 20
+	}
+
+	server, err := s.mcpRepo.FindByIDAndUserID(serverID, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	releases, total, err := s.mcpRepo.FindReleases(serverID, page, size)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]model.McpServerReleaseResponse, len(releases))
+	for i, release := range releases {
+		responses[i] = *release.ToResponse(server.ActiveReleaseID)
+	}
+	return responses, total, nil
+}
+
+// GetRelease returns one release of a server
+func (s *mcpServerService) GetRelease(serverID string, userID uint, releaseID string) (*model.McpServerReleaseResponse, error) {
+	server, err := s.mcpRepo.FindByIDAndUserID(serverID, userID)
+	if err != nil {
+		return nil, err
+	}
+	release, err := s.mcpRepo.FindRelease(serverID, releaseID)
+	if err != nil {
+		return nil, err
+	}
+	return release.ToResponse(server.ActiveReleaseID), nil
+}
+
+// CompareReleases compares the tools of two releases of a server. An empty
+// toID compares against the active release.
+func (s *mcpServerService) CompareReleases(serverID string, userID uint, fromID, toID string) (*model.McpServerReleaseDiff, error) {
+	server, err := s.mcpRepo.FindByIDAndUserID(serverID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if toID == "" {
+		if server.ActiveReleaseID == nil {
+			return nil, repository.ErrMcpReleaseNotFound
+		}
+		toID = *server.ActiveReleaseID
+	}
+
+	from, err := s.mcpRepo.FindRelease(serverID, fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.mcpRepo.FindRelease(serverID, toID)
+	if err != nil {
+		return nil, err
+	}
+	return compareReleases(from, to), nil
+}
+
+// Rollback makes an earlier release the one the runtime serves. Tools and
+// queries are not changed; publishing again snapshots their current state.
+func (s *mcpServerService) Rollback(serverID string, userID uint, releaseID string) (*model.McpServerResponse, error) {
+	server, err := s.mcpRepo.FindByIDAndUserID(serverID, userID)
+	if err != nil {
+		return nil, err
+	}
+	release, err := s.mcpRepo.FindRelease(serverID, releaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	server.ActiveReleaseID = &release.ID
+	server.Version = release.Version
+	if err := s.mcpRepo.Update(server); err != nil {
+		return nil, err
+	}
+
+	server.Tools = s.loadTools([]string(server.ToolIDs), userID)
+	return server.ToResponse(), nil
+}
+
+// activeRelease loads the release the runtime serves, or nil for servers
+// published before releases were recorded
+func (s *mcpServerService) activeRelease(server *model.McpServer) (*model.McpServerRelease, error) {
+	if server.ActiveReleaseID == nil {
+		return nil, nil
+	}
+	release, err := s.mcpRepo.FindRelease(server.ID, *server.ActiveReleaseID)
+	if errors.Is(err, repository.ErrMcpReleaseNotFound) {
+		return nil, nil
+	}
+	return release, err
+}
+
+// compareReleases lists the tools added, removed and changed between two
+// releases, in the order of the newer release
+func compareReleases(from, to *model.McpServerRelease) *model.McpServerReleaseDiff {
+	diff := &model.McpServerReleaseDiff{
+		McpServerID:   to.McpServerID,
+		FromReleaseID: from.ID,
+		FromVersion:   from.Version,
+		ToReleaseID:   to.ID,
+		ToVersion:     to.Version,
+		Tools:         []model.ReleaseToolChange{},
+	}
+
+	for i := range to.Tools {
+		newTool := &to.Tools[i]
+		oldTool := from.FindTool(newTool.Name)
+		if oldTool == nil {
+			diff.Tools = append(diff.Tools, model.ReleaseToolChange{Name: newTool.Name, Change: "added"})
+			continue
+		}
+		if change := compareReleaseTools(oldTool, newTool); change != nil {
+			diff.Tools = append(diff.Tools, *change)
+		}
+	}
+	for i := range from.Tools {
+		if to.FindTool(from.Tools[i].Name) == nil {
+			diff.Tools = append(diff.Tools, model.ReleaseToolChange{Name: from.Tools[i].Name, Change: "removed"})
+		}
+	}
+	return diff
+}
+
+// compareReleaseTools returns the changes to one tool, or nil if it is the same
+func compareReleaseTools(from, to *model.ReleaseTool) *model.ReleaseToolChange {
+	var fields []model.FieldChange
+	add := func(field, a, b string) {
+		if a != b {
+			fields = append(fields, model.FieldChange{Field: field, From: a, To: b})
+		}
+	}
+	asJSON := func(v interface{}) string {
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+
+	add("display_name", from.DisplayName, to.DisplayName)
+	add("description", from.Description, to.Description)
+	add("parameters", asJSON(from.Parameters), asJSON(to.Parameters))
+	add("output_schema", asJSON(from.OutputSchema), asJSON(to.OutputSchema))
+	add("max_rows", strconv.Itoa(from.MaxRows), strconv.Itoa(to.MaxRows))
+	add("cache_ttl", strconv.Itoa(from.CacheTTL), strconv.Itoa(to.CacheTTL))
+	add("cost_guard", asJSON(from.CostGuard), asJSON(to.CostGuard))
+	add("schedule_id", asJSON(from.ScheduleID), asJSON(to.ScheduleID))
+	add("query_id", from.QueryID, to.QueryID)
+	add("query_revision", strconv.Itoa(from.QueryRevision), strconv.Itoa(to.QueryRevision))
+	add("data_source_id", from.DataSourceID, to.DataSourceID)
+	add("query_parameters", asJSON(from.QueryParameters), asJSON(to.QueryParameters))
+
+	sqlChanged := from.SQLTemplate != to.SQLTemplate
+	if len(fields) == 0 && !sqlChanged {
+		return nil
+	}
+
+	change := &model.ReleaseToolChange{Name: to.Name, Change: "changed", Fields: fields}
+	if sqlChanged {
+		change.SQL = diffLines(from.SQLTemplate, to.SQLTemplate)
+	}
+	return change
+}
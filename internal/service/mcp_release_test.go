@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/dataweaver/internal/model"
+)
+
+func TestCompareReleases(t *testing.T) {
+	from := &model.McpServerRelease{
+		ID:          "r1",
+		McpServerID: "s1",
+		Version:     "1.0.1",
+		Tools: model.ReleaseTools{
+			{Name: "list_users", Description: "List users", MaxRows: 100, QueryRevision: 1, SQLTemplate: "SELECT id\nFROM users"},
+			{Name: "count_orders", QueryRevision: 1, SQLTemplate: "SELECT COUNT(*) FROM orders"},
+		},
+	}
+	to := &model.McpServerRelease{
+		ID:          "r2",
+		McpServerID: "s1",
+		Version:     "1.0.2",
+		Tools: model.ReleaseTools{
+			{Name: "list_users", Description: "List active users", MaxRows: 100, QueryRevision: 2, SQLTemplate: "SELECT id\nFROM users\nWHERE active"},
+			{Name: "get_user", QueryRevision: 1, SQLTemplate: "SELECT * FROM users WHERE id = :id"},
+		},
+	}
+
+	diff := compareReleases(from, to)
+	assert.Equal(t, "1.0.1", diff.FromVersion)
+	assert.Equal(t, "1.0.2", diff.ToVersion)
+	require.Len(t, diff.Tools, 3)
+
+	changed := diff.Tools[0]
+	assert.Equal(t, "list_users", changed.Name)
+	assert.Equal(t, "changed", changed.Change)
+	assert.Equal(t, []model.FieldChange{
+		{Field: "description", From: "List users", To: "List active users"},
+		{Field: "query_revision", From: "1", To: "2"},
+	}, changed.Fields)
+	assert.Equal(t, []model.DiffLine{
+		{Op: " ", Text: "SELECT id"},
+		{Op: " ", Text: "FROM users"},
+		{Op: "+", Text: "WHERE active"},
+	}, changed.SQL)
+
+	assert.Equal(t, model.ReleaseToolChange{Name: "get_user", Change: "added"}, diff.Tools[1])
+	assert.Equal(t, model.ReleaseToolChange{Name: "count_orders", Change: "removed"}, diff.Tools[2])
+
+	assert.Empty(t, compareReleases(to, to).Tools)
+}
@@ -1,8 +1,10 @@
 package service
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"sync"
 	"time"
 
@@ -27,11 +29,16 @@ var (
 // McpServerService handles business logic for MCP servers
 type McpServerService interface {
 	Create(userID uint, req *model.CreateMcpServerRequest) (*model.McpServerResponse, error)
-	List(userID uint, page, size int, keyword string) ([]model.McpServerResponse, int64, error)
+	List(userID uint, page, size int, filter *model.ListFilter) ([]model.McpServerResponse, int64, error)
 	Get(id string, userID uint) (*model.McpServerResponse, error)
 	Update(id string, userID uint, req *model.UpdateMcpServerRequest) (*model.McpServerResponse, error)
 	Delete(id string, userID uint) error
 
+	// Tags, favorites and bulk actions
+	SetTags(id string, userID uint, tags []string) (*model.McpServerResponse, error)
+	SetFavorite(id string, userID uint, favorite bool) error
+	Bulk(userID uint, req *model.BulkRequest) (*model.BulkResult, error)
+
 	// Publishing
 	Publish(id string, userID uint, baseURL string) (*model.PublishMcpServerResponse, error)
 	Unpublish(id string, userID uint) error
@@ -44,6 +51,15 @@ type McpServerService interface {
 	// Statistics
 	GetStatistics(serverID string, userID uint, days int) (*analytics.Statistics, error)
 
+	// Releases
+	ListReleases(serverID string, userID uint, page, size int) ([]model.McpServerReleaseResponse, int64, error)
+	GetRelease(serverID string, userID uint, releaseID string) (*model.McpServerReleaseResponse, error)
+	CompareReleases(serverID string, userID uint, fromID, toID string) (*model.McpServerReleaseDiff, error)
+	Rollback(serverID string, userID uint, releaseID string) (*model.McpServerResponse, error)
+
+	// Result cache
+	PurgeCache(serverID string, userID uint) (*model.CachePurgeResponse, error)
+
 	// Runtime operations
 	GetServerByApiKey(apiKey string) (*model.McpServer, error)
 	GetServerTools(serverID string) ([]model.Tool, error)
@@ -51,12 +67,15 @@ type McpServerService interface {
 }
 
 type mcpServerService struct {
-	mcpRepo    repository.McpServerRepository
-	toolRepo   repository.ToolRepository
-	queryRepo  repository.QueryRepository
-	dsRepo     repository.DataSourceRepository
-	logChannel chan *model.McpLog
-	logWg      sync.WaitGroup
+	mcpRepo      repository.McpServerRepository
+	toolRepo     repository.ToolRepository
+	queryRepo    repository.QueryRepository
+	dsRepo       repository.DataSourceRepository
+	scheduleRepo repository.QueryScheduleRepository
+	cache        *ResultCache
+	tagRepo      repository.TagRepository
+	logChannel   chan *model.McpLog
+	logWg        sync.WaitGroup
 }
 
 // NewMcpServerService creates a new McpServerService
@@ -65,13 +84,19 @@ func NewMcpServerService(
 	toolRepo repository.ToolRepository,
 	queryRepo repository.QueryRepository,
 	dsRepo repository.DataSourceRepository,
+	scheduleRepo repository.QueryScheduleRepository,
+	cache *ResultCache,
+	tagRepo repository.TagRepository,
 ) McpServerService {
 	svc := &mcpServerService{
-		mcpRepo:    mcpRepo,
-		toolRepo:   toolRepo,
-		queryRepo:  queryRepo,
-		dsRepo:     dsRepo,
-		logChannel: make(chan *model.McpLog, 1000),
+		mcpRepo:      mcpRepo,
+		toolRepo:     toolRepo,
+		queryRepo:    queryRepo,
+		dsRepo:       dsRepo,
+		scheduleRepo: scheduleRepo,
+		cache:        cache,
+		tagRepo:      tagRepo,
+		logChannel:   make(chan *model.McpLog, 1000),
 	}
 
 	// Start async log writer
@@ -138,8 +163,8 @@ func (s *mcpServerService) Create(userID uint, req *model.CreateMcpServerRequest
 	return server.ToResponse(), nil
 }
 
-// List returns all MCP servers for a user
-func (s *mcpServerService) List(userID uint, page, size int, keyword string) ([]model.McpServerResponse, int64, error) {
+// List returns the MCP servers of a user matching a filter
+func (s *mcpServerService) List(userID uint, page, size int, filter *model.ListFilter) ([]model.McpServerResponse, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -147,16 +172,7 @@ func (s *mcpServerService) List(userID uint, page, size int, keyword string) ([]
 		size = 20
 	}
 
-	var servers []model.McpServer
-	var total int64
-	var err error
-
-	if keyword != "" {
-		servers, total, err = s.mcpRepo.Search(userID, keyword, page, size)
-	} else {
-		servers, total, err = s.mcpRepo.FindAll(userID, page, size)
-	}
-
+	servers, total, err := s.mcpRepo.List(userID, normalizeListFilter(filter), page, size)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -165,6 +181,9 @@ func (s *mcpServerService) List(userID uint, page, size int, keyword string) ([]
 	for i, srv := range servers {
 		responses[i] = *srv.ToResponse()
 	}
+	if err := s.labelServers(userID, responses); err != nil {
+		return nil, 0, err
+	}
 
 	return responses, total, nil
 }
@@ -179,7 +198,17 @@ func (s *mcpServerService) Get(id string, userID uint) (*model.McpServerResponse
 	// Load tools
 	server.Tools = s.loadTools([]string(server.ToolIDs), userID)
 
-	return server.ToResponse(), nil
+	return s.toLabeledResponse(server)
+}
+
+// toLabeledResponse converts a server to a response with its tags and
+// favorite flag
+func (s *mcpServerService) toLabeledResponse(server *model.McpServer) (*model.McpServerResponse, error) {
+	responses := []model.McpServerResponse{*server.ToResponse()}
+	if err := s.labelServers(server.UserID, responses); err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
 }
 
 // Update updates an MCP server
@@ -226,12 +255,15 @@ func (s *mcpServerService) Update(id string, userID uint, req *model.UpdateMcpSe
 	// Load tools for response
 	server.Tools = s.loadTools([]string(server.ToolIDs), userID)
 
-	return server.ToResponse(), nil
+	return s.toLabeledResponse(server)
 }
 
 // Delete deletes an MCP server
 func (s *mcpServerService) Delete(id string, userID uint) error {
-	return s.mcpRepo.Delete(id, userID)
+	if err := s.mcpRepo.Delete(id, userID); err != nil {
+		return err
+	}
+	return s.tagRepo.DeleteResource(model.ResourceMcpServer, id)
 }
 
 // Publish publishes an MCP server
@@ -246,7 +278,12 @@ func (s *mcpServerService) Publish(id string, userID uint, baseURL string) (*mod
 		return nil, ErrNoToolsToPublish
 	}
 
-	// Validate all tools are still available
+	// Resolve every tool and the query revision it runs into a release
+	release := &model.McpServerRelease{
+		McpServerID: server.ID,
+		UserID:      userID,
+		Tools:       make(model.ReleaseTools, 0, len(server.ToolIDs)),
+	}
 	for _, toolID := range server.ToolIDs {
 		tool, err := s.toolRepo.FindByIDAndUserID(toolID, userID)
 		if err != nil {
@@ -255,6 +292,14 @@ func (s *mcpServerService) Publish(id string, userID uint, baseURL string) (*mod
 		if tool.Status != "active" {
 			return nil, fmt.Errorf("tool %s is not active", tool.Name)
 		}
+		query, err := s.queryRepo.FindByIDAndUserID(tool.QueryID, userID)
+		if err == nil {
+			query, err = queryAtRevision(s.queryRepo, query, tool.PinnedRevision())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("query of tool %s is not available: %w", tool.Name, err)
+		}
+		release.Tools = append(release.Tools, model.NewReleaseTool(tool, query))
 	}
 
 	// Generate endpoint and API key if not already set
@@ -269,11 +314,18 @@ func (s *mcpServerService) Publish(id string, userID uint, baseURL string) (*mod
 		server.ApiKey = apiKey
 	}
 
+	// Versions continue from the newest release, even after a rollback
+	version := server.Version
+	if latest, err := s.mcpRepo.FindLatestRelease(server.ID); err == nil {
+		version = latest.Version
+	}
+
 	// Update status
 	server.Status = string(model.McpServerStatusPublished)
-	server.Version = incrementVersion(server.Version)
+	server.Version = incrementVersion(version)
+	release.Version = server.Version
 
-	if err := s.mcpRepo.Update(server); err != nil {
+	if err := s.mcpRepo.PublishRelease(server, release); err != nil {
 		return nil, err
 	}
 
@@ -493,6 +545,19 @@ func (s *mcpServerService) GetServerTools(serverID string) ([]model.Tool, error)
 		return nil, err
 	}
 
+	// Serve the tools as they were published
+	release, err := s.activeRelease(server)
+	if err != nil {
+		return nil, err
+	}
+	if release != nil {
+		tools := make([]model.Tool, len(release.Tools))
+		for i := range release.Tools {
+			tools[i] = *release.Tools[i].Tool()
+		}
+		return tools, nil
+	}
+
 	tools := make([]model.Tool, 0, len(server.ToolIDs))
 	for _, toolID := range server.ToolIDs {
 		tool, err := s.toolRepo.FindByID(toolID)
@@ -512,16 +577,28 @@ func (s *mcpServerService) ExecuteTool(serverID, toolName string, params map[str
 		return nil, nil, err
 	}
 
-	// Find the tool by name
+	release, err := s.activeRelease(server)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Find the tool by name, in the active release when there is one
 	var tool *model.Tool
-	for _, toolID := range server.ToolIDs {
-		t, err := s.toolRepo.FindByID(toolID)
-		if err != nil {
-			continue
+	var released *model.ReleaseTool
+	if release != nil {
+		if released = release.FindTool(toolName); released != nil {
+			tool = released.Tool()
 		}
-		if t.Name == toolName {
-			tool = t
-			break
+	} else {
+		for _, toolID := range server.ToolIDs {
+			t, err := s.toolRepo.FindByID(toolID)
+			if err != nil {
+				continue
+			}
+			if t.Name == toolName {
+				tool = t
+				break
+			}
 		}
 	}
 
@@ -541,8 +618,16 @@ func (s *mcpServerService) ExecuteTool(serverID, toolName string, params map[str
 
 	start := time.Now()
 
-	// Get the query
-	query, err := s.queryRepo.FindByID(tool.QueryID)
+	// Get the query: the released definition, or the pinned revision
+	var query *model.Query
+	if released != nil {
+		query = released.Query()
+	} else {
+		query, err = s.queryRepo.FindByID(tool.QueryID)
+		if err == nil {
+			query, err = queryAtRevision(s.queryRepo, query, tool.PinnedRevision())
+		}
+	}
 	if err != nil {
 		log.Status = string(model.McpLogStatusError)
 		log.ErrorMessage = fmt.Sprintf("Query not found: %v", err)
@@ -553,8 +638,12 @@ func (s *mcpServerService) ExecuteTool(serverID, toolName string, params map[str
 		}, log, nil
 	}
 
-	// Validate parameters
-	if err := sqlparser.ValidateParameters(query.SQLTemplate, params); err != nil {
+	// Validate parameters against the tool definition and the SQL template
+	err = validateToolParameters(tool.Parameters, params)
+	if err == nil {
+		err = sqlparser.ValidateParameters(query.SQLTemplate, params)
+	}
+	if err != nil {
 		log.Status = string(model.McpLogStatusError)
 		log.ErrorMessage = fmt.Sprintf("Parameter validation failed: %v", err)
 		log.ResponseTimeMs = time.Since(start).Milliseconds()
@@ -564,6 +653,21 @@ func (s *mcpServerService) ExecuteTool(serverID, toolName string, params map[str
 		}, log, nil
 	}
 
+	// Answer from the latest snapshot of the tool's schedule without touching
+	// the database when the call asks for what the schedule ran; calls with
+	// other arguments, or for another revision of the query, run live
+	if tool.ScheduleID != nil {
+		schedule, err := s.scheduleRepo.FindByID(*tool.ScheduleID)
+		if err == nil && snapshotAnswers(schedule, tool.Parameters, query, params) {
+			snapshot, err := s.scheduleRepo.FindLatestSnapshot(schedule.ID)
+			if err == nil && snapshot.QueryRevision == query.Revision {
+				return answerFromSnapshot(snapshot, log, start), log, nil
+			}
+		}
+	}
+
+	limits := resultLimits(tool.MaxRows, server.Config.MaxRows)
+
 	// Get DataSource
 	ds, err := s.dsRepo.FindByID(query.DataSourceID)
 	if err != nil {
@@ -576,6 +680,29 @@ func (s *mcpServerService) ExecuteTool(serverID, toolName string, params map[str
 		}, log, nil
 	}
 
+	// Answer from the result cache while a result is fresh
+	var cacheKey string
+	var cacheTTL time.Duration
+	if server.Config.EnableCaching {
+		cacheTTL = s.cache.TTL(tool.CacheTTL)
+	}
+	if cacheTTL > 0 {
+		cacheKey = resultCacheKey(query, ds, tool.Parameters, params, limits)
+		if cached, ok := s.cache.Get(cacheKey); ok {
+			log.CacheHit = true
+			log.RowCount = len(cached.Data)
+			log.ResponseTimeMs = time.Since(start).Milliseconds()
+			from := fmt.Sprintf("Cached result from %s.", cached.CachedAt.UTC().Format(time.RFC3339))
+			return &model.McpToolCallResult{
+				Content: []model.McpContent{
+					{Type: "text", Text: from},
+					{Type: "text", Text: formatQueryResult(cached.QueryResult())},
+				},
+				IsError: false,
+			}, log, nil
+		}
+	}
+
 	// Decrypt password
 	password, err := crypto.Decrypt(ds.Password)
 	if err != nil {
@@ -597,6 +724,7 @@ func (s *mcpServerService) ExecuteTool(serverID, toolName string, params map[str
 		Password: password,
 		Database: ds.Database,
 		SSLMode:  ds.SSLMode,
+		ReadOnly: ds.IsReadOnly(),
 	}
 
 	connector := dbconnector.NewConnector(config)
@@ -611,8 +739,27 @@ func (s *mcpServerService) ExecuteTool(serverID, toolName string, params map[str
 	}
 	defer connector.Close()
 
+	// The plan and the query share the execution timeout
+	ctx, cancel := executionContext(server.Config.TimeoutSeconds)
+	defer cancel()
+
+	// Refuse or warn about queries the optimizer expects to be too expensive
+	var warning string
+	if reason := checkCostGuard(ctx, connector, tool.CostGuard, query.SQLTemplate, params); reason != "" {
+		if tool.CostGuard.Mode == model.CostGuardReject {
+			log.Status = string(model.McpLogStatusError)
+			log.ErrorMessage = fmt.Sprintf("Query refused by cost guardrail: %s. Call the tool with more selective arguments.", reason)
+			log.ResponseTimeMs = time.Since(start).Milliseconds()
+			return &model.McpToolCallResult{
+				Content: []model.McpContent{{Type: "text", Text: log.ErrorMessage}},
+				IsError: true,
+			}, log, nil
+		}
+		warning = fmt.Sprintf("Warning: cost guardrail: %s.", reason)
+	}
+
 	// Execute query
-	result, err := connector.ExecuteQueryWithColumns(query.SQLTemplate, params)
+	result, err := connector.ExecuteQueryWithLimitsContext(ctx, query.SQLTemplate, params, limits)
 	log.ResponseTimeMs = time.Since(start).Milliseconds()
 
 	if err != nil {
@@ -626,17 +773,90 @@ func (s *mcpServerService) ExecuteTool(serverID, toolName string, params map[str
 
 	log.RowCount = len(result.Data)
 
+	if cacheTTL > 0 {
+		s.cache.Set(cacheKey, query.ID, result, cacheTTL)
+	}
+
 	// Format result as JSON text
 	resultText := formatQueryResult(result)
 
+	content := []model.McpContent{{Type: "text", Text: resultText}}
+	if warning != "" {
+		content = append([]model.McpContent{{Type: "text", Text: warning}}, content...)
+	}
+
 	return &model.McpToolCallResult{
-		Content: []model.McpContent{{Type: "text", Text: resultText}},
+		Content: content,
 		IsError: false,
 	}, log, nil
 }
 
+// PurgeCache removes the cached results of the queries a server's tools run,
+// both in its draft and in the release the runtime serves
+func (s *mcpServerService) PurgeCache(serverID string, userID uint) (*model.CachePurgeResponse, error) {
+	server, err := s.mcpRepo.FindByIDAndUserID(serverID, userID)
+	if err != nil {
+		return nil, err
+	}
+	release, err := s.activeRelease(server)
+	if err != nil {
+		return nil, err
+	}
+
+	var queryIDs []string
+	for _, tool := range s.loadTools([]string(server.ToolIDs), userID) {
+		if !containsString(queryIDs, tool.QueryID) {
+			queryIDs = append(queryIDs, tool.QueryID)
+		}
+	}
+	if release != nil {
+		for _, tool := range release.Tools {
+			if !containsString(queryIDs, tool.QueryID) {
+				queryIDs = append(queryIDs, tool.QueryID)
+			}
+		}
+	}
+
+	return s.cache.Purge(queryIDs)
+}
+
 // Helper functions
 
+// answerFromSnapshot answers a tool call with the latest successful snapshot
+// of a schedule
+func answerFromSnapshot(snapshot *model.QuerySnapshot, log *model.McpLog, start time.Time) *model.McpToolCallResult {
+	log.ResponseTimeMs = time.Since(start).Milliseconds()
+	log.RowCount = snapshot.RowCount
+	result := &dbconnector.QueryResult{
+		Columns:   snapshot.Columns,
+		Data:      []map[string]interface{}(snapshot.Rows),
+		Truncated: snapshot.Truncated,
+	}
+	taken := fmt.Sprintf("Answered from the snapshot taken at %s.", snapshot.CreatedAt.UTC().Format(time.RFC3339))
+	return &model.McpToolCallResult{
+		Content: []model.McpContent{
+			{Type: "text", Text: taken},
+			{Type: "text", Text: formatQueryResult(result)},
+		},
+		IsError: false,
+	}
+}
+
+// snapshotAnswers reports whether the snapshots of a schedule answer a call
+// with params: the schedule runs the query, and the query takes no
+// parameters or params are the schedule's once coerced to the tool's types
+func snapshotAnswers(schedule *model.QuerySchedule, toolParams model.ToolParameters, query *model.Query, params map[string]interface{}) bool {
+	if schedule.QueryID != query.ID {
+		return false
+	}
+	if len(sqlparser.ExtractParametersWithInfo(query.SQLTemplate)) == 0 {
+		return true
+	}
+	want := coerceToolParameters(toolParams, schedule.Parameters)
+	got := coerceToolParameters(toolParams, params)
+	return reflect.DeepEqual(want, got)
+}
+
 // loadTools loads tools by IDs for a user
 func (s *mcpServerService) loadTools(toolIDs []string, userID uint) []model.Tool {
 	tools := make([]model.Tool, 0, len(toolIDs))
@@ -658,20 +878,45 @@ func incrementVersion(version string) string {
 	return fmt.Sprintf("%d.%d.%d", major, minor, patch+1)
 }
 
-// formatQueryResult formats query result as readable text
+// formatQueryResult formats query result as readable text, with a section
+// per result set when the statement returned several
 func formatQueryResult(result *dbconnector.QueryResult) string {
-	if len(result.Data) == 0 {
+	if len(result.ResultSets) == 0 {
+		return formatRows(result.Columns, result.Data, result.Truncated)
+	}
+
+	// Result sets after a limit was hit are dropped, so only the last one
+	// can be truncated
+	text := fmt.Sprintf("Returned %d result sets.\n\nResult set 1:\n%s", len(result.ResultSets)+1, formatRows(result.Columns, result.Data, false))
+	for i, set := range result.ResultSets {
+		text += fmt.Sprintf("\nResult set %d:\n%s", i+2, formatRows(set.Columns, set.Data, set.Truncated))
+	}
+	return text
+}
+
+// formatRows formats the rows of one result set as readable text
+func formatRows(columns []string, data []map[string]interface{}, truncated bool) string {
+	if len(data) == 0 {
 		return "No results found."
 	}
 
 	// Format as JSON-like text
-	text := fmt.Sprintf("Found %d rows.\n\nColumns: %v\n\nData:\n", len(result.Data), result.Columns)
-	for i, row := range result.Data {
+	text := fmt.Sprintf("Found %d rows.\n\nColumns: %v\n\nData:\n", len(data), columns)
+	if truncated {
+		text = fmt.Sprintf("Found %d rows (truncated, more rows matched than the result limit allows).\n\nColumns: %v\n\nData:\n", len(data), columns)
+	}
+	for i, row := range data {
 		if i >= 100 {
-			text += fmt.Sprintf("... and %d more rows\n", len(result.Data)-100)
+			text += fmt.Sprintf("... and %d more rows\n", len(data)-100)
 			break
 		}
-		text += fmt.Sprintf("%d: %v\n", i+1, row)
+		// Encode rows as JSON so typed values (decimals, documents, binary) read naturally
+		rowJSON, err := json.Marshal(row)
+		if err != nil {
+			text += fmt.Sprintf("%d: %v\n", i+1, row)
+			continue
+		}
+		text += fmt.Sprintf("%d: %s\n", i+1, rowJSON)
 	}
 	return text
 }
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/dataweaver/internal/model"
+)
+
+func TestSnapshotAnswers(t *testing.T) {
+	schedule := &model.QuerySchedule{QueryID: "q1", Parameters: model.ScheduleParameters{"region": "eu", "limit": float64(10)}}
+	query := &model.Query{ID: "q1", SQLTemplate: "SELECT * FROM sales WHERE region = :region LIMIT :limit"}
+	params := model.ToolParameters{{Name: "region", Type: "string"}, {Name: "limit", Type: "integer"}}
+
+	assert.True(t, snapshotAnswers(schedule, params, query, map[string]interface{}{"limit": float64(10), "region": "eu"}))
+	assert.False(t, snapshotAnswers(schedule, params, query, map[string]interface{}{"region": "us", "limit": float64(10)}))
+	assert.False(t, snapshotAnswers(schedule, params, query, map[string]interface{}{"region": "eu"}))
+	assert.False(t, snapshotAnswers(schedule, params, query, nil))
+
+	// Arguments are compared as the tool's types
+	assert.True(t, snapshotAnswers(schedule, params, query, map[string]interface{}{"limit": 10, "region": "eu"}))
+	assert.True(t, snapshotAnswers(schedule, params, query, map[string]interface{}{"limit": "10", "region": "eu"}))
+	assert.False(t, snapshotAnswers(schedule, params, query, map[string]interface{}{"limit": 10.5, "region": "eu"}))
+
+	// A schedule of another query never answers
+	other := &model.Query{ID: "q2", SQLTemplate: query.SQLTemplate}
+	assert.False(t, snapshotAnswers(schedule, params, other, map[string]interface{}{"limit": float64(10), "region": "eu"}))
+
+	// A query without parameters is answered whatever the arguments
+	plain := &model.Query{ID: "q1", SQLTemplate: "SELECT * FROM sales"}
+	assert.True(t, snapshotAnswers(&model.QuerySchedule{QueryID: "q1"}, nil, plain, map[string]interface{}{"region": "us"}))
+
+	// Optional parameters count as parameters
+	optional := &model.Query{ID: "q1", SQLTemplate: "SELECT * FROM sales WHERE 1 = 1 /*[ AND region = :region ]*/"}
+	assert.True(t, snapshotAnswers(&model.QuerySchedule{QueryID: "q1"}, params, optional, nil))
+	assert.False(t, snapshotAnswers(&model.QuerySchedule{QueryID: "q1"}, params, optional, map[string]interface{}{"region": "us"}))
+}
+
+func TestCoerceToolParameters(t *testing.T) {
+	params := model.ToolParameters{
+		{Name: "id", Type: "integer"},
+		{Name: "ratio", Type: "number"},
+		{Name: "active", Type: "boolean"},
+		{Name: "code", Type: "string"},
+		{Name: "ids", Type: "array", Items: "integer"},
+	}
+
+	coerced := coerceToolParameters(params, map[string]interface{}{
+		"id":     "5",
+		"ratio":  2,
+		"active": "true",
+		"code":   float64(42),
+		"ids":    []interface{}{float64(1), "2"},
+		"extra":  3,
+		"name":   "x",
+	})
+	assert.Equal(t, map[string]interface{}{
+		"id":     int64(5),
+		"ratio":  float64(2),
+		"active": true,
+		"code":   "42",
+		"ids":    []interface{}{int64(1), int64(2)},
+		"extra":  float64(3),
+		"name":   "x",
+	}, coerced)
+
+	// Values that do not convert are kept as they are
+	assert.Equal(t, map[string]interface{}{"id": "five"}, coerceToolParameters(params, map[string]interface{}{"id": "five"}))
+}
+
+func TestExecutionContext(t *testing.T) {
+	ctx, cancel := executionContext()
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(defaultQueryTimeoutSeconds*time.Second), deadline, time.Second)
+
+	// A server timeout shortens the deadline but never extends it
+	ctx, cancel = executionContext(30)
+	defer cancel()
+	deadline, _ = ctx.Deadline()
+	assert.WithinDuration(t, time.Now().Add(30*time.Second), deadline, time.Second)
+
+	ctx, cancel = executionContext(3600)
+	defer cancel()
+	deadline, _ = ctx.Deadline()
+	assert.WithinDuration(t, time.Now().Add(defaultQueryTimeoutSeconds*time.Second), deadline, time.Second)
+}
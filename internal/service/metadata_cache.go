@@ -0,0 +1,67 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// metadataCacheTTL bounds how long browsed schema metadata is reused before
+// the catalog is queried again
+const metadataCacheTTL = 10 * time.Minute
+
+// metadataCache holds introspected schema metadata per datasource so that
+// browsing a large database does not hit its catalog on every request
+type metadataCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]map[string]metadataCacheItem // datasource ID -> key -> item
+}
+
+type metadataCacheItem struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// newMetadataCache creates a metadataCache with the given TTL
+func newMetadataCache(ttl time.Duration) *metadataCache {
+	return &metadataCache{
+		ttl:     ttl,
+		entries: make(map[string]map[string]metadataCacheItem),
+	}
+}
+
+// get returns a cached value for a datasource, if present and not expired
+func (c *metadataCache) get(dataSourceID, key string) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	item, ok := c.entries[dataSourceID][key]
+	if !ok || time.Now().After(item.expiresAt) {
+		return nil, false
+	}
+	return item.value, true
+}
+
+// set stores a value for a datasource
+func (c *metadataCache) set(dataSourceID, key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	items, ok := c.entries[dataSourceID]
+	if !ok {
+		items = make(map[string]metadataCacheItem)
+		c.entries[dataSourceID] = items
+	}
+	items[key] = metadataCacheItem{
+		value:     value,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+}
+
+// invalidate drops everything cached for a datasource
+func (c *metadataCache) invalidate(dataSourceID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, dataSourceID)
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/internal/repository"
+)
+
+var (
+	ErrBulkTagsRequired       = errors.New("tags are required to tag or untag")
+	ErrBulkActionNotSupported = errors.New("bulk action is not supported for this type")
+	ErrFolderCycle            = errors.New("a folder cannot be moved into itself or its subfolders")
+	ErrFolderNameExists       = errors.New("a folder with this name already exists here")
+)
+
+// normalizeTags trims and lower-cases tag names, dropping empty and
+// repeated ones
+func normalizeTags(names []string) []string {
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" && !containsString(tags, name) {
+			tags = append(tags, name)
+		}
+	}
+	return tags
+}
+
+// normalizeListFilter normalizes the tag names of a list filter
+func normalizeListFilter(filter *model.ListFilter) *model.ListFilter {
+	if filter == nil {
+		return &model.ListFilter{}
+	}
+	normalized := *filter
+	normalized.Tags = normalizeTags(filter.Tags)
+	return &normalized
+}
+
+// itemLabels returns the tags and favorite flags of a user's items
+func itemLabels(tagRepo repository.TagRepository, userID uint, resourceType string, ids []string) (map[string][]string, map[string]bool, error) {
+	tags, err := tagRepo.FindNames(resourceType, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	favorites, err := tagRepo.FindFavorites(userID, resourceType, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tags, favorites, nil
+}
+
+// bulkTagging returns the per-item step of a tag or untag bulk action
+func bulkTagging(tagRepo repository.TagRepository, userID uint, resourceType string, req *model.BulkRequest) (func(id string) error, error) {
+	tags := normalizeTags(req.Tags)
+	if len(tags) == 0 {
+		return nil, ErrBulkTagsRequired
+	}
+	if req.Action == model.BulkActionTag {
+		return func(id string) error { return tagRepo.AddTags(userID, resourceType, id, tags) }, nil
+	}
+	return func(id string) error { return tagRepo.RemoveTags(userID, resourceType, id, tags) }, nil
+}
+
+// runBulk applies step to every ID, after check confirms the user owns the
+// item, and reports which succeeded. Repeated IDs are applied once.
+func runBulk(ids []string, check, step func(id string) error) *model.BulkResult {
+	result := &model.BulkResult{Succeeded: []string{}, Failed: []model.BulkFailure{}}
+	var seen []string
+	for _, id := range ids {
+		if containsString(seen, id) {
+			continue
+		}
+		seen = append(seen, id)
+
+		err := check(id)
+		if err == nil {
+			err = step(id)
+		}
+		if err != nil {
+			result.Failed = append(result.Failed, model.BulkFailure{ID: id, Error: err.Error()})
+			continue
+		}
+		result.Succeeded = append(result.Succeeded, id)
+	}
+	return result
+}
+
+// Queries
+
+// SetTags replaces the tags of a query
+func (s *queryService) SetTags(id string, userID uint, tags []string) (*model.QueryResponse, error) {
+	if _, err := s.queryRepo.FindByIDAndUserID(id, userID); err != nil {
+		return nil, err
+	}
+	if err := s.tagRepo.SetTags(userID, model.ResourceQuery, id, normalizeTags(tags)); err != nil {
+		return nil, err
+	}
+	return s.Get(id, userID)
+}
+
+// SetFavorite marks or unmarks a query as a favorite of the user
+func (s *queryService) SetFavorite(id string, userID uint, favorite bool) error {
+	if _, err := s.queryRepo.FindByIDAndUserID(id, userID); err != nil {
+		return err
+	}
+	return s.tagRepo.SetFavorite(userID, model.ResourceQuery, id, favorite)
+}
+
+// Bulk tags, untags, moves or deletes many queries
+func (s *queryService) Bulk(userID uint, req *model.BulkRequest) (*model.BulkResult, error) {
+	check := func(id string) error {
+		_, err := s.queryRepo.FindByIDAndUserID(id, userID)
+		return err
+	}
+
+	var step func(id string) error
+	switch req.Action {
+	case model.BulkActionTag, model.BulkActionUntag:
+		var err error
+		if step, err = bulkTagging(s.tagRepo, userID, model.ResourceQuery, req); err != nil {
+			return nil, err
+		}
+	case model.BulkActionMove:
+		folderID, err := s.checkFolder(req.FolderID, userID)
+		if err != nil {
+			return nil, err
+		}
+		step = func(id string) error { return s.folderRepo.MoveQuery(id, userID, folderID) }
+	case model.BulkActionDelete:
+		check = func(string) error { return nil } // Delete checks ownership itself
+		step = func(id string) error { return s.Delete(id, userID) }
+	default:
+		return nil, ErrBulkActionNotSupported
+	}
+
+	return runBulk(req.IDs, check, step), nil
+}
+
+// checkFolder confirms a folder given in a request belongs to the user and
+// returns the folder to store: nil for none or ""
+func (s *queryService) checkFolder(folderID *string, userID uint) (*string, error) {
+	if folderID == nil || *folderID == "" {
+		return nil, nil
+	}
+	if _, err := s.folderRepo.FindByIDAndUserID(*folderID, userID); err != nil {
+		return nil, err
+	}
+	return folderID, nil
+}
+
+// labelQueries sets the tags and favorite flags of query responses
+func (s *queryService) labelQueries(userID uint, responses []model.QueryResponse) error {
+	ids := make([]string, len(responses))
+	for i := range responses {
+		ids[i] = responses[i].ID
+	}
+	tags, favorites, err := itemLabels(s.tagRepo, userID, model.ResourceQuery, ids)
+	if err != nil {
+		return err
+	}
+	for i := range responses {
+		if names, ok := tags[responses[i].ID]; ok {
+			responses[i].Tags = names
+		}
+		responses[i].Favorite = favorites[responses[i].ID]
+	}
+	return nil
+}
+
+// Tools
+
+// SetTags replaces the tags of a tool
+func (s *toolService) SetTags(id string, userID uint, tags []string) (*model.ToolResponse, error) {
+	if _, err := s.toolRepo.FindByIDAndUserID(id, userID); err != nil {
+		return nil, err
+	}
+	if err := s.tagRepo.SetTags(userID, model.ResourceTool, id, normalizeTags(tags)); err != nil {
+		return nil, err
+	}
+	return s.Get(id, userID)
+}
+
+// SetFavorite marks or unmarks a tool as a favorite of the user
+func (s *toolService) SetFavorite(id string, userID uint, favorite bool) error {
+	if _, err := s.toolRepo.FindByIDAndUserID(id, userID); err != nil {
+		return err
+	}
+	return s.tagRepo.SetFavorite(userID, model.ResourceTool, id, favorite)
+}
+
+// Bulk tags, untags or deletes many tools
+func (s *toolService) Bulk(userID uint, req *model.BulkRequest) (*model.BulkResult, error) {
+	check := func(id string) error {
+		_, err := s.toolRepo.FindByIDAndUserID(id, userID)
+		return err
+	}
+
+	var step func(id string) error
+	switch req.Action {
+	case model.BulkActionTag, model.BulkActionUntag:
+		var err error
+		if step, err = bulkTagging(s.tagRepo, userID, model.ResourceTool, req); err != nil {
+			return nil, err
+		}
+	case model.BulkActionDelete:
+		check = func(string) error { return nil } // Delete checks ownership itself
+		step = func(id string) error { return s.Delete(id, userID) }
+	default:
+		return nil, ErrBulkActionNotSupported
+	}
+
+	return runBulk(req.IDs, check, step), nil
+}
+
+// labelTools sets the tags and favorite flags of tool responses
+func (s *toolService) labelTools(userID uint, responses []model.ToolResponse) error {
+	ids := make([]string, len(responses))
+	for i := range responses {
+		ids[i] = responses[i].ID
+	}
+	tags, favorites, err := itemLabels(s.tagRepo, userID, model.ResourceTool, ids)
+	if err != nil {
+		return err
+	}
+	for i := range responses {
+		if names, ok := tags[responses[i].ID]; ok {
+			responses[i].Tags = names
+		}
+		responses[i].Favorite = favorites[responses[i].ID]
+	}
+	return nil
+}
+
+// MCP servers
+
+// SetTags replaces the tags of an MCP server
+func (s *mcpServerService) SetTags(id string, userID uint, tags []string) (*model.McpServerResponse, error) {
+	if _, err := s.mcpRepo.FindByIDAndUserID(id, userID); err != nil {
+		return nil, err
+	}
+	if err := s.tagRepo.SetTags(userID, model.ResourceMcpServer, id, normalizeTags(tags)); err != nil {
+		return nil, err
+	}
+	return s.Get(id, userID)
+}
+
+// SetFavorite marks or unmarks an MCP server as a favorite of the user
+func (s *mcpServerService) SetFavorite(id string, userID uint, favorite bool) error {
+	if _, err := s.mcpRepo.FindByIDAndUserID(id, userID); err != nil {
+		return err
+	}
+	return s.tagRepo.SetFavorite(userID, model.ResourceMcpServer, id, favorite)
+}
+
+// Bulk tags, untags or deletes many MCP servers
+func (s *mcpServerService) Bulk(userID uint, req *model.BulkRequest) (*model.BulkResult, error) {
+	check := func(id string) error {
+		_, err := s.mcpRepo.FindByIDAndUserID(id, userID)
+		return err
+	}
+
+	var step func(id string) error
+	switch req.Action {
+	case model.BulkActionTag, model.BulkActionUntag:
+		var err error
+		if step, err = bulkTagging(s.tagRepo, userID, model.ResourceMcpServer, req); err != nil {
+			return nil, err
+		}
+	case model.BulkActionDelete:
+		check = func(string) error { return nil } // Delete checks ownership itself
+		step = func(id string) error { return s.Delete(id, userID) }
+	default:
+		return nil, ErrBulkActionNotSupported
+	}
+
+	return runBulk(req.IDs, check, step), nil
+}
+
+// labelServers sets the tags and favorite flags of MCP server responses
+func (s *mcpServerService) labelServers(userID uint, responses []model.McpServerResponse) error {
+	ids := make([]string, len(responses))
+	for i := range responses {
+		ids[i] = responses[i].ID
+	}
+	tags, favorites, err := itemLabels(s.tagRepo, userID, model.ResourceMcpServer, ids)
+	if err != nil {
+		return err
+	}
+	for i := range responses {
+		if names, ok := tags[responses[i].ID]; ok {
+			responses[i].Tags = names
+		}
+		responses[i].Favorite = favorites[responses[i].ID]
+	}
+	return nil
+}
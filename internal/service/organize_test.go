@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/dataweaver/internal/model"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	assert.Equal(t, []string{"finance", "daily report"}, normalizeTags([]string{" Finance", "daily report", "", "FINANCE ", "  "}))
+	assert.Empty(t, normalizeTags(nil))
+}
+
+func TestRunBulk(t *testing.T) {
+	var applied []string
+	check := func(id string) error {
+		if id == "missing" {
+			return errors.New("not found")
+		}
+		return nil
+	}
+	step := func(id string) error {
+		if id == "broken" {
+			return errors.New("failed")
+		}
+		applied = append(applied, id)
+		return nil
+	}
+
+	result := runBulk([]string{"a", "missing", "b", "a", "broken"}, check, step)
+
+	assert.Equal(t, []string{"a", "b"}, applied)
+	assert.Equal(t, []string{"a", "b"}, result.Succeeded)
+	require.Len(t, result.Failed, 2)
+	assert.Equal(t, model.BulkFailure{ID: "missing", Error: "not found"}, result.Failed[0])
+	assert.Equal(t, model.BulkFailure{ID: "broken", Error: "failed"}, result.Failed[1])
+}
+
+func TestFolderTree(t *testing.T) {
+	reports, finance, monthly := "f1", "f2", "f3"
+	folders := []model.QueryFolder{
+		{ID: reports, Name: "Reports"},
+		{ID: finance, Name: "Finance", ParentID: &reports},
+		{ID: monthly, Name: "Monthly", ParentID: &finance},
+	}
+	byID := folderIndex(folders)
+
+	assert.Equal(t, "Reports/Finance/Monthly", folderPath(byID, byID[monthly]))
+	assert.Equal(t, "Reports", folderPath(byID, byID[reports]))
+
+	// Moving a folder under itself or a subfolder would make a cycle
+	assert.True(t, isFolderWithin(byID, monthly, reports))
+	assert.True(t, isFolderWithin(byID, reports, reports))
+	assert.False(t, isFolderWithin(byID, reports, finance))
+
+	assert.True(t, siblingNamed(folders, &reports, "finance", ""))
+	assert.False(t, siblingNamed(folders, &reports, "finance", finance))
+	assert.False(t, siblingNamed(folders, nil, "Finance", ""))
+}
+
+func TestSortFolders(t *testing.T) {
+	folders := []model.FolderResponse{
+		{Path: "sales"},
+		{Path: "Reports/Finance"},
+		{Path: "Reports"},
+	}
+	sortFolders(folders)
+	assert.Equal(t, "Reports", folders[0].Path)
+	assert.Equal(t, "Reports/Finance", folders[1].Path)
+	assert.Equal(t, "sales", folders[2].Path)
+}
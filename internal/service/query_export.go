@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yourusername/dataweaver/config"
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/internal/repository"
+	"github.com/yourusername/dataweaver/pkg/dbconnector"
+	"github.com/yourusername/dataweaver/pkg/export"
+	"github.com/yourusername/dataweaver/pkg/sqlparser"
+)
+
+// Export bounds used when no configuration has been loaded
+const (
+	defaultExportMaxRows  = 1000000
+	defaultExportMaxBytes = 1 << 30
+)
+
+// QueryExport is a running query whose results are ready to be streamed.
+// Either Stream or Close must be called to release the connection.
+type QueryExport struct {
+	Format   export.Format
+	FileName string
+
+	queryRepo repository.QueryRepository
+	connector *dbconnector.Connector
+	rows      *dbconnector.RowIterator
+	cancel    context.CancelFunc
+	execution *model.QueryExecution
+	start     time.Time
+}
+
+// Export runs a query for export under ctx and the query timeout, so the
+// query is cancelled when the client goes away. Rows are read as they are
+// streamed, up to the export limits rather than the result limits of Execute.
+func (s *queryService) Export(ctx context.Context, id string, userID uint, req *model.ExportQueryRequest) (*QueryExport, error) {
+	format, err := export.ParseFormat(req.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := s.queryRepo.FindByIDWithDataSource(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sqlparser.ValidateParameters(q.SQLTemplate, req.Parameters); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMissingParameters, err)
+	}
+
+	ds, err := s.dsRepo.FindByIDAndUserID(q.DataSourceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	connector, err := connectDataSource(ds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to datasource: %w", err)
+	}
+
+	paramsJSON, _ := serializeParams(req.Parameters)
+	execution := &model.QueryExecution{
+		UserID:        userID,
+		QueryID:       id,
+		Parameters:    paramsJSON,
+		Format:        string(format),
+		QueryRevision: q.Revision,
+	}
+
+	ctx, cancel := withExecutionTimeout(ctx)
+	start := time.Now()
+	rows, err := connector.QueryRowsContext(ctx, q.SQLTemplate, req.Parameters)
+	if err != nil {
+		cancel()
+		connector.Close()
+		execution.Status = model.ExecutionStatusFailed
+		execution.ErrorMessage = err.Error()
+		execution.ExecutionTimeMs = time.Since(start).Milliseconds()
+		_ = s.queryRepo.CreateExecution(execution)
+		return nil, fmt.Errorf("%w: %v", ErrQueryExecution, err)
+	}
+
+	return &QueryExport{
+		Format:    format,
+		FileName:  exportFileName(q.Name, start) + "." + format.Extension(),
+		queryRepo: s.queryRepo,
+		connector: connector,
+		rows:      rows,
+		cancel:    cancel,
+		execution: execution,
+		start:     start,
+	}, nil
+}
+
+// Stream writes the results to w in the export format, then records the
+// export in the execution history. It returns the number of rows written and
+// whether the export limits stopped it before the last row.
+func (e *QueryExport) Stream(w io.Writer) (int, bool, error) {
+	defer e.Close()
+
+	rows := &limitedRows{exportRows: e.rows, limits: exportLimits()}
+	count, err := export.Copy(w, e.Format, e.rows.ColumnTypes(), rows)
+
+	e.execution.RowCount = count
+	e.execution.Truncated = rows.truncated
+	e.execution.ExecutionTimeMs = time.Since(e.start).Milliseconds()
+	if err != nil {
+		e.execution.Status = model.ExecutionStatusFailed
+		e.execution.ErrorMessage = err.Error()
+	} else {
+		e.execution.Status = model.ExecutionStatusSucceeded
+	}
+	// Save execution record (ignore errors, don't affect main flow)
+	_ = e.queryRepo.CreateExecution(e.execution)
+
+	return count, rows.truncated, err
+}
+
+// Close releases the rows and the connection
+func (e *QueryExport) Close() error {
+	if e.rows == nil {
+		return nil
+	}
+	e.rows.Close()
+	e.rows = nil
+	e.cancel()
+	return e.connector.Close()
+}
+
+// exportRows is the part of a dbconnector.RowIterator an export reads
+type exportRows interface {
+	export.Rows
+	RowSize() int64
+}
+
+// limitedRows ends an export, or the rows kept by a job, at the first row
+// over its limits
+type limitedRows struct {
+	exportRows
+	limits    dbconnector.ResultLimits
+	count     int
+	size      int64
+	truncated bool // A row was left unwritten
+}
+
+func (r *limitedRows) Next() bool {
+	if r.truncated || !r.exportRows.Next() {
+		return false
+	}
+	rowSize := r.RowSize()
+	if (r.limits.MaxRows > 0 && r.count >= r.limits.MaxRows) ||
+		(r.limits.MaxBytes > 0 && r.size+rowSize > r.limits.MaxBytes) {
+		r.truncated = true
+		return false
+	}
+	r.count++
+	r.size += rowSize
+	return true
+}
+
+// exportLimits returns the configured bounds on an export
+func exportLimits() dbconnector.ResultLimits {
+	if config.AppConfig == nil {
+		return dbconnector.ResultLimits{MaxRows: defaultExportMaxRows, MaxBytes: defaultExportMaxBytes}
+	}
+	return dbconnector.ResultLimits{
+		MaxRows:  config.AppConfig.Export.MaxRows,
+		MaxBytes: config.AppConfig.Export.MaxBytes,
+	}
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// exportFileName names an export after its query and start time
+func exportFileName(queryName string, start time.Time) string {
+	name := strings.Trim(unsafeFileNameChars.ReplaceAllString(queryName, "_"), "_")
+	if name == "" {
+		name = "export"
+	}
+	return name + "-" + start.UTC().Format("20060102-150405")
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/dataweaver/pkg/dbconnector"
+)
+
+// sizedRows serves n rows of the given size
+type sizedRows struct {
+	n, pos int
+	size   int64
+}
+
+func (r *sizedRows) Next() bool            { r.pos++; return r.pos <= r.n }
+func (r *sizedRows) Values() []interface{} { return []interface{}{r.pos} }
+func (r *sizedRows) Err() error            { return nil }
+func (r *sizedRows) RowSize() int64        { return r.size }
+
+func countRows(rows *limitedRows) int {
+	n := 0
+	for rows.Next() {
+		n++
+	}
+	return n
+}
+
+func TestLimitedRows(t *testing.T) {
+	// Within the limits every row is written
+	rows := &limitedRows{exportRows: &sizedRows{n: 5, size: 10}, limits: dbconnector.ResultLimits{MaxRows: 5, MaxBytes: 50}}
+	assert.Equal(t, 5, countRows(rows))
+	assert.False(t, rows.truncated)
+
+	rows = &limitedRows{exportRows: &sizedRows{n: 5, size: 10}, limits: dbconnector.ResultLimits{MaxRows: 3}}
+	assert.Equal(t, 3, countRows(rows))
+	assert.True(t, rows.truncated)
+
+	// The byte budget is checked before each row is written
+	rows = &limitedRows{exportRows: &sizedRows{n: 5, size: 10}, limits: dbconnector.ResultLimits{MaxBytes: 25}}
+	assert.Equal(t, 2, countRows(rows))
+	assert.True(t, rows.truncated)
+	assert.False(t, rows.Next())
+}
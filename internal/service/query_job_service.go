@@ -0,0 +1,557 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yourusername/dataweaver/config"
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/internal/repository"
+	"github.com/yourusername/dataweaver/pkg/dbconnector"
+	"github.com/yourusername/dataweaver/pkg/logger"
+	"github.com/yourusername/dataweaver/pkg/sqlparser"
+	"go.uber.org/zap"
+)
+
+// Job runtime settings
+const (
+	jobChunkRows         = 1000             // Rows per stored result chunk
+	jobPollInterval      = 2 * time.Second  // How often idle workers look for queued jobs
+	jobHeartbeatInterval = 10 * time.Second // How often running jobs report in
+	jobStaleAfter        = time.Minute      // Running jobs silent this long are failed
+	jobSweepInterval     = time.Minute      // How often expired results are deleted
+)
+
+// Job settings used when no configuration has been loaded
+const (
+	defaultJobWorkers        = 4
+	defaultJobMaxQueued      = 100
+	defaultJobResultTTL      = 24 * time.Hour
+	defaultJobMaxResultRows  = 1000000
+	defaultJobMaxResultBytes = 256 << 20
+	defaultJobTimeout        = time.Hour
+)
+
+var (
+	ErrJobQueueFull     = errors.New("too many queued jobs, try again later")
+	ErrJobNotFinished   = errors.New("job has not finished")
+	ErrJobFinished      = errors.New("job has already finished")
+	ErrJobFailed        = errors.New("job did not succeed")
+	ErrJobResultExpired = errors.New("job results have expired")
+)
+
+// QueryJobService runs queries asynchronously: a job is submitted, polled
+// and its stored result fetched page by page until it expires
+type QueryJobService interface {
+	Submit(queryID string, userID uint, req *model.ExecuteQueryRequest) (*model.QueryJobResponse, error)
+	Get(id string, userID uint) (*model.QueryJobResponse, error)
+	GetResults(id string, userID uint, page, size int) (*model.QueryJobResults, error)
+	Cancel(id string, userID uint) (*model.QueryJobResponse, error)
+
+	// Start runs the worker pool and the result sweeper until Stop is called
+	Start()
+	// Stop cancels running jobs, returning them to the queue, and waits for
+	// the workers to exit
+	Stop()
+}
+
+type queryJobService struct {
+	jobRepo   repository.QueryJobRepository
+	queryRepo repository.QueryRepository
+	dsRepo    repository.DataSourceRepository
+
+	wake chan struct{} // Signals idle workers that a job was queued
+
+	mu      sync.Mutex
+	running map[string]context.CancelFunc // Jobs running on this instance
+
+	ctx    context.Context
+	stop   context.CancelFunc
+	wg     sync.WaitGroup
+	closed bool
+}
+
+// NewQueryJobService creates a new QueryJobService
+func NewQueryJobService(jobRepo repository.QueryJobRepository, queryRepo repository.QueryRepository, dsRepo repository.DataSourceRepository) QueryJobService {
+	ctx, stop := context.WithCancel(context.Background())
+	return &queryJobService{
+		jobRepo:   jobRepo,
+		queryRepo: queryRepo,
+		dsRepo:    dsRepo,
+		wake:      make(chan struct{}, 1),
+		running:   make(map[string]context.CancelFunc),
+		ctx:       ctx,
+		stop:      stop,
+	}
+}
+
+// Submit queues a query for execution
+func (s *queryJobService) Submit(queryID string, userID uint, req *model.ExecuteQueryRequest) (*model.QueryJobResponse, error) {
+	q, err := s.queryRepo.FindByIDAndUserID(queryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sqlparser.ValidateParameters(q.SQLTemplate, req.Parameters); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMissingParameters, err)
+	}
+
+	queued, err := s.jobRepo.CountQueued()
+	if err != nil {
+		return nil, err
+	}
+	if queued >= int64(jobSettings().MaxQueued) {
+		return nil, ErrJobQueueFull
+	}
+
+	paramsJSON, _ := serializeParams(req.Parameters)
+	job := &model.QueryExecution{
+		UserID:        userID,
+		QueryID:       q.ID,
+		QueryRevision: q.Revision, // Run the definition that was submitted
+		Parameters:    paramsJSON,
+		Status:        model.ExecutionStatusQueued,
+		Format:        "json",
+	}
+	if err := s.jobRepo.Create(job); err != nil {
+		return nil, err
+	}
+
+	// Wake an idle worker instead of waiting for its next poll
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	job.Query = *q
+	return toJobResponse(job), nil
+}
+
+// Get returns the status of a job
+func (s *queryJobService) Get(id string, userID uint) (*model.QueryJobResponse, error) {
+	job, err := s.jobRepo.FindByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toJobResponse(job), nil
+}
+
+// GetResults returns a page of the result of a succeeded job
+func (s *queryJobService) GetResults(id string, userID uint, page, size int) (*model.QueryJobResults, error) {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 1000 {
+		size = 100
+	}
+
+	job, err := s.jobRepo.FindByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	switch job.Status {
+	case model.ExecutionStatusQueued, model.ExecutionStatusRunning:
+		return nil, ErrJobNotFinished
+	case model.ExecutionStatusSucceeded:
+	default:
+		return nil, ErrJobFailed
+	}
+
+	result, err := s.jobRepo.FindResult(job.ID)
+	if errors.Is(err, repository.ErrQueryResultNotFound) || (err == nil && time.Now().After(result.ExpiresAt)) {
+		return nil, ErrJobResultExpired
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	offset := (page - 1) * size
+	data := []map[string]interface{}{}
+	if offset < result.RowCount {
+		from, to := offset/result.ChunkSize, (offset+size-1)/result.ChunkSize
+		chunks, err := s.jobRepo.FindResultChunks(job.ID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		data = pageRows(chunks, result.ChunkSize, offset, size)
+	}
+
+	return &model.QueryJobResults{
+		JobID:       job.ID,
+		Columns:     []string(result.Columns),
+		ColumnTypes: []model.ResultColumn(result.ColumnTypes),
+		Data:        data,
+		Total:       result.RowCount,
+		Page:        page,
+		Size:        size,
+		Truncated:   job.Truncated,
+		ExpiresAt:   result.ExpiresAt,
+	}, nil
+}
+
+// Cancel cancels a queued or running job
+func (s *queryJobService) Cancel(id string, userID uint) (*model.QueryJobResponse, error) {
+	job, err := s.jobRepo.FindByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if model.IsJobFinished(job.Status) {
+		return nil, ErrJobFinished
+	}
+
+	now := time.Now()
+	for {
+		ok, err := s.jobRepo.Transition(job.ID, job.Status, map[string]interface{}{
+			"status":      model.ExecutionStatusCancelled,
+			"finished_at": now,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			break
+		}
+
+		// A worker claimed or finished the job in the meantime
+		if job, err = s.jobRepo.FindByIDAndUserID(id, userID); err != nil {
+			return nil, err
+		}
+		if model.IsJobFinished(job.Status) {
+			return nil, ErrJobFinished
+		}
+	}
+
+	// A job running on another instance notices at its next heartbeat
+	s.mu.Lock()
+	if cancel, ok := s.running[job.ID]; ok {
+		cancel()
+	}
+	s.mu.Unlock()
+
+	job.Status = model.ExecutionStatusCancelled
+	job.FinishedAt = &now
+	return toJobResponse(job), nil
+}
+
+// Start runs the worker pool and the result sweeper
+func (s *queryJobService) Start() {
+	for i := 0; i < jobSettings().Workers; i++ {
+		s.wg.Add(1)
+		go s.work()
+	}
+	s.wg.Add(1)
+	go s.sweep()
+}
+
+// Stop cancels running jobs and waits for the workers to exit
+func (s *queryJobService) Stop() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	s.stop()
+	s.wg.Wait()
+}
+
+// work claims and runs queued jobs until the service stops
+func (s *queryJobService) work() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if s.ctx.Err() != nil {
+			return
+		}
+
+		job, err := s.jobRepo.ClaimNext()
+		if err != nil {
+			logger.Error("Failed to claim query job", zap.Error(err))
+		}
+		if job != nil {
+			s.run(job)
+			continue
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// run executes a claimed job and stores its result
+func (s *queryJobService) run(job *model.QueryExecution) {
+	timeout := jobSettings().Timeout
+	ctx, cancel := context.WithTimeout(s.ctx, timeout)
+	defer cancel()
+
+	s.mu.Lock()
+	s.running[job.ID] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, job.ID)
+		s.mu.Unlock()
+	}()
+
+	// Report in while running, and stop if the job was cancelled elsewhere
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		ticker := time.NewTicker(jobHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if running, err := s.jobRepo.Heartbeat(job.ID); err == nil && !running {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	result, truncated, execErr := s.execute(ctx, job)
+	if execErr != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		execErr = fmt.Errorf("job did not finish within %s", timeout)
+	}
+	cancel()
+	<-heartbeatDone
+
+	finished := time.Now()
+	updates := map[string]interface{}{
+		"finished_at":       finished,
+		"execution_time_ms": finished.Sub(*job.StartedAt).Milliseconds(),
+	}
+
+	switch {
+	case execErr != nil && s.ctx.Err() != nil:
+		// The server is shutting down; leave the job for the next worker
+		s.discardResult(job.ID)
+		s.jobRepo.Transition(job.ID, model.ExecutionStatusRunning, map[string]interface{}{
+			"status":       model.ExecutionStatusQueued,
+			"started_at":   nil,
+			"heartbeat_at": nil,
+		})
+		return
+	case execErr != nil:
+		s.discardResult(job.ID)
+		updates["status"] = model.ExecutionStatusFailed
+		updates["error_message"] = execErr.Error()
+	default:
+		updates["status"] = model.ExecutionStatusSucceeded
+		updates["row_count"] = result.RowCount
+		updates["truncated"] = truncated
+		updates["result_expires_at"] = result.ExpiresAt
+	}
+
+	ok, err := s.jobRepo.Transition(job.ID, model.ExecutionStatusRunning, updates)
+	if err != nil {
+		logger.Error("Failed to finish query job", zap.String("job_id", job.ID), zap.Error(err))
+	}
+	if !ok {
+		// Cancelled while running: keep the cancellation, drop the result
+		s.discardResult(job.ID)
+		s.jobRepo.Transition(job.ID, model.ExecutionStatusCancelled, map[string]interface{}{
+			"execution_time_ms": updates["execution_time_ms"],
+		})
+	}
+}
+
+// execute runs the job's query and stores its rows in chunks
+func (s *queryJobService) execute(ctx context.Context, job *model.QueryExecution) (*model.QueryResult, bool, error) {
+	q, err := s.queryRepo.FindByIDAndUserID(job.QueryID, job.UserID)
+	if err == nil {
+		q, err = queryAtRevision(s.queryRepo, q, job.QueryRevision)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	ds, err := s.dsRepo.FindByIDAndUserID(q.DataSourceID, job.UserID)
+	if err != nil {
+		return nil, false, err
+	}
+	connector, err := connectDataSource(ds)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to connect to datasource: %w", err)
+	}
+	defer connector.Close()
+
+	rows, err := connector.QueryRowsContext(ctx, q.SQLTemplate, deserializeParams(job.Parameters))
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	settings := jobSettings()
+	// Limits are checked on every row, so a chunk never overshoots them
+	limited := &limitedRows{
+		exportRows: rows,
+		limits:     dbconnector.ResultLimits{MaxRows: settings.MaxResultRows, MaxBytes: settings.MaxResultBytes},
+	}
+	chunk := 0
+	buffer := make(model.ResultRows, 0, jobChunkRows)
+
+	flush := func() error {
+		if len(buffer) == 0 {
+			return nil
+		}
+		if err := s.jobRepo.SaveResultChunk(&model.QueryResultChunk{ExecutionID: job.ID, Chunk: chunk, Rows: buffer}); err != nil {
+			return err
+		}
+		chunk++
+		buffer = make(model.ResultRows, 0, jobChunkRows)
+		return nil
+	}
+
+	for limited.Next() {
+		buffer = append(buffer, rows.Map())
+		if len(buffer) == jobChunkRows {
+			if err := flush(); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+	if err := flush(); err != nil {
+		return nil, false, err
+	}
+
+	result := &model.QueryResult{
+		ExecutionID: job.ID,
+		Columns:     model.StringArray(rows.Columns()),
+		ColumnTypes: model.ResultColumns(toResultColumns(rows.ColumnTypes())),
+		RowCount:    limited.count,
+		ChunkSize:   jobChunkRows,
+		ExpiresAt:   time.Now().Add(settings.ResultTTL),
+	}
+	if err := s.jobRepo.SaveResult(result); err != nil {
+		return nil, false, err
+	}
+	return result, limited.truncated, nil
+}
+
+// discardResult deletes whatever part of a job's result was stored
+func (s *queryJobService) discardResult(jobID string) {
+	if err := s.jobRepo.DeleteResult(jobID); err != nil {
+		logger.Warn("Failed to delete query job result", zap.String("job_id", jobID), zap.Error(err))
+	}
+}
+
+// sweep deletes expired results and fails jobs whose worker died
+func (s *queryJobService) sweep() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(jobSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.jobRepo.FailStale(time.Now().Add(-jobStaleAfter), "job was abandoned by its worker"); err != nil {
+			logger.Warn("Failed to fail stale query jobs", zap.Error(err))
+		}
+		if _, err := s.jobRepo.DeleteExpiredResults(time.Now()); err != nil {
+			logger.Warn("Failed to delete expired query results", zap.Error(err))
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pageRows returns size rows from offset, out of consecutive chunks that
+// start at the chunk holding offset
+func pageRows(chunks []model.QueryResultChunk, chunkSize, offset, size int) []map[string]interface{} {
+	rows := make([]map[string]interface{}, 0, size)
+	skip := offset % chunkSize
+	for _, chunk := range chunks {
+		for _, row := range chunk.Rows {
+			if skip > 0 {
+				skip--
+				continue
+			}
+			if len(rows) == size {
+				return rows
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// jobConfig is the job configuration with defaults applied
+type jobConfig struct {
+	Workers        int
+	MaxQueued      int
+	ResultTTL      time.Duration
+	MaxResultRows  int
+	MaxResultBytes int64
+	Timeout        time.Duration
+}
+
+// jobSettings returns the configured job settings
+func jobSettings() jobConfig {
+	if config.AppConfig == nil {
+		return jobConfig{
+			Workers:        defaultJobWorkers,
+			MaxQueued:      defaultJobMaxQueued,
+			ResultTTL:      defaultJobResultTTL,
+			MaxResultRows:  defaultJobMaxResultRows,
+			MaxResultBytes: defaultJobMaxResultBytes,
+			Timeout:        defaultJobTimeout,
+		}
+	}
+	cfg := config.AppConfig.Jobs
+	return jobConfig{
+		Workers:        cfg.Workers,
+		MaxQueued:      cfg.MaxQueued,
+		ResultTTL:      time.Duration(cfg.ResultTTL) * time.Second,
+		MaxResultRows:  cfg.MaxResultRows,
+		MaxResultBytes: cfg.MaxResultBytes,
+		Timeout:        time.Duration(cfg.Timeout) * time.Second,
+	}
+}
+
+// toJobResponse converts a job record for API responses
+func toJobResponse(job *model.QueryExecution) *model.QueryJobResponse {
+	resp := &model.QueryJobResponse{
+		ID:              job.ID,
+		QueryID:         job.QueryID,
+		QueryRevision:   job.QueryRevision,
+		Parameters:      deserializeParams(job.Parameters),
+		Status:          job.Status,
+		RowCount:        job.RowCount,
+		Truncated:       job.Truncated,
+		ExecutionTimeMs: job.ExecutionTimeMs,
+		ErrorMessage:    job.ErrorMessage,
+		CreatedAt:       job.CreatedAt,
+		StartedAt:       job.StartedAt,
+		FinishedAt:      job.FinishedAt,
+		ResultExpiresAt: job.ResultExpiresAt,
+	}
+	if job.Query.ID != "" {
+		resp.QueryName = job.Query.Name
+	}
+	return resp
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/dataweaver/internal/model"
+)
+
+func TestPageRows(t *testing.T) {
+	chunk := func(n int, values ...int) model.QueryResultChunk {
+		rows := make(model.ResultRows, 0, len(values))
+		for _, v := range values {
+			rows = append(rows, map[string]interface{}{"n": v})
+		}
+		return model.QueryResultChunk{Chunk: n, Rows: rows}
+	}
+	values := func(rows []map[string]interface{}) []int {
+		out := make([]int, 0, len(rows))
+		for _, row := range rows {
+			out = append(out, row["n"].(int))
+		}
+		return out
+	}
+
+	chunks := []model.QueryResultChunk{chunk(1, 3, 4, 5), chunk(2, 6, 7, 8), chunk(3, 9)}
+
+	t.Run("within a chunk", func(t *testing.T) {
+		assert.Equal(t, []int{4, 5}, values(pageRows(chunks[:1], 3, 4, 2)))
+	})
+
+	t.Run("across chunks", func(t *testing.T) {
+		assert.Equal(t, []int{5, 6, 7, 8, 9}, values(pageRows(chunks, 3, 5, 10)))
+	})
+
+	t.Run("past the end", func(t *testing.T) {
+		assert.Empty(t, pageRows(nil, 3, 12, 10))
+	})
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/internal/repository"
+	"github.com/yourusername/dataweaver/pkg/sqlparser"
+)
+
+// ErrInvalidRevision is returned for revision numbers below 1
+var ErrInvalidRevision = errors.New("invalid revision number")
+
+// ListRevisions returns the revisions of a query, newest first
+func (s *queryService) ListRevisions(id string, userID uint, page, size int) ([]model.QueryRevisionResponse, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 20
+	}
+
+	q, err := s.queryRepo.FindByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	revisions, total, err := s.queryRepo.FindRevisions(id, page, size)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Queries saved before revision history have only their current definition
+	if total == 0 {
+		current := model.NewQueryRevision(q, "Created")
+		current.CreatedAt = q.UpdatedAt
+		return []model.QueryRevisionResponse{*current.ToResponse(q.Revision)}, 1, nil
+	}
+
+	responses := make([]model.QueryRevisionResponse, len(revisions))
+	for i, rev := range revisions {
+		responses[i] = *rev.ToResponse(q.Revision)
+	}
+	return responses, total, nil
+}
+
+// GetRevision returns one revision of a query
+func (s *queryService) GetRevision(id string, userID uint, revision int) (*model.QueryRevisionResponse, error) {
+	q, err := s.queryRepo.FindByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	rev, err := s.revisionOf(q, revision)
+	if err != nil {
+		return nil, err
+	}
+	return rev.ToResponse(q.Revision), nil
+}
+
+// DiffRevisions compares two revisions of a query. A zero "to" compares
+// against the current revision and a zero "from" against the revision
+// before "to".
+func (s *queryService) DiffRevisions(id string, userID uint, from, to int) (*model.QueryRevisionDiff, error) {
+	q, err := s.queryRepo.FindByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if to == 0 {
+		to = q.Revision
+	}
+	if from == 0 {
+		from = to - 1
+	}
+
+	fromRev, err := s.revisionOf(q, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := s.revisionOf(q, to)
+	if err != nil {
+		return nil, err
+	}
+	return diffRevisions(fromRev, toRev), nil
+}
+
+// RestoreRevision makes an earlier definition of a query current again. The
+// restore is itself recorded as a new revision, so it can be undone.
+func (s *queryService) RestoreRevision(id string, userID uint, revision int) (*model.QueryResponse, error) {
+	q, err := s.queryRepo.FindByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	rev, err := s.revisionOf(q, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	if rev.Revision != q.Revision {
+		ds, err := s.dsRepo.FindByIDAndUserID(rev.DataSourceID, userID)
+		if err != nil {
+			if errors.Is(err, repository.ErrDataSourceNotFound) {
+				return nil, ErrDataSourceNotFound
+			}
+			return nil, err
+		}
+
+		baseline := model.NewQueryRevision(q, "Created")
+		q.Name = rev.Name
+		q.Description = rev.Description
+		q.DataSourceID = rev.DataSourceID
+		q.SQLTemplate = rev.SQLTemplate
+		q.Parameters = rev.Parameters
+		q.Lineage = extractLineage(rev.SQLTemplate, sqlparser.DialectFor(ds.Type))
+
+		note := fmt.Sprintf("Restored revision %d", rev.Revision)
+		if err := s.queryRepo.UpdateWithRevision(q, baseline, note); err != nil {
+			return nil, err
+		}
+	}
+
+	q, err = s.queryRepo.FindByIDWithDataSource(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return q.ToResponse(), nil
+}
+
+// revisionOf loads a revision of a query. The current revision of a query
+// saved before revision history is taken from the query itself.
+func (s *queryService) revisionOf(q *model.Query, revision int) (*model.QueryRevision, error) {
+	if revision < 1 {
+		return nil, ErrInvalidRevision
+	}
+
+	rev, err := s.queryRepo.FindRevision(q.ID, revision)
+	if errors.Is(err, repository.ErrQueryRevisionNotFound) && revision == q.Revision {
+		current := model.NewQueryRevision(q, "Created")
+		current.CreatedAt = q.UpdatedAt
+		return current, nil
+	}
+	return rev, err
+}
+
+// queryAtRevision returns the query as it was at a revision. Revision 0, or
+// the query's current revision, returns the query itself.
+func queryAtRevision(repo repository.QueryRepository, q *model.Query, revision int) (*model.Query, error) {
+	if revision == 0 || revision == q.Revision {
+		return q, nil
+	}
+	rev, err := repo.FindRevision(q.ID, revision)
+	if err != nil {
+		return nil, err
+	}
+	return rev.Apply(q), nil
+}
+
+// revisionChanged reports whether two snapshots define a query differently
+func revisionChanged(a, b *model.QueryRevision) bool {
+	if a.Name != b.Name || a.Description != b.Description ||
+		a.DataSourceID != b.DataSourceID || a.SQLTemplate != b.SQLTemplate {
+		return true
+	}
+	aParams, _ := json.Marshal(a.Parameters)
+	bParams, _ := json.Marshal(b.Parameters)
+	return string(aParams) != string(bParams)
+}
+
+// diffRevisions compares the fields, SQL and parameters of two revisions
+func diffRevisions(from, to *model.QueryRevision) *model.QueryRevisionDiff {
+	diff := &model.QueryRevisionDiff{
+		QueryID:      to.QueryID,
+		FromRevision: from.Revision,
+		ToRevision:   to.Revision,
+		Fields:       []model.FieldChange{},
+		SQL:          diffLines(from.SQLTemplate, to.SQLTemplate),
+		SQLChanged:   from.SQLTemplate != to.SQLTemplate,
+		Parameters:   []model.ParameterChange{},
+	}
+
+	for _, field := range []model.FieldChange{
+		{Field: "name", From: from.Name, To: to.Name},
+		{Field: "description", From: from.Description, To: to.Description},
+		{Field: "data_source_id", From: from.DataSourceID, To: to.DataSourceID},
+	} {
+		if field.From != field.To {
+			diff.Fields = append(diff.Fields, field)
+		}
+	}
+
+	fromParams := make(map[string]model.QueryParameter)
+	for _, p := range from.Parameters {
+		fromParams[p.Name] = p
+	}
+	toParams := make(map[string]bool)
+	for _, p := range to.Parameters {
+		p := p
+		toParams[p.Name] = true
+		old, ok := fromParams[p.Name]
+		if !ok {
+			diff.Parameters = append(diff.Parameters, model.ParameterChange{Name: p.Name, Change: "added", To: &p})
+			continue
+		}
+		oldJSON, _ := json.Marshal(old)
+		newJSON, _ := json.Marshal(p)
+		if string(oldJSON) != string(newJSON) {
+			diff.Parameters = append(diff.Parameters, model.ParameterChange{Name: p.Name, Change: "changed", From: &old, To: &p})
+		}
+	}
+	for _, p := range from.Parameters {
+		p := p
+		if !toParams[p.Name] {
+			diff.Parameters = append(diff.Parameters, model.ParameterChange{Name: p.Name, Change: "removed", From: &p})
+		}
+	}
+
+	return diff
+}
+
+// diffLines is a line diff based on the longest common subsequence
+func diffLines(a, b string) []model.DiffLine {
+	from := strings.Split(a, "\n")
+	to := strings.Split(b, "\n")
+
+	// lcs[i][j] is the length of the common subsequence of from[i:] and to[j:]
+	lcs := make([][]int, len(from)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	lines := make([]model.DiffLine, 0, len(from)+len(to))
+	i, j := 0, 0
+	for i < len(from) && j < len(to) {
+		switch {
+		case from[i] == to[j]:
+			lines = append(lines, model.DiffLine{Op: " ", Text: from[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, model.DiffLine{Op: "-", Text: from[i]})
+			i++
+		default:
+			lines = append(lines, model.DiffLine{Op: "+", Text: to[j]})
+			j++
+		}
+	}
+	for ; i < len(from); i++ {
+		lines = append(lines, model.DiffLine{Op: "-", Text: from[i]})
+	}
+	for ; j < len(to); j++ {
+		lines = append(lines, model.DiffLine{Op: "+", Text: to[j]})
+	}
+	return lines
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/dataweaver/internal/model"
+)
+
+func TestDiffLines(t *testing.T) {
+	lines := diffLines("SELECT id\nFROM users\nWHERE id = :id", "SELECT id, name\nFROM users\nWHERE id = :id")
+
+	assert.Equal(t, []model.DiffLine{
+		{Op: "-", Text: "SELECT id"},
+		{Op: "+", Text: "SELECT id, name"},
+		{Op: " ", Text: "FROM users"},
+		{Op: " ", Text: "WHERE id = :id"},
+	}, lines)
+}
+
+func TestDiffRevisions(t *testing.T) {
+	from := &model.QueryRevision{
+		QueryID:     "q1",
+		Revision:    1,
+		Name:        "users",
+		SQLTemplate: "SELECT * FROM users WHERE id = :id",
+		Parameters: model.JSONParameters{
+			{Name: "id", Type: "string"},
+			{Name: "limit", Type: "integer"},
+		},
+	}
+	to := &model.QueryRevision{
+		QueryID:     "q1",
+		Revision:    2,
+		Name:        "users_by_id",
+		SQLTemplate: "SELECT * FROM users WHERE id = :id AND status = :status",
+		Parameters: model.JSONParameters{
+			{Name: "id", Type: "integer"},
+			{Name: "status", Type: "string"},
+		},
+	}
+
+	diff := diffRevisions(from, to)
+
+	assert.Equal(t, 1, diff.FromRevision)
+	assert.Equal(t, 2, diff.ToRevision)
+	assert.True(t, diff.SQLChanged)
+	assert.Equal(t, []model.FieldChange{{Field: "name", From: "users", To: "users_by_id"}}, diff.Fields)
+
+	changes := make(map[string]string)
+	for _, change := range diff.Parameters {
+		changes[change.Name] = change.Change
+	}
+	assert.Equal(t, map[string]string{"id": "changed", "status": "added", "limit": "removed"}, changes)
+}
+
+func TestRevisionChanged(t *testing.T) {
+	q := &model.Query{Name: "q", SQLTemplate: "SELECT 1", Parameters: model.JSONParameters{}}
+	baseline := model.NewQueryRevision(q, "")
+
+	q.Status = "inactive"
+	assert.False(t, revisionChanged(baseline, model.NewQueryRevision(q, "")))
+
+	q.SQLTemplate = "SELECT 2"
+	assert.True(t, revisionChanged(baseline, model.NewQueryRevision(q, "")))
+}
@@ -0,0 +1,677 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/yourusername/dataweaver/config"
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/internal/repository"
+	"github.com/yourusername/dataweaver/pkg/logger"
+	"github.com/yourusername/dataweaver/pkg/sqlparser"
+	"go.uber.org/zap"
+)
+
+// Scheduler runtime settings
+const (
+	schedulerLeaseName    = "query-scheduler"
+	schedulerPollInterval = 10 * time.Second // How often the leader looks for due schedules
+	schedulerRunTimeout   = 30 * time.Minute // Longest a scheduled query may run
+	// A run is claimed under scheduleRunLeasePrefix and the schedule ID, for
+	// the run timeout plus scheduleRunLeaseGrace to record the outcome
+	scheduleRunLeasePrefix = "schedule-run:"
+	scheduleRunLeaseGrace  = time.Minute
+)
+
+// Scheduler settings used when no configuration has been loaded
+const (
+	defaultSchedulerWorkers         = 2
+	defaultSchedulerLeaseTTL        = 30 * time.Second
+	defaultSchedulerRetainSnapshots = 100
+)
+
+var (
+	ErrInvalidCron          = errors.New("invalid cron expression")
+	ErrInvalidTimezone      = errors.New("invalid timezone")
+	ErrKeyColumnNotInResult = errors.New("key column is not in the result")
+	ErrNoPreviousSnapshot   = errors.New("snapshot has no previous snapshot to compare with")
+	ErrScheduleRunning      = errors.New("schedule is already running")
+)
+
+// QueryScheduleService runs queries on cron schedules and keeps a snapshot
+// of each result, diffed against the previous one
+type QueryScheduleService interface {
+	Create(userID uint, req *model.CreateQueryScheduleRequest) (*model.QueryScheduleResponse, error)
+	List(userID uint, queryID string, page, size int) ([]model.QueryScheduleResponse, int64, error)
+	Get(id string, userID uint) (*model.QueryScheduleResponse, error)
+	Update(id string, userID uint, req *model.UpdateQueryScheduleRequest) (*model.QueryScheduleResponse, error)
+	Delete(id string, userID uint) error
+	// RunNow runs a schedule as soon as a worker is free, outside its cron times
+	RunNow(id string, userID uint) (*model.QueryScheduleResponse, error)
+
+	// Snapshots
+	ListSnapshots(id string, userID uint, changedOnly bool, page, size int) ([]model.QuerySnapshotResponse, int64, error)
+	GetSnapshot(id string, userID uint, snapshotID string) (*model.QuerySnapshotResponse, error)
+	GetLatestSnapshot(id string, userID uint) (*model.QuerySnapshotResponse, error)
+	DiffSnapshot(id string, userID uint, snapshotID string) (*model.SnapshotDiff, error)
+
+	// Start takes part in the leader election and, while leader, runs due
+	// schedules until Stop is called
+	Start()
+	// Stop cancels running schedules and gives up the lease
+	Stop()
+}
+
+type queryScheduleService struct {
+	scheduleRepo repository.QueryScheduleRepository
+	queryRepo    repository.QueryRepository
+	dsRepo       repository.DataSourceRepository
+
+	holder string        // Identifies this instance in the lease
+	slots  chan struct{} // Bounds the schedules run at once
+	wake   chan struct{} // Signals the loop that a schedule was made due
+
+	ctx    context.Context
+	stop   context.CancelFunc
+	wg     sync.WaitGroup // The election loop
+	runs   sync.WaitGroup // Schedules running on this instance
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewQueryScheduleService creates a new QueryScheduleService
+func NewQueryScheduleService(
+	scheduleRepo repository.QueryScheduleRepository,
+	queryRepo repository.QueryRepository,
+	dsRepo repository.DataSourceRepository,
+) QueryScheduleService {
+	ctx, stop := context.WithCancel(context.Background())
+	return &queryScheduleService{
+		scheduleRepo: scheduleRepo,
+		queryRepo:    queryRepo,
+		dsRepo:       dsRepo,
+		holder:       schedulerHolder(),
+		slots:        make(chan struct{}, schedulerSettings().Workers),
+		wake:         make(chan struct{}, 1),
+		ctx:          ctx,
+		stop:         stop,
+	}
+}
+
+// Create creates a new schedule
+func (s *queryScheduleService) Create(userID uint, req *model.CreateQueryScheduleRequest) (*model.QueryScheduleResponse, error) {
+	q, err := s.queryRepo.FindByIDAndUserID(req.QueryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sqlparser.ValidateParameters(q.SQLTemplate, req.Parameters); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMissingParameters, err)
+	}
+
+	schedule := &model.QuerySchedule{
+		UserID:          userID,
+		QueryID:         q.ID,
+		Name:            req.Name,
+		Cron:            req.Cron,
+		Timezone:        req.Timezone,
+		Parameters:      model.ScheduleParameters(req.Parameters),
+		KeyColumns:      model.StringArray(req.KeyColumns),
+		RetainSnapshots: req.RetainSnapshots,
+		Enabled:         req.Enabled == nil || *req.Enabled,
+	}
+	if schedule.Timezone == "" {
+		schedule.Timezone = "UTC"
+	}
+	if err := scheduleNextRun(schedule, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduleRepo.Create(schedule); err != nil {
+		return nil, err
+	}
+
+	schedule.Query = *q
+	return schedule.ToResponse(), nil
+}
+
+// List returns the schedules of a user
+func (s *queryScheduleService) List(userID uint, queryID string, page, size int) ([]model.QueryScheduleResponse, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 20
+	}
+
+	schedules, total, err := s.scheduleRepo.FindAll(userID, queryID, page, size)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]model.QueryScheduleResponse, len(schedules))
+	for i := range schedules {
+		responses[i] = *schedules[i].ToResponse()
+	}
+	return responses, total, nil
+}
+
+// Get returns a schedule
+func (s *queryScheduleService) Get(id string, userID uint) (*model.QueryScheduleResponse, error) {
+	schedule, err := s.scheduleRepo.FindByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return schedule.ToResponse(), nil
+}
+
+// Update updates a schedule. Its next run is worked out again from now.
+func (s *queryScheduleService) Update(id string, userID uint, req *model.UpdateQueryScheduleRequest) (*model.QueryScheduleResponse, error) {
+	schedule, err := s.scheduleRepo.FindByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		schedule.Name = *req.Name
+	}
+	if req.Cron != nil {
+		schedule.Cron = *req.Cron
+	}
+	if req.Timezone != nil {
+		schedule.Timezone = *req.Timezone
+		if schedule.Timezone == "" {
+			schedule.Timezone = "UTC"
+		}
+	}
+	if req.Parameters != nil {
+		if err := sqlparser.ValidateParameters(schedule.Query.SQLTemplate, req.Parameters); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMissingParameters, err)
+		}
+		schedule.Parameters = model.ScheduleParameters(req.Parameters)
+	}
+	if req.KeyColumns != nil {
+		schedule.KeyColumns = model.StringArray(req.KeyColumns)
+	}
+	if req.RetainSnapshots != nil {
+		schedule.RetainSnapshots = *req.RetainSnapshots
+	}
+	if req.Enabled != nil {
+		schedule.Enabled = *req.Enabled
+	}
+	if err := scheduleNextRun(schedule, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduleRepo.Update(schedule); err != nil {
+		return nil, err
+	}
+	return schedule.ToResponse(), nil
+}
+
+// Delete deletes a schedule and its snapshots
+func (s *queryScheduleService) Delete(id string, userID uint) error {
+	return s.scheduleRepo.Delete(id, userID)
+}
+
+// RunNow makes a schedule due now. A disabled schedule is not seen by the
+// leader, so it runs once on this instance and stays disabled, unless a run
+// of it is already in flight.
+func (s *queryScheduleService) RunNow(id string, userID uint) (*model.QueryScheduleResponse, error) {
+	schedule, err := s.scheduleRepo.FindByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !schedule.Enabled {
+		claim, err := s.claimRun(schedule.ID)
+		if err != nil {
+			return nil, err
+		}
+		s.dispatch(schedule, claim)
+		return schedule.ToResponse(), nil
+	}
+
+	now := time.Now()
+	schedule.NextRunAt = &now
+	if err := s.scheduleRepo.Update(schedule); err != nil {
+		return nil, err
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return schedule.ToResponse(), nil
+}
+
+// ListSnapshots lists the snapshots of a schedule, newest first
+func (s *queryScheduleService) ListSnapshots(id string, userID uint, changedOnly bool, page, size int) ([]model.QuerySnapshotResponse, int64, error) {
+	if _, err := s.scheduleRepo.FindByIDAndUserID(id, userID); err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 20
+	}
+
+	snapshots, total, err := s.scheduleRepo.FindSnapshots(id, changedOnly, page, size)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]model.QuerySnapshotResponse, len(snapshots))
+	for i := range snapshots {
+		responses[i] = model.QuerySnapshotResponse{QuerySnapshot: snapshots[i]}
+	}
+	return responses, total, nil
+}
+
+// GetSnapshot returns a snapshot with its rows
+func (s *queryScheduleService) GetSnapshot(id string, userID uint, snapshotID string) (*model.QuerySnapshotResponse, error) {
+	if _, err := s.scheduleRepo.FindByIDAndUserID(id, userID); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := s.scheduleRepo.FindSnapshot(id, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	return toSnapshotResponse(snapshot), nil
+}
+
+// GetLatestSnapshot returns the newest successful snapshot with its rows
+func (s *queryScheduleService) GetLatestSnapshot(id string, userID uint) (*model.QuerySnapshotResponse, error) {
+	if _, err := s.scheduleRepo.FindByIDAndUserID(id, userID); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := s.scheduleRepo.FindLatestSnapshot(id)
+	if err != nil {
+		return nil, err
+	}
+	return toSnapshotResponse(snapshot), nil
+}
+
+// DiffSnapshot lists the rows added, removed and changed since the snapshot
+// before it, matched by the schedule's key columns
+func (s *queryScheduleService) DiffSnapshot(id string, userID uint, snapshotID string) (*model.SnapshotDiff, error) {
+	schedule, err := s.scheduleRepo.FindByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := s.scheduleRepo.FindSnapshot(id, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	if to.PreviousID == nil {
+		return nil, ErrNoPreviousSnapshot
+	}
+	from, err := s.scheduleRepo.FindSnapshot(id, *to.PreviousID)
+	if err != nil {
+		if errors.Is(err, repository.ErrQuerySnapshotNotFound) {
+			return nil, fmt.Errorf("%w: it has been pruned", ErrNoPreviousSnapshot)
+		}
+		return nil, err
+	}
+
+	diff, err := diffRows(schedule.KeyColumns, to.Columns, from.Rows, to.Rows)
+	if err != nil {
+		return nil, err
+	}
+	diff.FromSnapshotID = from.ID
+	diff.ToSnapshotID = to.ID
+	diff.Truncated = from.Truncated || to.Truncated
+	return diff, nil
+}
+
+// Start runs the election and scheduling loop
+func (s *queryScheduleService) Start() {
+	if !schedulerSettings().Enabled {
+		return
+	}
+	s.wg.Add(1)
+	go s.loop()
+}
+
+// Stop cancels running schedules and gives up the lease
+func (s *queryScheduleService) Stop() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	s.stop()
+	s.wg.Wait()
+	s.runs.Wait()
+}
+
+// loop renews the lease and, while this instance holds it, starts due schedules
+func (s *queryScheduleService) loop() {
+	defer s.wg.Done()
+
+	settings := schedulerSettings()
+	interval := schedulerPollInterval
+	if interval > settings.LeaseTTL/3 {
+		interval = settings.LeaseTTL / 3
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	leader := false
+	for {
+		now := time.Now()
+		acquired, err := s.scheduleRepo.AcquireLease(schedulerLeaseName, s.holder, now, now.Add(settings.LeaseTTL))
+		if err != nil {
+			logger.Warn("Failed to renew scheduler lease", zap.Error(err))
+			acquired = false
+		}
+		if acquired != leader {
+			leader = acquired
+			logger.Info("Query scheduler leadership changed", zap.String("holder", s.holder), zap.Bool("leader", leader))
+		}
+		if leader {
+			s.startDue(now)
+		}
+
+		select {
+		case <-s.ctx.Done():
+			// Hand the lease over once the cancelled runs have been recorded
+			s.runs.Wait()
+			if leader {
+				if err := s.scheduleRepo.ReleaseLease(schedulerLeaseName, s.holder); err != nil {
+					logger.Warn("Failed to release scheduler lease", zap.Error(err))
+				}
+			}
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// startDue starts as many due schedules as there are free workers, moving
+// each to its next run first so that no occurrence runs twice
+func (s *queryScheduleService) startDue(now time.Time) {
+	free := cap(s.slots) - len(s.slots)
+	if free == 0 {
+		return
+	}
+
+	schedules, err := s.scheduleRepo.FindDue(now, free)
+	if err != nil {
+		logger.Error("Failed to find due query schedules", zap.Error(err))
+		return
+	}
+
+	for i := range schedules {
+		schedule := &schedules[i]
+		due := *schedule.NextRunAt
+		if err := scheduleNextRun(schedule, now); err != nil {
+			// The expression was valid when saved; stop retrying a schedule
+			// that can no longer be run
+			schedule.NextRunAt = nil
+			logger.Warn("Query schedule has no next run", zap.String("schedule_id", schedule.ID), zap.Error(err))
+		}
+		advanced, err := s.scheduleRepo.Advance(schedule.ID, due, schedule.NextRunAt)
+		if err != nil {
+			logger.Error("Failed to advance query schedule", zap.String("schedule_id", schedule.ID), zap.Error(err))
+			continue
+		}
+		if !advanced {
+			continue
+		}
+		// A manual run of the schedule may still be in flight
+		claim, err := s.claimRun(schedule.ID)
+		if err != nil {
+			logger.Warn("Skipped query schedule run", zap.String("schedule_id", schedule.ID), zap.Error(err))
+			continue
+		}
+		s.dispatch(schedule, claim)
+	}
+}
+
+// scheduleRun is a claim on running a schedule, held until the run is
+// recorded. Runs must finish by the deadline, before the claim expires.
+type scheduleRun struct {
+	holder   string
+	deadline time.Time
+}
+
+// claimRun claims the run of a schedule across instances, or fails with
+// ErrScheduleRunning while another run holds it
+func (s *queryScheduleService) claimRun(id string) (*scheduleRun, error) {
+	now := time.Now()
+	claim := &scheduleRun{holder: schedulerHolder(), deadline: now.Add(schedulerRunTimeout)}
+	acquired, err := s.scheduleRepo.AcquireLease(scheduleRunLeasePrefix+id, claim.holder, now, claim.deadline.Add(scheduleRunLeaseGrace))
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, ErrScheduleRunning
+	}
+	return claim, nil
+}
+
+// dispatch runs a claimed schedule in the background once a worker is
+// free, and gives up the claim when done
+func (s *queryScheduleService) dispatch(schedule *model.QuerySchedule, claim *scheduleRun) {
+	s.runs.Add(1)
+	go func() {
+		defer s.runs.Done()
+		defer func() {
+			if err := s.scheduleRepo.ReleaseLease(scheduleRunLeasePrefix+schedule.ID, claim.holder); err != nil {
+				logger.Warn("Failed to release query schedule run", zap.String("schedule_id", schedule.ID), zap.Error(err))
+			}
+		}()
+		select {
+		case s.slots <- struct{}{}:
+		case <-s.ctx.Done():
+			return
+		}
+		defer func() { <-s.slots }()
+		s.run(schedule, claim.deadline)
+	}()
+}
+
+// run executes a schedule by the deadline, stores the snapshot and prunes
+// old ones
+func (s *queryScheduleService) run(schedule *model.QuerySchedule, deadline time.Time) {
+	ctx, cancel := context.WithDeadline(s.ctx, deadline)
+	defer cancel()
+
+	start := time.Now()
+	snapshot := &model.QuerySnapshot{
+		ScheduleID:    schedule.ID,
+		QueryID:       schedule.QueryID,
+		QueryRevision: schedule.Query.Revision,
+		Status:        model.ExecutionStatusSucceeded,
+	}
+	if err := s.execute(ctx, schedule, snapshot); err != nil {
+		snapshot.Status = model.ExecutionStatusFailed
+		snapshot.ErrorMessage = err.Error()
+		if s.ctx.Err() != nil {
+			snapshot.ErrorMessage = "scheduler stopped before the query finished"
+		}
+		snapshot.Rows = nil
+		snapshot.RowCount = 0
+	}
+	snapshot.ExecutionTimeMs = time.Since(start).Milliseconds()
+
+	if err := s.scheduleRepo.CreateSnapshot(snapshot); err != nil {
+		logger.Error("Failed to save query snapshot", zap.String("schedule_id", schedule.ID), zap.Error(err))
+		snapshot.Status = model.ExecutionStatusFailed
+		snapshot.ErrorMessage = err.Error()
+	}
+	if err := s.scheduleRepo.RecordRun(schedule.ID, start, snapshot.Status, snapshot.ErrorMessage); err != nil {
+		logger.Warn("Failed to record query schedule run", zap.String("schedule_id", schedule.ID), zap.Error(err))
+	}
+
+	keep := schedule.RetainSnapshots
+	if keep <= 0 {
+		keep = schedulerSettings().RetainSnapshots
+	}
+	if _, err := s.scheduleRepo.PruneSnapshots(schedule.ID, keep); err != nil {
+		logger.Warn("Failed to prune query snapshots", zap.String("schedule_id", schedule.ID), zap.Error(err))
+	}
+
+	if snapshot.HasChanges() {
+		logger.Info("Scheduled query result changed",
+			zap.String("schedule_id", schedule.ID),
+			zap.Int("added", snapshot.Added),
+			zap.Int("removed", snapshot.Removed),
+			zap.Int("changed", snapshot.Changed),
+		)
+	}
+}
+
+// execute runs the schedule's query into the snapshot and diffs it against
+// the latest successful snapshot
+func (s *queryScheduleService) execute(ctx context.Context, schedule *model.QuerySchedule, snapshot *model.QuerySnapshot) error {
+	q := &schedule.Query
+	if q.ID == "" {
+		return repository.ErrQueryNotFound
+	}
+
+	params := map[string]interface{}(schedule.Parameters)
+	if err := sqlparser.ValidateParameters(q.SQLTemplate, params); err != nil {
+		return fmt.Errorf("%w: %v", ErrMissingParameters, err)
+	}
+
+	ds, err := s.dsRepo.FindByIDAndUserID(q.DataSourceID, schedule.UserID)
+	if err != nil {
+		return err
+	}
+	connector, err := connectDataSource(ds)
+	if err != nil {
+		return fmt.Errorf("failed to connect to datasource: %w", err)
+	}
+	defer connector.Close()
+
+	result, err := connector.ExecuteQueryWithLimitsContext(ctx, q.SQLTemplate, params, resultLimits())
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrQueryExecution, err)
+	}
+
+	rows, err := normalizeRows(result.Data)
+	if err != nil {
+		return err
+	}
+	snapshot.Columns = model.StringArray(result.Columns)
+	snapshot.ColumnTypes = model.ResultColumns(toResultColumns(result.ColumnTypes))
+	snapshot.Rows = rows
+	snapshot.RowCount = len(rows)
+	snapshot.Truncated = result.Truncated
+
+	previous, err := s.scheduleRepo.FindLatestSnapshot(schedule.ID)
+	if errors.Is(err, repository.ErrQuerySnapshotNotFound) {
+		return nil // The first snapshot has nothing to compare with
+	}
+	if err != nil {
+		return err
+	}
+
+	diff, err := diffRows(schedule.KeyColumns, result.Columns, previous.Rows, rows)
+	if err != nil {
+		return err
+	}
+	snapshot.PreviousID = &previous.ID
+	snapshot.Added = len(diff.Added)
+	snapshot.Removed = len(diff.Removed)
+	snapshot.Changed = len(diff.Changed)
+	return nil
+}
+
+// toSnapshotResponse includes the rows of a snapshot
+func toSnapshotResponse(snapshot *model.QuerySnapshot) *model.QuerySnapshotResponse {
+	data := []map[string]interface{}(snapshot.Rows)
+	if data == nil {
+		data = []map[string]interface{}{}
+	}
+	return &model.QuerySnapshotResponse{QuerySnapshot: *snapshot, Data: data}
+}
+
+// scheduleNextRun sets when an enabled schedule next runs after now, and
+// clears it for a disabled one. It validates the cron expression and zone.
+func scheduleNextRun(schedule *model.QuerySchedule, now time.Time) error {
+	next, err := nextCronRun(schedule.Cron, schedule.Timezone, now)
+	if err != nil {
+		return err
+	}
+	if schedule.Enabled {
+		schedule.NextRunAt = &next
+	} else {
+		schedule.NextRunAt = nil
+	}
+	return nil
+}
+
+// nextCronRun returns the first time after now that a cron expression
+// matches, reading the expression in the given zone
+func nextCronRun(expr, timezone string, now time.Time) (time.Time, error) {
+	schedule, err := cron.ParseStandard(expr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", ErrInvalidCron, err)
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", ErrInvalidTimezone, err)
+	}
+
+	next := schedule.Next(now.In(loc))
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("%w: it never matches", ErrInvalidCron)
+	}
+	return next, nil
+}
+
+// schedulerHolder names this instance in the scheduler lease
+func schedulerHolder() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
+
+// schedulerConfig is the scheduler configuration with defaults applied
+type schedulerConfig struct {
+	Enabled         bool
+	Workers         int
+	LeaseTTL        time.Duration
+	RetainSnapshots int
+}
+
+// schedulerSettings returns the configured scheduler settings
+func schedulerSettings() schedulerConfig {
+	if config.AppConfig == nil {
+		return schedulerConfig{
+			Enabled:         true,
+			Workers:         defaultSchedulerWorkers,
+			LeaseTTL:        defaultSchedulerLeaseTTL,
+			RetainSnapshots: defaultSchedulerRetainSnapshots,
+		}
+	}
+	cfg := config.AppConfig.Scheduler
+	return schedulerConfig{
+		Enabled:         cfg.Enabled == nil || *cfg.Enabled,
+		Workers:         cfg.Workers,
+		LeaseTTL:        time.Duration(cfg.LeaseTTL) * time.Second,
+		RetainSnapshots: cfg.RetainSnapshots,
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/internal/repository"
+)
+
+func TestNextCronRun(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+
+	next, err := nextCronRun("0 9 * * *", "Asia/Tokyo", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), next.UTC())
+
+	next, err = nextCronRun("@hourly", "UTC", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC), next.UTC())
+
+	_, err = nextCronRun("every day", "UTC", now)
+	assert.True(t, errors.Is(err, ErrInvalidCron))
+
+	_, err = nextCronRun("0 9 * * *", "Mars/Olympus", now)
+	assert.True(t, errors.Is(err, ErrInvalidTimezone))
+}
+
+func TestScheduleNextRun(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+
+	schedule := &model.QuerySchedule{Cron: "*/15 * * * *", Timezone: "UTC", Enabled: true}
+	require.NoError(t, scheduleNextRun(schedule, now))
+	require.NotNil(t, schedule.NextRunAt)
+	assert.Equal(t, time.Date(2024, 3, 1, 10, 45, 0, 0, time.UTC), schedule.NextRunAt.UTC())
+
+	schedule.Enabled = false
+	require.NoError(t, scheduleNextRun(schedule, now))
+	assert.Nil(t, schedule.NextRunAt)
+}
+
+// leaseRepo keeps schedules and leases in memory
+type leaseRepo struct {
+	repository.QueryScheduleRepository
+	schedule *model.QuerySchedule
+	mu       sync.Mutex
+	leases   map[string]model.SchedulerLease
+}
+
+func (r *leaseRepo) FindByIDAndUserID(string, uint) (*model.QuerySchedule, error) {
+	return r.schedule, nil
+}
+
+func (r *leaseRepo) AcquireLease(name, holder string, now, expiresAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if lease, ok := r.leases[name]; ok && lease.Holder != holder && !lease.ExpiresAt.Before(now) {
+		return false, nil
+	}
+	r.leases[name] = model.SchedulerLease{Name: name, Holder: holder, ExpiresAt: expiresAt}
+	return true, nil
+}
+
+func (r *leaseRepo) ReleaseLease(name, holder string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.leases[name].Holder == holder {
+		delete(r.leases, name)
+	}
+	return nil
+}
+
+func TestQueryScheduleService_RunNow_Disabled(t *testing.T) {
+	repo := &leaseRepo{
+		schedule: &model.QuerySchedule{ID: "s1", Cron: "@hourly", Timezone: "UTC"},
+		leases:   map[string]model.SchedulerLease{},
+	}
+	s := NewQueryScheduleService(repo, nil, nil).(*queryScheduleService)
+	// Keep the workers busy so the run waits with its claim held
+	for i := 0; i < cap(s.slots); i++ {
+		s.slots <- struct{}{}
+	}
+
+	_, err := s.RunNow("s1", 1)
+	require.NoError(t, err)
+	_, err = s.RunNow("s1", 1)
+	assert.ErrorIs(t, err, ErrScheduleRunning)
+
+	// The claim is given up once the run ends
+	s.Stop()
+	assert.Empty(t, repo.leases)
+	_, err = s.claimRun("s1")
+	assert.NoError(t, err)
+}
@@ -1,11 +1,16 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/yourusername/dataweaver/config"
 	"github.com/yourusername/dataweaver/internal/model"
 	"github.com/yourusername/dataweaver/internal/repository"
 	"github.com/yourusername/dataweaver/pkg/crypto"
@@ -13,6 +18,13 @@ import (
 	"github.com/yourusername/dataweaver/pkg/sqlparser"
 )
 
+// Result bounds and timeout used when no configuration has been loaded
+const (
+	defaultMaxResultRows       = 10000
+	defaultMaxResultBytes      = 32 << 20
+	defaultQueryTimeoutSeconds = 300
+)
+
 var (
 	ErrInvalidSQL         = errors.New("invalid SQL syntax")
 	ErrNonReadOnlySQL     = errors.New("only SELECT queries are allowed")
@@ -24,28 +36,55 @@ var (
 // QueryService handles business logic for queries
 type QueryService interface {
 	Create(userID uint, req *model.CreateQueryRequest) (*model.QueryResponse, error)
-	List(userID uint, page, size int, keyword string) ([]model.QueryResponse, int64, error)
+	List(userID uint, page, size int, filter *model.ListFilter) ([]model.QueryResponse, int64, error)
 	Get(id string, userID uint) (*model.QueryResponse, error)
 	Update(id string, userID uint, req *model.UpdateQueryRequest) (*model.QueryResponse, error)
 	Delete(id string, userID uint) error
+	// Tags, favorites and bulk actions
+	SetTags(id string, userID uint, tags []string) (*model.QueryResponse, error)
+	SetFavorite(id string, userID uint, favorite bool) error
+	Bulk(userID uint, req *model.BulkRequest) (*model.BulkResult, error)
 	Execute(id string, userID uint, req *model.ExecuteQueryRequest) (*model.ExecuteQueryResponse, error)
-	ValidateSQL(sqlTemplate string) (*model.ValidateSQLResponse, error)
+	Export(ctx context.Context, id string, userID uint, req *model.ExportQueryRequest) (*QueryExport, error)
+	ValidateSQL(userID uint, req *model.ValidateSQLRequest) (*model.ValidateSQLResponse, error)
 	GetParameters(id string, userID uint) ([]model.QueryParameter, error)
 	ExtractParameters(sqlTemplate string) ([]model.QueryParameter, error)
+	ExecuteRawQuery(userID uint, dataSourceID, sqlTemplate string, params map[string]interface{}) (*model.ExecuteQueryResponse, error)
+	// Revision history
+	ListRevisions(id string, userID uint, page, size int) ([]model.QueryRevisionResponse, int64, error)
+	GetRevision(id string, userID uint, revision int) (*model.QueryRevisionResponse, error)
+	DiffRevisions(id string, userID uint, from, to int) (*model.QueryRevisionDiff, error)
+	RestoreRevision(id string, userID uint, revision int) (*model.QueryResponse, error)
 	// Execution history
-	GetExecutionHistory(userID uint, queryID string, page, size int) ([]model.QueryExecutionResponse, int64, error)
+	GetExecutionHistory(userID uint, filter *model.ExecutionHistoryFilter, page, size int) ([]model.QueryExecutionResponse, int64, error)
+	RerunExecution(executionID string, userID uint) (*model.ExecuteQueryResponse, error)
+	CompareExecutions(userID uint, fromID, toID string) (*model.ExecutionComparison, error)
+	// Result cache
+	PurgeCache(id string, userID uint) (*model.CachePurgeResponse, error)
 }
 
 type queryService struct {
-	queryRepo repository.QueryRepository
-	dsRepo    repository.DataSourceRepository
+	queryRepo  repository.QueryRepository
+	dsRepo     repository.DataSourceRepository
+	cache      *ResultCache
+	tagRepo    repository.TagRepository
+	folderRepo repository.FolderRepository
 }
 
 // NewQueryService creates a new QueryService
-func NewQueryService(queryRepo repository.QueryRepository, dsRepo repository.DataSourceRepository) QueryService {
+func NewQueryService(
+	queryRepo repository.QueryRepository,
+	dsRepo repository.DataSourceRepository,
+	cache *ResultCache,
+	tagRepo repository.TagRepository,
+	folderRepo repository.FolderRepository,
+) QueryService {
 	return &queryService{
-		queryRepo: queryRepo,
-		dsRepo:    dsRepo,
+		queryRepo:  queryRepo,
+		dsRepo:     dsRepo,
+		cache:      cache,
+		tagRepo:    tagRepo,
+		folderRepo: folderRepo,
 	}
 }
 
@@ -60,20 +99,29 @@ func (s *queryService) Create(userID uint, req *model.CreateQueryRequest) (*mode
 		return nil, err
 	}
 
+	// Accept @name, $1 and ? parameters pasted from other clients
+	dialect := sqlparser.DialectFor(ds.Type)
+	sqlTemplate, style := sqlparser.NormalizeParameters(req.SQLTemplate, dialect)
+
 	// Validate SQL syntax
-	if err := sqlparser.ValidateSQLSyntax(req.SQLTemplate); err != nil {
+	if err := sqlparser.ValidateSQLSyntax(sqlTemplate, dialect); err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidSQL, err)
 	}
 
 	// Validate SQL is read-only
-	if err := sqlparser.ValidateReadOnlySQL(req.SQLTemplate); err != nil {
+	if err := sqlparser.ValidateReadOnlyStatements(sqlTemplate, dialect, readOnlyOptions()); err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrNonReadOnlySQL, err)
 	}
 
 	// Extract parameters from SQL if not provided
 	params := req.Parameters
 	if len(params) == 0 {
-		params = s.extractParametersFromSQL(req.SQLTemplate)
+		params = s.extractParametersFromSQL(sqlTemplate)
+	}
+
+	folderID, err := s.checkFolder(req.FolderID, userID)
+	if err != nil {
+		return nil, err
 	}
 
 	query := &model.Query{
@@ -81,26 +129,30 @@ func (s *queryService) Create(userID uint, req *model.CreateQueryRequest) (*mode
 		Name:         req.Name,
 		Description:  req.Description,
 		DataSourceID: req.DataSourceID,
-		SQLTemplate:  req.SQLTemplate,
+		SQLTemplate:  sqlTemplate,
+		Lineage:      extractLineage(sqlTemplate, dialect),
 		Status:       "active",
+		FolderID:     folderID,
 	}
 
 	if err := query.SetParameters(params); err != nil {
 		return nil, fmt.Errorf("failed to set parameters: %w", err)
 	}
 
-	if err := s.queryRepo.Create(query); err != nil {
+	if err := s.queryRepo.CreateWithRevision(query, "Created"); err != nil {
 		return nil, err
 	}
 
 	// Set DataSource for response
 	query.DataSource = *ds
 
-	return query.ToResponse(), nil
+	resp := query.ToResponse()
+	resp.ParameterStyle = string(style)
+	return resp, nil
 }
 
-// List returns all queries for a user with optional search
-func (s *queryService) List(userID uint, page, size int, keyword string) ([]model.QueryResponse, int64, error) {
+// List returns the queries of a user matching a filter
+func (s *queryService) List(userID uint, page, size int, filter *model.ListFilter) ([]model.QueryResponse, int64, error) {
 	// Set defaults
 	if page < 1 {
 		page = 1
@@ -109,16 +161,7 @@ func (s *queryService) List(userID uint, page, size int, keyword string) ([]mode
 		size = 20
 	}
 
-	var queries []model.Query
-	var total int64
-	var err error
-
-	if keyword != "" {
-		queries, total, err = s.queryRepo.Search(userID, keyword, page, size)
-	} else {
-		queries, total, err = s.queryRepo.FindAll(userID, page, size)
-	}
-
+	queries, total, err := s.queryRepo.List(userID, normalizeListFilter(filter), page, size)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -127,6 +170,9 @@ func (s *queryService) List(userID uint, page, size int, keyword string) ([]mode
 	for i, q := range queries {
 		responses[i] = *q.ToResponse()
 	}
+	if err := s.labelQueries(userID, responses); err != nil {
+		return nil, 0, err
+	}
 
 	return responses, total, nil
 }
@@ -137,7 +183,17 @@ func (s *queryService) Get(id string, userID uint) (*model.QueryResponse, error)
 	if err != nil {
 		return nil, err
 	}
-	return q.ToResponse(), nil
+	return s.toLabeledResponse(q)
+}
+
+// toLabeledResponse converts a query to a response with its tags and
+// favorite flag
+func (s *queryService) toLabeledResponse(q *model.Query) (*model.QueryResponse, error) {
+	responses := []model.QueryResponse{*q.ToResponse()}
+	if err := s.labelQueries(q.UserID, responses); err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
 }
 
 // Update updates a query
@@ -147,6 +203,9 @@ func (s *queryService) Update(id string, userID uint, req *model.UpdateQueryRequ
 		return nil, err
 	}
 
+	// The definition before the change, to compare and record
+	baseline := model.NewQueryRevision(q, "Created")
+
 	// Update fields if provided
 	if req.Name != nil {
 		q.Name = *req.Name
@@ -165,22 +224,37 @@ func (s *queryService) Update(id string, userID uint, req *model.UpdateQueryRequ
 		}
 		q.DataSourceID = *req.DataSourceID
 	}
+	var style sqlparser.ParameterStyle
 	if req.SQLTemplate != nil {
+		ds, err := s.dsRepo.FindByIDAndUserID(q.DataSourceID, userID)
+		if err != nil {
+			if errors.Is(err, repository.ErrDataSourceNotFound) {
+				return nil, ErrDataSourceNotFound
+			}
+			return nil, err
+		}
+
+		// Accept @name, $1 and ? parameters pasted from other clients
+		dialect := sqlparser.DialectFor(ds.Type)
+		var sqlTemplate string
+		sqlTemplate, style = sqlparser.NormalizeParameters(*req.SQLTemplate, dialect)
+
 		// Validate SQL syntax
-		if err := sqlparser.ValidateSQLSyntax(*req.SQLTemplate); err != nil {
+		if err := sqlparser.ValidateSQLSyntax(sqlTemplate, dialect); err != nil {
 			return nil, fmt.Errorf("%w: %v", ErrInvalidSQL, err)
 		}
 
 		// Validate SQL is read-only
-		if err := sqlparser.ValidateReadOnlySQL(*req.SQLTemplate); err != nil {
+		if err := sqlparser.ValidateReadOnlyStatements(sqlTemplate, dialect, readOnlyOptions()); err != nil {
 			return nil, fmt.Errorf("%w: %v", ErrNonReadOnlySQL, err)
 		}
 
-		q.SQLTemplate = *req.SQLTemplate
+		q.SQLTemplate = sqlTemplate
+		q.Lineage = extractLineage(sqlTemplate, dialect)
 
 		// Re-extract parameters if SQL template changed and no new parameters provided
 		if req.Parameters == nil {
-			params := s.extractParametersFromSQL(*req.SQLTemplate)
+			params := s.extractParametersFromSQL(sqlTemplate)
 			if err := q.SetParameters(params); err != nil {
 				return nil, fmt.Errorf("failed to set parameters: %w", err)
 			}
@@ -194,8 +268,23 @@ func (s *queryService) Update(id string, userID uint, req *model.UpdateQueryRequ
 	if req.Status != nil {
 		q.Status = *req.Status
 	}
+	if req.FolderID != nil {
+		if q.FolderID, err = s.checkFolder(req.FolderID, userID); err != nil {
+			return nil, err
+		}
+	}
 
-	if err := s.queryRepo.Update(q); err != nil {
+	// Changes to the definition are recorded as a new revision
+	if revisionChanged(baseline, model.NewQueryRevision(q, "")) {
+		note := req.ChangeNote
+		if note == "" {
+			note = "Updated"
+		}
+		err = s.queryRepo.UpdateWithRevision(q, baseline, note)
+	} else {
+		err = s.queryRepo.Update(q)
+	}
+	if err != nil {
 		return nil, err
 	}
 
@@ -205,12 +294,20 @@ func (s *queryService) Update(id string, userID uint, req *model.UpdateQueryRequ
 		return nil, err
 	}
 
-	return q.ToResponse(), nil
+	resp, err := s.toLabeledResponse(q)
+	if err != nil {
+		return nil, err
+	}
+	resp.ParameterStyle = string(style)
+	return resp, nil
 }
 
 // Delete deletes a query
 func (s *queryService) Delete(id string, userID uint) error {
-	return s.queryRepo.Delete(id, userID)
+	if err := s.queryRepo.Delete(id, userID); err != nil {
+		return err
+	}
+	return s.tagRepo.DeleteResource(model.ResourceQuery, id)
 }
 
 // Execute executes a query with the provided parameters
@@ -232,6 +329,27 @@ func (s *queryService) Execute(id string, userID uint, req *model.ExecuteQueryRe
 		return nil, err
 	}
 
+	// Answer from the result cache while a result is fresh; cache hits are
+	// not recorded in the execution history
+	limits := resultLimits()
+	var cacheKey string
+	if req.UseCache {
+		cacheKey = resultCacheKey(q, ds, toToolParameters(q.Parameters), req.Parameters, limits)
+		if cached, ok := s.cache.Get(cacheKey); ok {
+			cachedAt := cached.CachedAt
+			return &model.ExecuteQueryResponse{
+				Columns:     cached.Columns,
+				ColumnTypes: toResultColumns(cached.ColumnTypes),
+				Data:        cached.Data,
+				RowCount:    len(cached.Data),
+				Truncated:   cached.Truncated,
+				Cached:      true,
+				CachedAt:    &cachedAt,
+				ResultSets:  toResultSetResponses(cached.QueryResult().ResultSets),
+			}, nil
+		}
+	}
+
 	// Decrypt password
 	password, err := crypto.Decrypt(ds.Password)
 	if err != nil {
@@ -247,6 +365,7 @@ func (s *queryService) Execute(id string, userID uint, req *model.ExecuteQueryRe
 		Password: password,
 		Database: ds.Database,
 		SSLMode:  ds.SSLMode,
+		ReadOnly: ds.IsReadOnly(),
 	}
 
 	connector := dbconnector.NewConnector(config)
@@ -259,8 +378,10 @@ func (s *queryService) Execute(id string, userID uint, req *model.ExecuteQueryRe
 	paramsJSON, _ := serializeParams(req.Parameters)
 
 	// Execute query with ordered columns
+	ctx, cancel := executionContext()
+	defer cancel()
 	start := time.Now()
-	queryResult, execErr := connector.ExecuteQueryWithColumns(q.SQLTemplate, req.Parameters)
+	queryResult, execErr := connector.ExecuteQueryWithLimitsContext(ctx, q.SQLTemplate, req.Parameters, limits)
 	executionTime := time.Since(start).Milliseconds()
 
 	// Save execution history
@@ -269,15 +390,18 @@ func (s *queryService) Execute(id string, userID uint, req *model.ExecuteQueryRe
 		QueryID:         id,
 		Parameters:      paramsJSON,
 		ExecutionTimeMs: executionTime,
+		Format:          "json",
+		QueryRevision:   q.Revision,
 	}
 
 	if execErr != nil {
-		execution.Status = "error"
+		execution.Status = model.ExecutionStatusFailed
 		execution.ErrorMessage = execErr.Error()
 		execution.RowCount = 0
 	} else {
-		execution.Status = "success"
+		execution.Status = model.ExecutionStatusSucceeded
 		execution.RowCount = len(queryResult.Data)
+		execution.Truncated = queryResult.Truncated
 	}
 
 	// Save execution record (ignore errors, don't affect main flow)
@@ -287,41 +411,255 @@ func (s *queryService) Execute(id string, userID uint, req *model.ExecuteQueryRe
 		return nil, fmt.Errorf("%w: %v", ErrQueryExecution, execErr)
 	}
 
+	if req.UseCache {
+		s.cache.Set(cacheKey, q.ID, queryResult, s.cache.TTL(0))
+	}
+
 	return &model.ExecuteQueryResponse{
 		Columns:         queryResult.Columns, // Use ordered columns from database
+		ColumnTypes:     toResultColumns(queryResult.ColumnTypes),
 		Data:            queryResult.Data,
 		RowCount:        len(queryResult.Data),
+		Truncated:       queryResult.Truncated,
 		ExecutionTimeMs: executionTime,
+		ResultSets:      toResultSetResponses(queryResult.ResultSets),
 	}, nil
 }
 
-// ValidateSQL validates SQL syntax and checks if it's read-only
-func (s *queryService) ValidateSQL(sqlTemplate string) (*model.ValidateSQLResponse, error) {
+// ValidateSQL normalizes parameter styles, validates SQL syntax, checks if
+// it's read-only and lints it. When a data source is given, parameter types are inferred from the columns
+// they are compared to in its schema, and table sizes feed the lint rules.
+func (s *queryService) ValidateSQL(userID uint, req *model.ValidateSQLRequest) (*model.ValidateSQLResponse, error) {
+	var ds *model.DataSource
+	dialect := sqlparser.DialectGeneric
+	if req.DataSourceID != "" {
+		var err error
+		ds, err = s.dsRepo.FindByIDAndUserID(req.DataSourceID, userID)
+		if err != nil {
+			if errors.Is(err, repository.ErrDataSourceNotFound) {
+				return nil, ErrDataSourceNotFound
+			}
+			return nil, err
+		}
+		dialect = sqlparser.DialectFor(ds.Type)
+	}
+
+	sqlTemplate, style := sqlparser.NormalizeParameters(req.SQLTemplate, dialect)
 	response := &model.ValidateSQLResponse{
-		Valid: true,
+		Valid:          true,
+		ParameterStyle: string(style),
+		Findings:       []model.LintFinding{},
+	}
+	if sqlTemplate != req.SQLTemplate {
+		response.NormalizedSQL = sqlTemplate
 	}
 
 	// Validate syntax
-	if err := sqlparser.ValidateSQLSyntax(sqlTemplate); err != nil {
+	if err := sqlparser.ValidateSQLSyntax(sqlTemplate, dialect); err != nil {
 		response.Valid = false
 		response.Message = fmt.Sprintf("Syntax error: %v", err)
+		var verr *sqlparser.ValidationError
+		if errors.As(err, &verr) {
+			response.Position = &model.SQLPosition{Line: verr.Line, Column: verr.Column, Offset: verr.Offset}
+		}
 		return response, nil
 	}
 
 	// Validate read-only
-	if err := sqlparser.ValidateReadOnlySQL(sqlTemplate); err != nil {
+	if err := sqlparser.ValidateReadOnlyStatements(sqlTemplate, dialect, readOnlyOptions()); err != nil {
 		response.Valid = false
 		response.Message = fmt.Sprintf("Security error: %v", err)
+		var verr *sqlparser.ValidationError
+		if errors.As(err, &verr) {
+			response.Position = &model.SQLPosition{Line: verr.Line, Column: verr.Column, Offset: verr.Offset}
+		}
 		return response, nil
 	}
 
 	// Extract parameters
 	response.Parameters = s.extractParametersFromSQL(sqlTemplate)
+
+	lintOpts := sqlparser.LintOptions{Config: lintConfig()}
+	if req.Parameters != nil {
+		lintOpts.DeclaredParameters = make([]string, len(req.Parameters))
+		for i, p := range req.Parameters {
+			lintOpts.DeclaredParameters[i] = p.Name
+		}
+	}
+
+	// Schema lookups are best effort; without a connection the name-based
+	// parameter types stand and table size checks are skipped
+	if ds != nil {
+		if connector, err := connectDataSource(ds); err == nil {
+			defer connector.Close()
+			tables := sqlparser.ReferencedTables(sqlTemplate, dialect)
+			inferParameterTypes(connector, dialect, tables, sqlTemplate, response.Parameters)
+			lintOpts.TableRows = referencedTableRows(connector, tables)
+		}
+	}
+
+	for _, f := range sqlparser.Lint(sqlTemplate, dialect, lintOpts) {
+		response.Findings = append(response.Findings, model.LintFinding{
+			Rule:     f.Rule,
+			Severity: string(f.Severity),
+			Message:  f.Message,
+			Line:     f.Line,
+			Column:   f.Column,
+			Offset:   f.Offset,
+		})
+	}
 	response.Message = "SQL is valid"
 
 	return response, nil
 }
 
+// lintConfig converts the configured lint settings for the SQL parser
+func lintConfig() sqlparser.LintConfig {
+	if config.AppConfig == nil {
+		return sqlparser.LintConfig{}
+	}
+	cfg := sqlparser.LintConfig{
+		LargeTableRows: config.AppConfig.Lint.LargeTableRows,
+		Rules:          make(map[string]sqlparser.LintRuleConfig, len(config.AppConfig.Lint.Rules)),
+	}
+	for id, rule := range config.AppConfig.Lint.Rules {
+		cfg.Rules[id] = sqlparser.LintRuleConfig{
+			Enabled:  rule.Enabled,
+			Severity: sqlparser.Severity(rule.Severity),
+		}
+	}
+	return cfg
+}
+
+// readOnlyOptions returns the read-only validation options from the config
+func readOnlyOptions() sqlparser.ReadOnlyOptions {
+	if config.AppConfig == nil {
+		return sqlparser.ReadOnlyOptions{}
+	}
+	return sqlparser.ReadOnlyOptions{AllowedRoutines: config.AppConfig.Query.AllowedRoutines}
+}
+
+// connectDataSource opens a connection to a data source
+func connectDataSource(ds *model.DataSource) (*dbconnector.Connector, error) {
+	password, err := crypto.Decrypt(ds.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt datasource password: %w", err)
+	}
+
+	connector := dbconnector.NewConnector(&dbconnector.ConnectionConfig{
+		Type:     dbconnector.DBType(ds.Type),
+		Host:     ds.Host,
+		Port:     ds.Port,
+		Username: ds.Username,
+		Password: password,
+		Database: ds.Database,
+		SSLMode:  ds.SSLMode,
+		ReadOnly: ds.IsReadOnly(),
+	})
+	if err := connector.Connect(); err != nil {
+		return nil, err
+	}
+	return connector, nil
+}
+
+// referencedTableRows returns the approximate row counts of the referenced
+// tables, keyed by lower-case name and schema.name, from catalog statistics.
+// Tables without statistics get sqlparser.UnknownTableRows.
+func referencedTableRows(connector *dbconnector.Connector, tables []sqlparser.TableRef) map[string]int64 {
+	rows := make(map[string]int64)
+	loaded := make(map[string]bool)
+	for _, table := range tables {
+		schema := table.Schema
+		if schema == "" {
+			schema = connector.DefaultSchema()
+		}
+		if loaded[schema] {
+			continue
+		}
+		loaded[schema] = true
+
+		infos, err := connector.ListTables(schema)
+		if err != nil {
+			continue
+		}
+		for _, info := range infos {
+			count := sqlparser.UnknownTableRows
+			if info.RowCount != nil {
+				count = *info.RowCount
+			}
+			rows[strings.ToLower(info.Name)] = count
+			rows[strings.ToLower(info.Schema+"."+info.Name)] = count
+		}
+	}
+	return rows
+}
+
+// inferParameterTypes replaces the name-based parameter types with the types
+// of the columns the parameters are compared to, looked up in the data source
+// schema. Parameters that cannot be matched to a column keep the name-based
+// types.
+func inferParameterTypes(connector *dbconnector.Connector, dialect sqlparser.Dialect, tables []sqlparser.TableRef, sqlTemplate string, params []model.QueryParameter) {
+	columns := sqlparser.ParameterColumns(sqlTemplate, dialect)
+	if len(columns) == 0 || len(tables) == 0 {
+		return
+	}
+
+	// Load each referenced table once; tables that fail to load are skipped
+	tableColumns := make([][]dbconnector.ColumnInfo, len(tables))
+	for i, table := range tables {
+		if cols, err := connector.GetTableSchema(table.Schema, table.Name); err == nil {
+			tableColumns[i] = cols
+		}
+	}
+
+	for i := range params {
+		ref, ok := columns[params[i].Name]
+		if !ok {
+			continue
+		}
+
+		for t, table := range tables {
+			// A qualified column belongs to the table with that alias or name
+			if ref.Qualifier != "" && !strings.EqualFold(ref.Qualifier, table.Alias) && !strings.EqualFold(ref.Qualifier, table.Name) {
+				continue
+			}
+			col := findColumn(tableColumns[t], ref.Column)
+			if col == nil {
+				continue
+			}
+
+			typ, format := sqlparser.ParameterTypeForColumn(col.Type)
+			if params[i].Type == "array" {
+				params[i].Items = typ
+			} else {
+				params[i].Type = typ
+			}
+			params[i].Format = format
+
+			schema := table.Schema
+			if schema == "" {
+				schema = connector.DefaultSchema()
+			}
+			if schema != "" {
+				params[i].Source = schema + "." + table.Name + "." + col.Name
+			} else {
+				params[i].Source = table.Name + "." + col.Name
+			}
+			break
+		}
+	}
+}
+
+// findColumn looks up a column by name, ignoring case
+func findColumn(columns []dbconnector.ColumnInfo, name string) *dbconnector.ColumnInfo {
+	for i := range columns {
+		if strings.EqualFold(columns[i].Name, name) {
+			return &columns[i]
+		}
+	}
+	return nil
+}
+
 // GetParameters returns the parameters for a query
 func (s *queryService) GetParameters(id string, userID uint) ([]model.QueryParameter, error) {
 	q, err := s.queryRepo.FindByIDAndUserID(id, userID)
@@ -346,6 +684,12 @@ func (s *queryService) ExtractParameters(sqlTemplate string) ([]model.QueryParam
 	return s.extractParametersFromSQL(sqlTemplate), nil
 }
 
+// extractLineage records which tables and columns a SQL template reads
+func extractLineage(sqlTemplate string, dialect sqlparser.Dialect) *model.QueryLineage {
+	lineage := model.QueryLineage(*sqlparser.ExtractLineage(sqlTemplate, dialect))
+	return &lineage
+}
+
 // extractParametersFromSQL extracts and converts parameters from SQL template
 func (s *queryService) extractParametersFromSQL(sqlTemplate string) []model.QueryParameter {
 	paramInfos := sqlparser.ExtractParametersWithInfo(sqlTemplate)
@@ -355,7 +699,8 @@ func (s *queryService) extractParametersFromSQL(sqlTemplate string) []model.Quer
 		params[i] = model.QueryParameter{
 			Name:        info.Name,
 			Type:        info.Type,
-			Required:    true,
+			Items:       info.Items,
+			Required:    !info.Optional,
 			Description: fmt.Sprintf("Parameter %s", info.Name),
 		}
 	}
@@ -363,14 +708,29 @@ func (s *queryService) extractParametersFromSQL(sqlTemplate string) []model.Quer
 	return params
 }
 
-// ExecuteRawQuery executes a raw SQL query against a datasource (for testing/preview)
+// ExecuteRawQuery executes ad-hoc SQL against a data source, as the SQL
+// console does, under the same read-only checks, result limits and timeout
+// as saved queries
 func (s *queryService) ExecuteRawQuery(userID uint, dataSourceID, sqlTemplate string, params map[string]interface{}) (*model.ExecuteQueryResponse, error) {
+	// Get DataSource
+	ds, err := s.dsRepo.FindByIDAndUserID(dataSourceID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrDataSourceNotFound) {
+			return nil, ErrDataSourceNotFound
+		}
+		return nil, err
+	}
+
+	// Accept @name, $1 and ? parameters pasted from other clients
+	dialect := sqlparser.DialectFor(ds.Type)
+	sqlTemplate, _ = sqlparser.NormalizeParameters(sqlTemplate, dialect)
+
 	// Validate SQL
-	if err := sqlparser.ValidateSQLSyntax(sqlTemplate); err != nil {
+	if err := sqlparser.ValidateSQLSyntax(sqlTemplate, dialect); err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidSQL, err)
 	}
 
-	if err := sqlparser.ValidateReadOnlySQL(sqlTemplate); err != nil {
+	if err := sqlparser.ValidateReadOnlyStatements(sqlTemplate, dialect, readOnlyOptions()); err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrNonReadOnlySQL, err)
 	}
 
@@ -379,12 +739,6 @@ func (s *queryService) ExecuteRawQuery(userID uint, dataSourceID, sqlTemplate st
 		return nil, fmt.Errorf("%w: %v", ErrMissingParameters, err)
 	}
 
-	// Get DataSource
-	ds, err := s.dsRepo.FindByIDAndUserID(dataSourceID, userID)
-	if err != nil {
-		return nil, err
-	}
-
 	// Decrypt password
 	password, err := crypto.Decrypt(ds.Password)
 	if err != nil {
@@ -400,6 +754,7 @@ func (s *queryService) ExecuteRawQuery(userID uint, dataSourceID, sqlTemplate st
 		Password: password,
 		Database: ds.Database,
 		SSLMode:  ds.SSLMode,
+		ReadOnly: ds.IsReadOnly(),
 	}
 
 	connector := dbconnector.NewConnector(config)
@@ -409,8 +764,10 @@ func (s *queryService) ExecuteRawQuery(userID uint, dataSourceID, sqlTemplate st
 	defer connector.Close()
 
 	// Execute query with ordered columns
+	ctx, cancel := executionContext()
+	defer cancel()
 	start := time.Now()
-	queryResult, err := connector.ExecuteQueryWithColumns(sqlTemplate, params)
+	queryResult, err := connector.ExecuteQueryWithLimitsContext(ctx, sqlTemplate, params, resultLimits())
 	executionTime := time.Since(start).Milliseconds()
 
 	if err != nil {
@@ -419,12 +776,101 @@ func (s *queryService) ExecuteRawQuery(userID uint, dataSourceID, sqlTemplate st
 
 	return &model.ExecuteQueryResponse{
 		Columns:         queryResult.Columns,
+		ColumnTypes:     toResultColumns(queryResult.ColumnTypes),
 		Data:            queryResult.Data,
 		RowCount:        len(queryResult.Data),
+		Truncated:       queryResult.Truncated,
 		ExecutionTimeMs: executionTime,
+		ResultSets:      toResultSetResponses(queryResult.ResultSets),
 	}, nil
 }
 
+// executionContext returns the context a synchronous execution runs under,
+// cancelled after the configured query timeout. The first positive override,
+// e.g. an MCP server's timeout, shortens it but can never extend it.
+func executionContext(timeoutSeconds ...int) (context.Context, context.CancelFunc) {
+	return withExecutionTimeout(context.Background(), timeoutSeconds...)
+}
+
+// withExecutionTimeout is executionContext derived from parent, such as the
+// context of the HTTP request the execution serves
+func withExecutionTimeout(parent context.Context, timeoutSeconds ...int) (context.Context, context.CancelFunc) {
+	timeout := time.Duration(defaultQueryTimeoutSeconds) * time.Second
+	if config.AppConfig != nil {
+		timeout = time.Duration(config.AppConfig.Query.TimeoutSeconds) * time.Second
+	}
+	for _, n := range timeoutSeconds {
+		if n > 0 {
+			if override := time.Duration(n) * time.Second; timeout <= 0 || override < timeout {
+				timeout = override
+			}
+			break
+		}
+	}
+	if timeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, timeout)
+}
+
+// resultLimits returns the configured bounds on query results. The first
+// positive override, e.g. a tool's then its server's row cap, replaces the
+// global row limit but can never raise it.
+func resultLimits(maxRows ...int) dbconnector.ResultLimits {
+	limits := dbconnector.ResultLimits{MaxRows: defaultMaxResultRows, MaxBytes: defaultMaxResultBytes}
+	if config.AppConfig != nil {
+		limits = dbconnector.ResultLimits{
+			MaxRows:  config.AppConfig.Query.MaxResultRows,
+			MaxBytes: config.AppConfig.Query.MaxResultBytes,
+		}
+	}
+
+	for _, n := range maxRows {
+		if n > 0 {
+			if limits.MaxRows <= 0 || n < limits.MaxRows {
+				limits.MaxRows = n
+			}
+			break
+		}
+	}
+	return limits
+}
+
+// toResultColumns converts connector column metadata for API responses
+func toResultColumns(meta []dbconnector.ColumnMeta) []model.ResultColumn {
+	columns := make([]model.ResultColumn, len(meta))
+	for i, m := range meta {
+		columns[i] = model.ResultColumn{
+			Name:         m.Name,
+			DatabaseType: m.DatabaseType,
+			Nullable:     m.Nullable,
+			Precision:    m.Precision,
+			Scale:        m.Scale,
+			Length:       m.Length,
+		}
+	}
+	return columns
+}
+
+// toResultSetResponses converts the result sets after the first for API
+// responses
+func toResultSetResponses(sets []dbconnector.ResultSet) []model.ResultSetResponse {
+	if len(sets) == 0 {
+		return nil
+	}
+	responses := make([]model.ResultSetResponse, len(sets))
+	for i, set := range sets {
+		responses[i] = model.ResultSetResponse{
+			Columns:     set.Columns,
+			ColumnTypes: toResultColumns(set.ColumnTypes),
+			Data:        set.Data,
+			RowCount:    len(set.Data),
+			Truncated:   set.Truncated,
+		}
+	}
+	return responses
+}
+
 // serializeParams converts parameters map to JSON string
 func serializeParams(params map[string]interface{}) (string, error) {
 	if params == nil || len(params) == 0 {
@@ -449,8 +895,17 @@ func deserializeParams(paramsJSON string) map[string]interface{} {
 	return params
 }
 
-// GetExecutionHistory returns execution history for queries
-func (s *queryService) GetExecutionHistory(userID uint, queryID string, page, size int) ([]model.QueryExecutionResponse, int64, error) {
+// PurgeCache removes the cached results of a query, for every revision and
+// parameter set
+func (s *queryService) PurgeCache(id string, userID uint) (*model.CachePurgeResponse, error) {
+	if _, err := s.queryRepo.FindByIDAndUserID(id, userID); err != nil {
+		return nil, err
+	}
+	return s.cache.Purge([]string{id})
+}
+
+// GetExecutionHistory returns execution history for queries, narrowed by filter
+func (s *queryService) GetExecutionHistory(userID uint, filter *model.ExecutionHistoryFilter, page, size int) ([]model.QueryExecutionResponse, int64, error) {
 	// Set defaults
 	if page < 1 {
 		page = 1
@@ -459,36 +914,99 @@ func (s *queryService) GetExecutionHistory(userID uint, queryID string, page, si
 		size = 20
 	}
 
-	var executions []model.QueryExecution
-	var total int64
-	var err error
+	executions, total, err := s.queryRepo.FindExecutions(userID, filter, page, size)
+	if err != nil {
+		return nil, 0, err
+	}
 
-	if queryID != "" {
-		executions, total, err = s.queryRepo.FindExecutionsByQueryID(queryID, userID, page, size)
-	} else {
-		executions, total, err = s.queryRepo.FindExecutionsByUserID(userID, page, size)
+	responses := make([]model.QueryExecutionResponse, len(executions))
+	for i := range executions {
+		responses[i] = toExecutionResponse(&executions[i])
 	}
 
+	return responses, total, nil
+}
+
+// RerunExecution executes the query of an earlier execution again, at its
+// current revision, with the same parameters
+func (s *queryService) RerunExecution(executionID string, userID uint) (*model.ExecuteQueryResponse, error) {
+	exec, err := s.queryRepo.FindExecution(executionID, userID)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
+	return s.Execute(exec.QueryID, userID, &model.ExecuteQueryRequest{
+		Parameters: deserializeParams(exec.Parameters),
+	})
+}
 
-	responses := make([]model.QueryExecutionResponse, len(executions))
-	for i, exec := range executions {
-		responses[i] = model.QueryExecutionResponse{
-			ID:              exec.ID,
-			QueryID:         exec.QueryID,
-			Parameters:      deserializeParams(exec.Parameters),
-			RowCount:        exec.RowCount,
-			ExecutionTimeMs: exec.ExecutionTimeMs,
-			Status:          exec.Status,
-			ErrorMessage:    exec.ErrorMessage,
-			CreatedAt:       exec.CreatedAt,
+// CompareExecutions compares the query, parameters and outcome of two executions
+func (s *queryService) CompareExecutions(userID uint, fromID, toID string) (*model.ExecutionComparison, error) {
+	from, err := s.queryRepo.FindExecution(fromID, userID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.queryRepo.FindExecution(toID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return compareExecutions(from, to), nil
+}
+
+// compareExecutions lists what differs between two executions. Parameter
+// changes are sorted by name.
+func compareExecutions(from, to *model.QueryExecution) *model.ExecutionComparison {
+	cmp := &model.ExecutionComparison{
+		From:                 toExecutionResponse(from),
+		To:                   toExecutionResponse(to),
+		SameQuery:            from.QueryID == to.QueryID,
+		StatusChanged:        from.Status != to.Status,
+		ParameterChanges:     []model.ParameterValueChange{},
+		RowCountDelta:        to.RowCount - from.RowCount,
+		ExecutionTimeDeltaMs: to.ExecutionTimeMs - from.ExecutionTimeMs,
+	}
+	cmp.RevisionChanged = cmp.SameQuery && from.QueryRevision != to.QueryRevision
+
+	fromParams := cmp.From.Parameters
+	toParams := cmp.To.Parameters
+	names := make([]string, 0, len(fromParams)+len(toParams))
+	for name := range fromParams {
+		names = append(names, name)
+	}
+	for name := range toParams {
+		if _, ok := fromParams[name]; !ok {
+			names = append(names, name)
 		}
-		if exec.Query.ID != "" {
-			responses[i].QueryName = exec.Query.Name
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		before, hadBefore := fromParams[name]
+		after, hasAfter := toParams[name]
+		if hadBefore && hasAfter && reflect.DeepEqual(before, after) {
+			continue
 		}
+		cmp.ParameterChanges = append(cmp.ParameterChanges, model.ParameterValueChange{Name: name, From: before, To: after})
 	}
+	return cmp
+}
 
-	return responses, total, nil
+// toExecutionResponse converts an execution record for the history API
+func toExecutionResponse(exec *model.QueryExecution) model.QueryExecutionResponse {
+	resp := model.QueryExecutionResponse{
+		ID:              exec.ID,
+		QueryID:         exec.QueryID,
+		QueryRevision:   exec.QueryRevision,
+		Parameters:      deserializeParams(exec.Parameters),
+		RowCount:        exec.RowCount,
+		ExecutionTimeMs: exec.ExecutionTimeMs,
+		Status:          exec.Status,
+		Format:          exec.Format,
+		Truncated:       exec.Truncated,
+		ErrorMessage:    exec.ErrorMessage,
+		CreatedAt:       exec.CreatedAt,
+	}
+	if exec.Query.ID != "" {
+		resp.QueryName = exec.Query.Name
+	}
+	return resp
 }
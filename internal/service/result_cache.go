@@ -0,0 +1,314 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/yourusername/dataweaver/config"
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/internal/repository"
+	"github.com/yourusername/dataweaver/pkg/dbconnector"
+	"github.com/yourusername/dataweaver/pkg/logger"
+	"github.com/yourusername/dataweaver/pkg/resultcache"
+	"go.uber.org/zap"
+)
+
+// Result cache runtime settings
+const (
+	cachePurgePollInterval = 5 * time.Second // How often purges made by other instances are picked up
+	cacheSweepInterval     = time.Minute     // How often expired shared entries are deleted
+	cachePurgeRetention    = time.Hour       // How long purge records are kept for other instances
+)
+
+// Result cache settings used when no configuration has been loaded
+const (
+	defaultCacheTTL            = 5 * time.Minute
+	defaultCacheMaxMemoryBytes = 64 << 20
+	defaultCacheMaxEntryBytes  = 4 << 20
+)
+
+// cachedResult is a query result as stored in the cache
+type cachedResult struct {
+	Columns     []string                 `json:"columns"`
+	ColumnTypes []dbconnector.ColumnMeta `json:"column_types"`
+	Data        []map[string]interface{} `json:"data"`
+	Truncated   bool                     `json:"truncated"`
+	ResultSets  []cachedResultSet        `json:"result_sets,omitempty"`
+	CachedAt    time.Time                `json:"cached_at"`
+}
+
+// cachedResultSet is a result set after the first, as stored in the cache
+type cachedResultSet struct {
+	Columns     []string                 `json:"columns"`
+	ColumnTypes []dbconnector.ColumnMeta `json:"column_types"`
+	Data        []map[string]interface{} `json:"data"`
+	Truncated   bool                     `json:"truncated"`
+}
+
+// QueryResult returns the cached result in connector form
+func (r *cachedResult) QueryResult() *dbconnector.QueryResult {
+	result := &dbconnector.QueryResult{
+		Columns:     r.Columns,
+		ColumnTypes: r.ColumnTypes,
+		Data:        r.Data,
+		Truncated:   r.Truncated,
+	}
+	for _, set := range r.ResultSets {
+		result.ResultSets = append(result.ResultSets, dbconnector.ResultSet(set))
+	}
+	return result
+}
+
+// ResultCache caches query results by query revision, parameters and result
+// limits. Every instance keeps recently used results in memory; with the
+// shared tier enabled, results are also stored in the metadata database so
+// that instances answer from each other's results, and purges made on one
+// instance reach the memory of the others.
+type ResultCache struct {
+	repo     repository.ResultCacheRepository
+	memory   *resultcache.LRU
+	settings cacheConfig
+
+	mu        sync.Mutex
+	lastPurge uint // Newest purge record already applied to memory
+
+	ctx    context.Context
+	stop   context.CancelFunc
+	wg     sync.WaitGroup
+	closed bool
+}
+
+// NewResultCache creates a result cache, using repo for the shared tier
+func NewResultCache(repo repository.ResultCacheRepository) *ResultCache {
+	settings := cacheSettings()
+	ctx, stop := context.WithCancel(context.Background())
+	return &ResultCache{
+		repo:     repo,
+		memory:   resultcache.NewLRU(settings.MaxMemoryBytes),
+		settings: settings,
+		ctx:      ctx,
+		stop:     stop,
+	}
+}
+
+// TTL returns how long a tool's results are cached: the tool's own TTL in
+// seconds, the default for 0, or nothing for a negative TTL
+func (c *ResultCache) TTL(toolTTL int) time.Duration {
+	if toolTTL < 0 {
+		return 0
+	}
+	if toolTTL > 0 {
+		return time.Duration(toolTTL) * time.Second
+	}
+	return c.settings.DefaultTTL
+}
+
+// Get returns the cached result stored under key. A memory miss falls
+// through to the shared tier, and a shared hit is kept in memory.
+func (c *ResultCache) Get(key string) (*cachedResult, bool) {
+	now := time.Now()
+	value, _, ok := c.memory.Get(key, now)
+	if !ok && c.settings.Shared {
+		entry, err := c.repo.Get(key, now)
+		if err != nil {
+			if !errors.Is(err, repository.ErrResultCacheMiss) {
+				logger.Warn("Failed to read shared result cache", zap.Error(err))
+			}
+			return nil, false
+		}
+		value = entry.Value
+		c.memory.Set(key, entry.QueryID, value, entry.ExpiresAt)
+		ok = true
+	}
+	if !ok {
+		return nil, false
+	}
+
+	var result cachedResult
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber() // Keep large integers exact
+	if err := decoder.Decode(&result); err != nil {
+		logger.Warn("Failed to decode cached result", zap.Error(err))
+		return nil, false
+	}
+	return &result, true
+}
+
+// Set caches a result of a query for ttl. Results larger than the
+// configured entry size are not cached.
+func (c *ResultCache) Set(key, queryID string, result *dbconnector.QueryResult, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	now := time.Now()
+	entry := &cachedResult{
+		Columns:     result.Columns,
+		ColumnTypes: result.ColumnTypes,
+		Data:        result.Data,
+		Truncated:   result.Truncated,
+		CachedAt:    now,
+	}
+	for _, set := range result.ResultSets {
+		entry.ResultSets = append(entry.ResultSets, cachedResultSet(set))
+	}
+	value, err := json.Marshal(entry)
+	if err != nil {
+		logger.Warn("Failed to encode result for caching", zap.Error(err))
+		return
+	}
+	if int64(len(value)) > c.settings.MaxEntryBytes {
+		return
+	}
+
+	expiresAt := now.Add(ttl)
+	c.memory.Set(key, queryID, value, expiresAt)
+	if c.settings.Shared {
+		if err := c.repo.Set(&model.ResultCacheEntry{
+			Key:       key,
+			QueryID:   queryID,
+			Value:     value,
+			ExpiresAt: expiresAt,
+		}); err != nil {
+			logger.Warn("Failed to write shared result cache", zap.Error(err))
+		}
+	}
+}
+
+// Purge removes the cached results of queries from memory and, with the
+// shared tier enabled, from the database and the memory of other instances
+func (c *ResultCache) Purge(queryIDs []string) (*model.CachePurgeResponse, error) {
+	resp := &model.CachePurgeResponse{Queries: len(queryIDs)}
+	for _, id := range queryIDs {
+		resp.Memory += c.memory.PurgeGroup(id)
+	}
+	if c.settings.Shared {
+		deleted, err := c.repo.Purge(queryIDs)
+		if err != nil {
+			return nil, err
+		}
+		resp.Shared = deleted
+	}
+	return resp, nil
+}
+
+// Start follows purges made by other instances and deletes expired shared
+// entries until Stop is called. It does nothing without the shared tier.
+func (c *ResultCache) Start() {
+	if !c.settings.Shared {
+		return
+	}
+
+	// Purges made before this instance started cannot affect its memory
+	latest, err := c.repo.LatestPurgeID()
+	if err != nil {
+		logger.Warn("Failed to find latest result cache purge", zap.Error(err))
+	}
+	c.lastPurge = latest
+
+	c.wg.Add(1)
+	go c.loop()
+}
+
+// Stop stops following purges and waits for the loop to exit
+func (c *ResultCache) Stop() {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return
+	}
+	c.closed = true
+	c.mu.Unlock()
+
+	c.stop()
+	c.wg.Wait()
+}
+
+// loop applies purge records to memory and periodically sweeps the shared tier
+func (c *ResultCache) loop() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(cachePurgePollInterval)
+	defer ticker.Stop()
+	lastSweep := time.Time{}
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		purges, err := c.repo.FindPurgesAfter(c.lastPurge)
+		if err != nil {
+			logger.Warn("Failed to find result cache purges", zap.Error(err))
+		}
+		for _, purge := range purges {
+			c.memory.PurgeGroup(purge.QueryID)
+			c.lastPurge = purge.ID
+		}
+
+		if now := time.Now(); now.Sub(lastSweep) >= cacheSweepInterval {
+			lastSweep = now
+			if _, err := c.repo.DeleteExpired(now, now.Add(-cachePurgeRetention)); err != nil {
+				logger.Warn("Failed to delete expired cached results", zap.Error(err))
+			}
+		}
+	}
+}
+
+// resultCacheKey identifies the result of running a query definition with
+// parameters on a data source under result limits. Parameters are coerced to
+// their declared types and encoded with sorted keys, so 5 and "5" for an
+// integer, or the order arguments were given in, do not matter. Editing the
+// data source changes its UpdatedAt, so results from before the edit are
+// not served.
+func resultCacheKey(q *model.Query, ds *model.DataSource, paramTypes model.ToolParameters, params map[string]interface{}, limits dbconnector.ResultLimits) string {
+	data, _ := json.Marshal(struct {
+		QueryID             string                 `json:"query_id"`
+		Revision            int                    `json:"revision"`
+		DataSourceID        string                 `json:"data_source_id"`
+		DataSourceUpdatedAt time.Time              `json:"data_source_updated_at"`
+		SQLTemplate         string                 `json:"sql_template"`
+		Parameters          map[string]interface{} `json:"parameters"`
+		MaxRows             int                    `json:"max_rows"`
+		MaxBytes            int64                  `json:"max_bytes"`
+	}{
+		q.ID, q.Revision, ds.ID, ds.UpdatedAt, q.SQLTemplate,
+		coerceToolParameters(paramTypes, params), limits.MaxRows, limits.MaxBytes,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// cacheConfig is the result cache configuration with defaults applied
+type cacheConfig struct {
+	DefaultTTL     time.Duration
+	MaxMemoryBytes int64
+	MaxEntryBytes  int64
+	Shared         bool
+}
+
+// cacheSettings returns the configured result cache settings
+func cacheSettings() cacheConfig {
+	if config.AppConfig == nil {
+		return cacheConfig{
+			DefaultTTL:     defaultCacheTTL,
+			MaxMemoryBytes: defaultCacheMaxMemoryBytes,
+			MaxEntryBytes:  defaultCacheMaxEntryBytes,
+		}
+	}
+	cfg := config.AppConfig.Cache
+	return cacheConfig{
+		DefaultTTL:     time.Duration(cfg.DefaultTTL) * time.Second,
+		MaxMemoryBytes: cfg.MaxMemoryBytes,
+		MaxEntryBytes:  cfg.MaxEntryBytes,
+		Shared:         cfg.Shared,
+	}
+}
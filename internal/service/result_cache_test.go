@@ -0,0 +1,97 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/pkg/dbconnector"
+)
+
+func TestResultCacheKey(t *testing.T) {
+	q := &model.Query{ID: "q1", Revision: 3, DataSourceID: "ds1", SQLTemplate: "SELECT * FROM t WHERE a = {{a}}"}
+	ds := &model.DataSource{ID: "ds1", UpdatedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+	types := model.ToolParameters{{Name: "a", Type: "integer"}, {Name: "b", Type: "string"}}
+	limits := dbconnector.ResultLimits{MaxRows: 100, MaxBytes: 1 << 20}
+
+	key := resultCacheKey(q, ds, types, map[string]interface{}{"a": 1, "b": "x"}, limits)
+	assert.Len(t, key, 64)
+	assert.Equal(t, key, resultCacheKey(q, ds, types, map[string]interface{}{"b": "x", "a": 1}, limits))
+	assert.Equal(t, resultCacheKey(q, ds, types, nil, limits), resultCacheKey(q, ds, types, map[string]interface{}{}, limits))
+
+	// Arguments are keyed by their value as the declared type
+	assert.Equal(t, key, resultCacheKey(q, ds, types, map[string]interface{}{"a": "1", "b": "x"}, limits))
+	assert.Equal(t, key, resultCacheKey(q, ds, types, map[string]interface{}{"a": 1.0, "b": "x"}, limits))
+
+	assert.NotEqual(t, key, resultCacheKey(q, ds, types, map[string]interface{}{"a": 2, "b": "x"}, limits))
+	assert.NotEqual(t, key, resultCacheKey(q, ds, types, map[string]interface{}{"a": 1, "b": "x"}, dbconnector.ResultLimits{MaxRows: 10, MaxBytes: 1 << 20}))
+
+	revised := *q
+	revised.Revision = 4
+	assert.NotEqual(t, key, resultCacheKey(&revised, ds, types, map[string]interface{}{"a": 1, "b": "x"}, limits))
+
+	// Editing the data source invalidates its results
+	edited := *ds
+	edited.UpdatedAt = ds.UpdatedAt.Add(time.Minute)
+	assert.NotEqual(t, key, resultCacheKey(q, &edited, types, map[string]interface{}{"a": 1, "b": "x"}, limits))
+}
+
+func TestResultCache_MemoryTier(t *testing.T) {
+	cache := NewResultCache(nil)
+	result := &dbconnector.QueryResult{
+		Columns: []string{"id", "name"},
+		Data:    []map[string]interface{}{{"id": int64(12345678901234), "name": "alice"}},
+	}
+
+	_, ok := cache.Get("k")
+	assert.False(t, ok)
+
+	cache.Set("k", "q1", result, time.Minute)
+	cached, ok := cache.Get("k")
+	require.True(t, ok)
+	assert.Equal(t, []string{"id", "name"}, cached.Columns)
+	assert.Equal(t, json.Number("12345678901234"), cached.Data[0]["id"])
+	assert.WithinDuration(t, time.Now(), cached.CachedAt, time.Minute)
+
+	purged, err := cache.Purge([]string{"q1"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged.Memory)
+	_, ok = cache.Get("k")
+	assert.False(t, ok)
+}
+
+func TestResultCache_ResultSets(t *testing.T) {
+	cache := NewResultCache(nil)
+	result := &dbconnector.QueryResult{
+		Columns: []string{"id"},
+		Data:    []map[string]interface{}{{"id": "a"}},
+		ResultSets: []dbconnector.ResultSet{
+			{Columns: []string{"total"}, Data: []map[string]interface{}{{"total": "1"}}, Truncated: true},
+		},
+	}
+
+	cache.Set("k", "q1", result, time.Minute)
+	cached, ok := cache.Get("k")
+	require.True(t, ok)
+
+	restored := cached.QueryResult()
+	require.Len(t, restored.ResultSets, 1)
+	assert.Equal(t, []string{"total"}, restored.ResultSets[0].Columns)
+	assert.Equal(t, "1", restored.ResultSets[0].Data[0]["total"])
+	assert.True(t, restored.ResultSets[0].Truncated)
+}
+
+func TestResultCache_TTL(t *testing.T) {
+	cache := NewResultCache(nil)
+
+	assert.Equal(t, defaultCacheTTL, cache.TTL(0))
+	assert.Equal(t, 30*time.Second, cache.TTL(30))
+	assert.Equal(t, time.Duration(0), cache.TTL(-1))
+
+	cache.Set("k", "q1", &dbconnector.QueryResult{}, cache.TTL(-1))
+	_, ok := cache.Get("k")
+	assert.False(t, ok)
+}
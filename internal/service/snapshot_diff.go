@@ -0,0 +1,133 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/yourusername/dataweaver/internal/model"
+)
+
+// normalizeRows converts rows to the form they are stored in, so that fresh
+// rows compare equal to the same rows read back from a snapshot
+func normalizeRows(rows []map[string]interface{}) (model.ResultRows, error) {
+	data, err := model.ResultRows(rows).Value()
+	if err != nil {
+		return nil, err
+	}
+	var normalized model.ResultRows
+	if err := normalized.Scan(data); err != nil {
+		return nil, err
+	}
+	if normalized == nil {
+		normalized = model.ResultRows{}
+	}
+	return normalized, nil
+}
+
+// diffRows compares two results. With key columns, rows with the same key
+// are matched and compared column by column; without them, rows are compared
+// whole, so a modified row shows as removed and added. Rows sharing a key are
+// matched in order.
+func diffRows(keyColumns, columns []string, before, after model.ResultRows) (*model.SnapshotDiff, error) {
+	for _, key := range keyColumns {
+		if !containsString(columns, key) {
+			return nil, fmt.Errorf("%w: %s", ErrKeyColumnNotInResult, key)
+		}
+	}
+
+	keyOf := func(row map[string]interface{}) string {
+		if len(keyColumns) == 0 {
+			return encodeValue(row)
+		}
+		values := make([]interface{}, len(keyColumns))
+		for i, key := range keyColumns {
+			values[i] = row[key]
+		}
+		return encodeValue(values)
+	}
+
+	pending := make(map[string][]map[string]interface{})
+	for _, row := range before {
+		key := keyOf(row)
+		pending[key] = append(pending[key], row)
+	}
+
+	diff := &model.SnapshotDiff{
+		KeyColumns: keyColumns,
+		Added:      []map[string]interface{}{},
+		Removed:    []map[string]interface{}{},
+		Changed:    []model.SnapshotRowChange{},
+	}
+	if diff.KeyColumns == nil {
+		diff.KeyColumns = []string{}
+	}
+
+	matched := make(map[string]int)
+	for _, row := range after {
+		key := keyOf(row)
+		old := pending[key]
+		if len(old) == 0 {
+			diff.Added = append(diff.Added, row)
+			continue
+		}
+		pending[key] = old[1:]
+		matched[key]++
+
+		if changed := changedColumns(columns, old[0], row); len(changed) > 0 {
+			keyValues := make(map[string]interface{}, len(keyColumns))
+			for _, k := range keyColumns {
+				keyValues[k] = row[k]
+			}
+			diff.Changed = append(diff.Changed, model.SnapshotRowChange{
+				Key:     keyValues,
+				Columns: changed,
+				Before:  old[0],
+				After:   row,
+			})
+		}
+	}
+
+	// Rows of the previous result left unmatched were removed, listed in
+	// their original order
+	seen := make(map[string]int)
+	for _, row := range before {
+		key := keyOf(row)
+		seen[key]++
+		if seen[key] > matched[key] {
+			diff.Removed = append(diff.Removed, row)
+		}
+	}
+
+	return diff, nil
+}
+
+// changedColumns lists the columns whose values differ between two rows, in
+// result column order followed by columns only the previous row had
+func changedColumns(columns []string, before, after map[string]interface{}) []string {
+	var changed []string
+	for _, column := range columns {
+		if encodeValue(before[column]) != encodeValue(after[column]) {
+			changed = append(changed, column)
+		}
+	}
+
+	var dropped []string
+	for column := range before {
+		if _, ok := after[column]; !ok && !containsString(columns, column) {
+			dropped = append(dropped, column)
+		}
+	}
+	sort.Strings(dropped)
+	return append(changed, dropped...)
+}
+
+// encodeValue encodes a value for comparison. Map keys are encoded in sorted
+// order, so equal rows encode the same.
+func encodeValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/dataweaver/internal/model"
+)
+
+func TestDiffRows(t *testing.T) {
+	columns := []string{"id", "name", "balance"}
+	before, err := normalizeRows([]map[string]interface{}{
+		{"id": 1, "name": "alice", "balance": "10.50"},
+		{"id": 2, "name": "bob", "balance": "3.00"},
+		{"id": 3, "name": "carol", "balance": "7.25"},
+	})
+	require.NoError(t, err)
+	after, err := normalizeRows([]map[string]interface{}{
+		{"id": 1, "name": "alice", "balance": "10.50"},
+		{"id": 3, "name": "carol", "balance": "8.00"},
+		{"id": 4, "name": "dave", "balance": "1.00"},
+	})
+	require.NoError(t, err)
+
+	t.Run("by key columns", func(t *testing.T) {
+		diff, err := diffRows([]string{"id"}, columns, before, after)
+		require.NoError(t, err)
+
+		assert.Len(t, diff.Added, 1)
+		assert.Equal(t, "dave", diff.Added[0]["name"])
+		assert.Len(t, diff.Removed, 1)
+		assert.Equal(t, "bob", diff.Removed[0]["name"])
+		require.Len(t, diff.Changed, 1)
+		assert.Equal(t, []string{"balance"}, diff.Changed[0].Columns)
+		assert.Equal(t, "7.25", diff.Changed[0].Before["balance"])
+		assert.Equal(t, "8.00", diff.Changed[0].After["balance"])
+	})
+
+	t.Run("whole rows", func(t *testing.T) {
+		diff, err := diffRows(nil, columns, before, after)
+		require.NoError(t, err)
+
+		assert.Len(t, diff.Added, 2)
+		assert.Len(t, diff.Removed, 2)
+		assert.Empty(t, diff.Changed)
+	})
+
+	t.Run("duplicate rows", func(t *testing.T) {
+		rows, err := normalizeRows([]map[string]interface{}{{"id": 1}, {"id": 1}})
+		require.NoError(t, err)
+
+		diff, err := diffRows(nil, []string{"id"}, rows, rows[:1])
+		require.NoError(t, err)
+		assert.Empty(t, diff.Added)
+		assert.Len(t, diff.Removed, 1)
+	})
+
+	t.Run("unknown key column", func(t *testing.T) {
+		_, err := diffRows([]string{"missing"}, columns, before, after)
+		assert.True(t, errors.Is(err, ErrKeyColumnNotInResult))
+	})
+}
+
+func TestNormalizeRowsMatchesStoredRows(t *testing.T) {
+	fresh, err := normalizeRows([]map[string]interface{}{{"n": int64(12345678901234)}})
+	require.NoError(t, err)
+
+	var stored model.ResultRows
+	require.NoError(t, stored.Scan([]byte(`[{"n":12345678901234}]`)))
+
+	assert.Equal(t, encodeValue(stored), encodeValue(fresh))
+}
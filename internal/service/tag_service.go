@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/internal/repository"
+)
+
+var ErrInvalidTagName = errors.New("tag name cannot be blank")
+
+// TagService handles business logic for the tags shared by queries, tools
+// and MCP servers
+type TagService interface {
+	List(userID uint) ([]model.TagResponse, error)
+	Rename(id string, userID uint, req *model.RenameTagRequest) (*model.TagResponse, error)
+	// Delete deletes a tag, removing it from every item
+	Delete(id string, userID uint) error
+}
+
+type tagService struct {
+	tagRepo repository.TagRepository
+}
+
+// NewTagService creates a new TagService
+func NewTagService(tagRepo repository.TagRepository) TagService {
+	return &tagService{tagRepo: tagRepo}
+}
+
+// List returns a user's tags with how many items of each type carry them
+func (s *tagService) List(userID uint) ([]model.TagResponse, error) {
+	tags, err := s.tagRepo.FindAll(userID)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.tagRepo.CountUsage(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]model.TagResponse, len(tags))
+	index := make(map[string]*model.TagResponse, len(tags))
+	for i, tag := range tags {
+		responses[i] = model.TagResponse{ID: tag.ID, Name: tag.Name, CreatedAt: tag.CreatedAt}
+		index[tag.ID] = &responses[i]
+	}
+	for _, u := range usage {
+		resp := index[u.TagID]
+		if resp == nil {
+			continue
+		}
+		switch u.ResourceType {
+		case model.ResourceQuery:
+			resp.Queries = u.Count
+		case model.ResourceTool:
+			resp.Tools = u.Count
+		case model.ResourceMcpServer:
+			resp.McpServers = u.Count
+		}
+	}
+	return responses, nil
+}
+
+// Rename changes the name of a tag on every item carrying it
+func (s *tagService) Rename(id string, userID uint, req *model.RenameTagRequest) (*model.TagResponse, error) {
+	names := normalizeTags([]string{req.Name})
+	if len(names) == 0 {
+		return nil, ErrInvalidTagName
+	}
+
+	tag, err := s.tagRepo.FindByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	tag.Name = names[0]
+	if err := s.tagRepo.Rename(tag); err != nil {
+		return nil, err
+	}
+	return &model.TagResponse{ID: tag.ID, Name: tag.Name, CreatedAt: tag.CreatedAt}, nil
+}
+
+// Delete deletes a tag
+func (s *tagService) Delete(id string, userID uint) error {
+	return s.tagRepo.Delete(id, userID)
+}
@@ -1,9 +1,13 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 	"unicode"
@@ -20,26 +24,34 @@ var (
 	ErrToolNameExists  = errors.New("tool name already exists")
 	ErrQueryRequired   = errors.New("query is required to create tool")
 	ErrInvalidToolName = errors.New("invalid tool name format")
+	ErrScheduleQuery   = errors.New("schedule must run the tool's query")
 )
 
 // ToolService handles business logic for tools
 type ToolService interface {
 	Create(userID uint, req *model.CreateToolRequest) (*model.ToolResponse, error)
 	CreateFromQuery(userID uint, queryID string, req *model.CreateToolFromQueryRequest) (*model.ToolResponse, error)
-	List(userID uint, page, size int, keyword string) ([]model.ToolResponse, int64, error)
+	List(userID uint, page, size int, filter *model.ListFilter) ([]model.ToolResponse, int64, error)
 	Get(id string, userID uint) (*model.ToolResponse, error)
 	Update(id string, userID uint, req *model.UpdateToolRequest) (*model.ToolResponse, error)
 	Delete(id string, userID uint) error
+	// Tags, favorites and bulk actions
+	SetTags(id string, userID uint, tags []string) (*model.ToolResponse, error)
+	SetFavorite(id string, userID uint, favorite bool) error
+	Bulk(userID uint, req *model.BulkRequest) (*model.BulkResult, error)
 	TestTool(id string, userID uint, req *model.TestToolRequest) (*model.TestToolResponse, error)
 	Export(id string, userID uint) (*model.MCPToolDefinition, error)
 	ExportAll(userID uint) ([]*model.MCPToolDefinition, error)
 	GenerateDescription(id string, userID uint, req *model.GenerateDescriptionRequest) (*model.GenerateDescriptionResponse, error)
+	FindByColumn(userID uint, req *model.ColumnUsageRequest) ([]model.ToolColumnUsage, error)
 }
 
 type toolService struct {
-	toolRepo  repository.ToolRepository
-	queryRepo repository.QueryRepository
-	dsRepo    repository.DataSourceRepository
+	toolRepo     repository.ToolRepository
+	queryRepo    repository.QueryRepository
+	dsRepo       repository.DataSourceRepository
+	scheduleRepo repository.QueryScheduleRepository
+	tagRepo      repository.TagRepository
 }
 
 // NewToolService creates a new ToolService
@@ -47,11 +59,15 @@ func NewToolService(
 	toolRepo repository.ToolRepository,
 	queryRepo repository.QueryRepository,
 	dsRepo repository.DataSourceRepository,
+	scheduleRepo repository.QueryScheduleRepository,
+	tagRepo repository.TagRepository,
 ) ToolService {
 	return &toolService{
-		toolRepo:  toolRepo,
-		queryRepo: queryRepo,
-		dsRepo:    dsRepo,
+		toolRepo:     toolRepo,
+		queryRepo:    queryRepo,
+		dsRepo:       dsRepo,
+		scheduleRepo: scheduleRepo,
+		tagRepo:      tagRepo,
 	}
 }
 
@@ -71,16 +87,35 @@ func (s *toolService) Create(userID uint, req *model.CreateToolRequest) (*model.
 		return nil, err
 	}
 
+	// A pinned revision must exist
+	if req.QueryRevision != nil {
+		if _, err := queryAtRevision(s.queryRepo, query, *req.QueryRevision); err != nil {
+			return nil, err
+		}
+	}
+
+	// Snapshots must come from a schedule of the same query
+	if req.ScheduleID != nil {
+		if err := s.checkSchedule(*req.ScheduleID, userID, query.ID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create tool
 	tool := &model.Tool{
-		UserID:       userID,
-		Name:         req.Name,
-		DisplayName:  req.DisplayName,
-		Description:  req.Description,
-		QueryID:      req.QueryID,
-		Parameters:   model.ToolParameters(req.Parameters),
-		OutputSchema: model.OutputSchema(req.OutputSchema),
-		Status:       "active",
+		UserID:        userID,
+		Name:          req.Name,
+		DisplayName:   req.DisplayName,
+		Description:   req.Description,
+		QueryID:       req.QueryID,
+		QueryRevision: req.QueryRevision,
+		Parameters:    model.ToolParameters(req.Parameters),
+		OutputSchema:  model.OutputSchema(req.OutputSchema),
+		MaxRows:       req.MaxRows,
+		CacheTTL:      req.CacheTTL,
+		CostGuard:     req.CostGuard,
+		ScheduleID:    req.ScheduleID,
+		Status:        "active",
 	}
 
 	if err := s.toolRepo.Create(tool); err != nil {
@@ -138,16 +173,7 @@ func (s *toolService) CreateFromQuery(userID uint, queryID string, req *model.Cr
 		return nil, fmt.Errorf("failed to get query parameters: %w", err)
 	}
 
-	toolParams := make([]model.ToolParameter, len(queryParams))
-	for i, qp := range queryParams {
-		toolParams[i] = model.ToolParameter{
-			Name:        qp.Name,
-			Type:        qp.Type,
-			Required:    qp.Required,
-			Default:     qp.Default,
-			Description: qp.Description,
-		}
-	}
+	toolParams := toToolParameters(queryParams)
 
 	// Infer output schema from query (basic inference)
 	outputSchema := inferOutputSchema(query)
@@ -177,8 +203,8 @@ func (s *toolService) CreateFromQuery(userID uint, queryID string, req *model.Cr
 	return tool.ToResponse(), nil
 }
 
-// List returns all tools for a user with optional search
-func (s *toolService) List(userID uint, page, size int, keyword string) ([]model.ToolResponse, int64, error) {
+// List returns the tools of a user matching a filter
+func (s *toolService) List(userID uint, page, size int, filter *model.ListFilter) ([]model.ToolResponse, int64, error) {
 	// Set defaults
 	if page < 1 {
 		page = 1
@@ -187,16 +213,7 @@ func (s *toolService) List(userID uint, page, size int, keyword string) ([]model
 		size = 20
 	}
 
-	var tools []model.Tool
-	var total int64
-	var err error
-
-	if keyword != "" {
-		tools, total, err = s.toolRepo.Search(userID, keyword, page, size)
-	} else {
-		tools, total, err = s.toolRepo.FindAll(userID, page, size)
-	}
-
+	tools, total, err := s.toolRepo.List(userID, normalizeListFilter(filter), page, size)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -205,6 +222,9 @@ func (s *toolService) List(userID uint, page, size int, keyword string) ([]model
 	for i, t := range tools {
 		responses[i] = *t.ToResponse()
 	}
+	if err := s.labelTools(userID, responses); err != nil {
+		return nil, 0, err
+	}
 
 	return responses, total, nil
 }
@@ -215,7 +235,11 @@ func (s *toolService) Get(id string, userID uint) (*model.ToolResponse, error) {
 	if err != nil {
 		return nil, err
 	}
-	return tool.ToResponse(), nil
+	responses := []model.ToolResponse{*tool.ToResponse()}
+	if err := s.labelTools(userID, responses); err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
 }
 
 // Update updates a tool
@@ -238,7 +262,7 @@ func (s *toolService) Update(id string, userID uint, req *model.UpdateToolReques
 	if req.Description != nil {
 		tool.Description = *req.Description
 	}
-	if req.QueryID != nil {
+	if req.QueryID != nil && *req.QueryID != tool.QueryID {
 		// Validate the new query exists and belongs to the user
 		_, err := s.queryRepo.FindByIDAndUserID(*req.QueryID, userID)
 		if err != nil {
@@ -248,6 +272,23 @@ func (s *toolService) Update(id string, userID uint, req *model.UpdateToolReques
 			return nil, err
 		}
 		tool.QueryID = *req.QueryID
+		// A revision pin and a schedule belong to the previous query
+		tool.QueryRevision = nil
+		tool.ScheduleID = nil
+	}
+	if req.QueryRevision != nil {
+		if *req.QueryRevision == 0 {
+			tool.QueryRevision = nil
+		} else {
+			query, err := s.queryRepo.FindByIDAndUserID(tool.QueryID, userID)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := queryAtRevision(s.queryRepo, query, *req.QueryRevision); err != nil {
+				return nil, err
+			}
+			tool.QueryRevision = req.QueryRevision
+		}
 	}
 	if req.Parameters != nil {
 		tool.Parameters = model.ToolParameters(req.Parameters)
@@ -255,6 +296,25 @@ func (s *toolService) Update(id string, userID uint, req *model.UpdateToolReques
 	if req.OutputSchema != nil {
 		tool.OutputSchema = model.OutputSchema(req.OutputSchema)
 	}
+	if req.MaxRows != nil {
+		tool.MaxRows = *req.MaxRows
+	}
+	if req.CacheTTL != nil {
+		tool.CacheTTL = *req.CacheTTL
+	}
+	if req.CostGuard != nil {
+		tool.CostGuard = *req.CostGuard
+	}
+	if req.ScheduleID != nil {
+		if *req.ScheduleID == "" {
+			tool.ScheduleID = nil
+		} else {
+			if err := s.checkSchedule(*req.ScheduleID, userID, tool.QueryID); err != nil {
+				return nil, err
+			}
+			tool.ScheduleID = req.ScheduleID
+		}
+	}
 	if req.Status != nil {
 		tool.Status = *req.Status
 	}
@@ -266,18 +326,16 @@ func (s *toolService) Update(id string, userID uint, req *model.UpdateToolReques
 		return nil, err
 	}
 
-	// Reload with Query
-	tool, err = s.toolRepo.FindByIDWithQuery(id, userID)
-	if err != nil {
-		return nil, err
-	}
-
-	return tool.ToResponse(), nil
+	// Reload with Query and tags
+	return s.Get(id, userID)
 }
 
 // Delete deletes a tool
 func (s *toolService) Delete(id string, userID uint) error {
-	return s.toolRepo.Delete(id, userID)
+	if err := s.toolRepo.Delete(id, userID); err != nil {
+		return err
+	}
+	return s.tagRepo.DeleteResource(model.ResourceTool, id)
 }
 
 // TestTool tests a tool by executing its associated query
@@ -296,8 +354,11 @@ func (s *toolService) TestTool(id string, userID uint, req *model.TestToolReques
 		}, nil
 	}
 
-	// Get the query with DataSource
+	// Get the query with DataSource, as of the pinned revision
 	query, err := s.queryRepo.FindByIDWithDataSource(tool.QueryID, userID)
+	if err == nil {
+		query, err = queryAtRevision(s.queryRepo, query, tool.PinnedRevision())
+	}
 	if err != nil {
 		return &model.TestToolResponse{
 			Success: false,
@@ -340,6 +401,7 @@ func (s *toolService) TestTool(id string, userID uint, req *model.TestToolReques
 		Password: password,
 		Database: ds.Database,
 		SSLMode:  ds.SSLMode,
+		ReadOnly: ds.IsReadOnly(),
 	}
 
 	connector := dbconnector.NewConnector(config)
@@ -351,9 +413,25 @@ func (s *toolService) TestTool(id string, userID uint, req *model.TestToolReques
 	}
 	defer connector.Close()
 
+	limits := resultLimits(tool.MaxRows)
+	ctx, cancel := executionContext()
+	defer cancel()
+
+	// Check the plan estimates before running the query
+	var warnings []string
+	if reason := checkCostGuard(ctx, connector, tool.CostGuard, query.SQLTemplate, req.Parameters); reason != "" {
+		if tool.CostGuard.Mode == model.CostGuardReject {
+			return &model.TestToolResponse{
+				Success: false,
+				Message: fmt.Sprintf("Query refused by cost guardrail: %s", reason),
+			}, nil
+		}
+		warnings = append(warnings, fmt.Sprintf("Cost guardrail: %s", reason))
+	}
+
 	// Execute query
 	start := time.Now()
-	result, err := connector.ExecuteQueryWithColumns(query.SQLTemplate, req.Parameters)
+	result, err := connector.ExecuteQueryWithLimitsContext(ctx, query.SQLTemplate, req.Parameters, limits)
 	executionTime := time.Since(start).Milliseconds()
 
 	if err != nil {
@@ -369,8 +447,12 @@ func (s *toolService) TestTool(id string, userID uint, req *model.TestToolReques
 		Message:         "Tool executed successfully",
 		ExecutionTimeMs: executionTime,
 		RowCount:        len(result.Data),
+		Truncated:       result.Truncated,
+		Warnings:        warnings,
 		Data:            result.Data,
 		Columns:         result.Columns,
+		ColumnTypes:     toResultColumns(result.ColumnTypes),
+		ResultSets:      toResultSetResponses(result.ResultSets),
 	}, nil
 }
 
@@ -430,6 +512,95 @@ func (s *toolService) GenerateDescription(id string, userID uint, req *model.Gen
 
 // Helper functions
 
+// FindByColumn returns the tools whose query reads a column, either as the
+// source of a result column or as a column compared against a parameter
+func (s *toolService) FindByColumn(userID uint, req *model.ColumnUsageRequest) ([]model.ToolColumnUsage, error) {
+	tools, err := s.toolRepo.FindAllWithQuery(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	usages := []model.ToolColumnUsage{}
+	for _, tool := range tools {
+		query := tool.Query
+		if query.ID == "" || (req.DataSourceID != "" && query.DataSourceID != req.DataSourceID) {
+			continue
+		}
+
+		// Queries saved before lineage was recorded are analyzed on the fly
+		lineage := query.Lineage
+		if lineage == nil {
+			lineage = extractLineage(query.SQLTemplate, sqlparser.DialectFor(query.DataSource.Type))
+		}
+
+		usage := model.ToolColumnUsage{
+			ToolID:        tool.ID,
+			ToolName:      tool.Name,
+			McpServerID:   tool.McpServerID,
+			QueryID:       query.ID,
+			QueryName:     query.Name,
+			DataSourceID:  query.DataSourceID,
+			ResultColumns: []string{},
+			Parameters:    []string{},
+		}
+		for _, column := range lineage.Columns {
+			for _, source := range column.Sources {
+				if lineageMatches(lineage, source, req) {
+					usage.ResultColumns = append(usage.ResultColumns, column.Name)
+					break
+				}
+			}
+		}
+		for _, filter := range lineage.Filters {
+			if lineageMatches(lineage, filter.Column, req) && !containsString(usage.Parameters, filter.Parameter) {
+				usage.Parameters = append(usage.Parameters, filter.Parameter)
+			}
+		}
+
+		if len(usage.ResultColumns) > 0 || len(usage.Parameters) > 0 {
+			usages = append(usages, usage)
+		}
+	}
+	return usages, nil
+}
+
+// lineageMatches reports whether a lineage source may be the requested
+// column. A source without a schema matches any schema, a * source matches
+// any column of its table, and a column the parser could not tie to a table
+// matches when the query reads the requested table.
+func lineageMatches(lineage *model.QueryLineage, source sqlparser.LineageSource, req *model.ColumnUsageRequest) bool {
+	if source.Column != "*" && !strings.EqualFold(source.Column, req.Column) {
+		return false
+	}
+	if source.Column == "*" && req.Table == "" {
+		return false
+	}
+	if req.Table == "" {
+		return true
+	}
+
+	if source.Table == "" {
+		for _, table := range lineage.Tables {
+			if strings.EqualFold(table.Name, req.Table) &&
+				(req.Schema == "" || table.Schema == "" || strings.EqualFold(table.Schema, req.Schema)) {
+				return true
+			}
+		}
+		return false
+	}
+	return strings.EqualFold(source.Table, req.Table) &&
+		(req.Schema == "" || source.Schema == "" || strings.EqualFold(source.Schema, req.Schema))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 // isValidToolName validates tool name format (snake_case)
 func isValidToolName(name string) bool {
 	if name == "" {
@@ -568,6 +739,12 @@ func validateToolParameters(toolParams model.ToolParameters, inputParams map[str
 	// Validate parameter types (basic validation)
 	for _, param := range toolParams {
 		if value, exists := inputParams[param.Name]; exists {
+			if param.Type == "array" {
+				if err := validateArrayParameter(param, value); err != nil {
+					return err
+				}
+				continue
+			}
 			if err := validateParameterType(param.Name, param.Type, value); err != nil {
 				return err
 			}
@@ -577,6 +754,32 @@ func validateToolParameters(toolParams model.ToolParameters, inputParams map[str
 	return nil
 }
 
+// validateArrayParameter validates an array parameter and each of its elements
+func validateArrayParameter(param model.ToolParameter, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	items, ok := value.([]interface{})
+	if !ok {
+		return fmt.Errorf("parameter %s must be an array", param.Name)
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("parameter %s must not be empty", param.Name)
+	}
+	if len(items) > sqlparser.MaxArrayParameterLength {
+		return fmt.Errorf("parameter %s must have at most %d items", param.Name, sqlparser.MaxArrayParameterLength)
+	}
+
+	for i, item := range items {
+		if err := validateParameterType(fmt.Sprintf("%s[%d]", param.Name, i), param.Items, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // validateParameterType validates parameter type
 func validateParameterType(name, expectedType string, value interface{}) error {
 	if value == nil {
@@ -615,3 +818,137 @@ func validateParameterType(name, expectedType string, value interface{}) error {
 
 	return nil
 }
+
+// toToolParameters converts the parameters of a query to tool parameters
+func toToolParameters(queryParams []model.QueryParameter) model.ToolParameters {
+	toolParams := make(model.ToolParameters, len(queryParams))
+	for i, qp := range queryParams {
+		toolParams[i] = model.ToolParameter{
+			Name:        qp.Name,
+			Type:        qp.Type,
+			Items:       qp.Items,
+			Format:      qp.Format,
+			Required:    qp.Required,
+			Default:     qp.Default,
+			Description: qp.Description,
+		}
+	}
+	return toolParams
+}
+
+// coerceToolParameters converts arguments to the types the tool declares, so
+// that arguments meaning the same value, such as 5, 5.0 and "5" for an
+// integer, compare and hash the same. Values that do not convert are kept.
+func coerceToolParameters(toolParams model.ToolParameters, params map[string]interface{}) map[string]interface{} {
+	declared := make(map[string]model.ToolParameter, len(toolParams))
+	for _, param := range toolParams {
+		declared[param.Name] = param
+	}
+
+	coerced := make(map[string]interface{}, len(params))
+	for name, value := range params {
+		param := declared[name]
+		items, isArray := value.([]interface{})
+		if param.Type == "array" && isArray {
+			values := make([]interface{}, len(items))
+			for i, item := range items {
+				values[i] = coerceParameterValue(param.Items, item)
+			}
+			coerced[name] = values
+			continue
+		}
+		coerced[name] = coerceParameterValue(param.Type, value)
+	}
+	return coerced
+}
+
+// coerceParameterValue converts one value to a parameter type. Numbers of
+// undeclared parameters become float64, as JSON decoding makes them.
+func coerceParameterValue(paramType string, value interface{}) interface{} {
+	switch paramType {
+	case "integer", "number":
+		n, ok := parameterNumber(value, true)
+		if !ok {
+			return value
+		}
+		if paramType == "integer" && n == math.Trunc(n) {
+			return int64(n)
+		}
+		return n
+	case "boolean":
+		if s, ok := value.(string); ok {
+			if b, err := strconv.ParseBool(s); err == nil {
+				return b
+			}
+		}
+		return value
+	case "string", "date", "datetime":
+		if b, ok := value.(bool); ok {
+			return strconv.FormatBool(b)
+		}
+		if n, ok := parameterNumber(value, false); ok {
+			return strconv.FormatFloat(n, 'f', -1, 64)
+		}
+		return value
+	default:
+		if n, ok := parameterNumber(value, false); ok {
+			return n
+		}
+		return value
+	}
+}
+
+// parameterNumber reads a numeric value, and numeric strings if asked
+func parameterNumber(value interface{}, parseStrings bool) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	case string:
+		if !parseStrings {
+			return 0, false
+		}
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// checkSchedule checks that a schedule of the user runs the given query
+func (s *toolService) checkSchedule(scheduleID string, userID uint, queryID string) error {
+	schedule, err := s.scheduleRepo.FindByIDAndUserID(scheduleID, userID)
+	if err != nil {
+		return err
+	}
+	if schedule.QueryID != queryID {
+		return ErrScheduleQuery
+	}
+	return nil
+}
+
+// checkCostGuard explains the query and compares the optimizer's estimates
+// with the tool's cost guard. It returns why the query exceeds a threshold,
+// or "" when it does not. A plan that cannot be obtained is reported as a
+// reason too, so reject mode refuses the query rather than running it
+// unchecked and warn mode says the guard could not be applied.
+func checkCostGuard(ctx context.Context, connector *dbconnector.Connector, guard model.CostGuard, sqlTemplate string, params map[string]interface{}) string {
+	if !guard.Enabled() {
+		return ""
+	}
+	estimate, err := connector.Explain(ctx, sqlTemplate, params)
+	if err != nil {
+		return fmt.Sprintf("no plan estimate could be obtained (%v)", err)
+	}
+	return guard.Check(estimate.Cost, estimate.Rows)
+}
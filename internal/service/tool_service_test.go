@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/dataweaver/internal/model"
+	"github.com/yourusername/dataweaver/pkg/dbconnector"
+)
+
+func TestCheckCostGuard_NoEstimate(t *testing.T) {
+	// Oracle has no plan estimates, and the connector is not connected
+	connector := dbconnector.NewConnector(&dbconnector.ConnectionConfig{Type: dbconnector.Oracle})
+	sql := "SELECT * FROM orders"
+
+	assert.Empty(t, checkCostGuard(context.Background(), connector, model.CostGuard{}, sql, nil))
+	assert.Empty(t, checkCostGuard(context.Background(), connector, model.CostGuard{Mode: model.CostGuardReject}, sql, nil))
+
+	// An enabled guard never lets an unestimated query through silently
+	for _, mode := range []string{model.CostGuardWarn, model.CostGuardReject} {
+		reason := checkCostGuard(context.Background(), connector, model.CostGuard{Mode: mode, MaxEstimatedRows: 1000}, sql, nil)
+		assert.Contains(t, reason, "no plan estimate could be obtained", mode)
+	}
+}
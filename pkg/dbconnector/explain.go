@@ -0,0 +1,240 @@
+package dbconnector
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// showplanResetTimeout bounds switching SHOWPLAN_XML back off, which runs
+// even when the context of the explain is done
+const showplanResetTimeout = 5 * time.Second
+
+// ErrExplainUnsupported is returned when plan estimates are not available
+// for the database type
+var ErrExplainUnsupported = errors.New("query plan estimates are not supported for this database type")
+
+// PlanEstimate holds the optimizer's estimates for a query
+type PlanEstimate struct {
+	Cost float64 `json:"cost"` // Total estimated cost, in the database's own units
+	Rows float64 `json:"rows"` // Largest row estimate of any step in the plan
+}
+
+// Explain asks the optimizer for the plan of a query without running it, with
+// the parameters bound as they would be for ExecuteQueryWithLimits and, like
+// a query, inside a read-only transaction where the engine has one. The row
+// limit is not injected: a LIMIT or TOP at the root of the plan would cap the
+// estimate at the limit and hide the cost of the scans beneath it.
+func (c *Connector) Explain(ctx context.Context, query string, params map[string]interface{}) (*PlanEstimate, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+
+	convertedQuery, args, err := c.prepareQuery(query, params, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c.config.Type {
+	case PostgreSQL:
+		plan, err := c.explainRow(ctx, "EXPLAIN (FORMAT JSON) "+convertedQuery, args)
+		if err != nil {
+			return nil, err
+		}
+		return parsePostgreSQLPlan(plan)
+	case MySQL:
+		plan, err := c.explainRow(ctx, "EXPLAIN FORMAT=JSON "+convertedQuery, args)
+		if err != nil {
+			return nil, err
+		}
+		return parseMySQLPlan(plan)
+	case MSSQL:
+		return c.explainMSSQL(ctx, convertedQuery, args)
+	default:
+		return nil, ErrExplainUnsupported
+	}
+}
+
+// explainRow reads a plan returned as a single value
+func (c *Connector) explainRow(ctx context.Context, query string, args []interface{}) ([]byte, error) {
+	var plan []byte
+	if !c.useReadOnlyTx() {
+		if err := c.db.QueryRowContext(ctx, query, args...).Scan(&plan); err != nil {
+			return nil, fmt.Errorf("explain failed: %w", err)
+		}
+		return plan, nil
+	}
+
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin read-only transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&plan); err != nil {
+		return nil, fmt.Errorf("explain failed: %w", err)
+	}
+	return plan, nil
+}
+
+// explainMSSQL reads the estimated showplan. SHOWPLAN_XML is a session
+// setting, so it is switched on and off on one pinned connection. A
+// connection whose setting cannot be switched back off would answer the
+// next query with a plan instead of rows, so it is discarded rather than
+// returned to the pool.
+func (c *Connector) explainMSSQL(ctx context.Context, query string, args []interface{}) (*PlanEstimate, error) {
+	conn, err := c.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("explain failed: %w", err)
+	}
+	defer func() {
+		resetCtx, cancel := context.WithTimeout(context.Background(), showplanResetTimeout)
+		defer cancel()
+		if _, err := conn.ExecContext(resetCtx, "SET SHOWPLAN_XML OFF"); err != nil {
+			_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}()
+
+	if _, err := conn.ExecContext(ctx, "SET SHOWPLAN_XML ON"); err != nil {
+		return nil, fmt.Errorf("explain failed: %w", err)
+	}
+
+	var plan string
+	if err := conn.QueryRowContext(ctx, query, args...).Scan(&plan); err != nil {
+		return nil, fmt.Errorf("explain failed: %w", err)
+	}
+	return parseMSSQLPlan(plan)
+}
+
+// parsePostgreSQLPlan reads the output of EXPLAIN (FORMAT JSON): the total
+// cost of the root node and the largest "Plan Rows" of any node
+func parsePostgreSQLPlan(data []byte) (*PlanEstimate, error) {
+	type node struct {
+		TotalCost float64 `json:"Total Cost"`
+		PlanRows  float64 `json:"Plan Rows"`
+		Plans     []node  `json:"Plans"`
+	}
+	var plans []struct {
+		Plan node `json:"Plan"`
+	}
+	if err := json.Unmarshal(data, &plans); err != nil {
+		return nil, fmt.Errorf("failed to parse query plan: %w", err)
+	}
+	if len(plans) == 0 {
+		return nil, fmt.Errorf("failed to parse query plan: empty plan")
+	}
+
+	var maxRows func(n node) float64
+	maxRows = func(n node) float64 {
+		rows := n.PlanRows
+		for _, child := range n.Plans {
+			if r := maxRows(child); r > rows {
+				rows = r
+			}
+		}
+		return rows
+	}
+
+	root := plans[0].Plan
+	return &PlanEstimate{Cost: root.TotalCost, Rows: maxRows(root)}, nil
+}
+
+// parseMySQLPlan reads the output of EXPLAIN FORMAT=JSON: the query cost of
+// the outer query block and the largest "rows_examined_per_scan" anywhere
+func parseMySQLPlan(data []byte) (*PlanEstimate, error) {
+	var plan map[string]interface{}
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse query plan: %w", err)
+	}
+
+	estimate := &PlanEstimate{}
+	if block, ok := plan["query_block"].(map[string]interface{}); ok {
+		if info, ok := block["cost_info"].(map[string]interface{}); ok {
+			estimate.Cost = jsonNumber(info["query_cost"])
+		}
+	}
+
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for key, child := range v {
+				if key == "rows_examined_per_scan" {
+					if rows := jsonNumber(child); rows > estimate.Rows {
+						estimate.Rows = rows
+					}
+					continue
+				}
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(plan)
+
+	return estimate, nil
+}
+
+// jsonNumber reads a number that MySQL may encode as a string
+func jsonNumber(v interface{}) float64 {
+	switch v := v.(type) {
+	case float64:
+		return v
+	case string:
+		n, _ := strconv.ParseFloat(v, 64)
+		return n
+	default:
+		return 0
+	}
+}
+
+// parseMSSQLPlan reads a showplan XML document: the subtree cost of the
+// first statement and the largest EstimateRows of any operator
+func parseMSSQLPlan(plan string) (*PlanEstimate, error) {
+	decoder := xml.NewDecoder(strings.NewReader(plan))
+	estimate := &PlanEstimate{}
+	foundStatement := false
+
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse query plan: %w", err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		for _, attr := range start.Attr {
+			switch {
+			case start.Name.Local == "StmtSimple" && attr.Name.Local == "StatementSubTreeCost" && !foundStatement:
+				estimate.Cost, _ = strconv.ParseFloat(attr.Value, 64)
+				foundStatement = true
+			case start.Name.Local == "RelOp" && attr.Name.Local == "EstimateRows":
+				if rows, _ := strconv.ParseFloat(attr.Value, 64); rows > estimate.Rows {
+					estimate.Rows = rows
+				}
+			}
+		}
+	}
+
+	if !foundStatement {
+		return nil, fmt.Errorf("failed to parse query plan: no statement found")
+	}
+	return estimate, nil
+}
@@ -0,0 +1,88 @@
+package dbconnector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePostgreSQLPlan(t *testing.T) {
+	plan := `[{"Plan": {"Node Type": "Limit", "Total Cost": 1250.5, "Plan Rows": 101,
+		"Plans": [{"Node Type": "Seq Scan", "Total Cost": 98000.0, "Plan Rows": 4200000}]}}]`
+
+	estimate, err := parsePostgreSQLPlan([]byte(plan))
+	require.NoError(t, err)
+	assert.Equal(t, 1250.5, estimate.Cost)
+	assert.Equal(t, float64(4200000), estimate.Rows)
+
+	_, err = parsePostgreSQLPlan([]byte(`[]`))
+	assert.Error(t, err)
+}
+
+func TestParseMySQLPlan(t *testing.T) {
+	plan := `{"query_block": {"select_id": 1, "cost_info": {"query_cost": "512.75"},
+		"nested_loop": [
+			{"table": {"table_name": "o", "access_type": "ALL", "rows_examined_per_scan": 5000}},
+			{"table": {"table_name": "c", "access_type": "eq_ref", "rows_examined_per_scan": 1}}
+		]}}`
+
+	estimate, err := parseMySQLPlan([]byte(plan))
+	require.NoError(t, err)
+	assert.Equal(t, 512.75, estimate.Cost)
+	assert.Equal(t, float64(5000), estimate.Rows)
+}
+
+func TestParseMSSQLPlan(t *testing.T) {
+	plan := `<ShowPlanXML xmlns="http://schemas.microsoft.com/sqlserver/2004/07/showplan"><BatchSequence><Batch><Statements>
+		<StmtSimple StatementText="SELECT * FROM orders" StatementSubTreeCost="17.25" StatementEstRows="101">
+			<QueryPlan><RelOp NodeId="0" EstimateRows="101"><RelOp NodeId="1" EstimateRows="250000"></RelOp></RelOp></QueryPlan>
+		</StmtSimple></Statements></Batch></BatchSequence></ShowPlanXML>`
+
+	estimate, err := parseMSSQLPlan(plan)
+	require.NoError(t, err)
+	assert.Equal(t, 17.25, estimate.Cost)
+	assert.Equal(t, float64(250000), estimate.Rows)
+
+	_, err = parseMSSQLPlan(`<ShowPlanXML></ShowPlanXML>`)
+	assert.Error(t, err)
+}
+
+func TestConnector_Explain_Unsupported(t *testing.T) {
+	connector := newStubConnector(t)
+	connector.config.Type = Oracle
+
+	_, err := connector.Explain(context.Background(), "SELECT 1 FROM dual", nil)
+	assert.ErrorIs(t, err, ErrExplainUnsupported)
+}
+
+func TestConnector_Explain_ReadOnly(t *testing.T) {
+	connector := newStubConnector(t)
+	connector.config.ReadOnly = true
+	stubTxReadOnly, stubTxRolledBack = false, false
+
+	// The stub returns rows rather than a plan; only the transaction matters
+	_, _ = connector.Explain(context.Background(), "SELECT * FROM users", nil)
+	assert.True(t, stubTxReadOnly)
+	assert.True(t, stubTxRolledBack)
+}
+
+func TestConnector_Explain_MSSQLDiscardsShowplanConnection(t *testing.T) {
+	connector := newStubConnector(t)
+	connector.config.Type = MSSQL
+
+	stubShowplanResetFails = true
+	defer func() { stubShowplanResetFails = false }()
+	closed := stubConnsClosed
+
+	_, err := connector.Explain(context.Background(), "SELECT * FROM users", nil)
+	assert.Error(t, err)
+	assert.Equal(t, closed+1, stubConnsClosed, "connection left in showplan mode must not return to the pool")
+
+	// With the reset working, the connection goes back to the pool
+	stubShowplanResetFails = false
+	closed = stubConnsClosed
+	_, _ = connector.Explain(context.Background(), "SELECT * FROM users", nil)
+	assert.Equal(t, closed, stubConnsClosed)
+}
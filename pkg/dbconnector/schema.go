@@ -0,0 +1,870 @@
+package dbconnector
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// ErrTableNotFound is returned when a requested table does not exist
+var ErrTableNotFound = errors.New("table not found")
+
+// TableType classifies a relation returned by schema introspection
+type TableType string
+
+const (
+	TableTypeTable            TableType = "table"
+	TableTypeView             TableType = "view"
+	TableTypeMaterializedView TableType = "materialized_view"
+)
+
+type TableInfo struct {
+	Name        string           `json:"name"`
+	Schema      string           `json:"schema"`
+	Type        TableType        `json:"type"`
+	Comment     string           `json:"comment,omitempty"`
+	RowCount    *int64           `json:"row_count,omitempty"` // Approximate, taken from catalog statistics; nil when unknown
+	Columns     []ColumnInfo     `json:"columns"`
+	Indexes     []IndexInfo      `json:"indexes,omitempty"`
+	ForeignKeys []ForeignKeyInfo `json:"foreign_keys,omitempty"`
+}
+
+type ColumnInfo struct {
+	Name       string  `json:"name"`
+	Type       string  `json:"type"`
+	Nullable   bool    `json:"nullable"`
+	PrimaryKey bool    `json:"primary_key"`
+	Default    *string `json:"default,omitempty"`
+	Comment    string  `json:"comment,omitempty"`
+}
+
+type IndexInfo struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique"`
+	Primary bool     `json:"primary"`
+}
+
+type ForeignKeyInfo struct {
+	Name              string   `json:"name"`
+	Columns           []string `json:"columns"`
+	ReferencedSchema  string   `json:"referenced_schema"`
+	ReferencedTable   string   `json:"referenced_table"`
+	ReferencedColumns []string `json:"referenced_columns"`
+}
+
+// GetSchema returns the schema information for the database, including
+// columns, indexes and foreign keys of every table. Catalog queries are
+// batched per schema rather than issued per table.
+func (c *Connector) GetSchema() ([]TableInfo, error) {
+	tables, err := c.ListTables("")
+	if err != nil {
+		return nil, err
+	}
+
+	// Group table positions by schema, preserving order
+	bySchema := make(map[string][]int)
+	var schemas []string
+	for i, t := range tables {
+		if _, ok := bySchema[t.Schema]; !ok {
+			schemas = append(schemas, t.Schema)
+		}
+		bySchema[t.Schema] = append(bySchema[t.Schema], i)
+	}
+
+	for _, schema := range schemas {
+		// A nil table list loads metadata for the whole schema in one query each
+		columns, indexes, foreignKeys, err := c.loadTableDetails(schema, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range bySchema[schema] {
+			name := tables[i].Name
+			tables[i].Columns = columns[name]
+			tables[i].Indexes = indexes[name]
+			tables[i].ForeignKeys = foreignKeys[name]
+		}
+	}
+
+	return tables, nil
+}
+
+// ListSchemas returns the names of the schemas visible to the connection
+func (c *Connector) ListSchemas() ([]string, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+
+	var query string
+	switch c.config.Type {
+	case PostgreSQL:
+		query = `
+			SELECT nspname
+			FROM pg_namespace
+			WHERE nspname NOT IN ('pg_catalog', 'information_schema')
+				AND nspname NOT LIKE 'pg_toast%'
+				AND nspname NOT LIKE 'pg_temp%'
+			ORDER BY nspname
+		`
+	case MySQL:
+		query = `
+			SELECT SCHEMA_NAME
+			FROM information_schema.SCHEMATA
+			WHERE SCHEMA_NAME NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
+			ORDER BY SCHEMA_NAME
+		`
+	case MSSQL:
+		query = `
+			SELECT s.name
+			FROM sys.schemas s
+			WHERE EXISTS (
+				SELECT 1 FROM sys.objects o
+				WHERE o.schema_id = s.schema_id AND o.type IN ('U', 'V') AND o.is_ms_shipped = 0
+			)
+			ORDER BY s.name
+		`
+	case Oracle:
+		query = `
+			SELECT DISTINCT OWNER
+			FROM ALL_OBJECTS
+			WHERE OBJECT_TYPE IN ('TABLE', 'VIEW', 'MATERIALIZED VIEW')
+				AND OWNER NOT IN (` + oracleSystemSchemas + `)
+			ORDER BY OWNER
+		`
+	default:
+		return nil, fmt.Errorf("unsupported database type: %s", c.config.Type)
+	}
+
+	rows, err := c.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schemas []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, name)
+	}
+
+	return schemas, rows.Err()
+}
+
+// ListTables returns tables, views and materialized views in a schema with
+// their comments and approximate row counts, but without column details.
+// An empty schema lists every schema (the current database for MySQL).
+func (c *Connector) ListTables(schema string) ([]TableInfo, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+
+	switch c.config.Type {
+	case PostgreSQL:
+		return c.getPostgreSQLTables(schema)
+	case MySQL:
+		return c.getMySQLTables(schema)
+	case MSSQL:
+		return c.getMSSQLTables(schema)
+	case Oracle:
+		return c.getOracleTables(schema)
+	default:
+		return nil, fmt.Errorf("unsupported database type: %s", c.config.Type)
+	}
+}
+
+// GetColumns returns the columns of the given tables in a schema, keyed by
+// table name, using a single catalog query
+func (c *Connector) GetColumns(schema string, tables []string) (map[string][]ColumnInfo, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+
+	switch c.config.Type {
+	case PostgreSQL:
+		return c.getPostgreSQLColumns(schema, tables)
+	case MySQL:
+		return c.getMySQLColumns(schema, tables)
+	case MSSQL:
+		return c.getMSSQLColumns(schema, tables)
+	case Oracle:
+		return c.getOracleColumns(schema, tables)
+	default:
+		return nil, fmt.Errorf("unsupported database type: %s", c.config.Type)
+	}
+}
+
+// DefaultSchema returns the schema unqualified table names resolve to. It
+// is empty for MySQL, where the connected database is the schema.
+func (c *Connector) DefaultSchema() string {
+	switch c.config.Type {
+	case PostgreSQL:
+		return "public"
+	case MSSQL:
+		return "dbo"
+	case Oracle:
+		return strings.ToUpper(c.config.Username)
+	default:
+		return ""
+	}
+}
+
+// GetTableSchema returns the schema for a specific table. An empty schema
+// means the default schema of the connection. The name is matched as written
+// in a query: as given, folded the way the database folds unquoted names,
+// and then ignoring case.
+func (c *Connector) GetTableSchema(schema, tableName string) ([]ColumnInfo, error) {
+	if schema == "" {
+		schema = c.DefaultSchema()
+	}
+	names := []string{tableName}
+	folded := c.foldName(tableName)
+	if folded != tableName {
+		names = append(names, folded)
+	}
+	columns, err := c.GetColumns(schema, names)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		if cols, ok := columns[name]; ok {
+			return cols, nil
+		}
+	}
+	for name, cols := range columns {
+		if strings.EqualFold(name, tableName) {
+			return cols, nil
+		}
+	}
+	return nil, nil
+}
+
+// foldName returns an unquoted identifier as the catalog stores it: lower
+// case in PostgreSQL, upper case in Oracle, and as written elsewhere
+func (c *Connector) foldName(name string) string {
+	switch c.config.Type {
+	case PostgreSQL:
+		return strings.ToLower(name)
+	case Oracle:
+		return strings.ToUpper(name)
+	default:
+		return name
+	}
+}
+
+// GetTableDetail returns a single table with its columns, indexes and foreign keys
+func (c *Connector) GetTableDetail(schema, tableName string) (*TableInfo, error) {
+	tables, err := c.ListTables(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var table *TableInfo
+	for i := range tables {
+		if tables[i].Name == tableName {
+			table = &tables[i]
+			break
+		}
+	}
+	if table == nil {
+		return nil, ErrTableNotFound
+	}
+
+	columns, indexes, foreignKeys, err := c.loadTableDetails(table.Schema, []string{tableName})
+	if err != nil {
+		return nil, err
+	}
+	table.Columns = columns[tableName]
+	table.Indexes = indexes[tableName]
+	table.ForeignKeys = foreignKeys[tableName]
+
+	return table, nil
+}
+
+// loadTableDetails loads columns, indexes and foreign keys for the given
+// tables of a schema, or for every table in it when tables is nil
+func (c *Connector) loadTableDetails(schema string, tables []string) (map[string][]ColumnInfo, map[string][]IndexInfo, map[string][]ForeignKeyInfo, error) {
+	columns, err := c.GetColumns(schema, tables)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var indexes map[string][]IndexInfo
+	var foreignKeys map[string][]ForeignKeyInfo
+
+	switch c.config.Type {
+	case PostgreSQL:
+		indexes, err = c.getPostgreSQLIndexes(schema, tables)
+		if err == nil {
+			foreignKeys, err = c.getPostgreSQLForeignKeys(schema, tables)
+		}
+	case MySQL:
+		indexes, err = c.getMySQLIndexes(schema, tables)
+		if err == nil {
+			foreignKeys, err = c.getMySQLForeignKeys(schema, tables)
+		}
+	case MSSQL:
+		indexes, err = c.getMSSQLIndexes(schema, tables)
+		if err == nil {
+			foreignKeys, err = c.getMSSQLForeignKeys(schema, tables)
+		}
+	case Oracle:
+		indexes, err = c.getOracleIndexes(schema, tables)
+		if err == nil {
+			foreignKeys, err = c.getOracleForeignKeys(schema, tables)
+		}
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return columns, indexes, foreignKeys, nil
+}
+
+// tableFilter builds an "AND column IN (...)" clause restricting a catalog
+// query to the given tables, with placeholders numbered from argIndex. It
+// returns an empty clause when tables is empty.
+func (c *Connector) tableFilter(column string, tables []string, argIndex int) (string, []interface{}) {
+	if len(tables) == 0 {
+		return "", nil
+	}
+
+	if c.config.Type == PostgreSQL {
+		return fmt.Sprintf(" AND %s = ANY($%d)", column, argIndex), []interface{}{pq.Array(tables)}
+	}
+
+	placeholders := make([]string, len(tables))
+	args := make([]interface{}, len(tables))
+	for i, t := range tables {
+		switch c.config.Type {
+		case MSSQL:
+			placeholders[i] = fmt.Sprintf("@p%d", argIndex+i)
+		case Oracle:
+			placeholders[i] = fmt.Sprintf(":%d", argIndex+i)
+			t = strings.ToUpper(t)
+		default:
+			placeholders[i] = "?"
+		}
+		args[i] = t
+	}
+
+	return fmt.Sprintf(" AND %s IN (%s)", column, strings.Join(placeholders, ", ")), args
+}
+
+// scanTables reads (schema, name, type, comment, row_count) rows into TableInfo values
+func scanTables(rows *sql.Rows) ([]TableInfo, error) {
+	defer rows.Close()
+
+	var tables []TableInfo
+	for rows.Next() {
+		var schema, name, tableType string
+		var comment sql.NullString
+		var rowCount sql.NullInt64
+		if err := rows.Scan(&schema, &name, &tableType, &comment, &rowCount); err != nil {
+			return nil, err
+		}
+		// A missing or negative estimate means the table was never analyzed,
+		// which is not the same as empty
+		var count *int64
+		if rowCount.Valid && rowCount.Int64 >= 0 {
+			count = &rowCount.Int64
+		}
+		tables = append(tables, TableInfo{
+			Name:     name,
+			Schema:   schema,
+			Type:     TableType(tableType),
+			Comment:  comment.String,
+			RowCount: count,
+		})
+	}
+
+	return tables, rows.Err()
+}
+
+// scanColumns reads (table, name, type, nullable, primary_key, default, comment)
+// rows into columns keyed by table
+func scanColumns(rows *sql.Rows) (map[string][]ColumnInfo, error) {
+	defer rows.Close()
+
+	columns := make(map[string][]ColumnInfo)
+	for rows.Next() {
+		var table, name, dataType string
+		var nullable, isPK bool
+		var def, comment sql.NullString
+		if err := rows.Scan(&table, &name, &dataType, &nullable, &isPK, &def, &comment); err != nil {
+			return nil, err
+		}
+		columns[table] = append(columns[table], ColumnInfo{
+			Name:       name,
+			Type:       dataType,
+			Nullable:   nullable,
+			PrimaryKey: isPK,
+			Default:    nullStringPtr(def),
+			Comment:    comment.String,
+		})
+	}
+
+	return columns, rows.Err()
+}
+
+// scanIndexes reads (table, index_name, unique, primary, column) rows ordered
+// by table, index and key position, grouping consecutive rows into one
+// IndexInfo per index
+func scanIndexes(rows *sql.Rows) (map[string][]IndexInfo, error) {
+	defer rows.Close()
+
+	indexes := make(map[string][]IndexInfo)
+	for rows.Next() {
+		var table, name, column string
+		var unique, primary bool
+		if err := rows.Scan(&table, &name, &unique, &primary, &column); err != nil {
+			return nil, err
+		}
+		list := indexes[table]
+		if n := len(list); n > 0 && list[n-1].Name == name {
+			list[n-1].Columns = append(list[n-1].Columns, column)
+			continue
+		}
+		indexes[table] = append(list, IndexInfo{
+			Name:    name,
+			Columns: []string{column},
+			Unique:  unique,
+			Primary: primary,
+		})
+	}
+
+	return indexes, rows.Err()
+}
+
+// scanForeignKeys reads (table, constraint_name, ref_schema, ref_table, column,
+// ref_column) rows ordered by table, constraint and position, grouping them
+// per constraint
+func scanForeignKeys(rows *sql.Rows) (map[string][]ForeignKeyInfo, error) {
+	defer rows.Close()
+
+	fks := make(map[string][]ForeignKeyInfo)
+	for rows.Next() {
+		var table, name, refSchema, refTable, column, refColumn string
+		if err := rows.Scan(&table, &name, &refSchema, &refTable, &column, &refColumn); err != nil {
+			return nil, err
+		}
+		list := fks[table]
+		if n := len(list); n > 0 && list[n-1].Name == name {
+			list[n-1].Columns = append(list[n-1].Columns, column)
+			list[n-1].ReferencedColumns = append(list[n-1].ReferencedColumns, refColumn)
+			continue
+		}
+		fks[table] = append(list, ForeignKeyInfo{
+			Name:              name,
+			Columns:           []string{column},
+			ReferencedSchema:  refSchema,
+			ReferencedTable:   refTable,
+			ReferencedColumns: []string{refColumn},
+		})
+	}
+
+	return fks, rows.Err()
+}
+
+// nullStringPtr converts a nullable string into a pointer, nil when NULL
+func nullStringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	v := s.String
+	return &v
+}
+
+// PostgreSQL
+
+func (c *Connector) getPostgreSQLTables(schema string) ([]TableInfo, error) {
+	query := `
+		SELECT n.nspname, c.relname,
+			   CASE c.relkind WHEN 'v' THEN 'view' WHEN 'm' THEN 'materialized_view' ELSE 'table' END,
+			   obj_description(c.oid, 'pg_class'),
+			   CASE WHEN c.relkind <> 'v' THEN c.reltuples::bigint END
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind IN ('r', 'p', 'v', 'm', 'f')
+			AND NOT c.relispartition
+			AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+			AND n.nspname NOT LIKE 'pg_toast%'
+			AND ($1 = '' OR n.nspname = $1)
+		ORDER BY n.nspname, c.relname
+	`
+	rows, err := c.db.Query(query, schema)
+	if err != nil {
+		return nil, err
+	}
+	return scanTables(rows)
+}
+
+func (c *Connector) getPostgreSQLColumns(schema string, tables []string) (map[string][]ColumnInfo, error) {
+	filter, filterArgs := c.tableFilter("c.relname", tables, 2)
+	query := `
+		SELECT c.relname, a.attname, format_type(a.atttypid, a.atttypmod), NOT a.attnotnull,
+			   EXISTS (
+				   SELECT 1 FROM pg_index i
+				   WHERE i.indrelid = a.attrelid AND i.indisprimary AND a.attnum = ANY(i.indkey)
+			   ),
+			   pg_get_expr(d.adbin, d.adrelid),
+			   col_description(a.attrelid, a.attnum)
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE n.nspname = $1 AND c.relkind IN ('r', 'p', 'v', 'm', 'f')
+			AND a.attnum > 0 AND NOT a.attisdropped` + filter + `
+		ORDER BY c.relname, a.attnum
+	`
+	rows, err := c.db.Query(query, append([]interface{}{schema}, filterArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	return scanColumns(rows)
+}
+
+// getPostgreSQLIndexes lists the key columns of indexes; INCLUDE columns are
+// stored but not searchable, so they are left out
+func (c *Connector) getPostgreSQLIndexes(schema string, tables []string) (map[string][]IndexInfo, error) {
+	filter, filterArgs := c.tableFilter("c.relname", tables, 2)
+	query := `
+		SELECT c.relname, ic.relname, i.indisunique, i.indisprimary, pg_get_indexdef(i.indexrelid, k.ord, true)
+		FROM pg_index i
+		JOIN pg_class ic ON ic.oid = i.indexrelid
+		JOIN pg_class c ON c.oid = i.indrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		CROSS JOIN LATERAL generate_series(1, i.indnkeyatts) AS k(ord)
+		WHERE n.nspname = $1` + filter + `
+		ORDER BY c.relname, ic.relname, k.ord
+	`
+	rows, err := c.db.Query(query, append([]interface{}{schema}, filterArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	return scanIndexes(rows)
+}
+
+func (c *Connector) getPostgreSQLForeignKeys(schema string, tables []string) (map[string][]ForeignKeyInfo, error) {
+	filter, filterArgs := c.tableFilter("c.relname", tables, 2)
+	query := `
+		SELECT c.relname, con.conname, rn.nspname, rc.relname, a.attname, ra.attname
+		FROM pg_constraint con
+		JOIN pg_class c ON c.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_class rc ON rc.oid = con.confrelid
+		JOIN pg_namespace rn ON rn.oid = rc.relnamespace
+		CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, refattnum, ord)
+		JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+		JOIN pg_attribute ra ON ra.attrelid = con.confrelid AND ra.attnum = k.refattnum
+		WHERE con.contype = 'f' AND n.nspname = $1` + filter + `
+		ORDER BY c.relname, con.conname, k.ord
+	`
+	rows, err := c.db.Query(query, append([]interface{}{schema}, filterArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	return scanForeignKeys(rows)
+}
+
+// MySQL
+
+func (c *Connector) getMySQLTables(schema string) ([]TableInfo, error) {
+	// Views report the literal string 'VIEW' as their comment
+	query := `
+		SELECT TABLE_SCHEMA, TABLE_NAME,
+			   CASE WHEN TABLE_TYPE LIKE '%VIEW' THEN 'view' ELSE 'table' END,
+			   CASE WHEN TABLE_TYPE LIKE '%VIEW' THEN NULL ELSE TABLE_COMMENT END,
+			   TABLE_ROWS
+		FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())
+		ORDER BY TABLE_NAME
+	`
+	rows, err := c.db.Query(query, schema)
+	if err != nil {
+		return nil, err
+	}
+	return scanTables(rows)
+}
+
+func (c *Connector) getMySQLColumns(schema string, tables []string) (map[string][]ColumnInfo, error) {
+	filter, filterArgs := c.tableFilter("TABLE_NAME", tables, 2)
+	query := `
+		SELECT TABLE_NAME, COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE = 'YES', COLUMN_KEY = 'PRI',
+			   COLUMN_DEFAULT, COLUMN_COMMENT
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())` + filter + `
+		ORDER BY TABLE_NAME, ORDINAL_POSITION
+	`
+	rows, err := c.db.Query(query, append([]interface{}{schema}, filterArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	return scanColumns(rows)
+}
+
+func (c *Connector) getMySQLIndexes(schema string, tables []string) (map[string][]IndexInfo, error) {
+	filter, filterArgs := c.tableFilter("TABLE_NAME", tables, 2)
+	query := `
+		SELECT TABLE_NAME, INDEX_NAME, NON_UNIQUE = 0, INDEX_NAME = 'PRIMARY', COLUMN_NAME
+		FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())` + filter + `
+		ORDER BY TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX
+	`
+	rows, err := c.db.Query(query, append([]interface{}{schema}, filterArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	return scanIndexes(rows)
+}
+
+func (c *Connector) getMySQLForeignKeys(schema string, tables []string) (map[string][]ForeignKeyInfo, error) {
+	filter, filterArgs := c.tableFilter("TABLE_NAME", tables, 2)
+	query := `
+		SELECT TABLE_NAME, CONSTRAINT_NAME, REFERENCED_TABLE_SCHEMA, REFERENCED_TABLE_NAME,
+			   COLUMN_NAME, REFERENCED_COLUMN_NAME
+		FROM information_schema.KEY_COLUMN_USAGE
+		WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())
+			AND REFERENCED_TABLE_NAME IS NOT NULL` + filter + `
+		ORDER BY TABLE_NAME, CONSTRAINT_NAME, ORDINAL_POSITION
+	`
+	rows, err := c.db.Query(query, append([]interface{}{schema}, filterArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	return scanForeignKeys(rows)
+}
+
+// SQL Server
+
+func (c *Connector) getMSSQLTables(schema string) ([]TableInfo, error) {
+	// Indexed views are SQL Server's equivalent of materialized views
+	query := `
+		SELECT s.name, o.name,
+			   CASE
+				   WHEN o.type = 'V' AND EXISTS (
+					   SELECT 1 FROM sys.indexes i WHERE i.object_id = o.object_id AND i.index_id = 1
+				   ) THEN 'materialized_view'
+				   WHEN o.type = 'V' THEN 'view'
+				   ELSE 'table'
+			   END,
+			   CAST(ep.value AS NVARCHAR(4000)),
+			   (SELECT SUM(p.rows) FROM sys.partitions p WHERE p.object_id = o.object_id AND p.index_id IN (0, 1))
+		FROM sys.objects o
+		JOIN sys.schemas s ON s.schema_id = o.schema_id
+		LEFT JOIN sys.extended_properties ep
+			ON ep.class = 1 AND ep.major_id = o.object_id AND ep.minor_id = 0 AND ep.name = 'MS_Description'
+		WHERE o.type IN ('U', 'V') AND o.is_ms_shipped = 0
+			AND (@p1 = '' OR s.name = @p1)
+		ORDER BY s.name, o.name
+	`
+	rows, err := c.db.Query(query, schema)
+	if err != nil {
+		return nil, err
+	}
+	return scanTables(rows)
+}
+
+func (c *Connector) getMSSQLColumns(schema string, tables []string) (map[string][]ColumnInfo, error) {
+	filter, filterArgs := c.tableFilter("o.name", tables, 2)
+	query := `
+		SELECT o.name, c.name, t.name, c.is_nullable,
+			   CASE WHEN EXISTS (
+				   SELECT 1 FROM sys.index_columns ic
+				   JOIN sys.indexes i ON i.object_id = ic.object_id AND i.index_id = ic.index_id
+				   WHERE i.is_primary_key = 1 AND ic.object_id = c.object_id AND ic.column_id = c.column_id
+			   ) THEN 1 ELSE 0 END AS IS_PRIMARY_KEY,
+			   OBJECT_DEFINITION(c.default_object_id),
+			   CAST(ep.value AS NVARCHAR(4000))
+		FROM sys.columns c
+		JOIN sys.objects o ON o.object_id = c.object_id
+		JOIN sys.schemas s ON s.schema_id = o.schema_id
+		JOIN sys.types t ON t.user_type_id = c.user_type_id
+		LEFT JOIN sys.extended_properties ep
+			ON ep.class = 1 AND ep.major_id = c.object_id AND ep.minor_id = c.column_id AND ep.name = 'MS_Description'
+		WHERE s.name = @p1 AND o.type IN ('U', 'V')` + filter + `
+		ORDER BY o.name, c.column_id
+	`
+	rows, err := c.db.Query(query, append([]interface{}{schema}, filterArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	return scanColumns(rows)
+}
+
+func (c *Connector) getMSSQLIndexes(schema string, tables []string) (map[string][]IndexInfo, error) {
+	filter, filterArgs := c.tableFilter("o.name", tables, 2)
+	query := `
+		SELECT o.name, i.name, i.is_unique, i.is_primary_key, c.name
+		FROM sys.indexes i
+		JOIN sys.objects o ON o.object_id = i.object_id
+		JOIN sys.schemas s ON s.schema_id = o.schema_id
+		JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+		JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+		WHERE s.name = @p1 AND o.type IN ('U', 'V')
+			AND i.name IS NOT NULL AND ic.is_included_column = 0` + filter + `
+		ORDER BY o.name, i.name, ic.key_ordinal
+	`
+	rows, err := c.db.Query(query, append([]interface{}{schema}, filterArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	return scanIndexes(rows)
+}
+
+func (c *Connector) getMSSQLForeignKeys(schema string, tables []string) (map[string][]ForeignKeyInfo, error) {
+	filter, filterArgs := c.tableFilter("o.name", tables, 2)
+	query := `
+		SELECT o.name, fk.name, rs.name, rt.name, pc.name, rc.name
+		FROM sys.foreign_keys fk
+		JOIN sys.objects o ON o.object_id = fk.parent_object_id
+		JOIN sys.schemas s ON s.schema_id = o.schema_id
+		JOIN sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id
+		JOIN sys.columns pc ON pc.object_id = fkc.parent_object_id AND pc.column_id = fkc.parent_column_id
+		JOIN sys.objects rt ON rt.object_id = fkc.referenced_object_id
+		JOIN sys.schemas rs ON rs.schema_id = rt.schema_id
+		JOIN sys.columns rc ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id
+		WHERE s.name = @p1` + filter + `
+		ORDER BY o.name, fk.name, fkc.constraint_column_id
+	`
+	rows, err := c.db.Query(query, append([]interface{}{schema}, filterArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	return scanForeignKeys(rows)
+}
+
+// Oracle
+
+// oracleSystemSchemas lists the owners excluded from Oracle schema browsing
+const oracleSystemSchemas = `'SYS', 'SYSTEM', 'CTXSYS', 'DBSNMP', 'MDSYS', 'OLAPSYS', 'ORDDATA', 'ORDSYS', 'OUTLN', 'WMSYS', 'XDB'`
+
+func (c *Connector) getOracleTables(schema string) ([]TableInfo, error) {
+	// A materialized view also registers a container TABLE object of the same
+	// name, which is skipped so each relation is listed once
+	query := `
+		SELECT o.OWNER, o.OBJECT_NAME,
+			   CASE o.OBJECT_TYPE WHEN 'VIEW' THEN 'view' WHEN 'MATERIALIZED VIEW' THEN 'materialized_view' ELSE 'table' END,
+			   tc.COMMENTS,
+			   t.NUM_ROWS
+		FROM ALL_OBJECTS o
+		LEFT JOIN ALL_TAB_COMMENTS tc ON tc.OWNER = o.OWNER AND tc.TABLE_NAME = o.OBJECT_NAME
+		LEFT JOIN ALL_TABLES t ON t.OWNER = o.OWNER AND t.TABLE_NAME = o.OBJECT_NAME
+		WHERE o.OBJECT_TYPE IN ('TABLE', 'VIEW', 'MATERIALIZED VIEW')
+			AND o.OWNER NOT IN (` + oracleSystemSchemas + `)
+			AND NOT (o.OBJECT_TYPE = 'TABLE' AND EXISTS (
+				SELECT 1 FROM ALL_MVIEWS m WHERE m.OWNER = o.OWNER AND m.MVIEW_NAME = o.OBJECT_NAME
+			))`
+	var args []interface{}
+	if schema != "" {
+		query += `
+			AND o.OWNER = :1`
+		args = append(args, strings.ToUpper(schema))
+	}
+	query += `
+		ORDER BY o.OWNER, o.OBJECT_NAME
+	`
+	rows, err := c.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanTables(rows)
+}
+
+func (c *Connector) getOracleColumns(schema string, tables []string) (map[string][]ColumnInfo, error) {
+	filter, filterArgs := c.tableFilter("c.TABLE_NAME", tables, 2)
+	query := `
+		SELECT c.TABLE_NAME, c.COLUMN_NAME, c.DATA_TYPE,
+			   CASE WHEN c.NULLABLE = 'Y' THEN 1 ELSE 0 END,
+			   CASE WHEN pk.COLUMN_NAME IS NOT NULL THEN 1 ELSE 0 END AS IS_PRIMARY_KEY,
+			   c.DATA_DEFAULT,
+			   cc.COMMENTS
+		FROM ALL_TAB_COLUMNS c
+		LEFT JOIN (
+			SELECT cols.OWNER, cols.TABLE_NAME, cols.COLUMN_NAME
+			FROM ALL_CONSTRAINTS cons
+			JOIN ALL_CONS_COLUMNS cols
+				ON cons.OWNER = cols.OWNER AND cons.CONSTRAINT_NAME = cols.CONSTRAINT_NAME
+			WHERE cons.CONSTRAINT_TYPE = 'P'
+		) pk ON c.OWNER = pk.OWNER
+			AND c.TABLE_NAME = pk.TABLE_NAME
+			AND c.COLUMN_NAME = pk.COLUMN_NAME
+		LEFT JOIN ALL_COL_COMMENTS cc
+			ON cc.OWNER = c.OWNER AND cc.TABLE_NAME = c.TABLE_NAME AND cc.COLUMN_NAME = c.COLUMN_NAME
+		WHERE c.OWNER = :1` + filter + `
+		ORDER BY c.TABLE_NAME, c.COLUMN_ID
+	`
+	rows, err := c.db.Query(query, append([]interface{}{strings.ToUpper(schema)}, filterArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := scanColumns(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// DATA_DEFAULT is stored as the raw expression text, often with trailing whitespace
+	for _, cols := range columns {
+		for i := range cols {
+			if cols[i].Default != nil {
+				def := strings.TrimSpace(*cols[i].Default)
+				cols[i].Default = &def
+			}
+		}
+	}
+
+	return columns, nil
+}
+
+func (c *Connector) getOracleIndexes(schema string, tables []string) (map[string][]IndexInfo, error) {
+	filter, filterArgs := c.tableFilter("i.TABLE_NAME", tables, 2)
+	query := `
+		SELECT i.TABLE_NAME, i.INDEX_NAME,
+			   CASE WHEN i.UNIQUENESS = 'UNIQUE' THEN 1 ELSE 0 END,
+			   CASE WHEN EXISTS (
+				   SELECT 1 FROM ALL_CONSTRAINTS cons
+				   WHERE cons.OWNER = i.TABLE_OWNER AND cons.TABLE_NAME = i.TABLE_NAME
+					   AND cons.CONSTRAINT_TYPE = 'P' AND cons.INDEX_NAME = i.INDEX_NAME
+			   ) THEN 1 ELSE 0 END,
+			   ic.COLUMN_NAME
+		FROM ALL_INDEXES i
+		JOIN ALL_IND_COLUMNS ic ON ic.INDEX_OWNER = i.OWNER AND ic.INDEX_NAME = i.INDEX_NAME
+		WHERE i.TABLE_OWNER = :1` + filter + `
+		ORDER BY i.TABLE_NAME, i.INDEX_NAME, ic.COLUMN_POSITION
+	`
+	rows, err := c.db.Query(query, append([]interface{}{strings.ToUpper(schema)}, filterArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	return scanIndexes(rows)
+}
+
+func (c *Connector) getOracleForeignKeys(schema string, tables []string) (map[string][]ForeignKeyInfo, error) {
+	filter, filterArgs := c.tableFilter("cons.TABLE_NAME", tables, 2)
+	query := `
+		SELECT cons.TABLE_NAME, cons.CONSTRAINT_NAME, rcons.OWNER, rcons.TABLE_NAME,
+			   cols.COLUMN_NAME, rcols.COLUMN_NAME
+		FROM ALL_CONSTRAINTS cons
+		JOIN ALL_CONS_COLUMNS cols
+			ON cols.OWNER = cons.OWNER AND cols.CONSTRAINT_NAME = cons.CONSTRAINT_NAME
+		JOIN ALL_CONSTRAINTS rcons
+			ON rcons.OWNER = cons.R_OWNER AND rcons.CONSTRAINT_NAME = cons.R_CONSTRAINT_NAME
+		JOIN ALL_CONS_COLUMNS rcols
+			ON rcols.OWNER = rcons.OWNER AND rcols.CONSTRAINT_NAME = rcons.CONSTRAINT_NAME
+			AND rcols.POSITION = cols.POSITION
+		WHERE cons.CONSTRAINT_TYPE = 'R' AND cons.OWNER = :1` + filter + `
+		ORDER BY cons.TABLE_NAME, cons.CONSTRAINT_NAME, cols.POSITION
+	`
+	rows, err := c.db.Query(query, append([]interface{}{strings.ToUpper(schema)}, filterArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	return scanForeignKeys(rows)
+}
@@ -0,0 +1,421 @@
+package dbconnector
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// catalogDriver answers catalog queries with canned rows, in the manner of
+// sqlmock: each query is matched by a substring of its text against the
+// results registered under the DSN, and the arguments it was run with are
+// recorded
+type catalogDriver struct{}
+
+type catalogConn struct {
+	dsn string
+}
+
+type catalogStmt struct {
+	dsn    string
+	result *catalogResult
+}
+
+// catalogResult is the result set served for queries containing match
+type catalogResult struct {
+	match   string
+	columns []string
+	rows    [][]driver.Value
+	args    []driver.Value // Arguments of the last matching query
+}
+
+type catalogRows struct {
+	result *catalogResult
+	pos    int
+}
+
+var catalogFixtures sync.Map // DSN -> []*catalogResult
+
+func init() {
+	sql.Register("dbconnector-catalog", catalogDriver{})
+}
+
+func (catalogDriver) Open(dsn string) (driver.Conn, error) { return catalogConn{dsn: dsn}, nil }
+
+func (c catalogConn) Prepare(query string) (driver.Stmt, error) {
+	results, _ := catalogFixtures.Load(c.dsn)
+	for _, result := range results.([]*catalogResult) {
+		if strings.Contains(query, result.match) {
+			return catalogStmt{dsn: c.dsn, result: result}, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected query: %s", query)
+}
+func (catalogConn) Close() error              { return nil }
+func (catalogConn) Begin() (driver.Tx, error) { return nil, driver.ErrSkip }
+
+func (catalogStmt) Close() error                               { return nil }
+func (catalogStmt) NumInput() int                              { return -1 }
+func (catalogStmt) Exec([]driver.Value) (driver.Result, error) { return nil, driver.ErrSkip }
+func (s catalogStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.result.args = args
+	return &catalogRows{result: s.result}, nil
+}
+
+func (r *catalogRows) Columns() []string { return r.result.columns }
+func (r *catalogRows) Close() error      { return nil }
+
+func (r *catalogRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.result.rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.rows[r.pos])
+	r.pos++
+	return nil
+}
+
+// newCatalogConnector returns a connector of the given type whose queries
+// are answered from results
+func newCatalogConnector(t *testing.T, dbType DBType, results ...*catalogResult) *Connector {
+	catalogFixtures.Store(t.Name(), results)
+	t.Cleanup(func() { catalogFixtures.Delete(t.Name()) })
+
+	db, err := sql.Open("dbconnector-catalog", t.Name())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return &Connector{config: &ConnectionConfig{Type: dbType, Username: "app"}, db: db}
+}
+
+var (
+	catalogTableColumns  = []string{"schema", "name", "type", "comment", "row_count"}
+	catalogColumnColumns = []string{"table", "name", "type", "nullable", "primary_key", "default", "comment"}
+	catalogIndexColumns  = []string{"table", "index_name", "unique", "primary", "column"}
+	catalogFKColumns     = []string{"table", "constraint_name", "ref_schema", "ref_table", "column", "ref_column"}
+)
+
+// findTable returns the table with the given name, failing the test if absent
+func findTable(t *testing.T, tables []TableInfo, name string) TableInfo {
+	t.Helper()
+	for _, table := range tables {
+		if table.Name == name {
+			return table
+		}
+	}
+	require.Failf(t, "table not found", "%s", name)
+	return TableInfo{}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestConnector_GetSchema_PostgreSQL(t *testing.T) {
+	connector := newCatalogConnector(t, PostgreSQL,
+		&catalogResult{match: "obj_description", columns: catalogTableColumns, rows: [][]driver.Value{
+			{"public", "customers", "table", "Customer accounts", int64(1200)},
+			{"public", "order_totals", "materialized_view", nil, nil},
+			{"public", "orders", "table", nil, int64(-1)},
+		}},
+		&catalogResult{match: "format_type", columns: catalogColumnColumns, rows: [][]driver.Value{
+			{"customers", "tenant_id", "integer", false, true, nil, nil},
+			{"customers", "id", "integer", false, true, "nextval('customers_id_seq'::regclass)", nil},
+			{"customers", "region", "text", true, false, nil, "Sales region"},
+			{"orders", "id", "bigint", false, true, nil, nil},
+			{"orders", "tenant_id", "integer", true, false, nil, nil},
+			{"orders", "customer_id", "integer", true, false, nil, nil},
+			{"orders", "status", "text", false, false, "'new'::text", nil},
+		}},
+		// INCLUDE columns are not part of an index key
+		&catalogResult{match: "generate_series(1, i.indnkeyatts)", columns: catalogIndexColumns, rows: [][]driver.Value{
+			{"customers", "customers_pkey", true, true, "tenant_id"},
+			{"customers", "customers_pkey", true, true, "id"},
+			{"customers", "idx_customers_region", false, false, "region"},
+			{"orders", "idx_orders_status_customer", false, false, "status"},
+			{"orders", "idx_orders_status_customer", false, false, "customer_id"},
+			{"orders", "orders_pkey", true, true, "id"},
+		}},
+		&catalogResult{match: "pg_constraint", columns: catalogFKColumns, rows: [][]driver.Value{
+			{"orders", "orders_customer_fk", "public", "customers", "tenant_id", "tenant_id"},
+			{"orders", "orders_customer_fk", "public", "customers", "customer_id", "id"},
+		}},
+	)
+
+	tables, err := connector.GetSchema()
+	require.NoError(t, err)
+	require.Len(t, tables, 3)
+
+	customers := findTable(t, tables, "customers")
+	assert.Equal(t, "public", customers.Schema)
+	assert.Equal(t, TableTypeTable, customers.Type)
+	assert.Equal(t, "Customer accounts", customers.Comment)
+	assert.Equal(t, int64(1200), *customers.RowCount)
+	assert.Equal(t, []ColumnInfo{
+		{Name: "tenant_id", Type: "integer", PrimaryKey: true},
+		{Name: "id", Type: "integer", PrimaryKey: true, Default: stringPtr("nextval('customers_id_seq'::regclass)")},
+		{Name: "region", Type: "text", Nullable: true, Comment: "Sales region"},
+	}, customers.Columns)
+	assert.Equal(t, []IndexInfo{
+		{Name: "customers_pkey", Columns: []string{"tenant_id", "id"}, Unique: true, Primary: true},
+		{Name: "idx_customers_region", Columns: []string{"region"}},
+	}, customers.Indexes)
+	assert.Empty(t, customers.ForeignKeys)
+
+	// NULL and negative estimates are both reported as unknown
+	assert.Nil(t, findTable(t, tables, "order_totals").RowCount)
+	assert.Equal(t, TableTypeMaterializedView, findTable(t, tables, "order_totals").Type)
+
+	orders := findTable(t, tables, "orders")
+	assert.Nil(t, orders.RowCount)
+	assert.Equal(t, stringPtr("'new'::text"), orders.Columns[3].Default)
+	assert.Nil(t, orders.Columns[1].Default)
+	assert.Equal(t, []IndexInfo{
+		{Name: "idx_orders_status_customer", Columns: []string{"status", "customer_id"}},
+		{Name: "orders_pkey", Columns: []string{"id"}, Unique: true, Primary: true},
+	}, orders.Indexes)
+	assert.Equal(t, []ForeignKeyInfo{{
+		Name:              "orders_customer_fk",
+		Columns:           []string{"tenant_id", "customer_id"},
+		ReferencedSchema:  "public",
+		ReferencedTable:   "customers",
+		ReferencedColumns: []string{"tenant_id", "id"},
+	}}, orders.ForeignKeys)
+}
+
+func TestConnector_GetSchema_MySQL(t *testing.T) {
+	// The MySQL driver returns text as []byte and boolean expressions as integers
+	connector := newCatalogConnector(t, MySQL,
+		&catalogResult{match: "information_schema.TABLES", columns: catalogTableColumns, rows: [][]driver.Value{
+			{[]byte("shop"), []byte("order_items"), []byte("table"), []byte(""), int64(52000)},
+			{[]byte("shop"), []byte("order_summary"), []byte("view"), nil, nil},
+		}},
+		&catalogResult{match: "information_schema.COLUMNS", columns: catalogColumnColumns, rows: [][]driver.Value{
+			{[]byte("order_items"), []byte("order_id"), []byte("int unsigned"), int64(0), int64(1), nil, []byte("")},
+			{[]byte("order_items"), []byte("line_no"), []byte("smallint"), int64(0), int64(1), nil, []byte("")},
+			{[]byte("order_items"), []byte("quantity"), []byte("int"), int64(0), int64(0), []byte("1"), []byte("Units ordered")},
+			{[]byte("order_items"), []byte("note"), []byte("varchar(255)"), int64(1), int64(0), nil, []byte("")},
+			{[]byte("order_summary"), []byte("order_id"), []byte("int unsigned"), int64(0), int64(0), nil, []byte("")},
+		}},
+		&catalogResult{match: "information_schema.STATISTICS", columns: catalogIndexColumns, rows: [][]driver.Value{
+			{[]byte("order_items"), []byte("PRIMARY"), int64(1), int64(1), []byte("order_id")},
+			{[]byte("order_items"), []byte("PRIMARY"), int64(1), int64(1), []byte("line_no")},
+			{[]byte("order_items"), []byte("fk_items_product"), int64(0), int64(0), []byte("product_vendor")},
+			{[]byte("order_items"), []byte("fk_items_product"), int64(0), int64(0), []byte("product_sku")},
+		}},
+		&catalogResult{match: "KEY_COLUMN_USAGE", columns: catalogFKColumns, rows: [][]driver.Value{
+			{[]byte("order_items"), []byte("fk_items_product"), []byte("catalog"), []byte("products"), []byte("product_vendor"), []byte("vendor_id")},
+			{[]byte("order_items"), []byte("fk_items_product"), []byte("catalog"), []byte("products"), []byte("product_sku"), []byte("sku")},
+		}},
+	)
+
+	tables, err := connector.GetSchema()
+	require.NoError(t, err)
+	require.Len(t, tables, 2)
+
+	items := findTable(t, tables, "order_items")
+	assert.Equal(t, int64(52000), *items.RowCount)
+	assert.Equal(t, []ColumnInfo{
+		{Name: "order_id", Type: "int unsigned", PrimaryKey: true},
+		{Name: "line_no", Type: "smallint", PrimaryKey: true},
+		{Name: "quantity", Type: "int", Default: stringPtr("1"), Comment: "Units ordered"},
+		{Name: "note", Type: "varchar(255)", Nullable: true},
+	}, items.Columns)
+	assert.Equal(t, []IndexInfo{
+		{Name: "PRIMARY", Columns: []string{"order_id", "line_no"}, Unique: true, Primary: true},
+		{Name: "fk_items_product", Columns: []string{"product_vendor", "product_sku"}},
+	}, items.Indexes)
+	assert.Equal(t, []ForeignKeyInfo{{
+		Name:              "fk_items_product",
+		Columns:           []string{"product_vendor", "product_sku"},
+		ReferencedSchema:  "catalog",
+		ReferencedTable:   "products",
+		ReferencedColumns: []string{"vendor_id", "sku"},
+	}}, items.ForeignKeys)
+
+	// Views have no TABLE_ROWS and no comment
+	summary := findTable(t, tables, "order_summary")
+	assert.Equal(t, TableTypeView, summary.Type)
+	assert.Nil(t, summary.RowCount)
+	assert.Empty(t, summary.Comment)
+	assert.Len(t, summary.Columns, 1)
+	assert.Empty(t, summary.Indexes)
+}
+
+func TestConnector_GetSchema_MSSQL(t *testing.T) {
+	// bit columns arrive as bools, CASE expressions as integers
+	connector := newCatalogConnector(t, MSSQL,
+		&catalogResult{match: "sys.partitions", columns: catalogTableColumns, rows: [][]driver.Value{
+			{"sales", "Invoice", "table", "Issued invoices", int64(830)},
+			{"sales", "InvoiceLine", "table", nil, nil},
+		}},
+		&catalogResult{match: "OBJECT_DEFINITION", columns: catalogColumnColumns, rows: [][]driver.Value{
+			{"Invoice", "InvoiceID", "int", false, int64(1), nil, nil},
+			{"Invoice", "IssuedAt", "datetime2", false, int64(0), "(sysutcdatetime())", nil},
+			{"InvoiceLine", "InvoiceID", "int", false, int64(1), nil, nil},
+			{"InvoiceLine", "LineNo", "smallint", false, int64(1), nil, nil},
+			{"InvoiceLine", "Discount", "decimal", true, int64(0), "((0))", "Fraction off list price"},
+		}},
+		&catalogResult{match: "is_included_column", columns: catalogIndexColumns, rows: [][]driver.Value{
+			{"Invoice", "PK_Invoice", true, true, "InvoiceID"},
+			{"InvoiceLine", "PK_InvoiceLine", true, true, "InvoiceID"},
+			{"InvoiceLine", "PK_InvoiceLine", true, true, "LineNo"},
+		}},
+		&catalogResult{match: "sys.foreign_keys", columns: catalogFKColumns, rows: [][]driver.Value{
+			{"InvoiceLine", "FK_InvoiceLine_Invoice", "sales", "Invoice", "InvoiceID", "InvoiceID"},
+		}},
+	)
+
+	tables, err := connector.GetSchema()
+	require.NoError(t, err)
+	require.Len(t, tables, 2)
+
+	invoice := findTable(t, tables, "Invoice")
+	assert.Equal(t, "Issued invoices", invoice.Comment)
+	assert.Equal(t, int64(830), *invoice.RowCount)
+	assert.Equal(t, []ColumnInfo{
+		{Name: "InvoiceID", Type: "int", PrimaryKey: true},
+		{Name: "IssuedAt", Type: "datetime2", Default: stringPtr("(sysutcdatetime())")},
+	}, invoice.Columns)
+
+	lines := findTable(t, tables, "InvoiceLine")
+	assert.Nil(t, lines.RowCount)
+	assert.Equal(t, ColumnInfo{
+		Name: "Discount", Type: "decimal", Nullable: true, Default: stringPtr("((0))"), Comment: "Fraction off list price",
+	}, lines.Columns[2])
+	assert.Equal(t, []IndexInfo{
+		{Name: "PK_InvoiceLine", Columns: []string{"InvoiceID", "LineNo"}, Unique: true, Primary: true},
+	}, lines.Indexes)
+	assert.Equal(t, []ForeignKeyInfo{{
+		Name:              "FK_InvoiceLine_Invoice",
+		Columns:           []string{"InvoiceID"},
+		ReferencedSchema:  "sales",
+		ReferencedTable:   "Invoice",
+		ReferencedColumns: []string{"InvoiceID"},
+	}}, lines.ForeignKeys)
+}
+
+func TestConnector_GetSchema_Oracle(t *testing.T) {
+	connector := newCatalogConnector(t, Oracle,
+		&catalogResult{match: "ALL_OBJECTS", columns: catalogTableColumns, rows: [][]driver.Value{
+			{"HR", "EMPLOYEES", "table", "Staff records", int64(107)},
+			{"HR", "JOB_HISTORY", "table", nil, nil},
+			{"HR", "EMP_DETAILS", "view", nil, nil},
+		}},
+		&catalogResult{match: "ALL_TAB_COLUMNS", columns: catalogColumnColumns, rows: [][]driver.Value{
+			{"EMPLOYEES", "EMPLOYEE_ID", "NUMBER", int64(0), int64(1), nil, nil},
+			{"EMPLOYEES", "HIRE_DATE", "DATE", int64(0), int64(0), "SYSDATE \n", nil},
+			{"EMPLOYEES", "MANAGER_ID", "NUMBER", int64(1), int64(0), nil, "Reports to"},
+			{"JOB_HISTORY", "EMPLOYEE_ID", "NUMBER", int64(0), int64(1), nil, nil},
+			{"JOB_HISTORY", "START_DATE", "DATE", int64(0), int64(1), nil, nil},
+			{"EMP_DETAILS", "EMPLOYEE_ID", "NUMBER", int64(0), int64(0), nil, nil},
+		}},
+		&catalogResult{match: "ALL_INDEXES", columns: catalogIndexColumns, rows: [][]driver.Value{
+			{"EMPLOYEES", "EMP_EMP_ID_PK", int64(1), int64(1), "EMPLOYEE_ID"},
+			{"EMPLOYEES", "EMP_NAME_IX", int64(0), int64(0), "LAST_NAME"},
+			{"EMPLOYEES", "EMP_NAME_IX", int64(0), int64(0), "FIRST_NAME"},
+			{"JOB_HISTORY", "JHIST_EMP_ID_ST_DATE_PK", int64(1), int64(1), "EMPLOYEE_ID"},
+			{"JOB_HISTORY", "JHIST_EMP_ID_ST_DATE_PK", int64(1), int64(1), "START_DATE"},
+		}},
+		&catalogResult{match: "CONSTRAINT_TYPE = 'R'", columns: catalogFKColumns, rows: [][]driver.Value{
+			{"EMPLOYEES", "EMP_MANAGER_FK", "HR", "EMPLOYEES", "MANAGER_ID", "EMPLOYEE_ID"},
+			{"JOB_HISTORY", "JHIST_EMP_FK", "HR", "EMPLOYEES", "EMPLOYEE_ID", "EMPLOYEE_ID"},
+		}},
+	)
+
+	tables, err := connector.GetSchema()
+	require.NoError(t, err)
+	require.Len(t, tables, 3)
+
+	employees := findTable(t, tables, "EMPLOYEES")
+	assert.Equal(t, int64(107), *employees.RowCount)
+	assert.Equal(t, []ColumnInfo{
+		{Name: "EMPLOYEE_ID", Type: "NUMBER", PrimaryKey: true},
+		{Name: "HIRE_DATE", Type: "DATE", Default: stringPtr("SYSDATE")},
+		{Name: "MANAGER_ID", Type: "NUMBER", Nullable: true, Comment: "Reports to"},
+	}, employees.Columns)
+	assert.Equal(t, []IndexInfo{
+		{Name: "EMP_EMP_ID_PK", Columns: []string{"EMPLOYEE_ID"}, Unique: true, Primary: true},
+		{Name: "EMP_NAME_IX", Columns: []string{"LAST_NAME", "FIRST_NAME"}},
+	}, employees.Indexes)
+	assert.Equal(t, []ForeignKeyInfo{{
+		Name:              "EMP_MANAGER_FK",
+		Columns:           []string{"MANAGER_ID"},
+		ReferencedSchema:  "HR",
+		ReferencedTable:   "EMPLOYEES",
+		ReferencedColumns: []string{"EMPLOYEE_ID"},
+	}}, employees.ForeignKeys)
+
+	history := findTable(t, tables, "JOB_HISTORY")
+	assert.Nil(t, history.RowCount)
+	assert.True(t, history.Columns[0].PrimaryKey)
+	assert.True(t, history.Columns[1].PrimaryKey)
+	assert.Equal(t, []string{"EMPLOYEE_ID", "START_DATE"}, history.Indexes[0].Columns)
+
+	details := findTable(t, tables, "EMP_DETAILS")
+	assert.Equal(t, TableTypeView, details.Type)
+	assert.Empty(t, details.Indexes)
+	assert.Empty(t, details.ForeignKeys)
+}
+
+func TestConnector_GetTableDetail_Oracle(t *testing.T) {
+	columns := &catalogResult{match: "ALL_TAB_COLUMNS", columns: catalogColumnColumns, rows: [][]driver.Value{
+		{"EMPLOYEES", "EMPLOYEE_ID", "NUMBER", int64(0), int64(1), nil, nil},
+	}}
+	connector := newCatalogConnector(t, Oracle,
+		&catalogResult{match: "ALL_OBJECTS", columns: catalogTableColumns, rows: [][]driver.Value{
+			{"HR", "EMPLOYEES", "table", nil, int64(107)},
+		}},
+		columns,
+		&catalogResult{match: "ALL_INDEXES", columns: catalogIndexColumns},
+		&catalogResult{match: "CONSTRAINT_TYPE = 'R'", columns: catalogFKColumns},
+	)
+
+	table, err := connector.GetTableDetail("hr", "EMPLOYEES")
+	require.NoError(t, err)
+	assert.Len(t, table.Columns, 1)
+	assert.Empty(t, table.Indexes)
+
+	// Owner and table names are folded to Oracle's upper case
+	assert.Equal(t, []driver.Value{"HR", "EMPLOYEES"}, columns.args)
+
+	_, err = connector.GetTableDetail("hr", "DEPARTMENTS")
+	assert.ErrorIs(t, err, ErrTableNotFound)
+}
+
+func TestConnector_GetTableSchema(t *testing.T) {
+	employees := [][]driver.Value{{"EMPLOYEES", "EMPLOYEE_ID", "NUMBER", int64(0), int64(1), nil, nil}}
+	oracle := newCatalogConnector(t, Oracle,
+		&catalogResult{match: "ALL_TAB_COLUMNS", columns: catalogColumnColumns, rows: employees},
+	)
+
+	// Unquoted names are folded to Oracle's upper case
+	columns, err := oracle.GetTableSchema("hr", "employees")
+	require.NoError(t, err)
+	require.Len(t, columns, 1)
+	assert.Equal(t, "EMPLOYEE_ID", columns[0].Name)
+
+	// A case-insensitive collation finds the table under its own spelling
+	invoices := &catalogResult{match: "OBJECT_DEFINITION", columns: catalogColumnColumns, rows: [][]driver.Value{
+		{"Invoice", "InvoiceID", "int", int64(0), int64(1), nil, nil},
+	}}
+	mssql := newCatalogConnector(t, MSSQL, invoices)
+	columns, err = mssql.GetTableSchema("sales", "invoice")
+	require.NoError(t, err)
+	assert.Len(t, columns, 1)
+
+	// PostgreSQL looks up both the name as written and folded to lower case
+	orders := &catalogResult{match: "format_type", columns: catalogColumnColumns, rows: [][]driver.Value{
+		{"orders", "id", "bigint", false, true, nil, nil},
+	}}
+	postgres := newCatalogConnector(t, PostgreSQL, orders)
+	columns, err = postgres.GetTableSchema("", "Orders")
+	require.NoError(t, err)
+	assert.Len(t, columns, 1)
+	assert.Equal(t, []driver.Value{"public", "{\"Orders\",\"orders\"}"}, orders.args)
+}
@@ -0,0 +1,358 @@
+package dbconnector
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ResultLimits bounds how much of a result is buffered in memory, across all
+// of its result sets. A zero value for a field disables that bound.
+type ResultLimits struct {
+	MaxRows  int   // Maximum number of rows to keep
+	MaxBytes int64 // Approximate maximum size of the kept rows
+}
+
+// RowIterator streams query results one row at a time with ordered columns
+type RowIterator struct {
+	connector  *Connector
+	rows       *sql.Rows
+	tx         *sql.Tx // Read-only transaction wrapping the query, if any
+	columns    []string
+	meta       []ColumnMeta
+	converters []valueConverter
+	values     []interface{}
+	ptrs       []interface{}
+	err        error
+}
+
+// QueryRows executes a query with named parameters and returns an iterator
+// over its rows. The caller must Close the iterator.
+func (c *Connector) QueryRows(query string, params map[string]interface{}) (*RowIterator, error) {
+	return c.queryRows(context.Background(), query, params, 0)
+}
+
+// QueryRowsContext is QueryRows with a context; cancelling it aborts the
+// query on the server and ends the iteration
+func (c *Connector) QueryRowsContext(ctx context.Context, query string, params map[string]interface{}) (*RowIterator, error) {
+	return c.queryRows(ctx, query, params, 0)
+}
+
+// queryRows executes a query, capping it at rowLimit rows in the SQL itself
+// when rowLimit is positive
+func (c *Connector) queryRows(ctx context.Context, query string, params map[string]interface{}, rowLimit int) (*RowIterator, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+
+	convertedQuery, args, err := c.prepareQuery(query, params, rowLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.useReadOnlyTx() {
+		rows, err := c.db.QueryContext(ctx, convertedQuery, args...)
+		if err != nil {
+			return nil, fmt.Errorf("query execution failed: %w", err)
+		}
+		return c.newRowIterator(rows)
+	}
+
+	// lib/pq opens the transaction with BEGIN READ ONLY and go-sql-driver/mysql
+	// with START TRANSACTION READ ONLY, so the server rejects any write
+	// regardless of what the SQL parser concluded
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin read-only transaction: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, convertedQuery, args...)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("query execution failed: %w", err)
+	}
+
+	it, err := c.newRowIterator(rows)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	it.tx = tx
+	return it, nil
+}
+
+// useReadOnlyTx reports whether queries run inside a read-only transaction.
+// SQL Server has no such transaction mode; it relies on ApplicationIntent.
+func (c *Connector) useReadOnlyTx() bool {
+	if !c.config.ReadOnly {
+		return false
+	}
+	return c.config.Type == PostgreSQL || c.config.Type == MySQL
+}
+
+// ExecuteQueryWithLimits executes a query and buffers at most limits worth of
+// rows. Result.Truncated reports whether more rows were available. A row
+// bound is also written into the SQL as the dialect's limit clause, set one
+// above MaxRows so that truncation can still be detected.
+func (c *Connector) ExecuteQueryWithLimits(query string, params map[string]interface{}, limits ResultLimits) (*QueryResult, error) {
+	return c.ExecuteQueryWithLimitsContext(context.Background(), query, params, limits)
+}
+
+// ExecuteQueryWithLimitsContext is ExecuteQueryWithLimits with a context;
+// cancelling it aborts the query on the server
+func (c *Connector) ExecuteQueryWithLimitsContext(ctx context.Context, query string, params map[string]interface{}, limits ResultLimits) (*QueryResult, error) {
+	rowLimit := 0
+	if limits.MaxRows > 0 {
+		rowLimit = limits.MaxRows + 1
+	}
+
+	it, err := c.queryRows(ctx, query, params, rowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	return collectRows(it, limits)
+}
+
+func (c *Connector) newRowIterator(rows *sql.Rows) (*RowIterator, error) {
+	it := &RowIterator{connector: c, rows: rows}
+	if err := it.describe(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	return it, nil
+}
+
+// describe reads the columns of the current result set
+func (it *RowIterator) describe() error {
+	columns, err := it.rows.Columns()
+	if err != nil {
+		return fmt.Errorf("failed to get columns: %w", err)
+	}
+
+	// Fall back to untyped columns if the driver cannot describe them
+	meta := make([]ColumnMeta, len(columns))
+	for i, col := range columns {
+		meta[i] = ColumnMeta{Name: col}
+	}
+	if types, err := it.rows.ColumnTypes(); err == nil && len(types) == len(columns) {
+		meta = columnMetaFromTypes(types)
+	}
+
+	converters := make([]valueConverter, len(columns))
+	for i := range meta {
+		converters[i] = it.connector.converterFor(meta[i].DatabaseType)
+	}
+
+	values := make([]interface{}, len(columns))
+	ptrs := make([]interface{}, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+
+	it.columns = columns
+	it.meta = meta
+	it.converters = converters
+	it.values = values
+	it.ptrs = ptrs
+	return nil
+}
+
+// Columns returns the column names in the order returned by the database
+func (it *RowIterator) Columns() []string {
+	return it.columns
+}
+
+// ColumnTypes returns the type metadata of each column, in column order
+func (it *RowIterator) ColumnTypes() []ColumnMeta {
+	return it.meta
+}
+
+// Next advances to the next row, returning false when the rows are
+// exhausted or an error occurred
+func (it *RowIterator) Next() bool {
+	if it.err != nil || !it.rows.Next() {
+		return false
+	}
+
+	for i := range it.values {
+		it.values[i] = nil
+	}
+	if err := it.rows.Scan(it.ptrs...); err != nil {
+		it.err = fmt.Errorf("failed to scan row: %w", err)
+		return false
+	}
+
+	for i, val := range it.values {
+		if val != nil {
+			it.values[i] = it.converters[i](val)
+		}
+	}
+	return true
+}
+
+// NextResultSet advances to the next result set of a statement that returns
+// several, such as a stored procedure, skipping any rows not yet read. It
+// returns false when there are no more result sets or an error occurred.
+func (it *RowIterator) NextResultSet() bool {
+	if it.err != nil || !it.rows.NextResultSet() {
+		return false
+	}
+	if err := it.describe(); err != nil {
+		it.err = err
+		return false
+	}
+	return true
+}
+
+// Values returns the current row in column order. The slice is reused by
+// the next call to Next.
+func (it *RowIterator) Values() []interface{} {
+	return it.values
+}
+
+// Map returns a copy of the current row keyed by column name
+func (it *RowIterator) Map() map[string]interface{} {
+	row := make(map[string]interface{}, len(it.columns))
+	for i, col := range it.columns {
+		row[col] = it.values[i]
+	}
+	return row
+}
+
+// RowSize approximates the JSON-encoded size of the current row, the measure
+// ResultLimits.MaxBytes is checked against
+func (it *RowIterator) RowSize() int64 {
+	return estimateRowSize(it.columns, it.values)
+}
+
+// Err returns the error, if any, that stopped the iteration
+func (it *RowIterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	if err := it.rows.Err(); err != nil {
+		return fmt.Errorf("row iteration error: %w", err)
+	}
+	return nil
+}
+
+// Close releases the underlying rows and ends the read-only transaction, if any
+func (it *RowIterator) Close() error {
+	err := it.rows.Close()
+	if it.tx != nil {
+		// Nothing was written, so rolling back is the cheapest way to end it
+		if rbErr := it.tx.Rollback(); rbErr != nil && err == nil && rbErr != sql.ErrTxDone {
+			err = rbErr
+		}
+	}
+	return err
+}
+
+// collectRows buffers the rows of every result set from the iterator until
+// it is exhausted or a limit is hit. Result sets after the one where a limit
+// was hit are dropped.
+func collectRows(it *RowIterator, limits ResultLimits) (*QueryResult, error) {
+	var kept int
+	var size int64
+
+	first, err := collectResultSet(it, limits, &kept, &size)
+	if err != nil {
+		return nil, err
+	}
+	result := &QueryResult{
+		Columns:     first.Columns,
+		ColumnTypes: first.ColumnTypes,
+		Data:        first.Data,
+		Truncated:   first.Truncated,
+	}
+
+	for !result.Truncated && it.NextResultSet() {
+		// Row counts and other results without columns carry no data
+		if len(it.columns) == 0 {
+			continue
+		}
+		set, err := collectResultSet(it, limits, &kept, &size)
+		if err != nil {
+			return nil, err
+		}
+		result.ResultSets = append(result.ResultSets, *set)
+		result.Truncated = set.Truncated
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// collectResultSet buffers the rows of the current result set. kept and size
+// count the rows and bytes buffered so far across result sets.
+func collectResultSet(it *RowIterator, limits ResultLimits, kept *int, size *int64) (*ResultSet, error) {
+	var results []map[string]interface{}
+	truncated := false
+
+	for it.Next() {
+		if limits.MaxRows > 0 && *kept >= limits.MaxRows {
+			truncated = true
+			break
+		}
+
+		rowSize := it.RowSize()
+		if limits.MaxBytes > 0 && *size+rowSize > limits.MaxBytes {
+			truncated = true
+			break
+		}
+		*size += rowSize
+		*kept++
+
+		results = append(results, it.Map())
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return &ResultSet{
+		Columns:     it.columns,
+		ColumnTypes: it.meta,
+		Data:        results,
+		Truncated:   truncated,
+	}, nil
+}
+
+// estimateRowSize approximates the JSON-encoded size of a row
+func estimateRowSize(columns []string, values []interface{}) int64 {
+	var size int64
+	for i, col := range columns {
+		size += int64(len(col)) + 4 // quotes, colon and separator
+		size += estimateValueSize(values[i])
+	}
+	return size
+}
+
+func estimateValueSize(val interface{}) int64 {
+	switch v := val.(type) {
+	case nil:
+		return 4
+	case string:
+		return int64(len(v)) + 2
+	case json.Number:
+		return int64(len(v))
+	case json.RawMessage:
+		return int64(len(v))
+	case []byte:
+		return int64(len(v)) + 2
+	case bool:
+		return 5
+	case int64, uint64, float64, int32, float32, int:
+		return 8
+	case time.Time:
+		return 32
+	default:
+		return int64(len(fmt.Sprint(v)))
+	}
+}
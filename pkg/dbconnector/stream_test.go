@@ -0,0 +1,220 @@
+package dbconnector
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubDriver serves a fixed result set for every query, followed by a
+// second one for EXEC statements
+type stubDriver struct{}
+
+type stubConn struct{}
+
+type stubStmt struct {
+	query string
+}
+
+// stubTx records how the last transaction was opened and ended
+type stubTx struct{}
+
+var (
+	stubTxReadOnly   bool
+	stubTxRolledBack bool
+)
+
+// stubShowplanResetFails makes SET SHOWPLAN_XML OFF fail; stubConnsClosed
+// counts the driver connections closed
+var (
+	stubShowplanResetFails bool
+	stubConnsClosed        int
+)
+
+type stubRows struct {
+	pos   int
+	set   int  // Index of the current result set
+	multi bool // Whether a second result set follows
+}
+
+var stubColumns = []string{"id", "name"}
+
+var stubData = [][]driver.Value{
+	{int64(1), []byte("alice")},
+	{int64(2), []byte("bob")},
+	{int64(3), nil},
+}
+
+var stubTotalsColumns = []string{"total"}
+
+var stubTotalsData = [][]driver.Value{
+	{int64(3)},
+}
+
+func init() {
+	sql.Register("dbconnector-stub", stubDriver{})
+}
+
+func (stubDriver) Open(string) (driver.Conn, error) { return stubConn{}, nil }
+
+func (stubConn) Prepare(query string) (driver.Stmt, error) { return stubStmt{query: query}, nil }
+func (stubConn) Close() error                              { stubConnsClosed++; return nil }
+func (stubConn) Begin() (driver.Tx, error)                 { return nil, driver.ErrSkip }
+
+func (stubConn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	stubTxReadOnly = opts.ReadOnly
+	stubTxRolledBack = false
+	return stubTx{}, nil
+}
+
+func (stubTx) Commit() error   { return nil }
+func (stubTx) Rollback() error { stubTxRolledBack = true; return nil }
+
+func (stubStmt) Close() error  { return nil }
+func (stubStmt) NumInput() int { return -1 }
+func (s stubStmt) Exec([]driver.Value) (driver.Result, error) {
+	if stubShowplanResetFails && s.query == "SET SHOWPLAN_XML OFF" {
+		return nil, errors.New("showplan reset failed")
+	}
+	return driver.ResultNoRows, nil
+}
+func (s stubStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &stubRows{multi: strings.HasPrefix(s.query, "EXEC")}, nil
+}
+
+func (r *stubRows) Columns() []string {
+	if r.set > 0 {
+		return stubTotalsColumns
+	}
+	return stubColumns
+}
+
+func (r *stubRows) Close() error { return nil }
+
+func (r *stubRows) Next(dest []driver.Value) error {
+	data := stubData
+	if r.set > 0 {
+		data = stubTotalsData
+	}
+	if r.pos >= len(data) {
+		return io.EOF
+	}
+	copy(dest, data[r.pos])
+	r.pos++
+	return nil
+}
+
+func (r *stubRows) HasNextResultSet() bool { return r.multi && r.set == 0 }
+
+func (r *stubRows) NextResultSet() error {
+	if !r.HasNextResultSet() {
+		return io.EOF
+	}
+	r.set++
+	r.pos = 0
+	return nil
+}
+
+func newStubConnector(t *testing.T) *Connector {
+	db, err := sql.Open("dbconnector-stub", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return &Connector{config: &ConnectionConfig{Type: PostgreSQL}, db: db}
+}
+
+func TestConnector_QueryRows_ReadOnly(t *testing.T) {
+	connector := newStubConnector(t)
+	connector.config.ReadOnly = true
+
+	it, err := connector.QueryRows("SELECT id, name FROM users", nil)
+	require.NoError(t, err)
+	assert.True(t, stubTxReadOnly)
+
+	for it.Next() {
+	}
+	assert.NoError(t, it.Err())
+	assert.NoError(t, it.Close())
+	assert.True(t, stubTxRolledBack)
+
+	_, err = connector.Exec("DELETE FROM users")
+	assert.ErrorIs(t, err, ErrReadOnly)
+}
+
+func TestConnector_QueryRows(t *testing.T) {
+	connector := newStubConnector(t)
+
+	it, err := connector.QueryRows("SELECT id, name FROM users", nil)
+	require.NoError(t, err)
+	defer it.Close()
+
+	assert.Equal(t, []string{"id", "name"}, it.Columns())
+
+	var names []interface{}
+	for it.Next() {
+		names = append(names, it.Values()[1])
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []interface{}{"alice", "bob", nil}, names)
+}
+
+func TestConnector_ExecuteQueryWithLimits(t *testing.T) {
+	connector := newStubConnector(t)
+
+	result, err := connector.ExecuteQueryWithLimits("SELECT id, name FROM users", nil, ResultLimits{})
+	require.NoError(t, err)
+	assert.Len(t, result.Data, 3)
+	assert.False(t, result.Truncated)
+
+	result, err = connector.ExecuteQueryWithLimits("SELECT id, name FROM users", nil, ResultLimits{MaxRows: 2})
+	require.NoError(t, err)
+	assert.Len(t, result.Data, 2)
+	assert.True(t, result.Truncated)
+	assert.Equal(t, "bob", result.Data[1]["name"])
+
+	// Exactly as many rows as the limit is not a truncation
+	result, err = connector.ExecuteQueryWithLimits("SELECT id, name FROM users", nil, ResultLimits{MaxRows: 3})
+	require.NoError(t, err)
+	assert.Len(t, result.Data, 3)
+	assert.False(t, result.Truncated)
+
+	result, err = connector.ExecuteQueryWithLimits("SELECT id, name FROM users", nil, ResultLimits{MaxBytes: 40})
+	require.NoError(t, err)
+	assert.Len(t, result.Data, 1)
+	assert.True(t, result.Truncated)
+}
+
+func TestConnector_ExecuteQueryWithLimits_MultipleResultSets(t *testing.T) {
+	connector := newStubConnector(t)
+
+	result, err := connector.ExecuteQueryWithLimits("EXEC dbo.usp_report", nil, ResultLimits{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "name"}, result.Columns)
+	assert.Len(t, result.Data, 3)
+	require.Len(t, result.ResultSets, 1)
+	assert.Equal(t, []string{"total"}, result.ResultSets[0].Columns)
+	assert.Equal(t, []map[string]interface{}{{"total": int64(3)}}, result.ResultSets[0].Data)
+	assert.False(t, result.Truncated)
+
+	// The row limit covers all result sets together
+	result, err = connector.ExecuteQueryWithLimits("EXEC dbo.usp_report", nil, ResultLimits{MaxRows: 3})
+	require.NoError(t, err)
+	assert.Len(t, result.Data, 3)
+	require.Len(t, result.ResultSets, 1)
+	assert.Empty(t, result.ResultSets[0].Data)
+	assert.True(t, result.ResultSets[0].Truncated)
+	assert.True(t, result.Truncated)
+
+	// Result sets after a truncated one are dropped
+	result, err = connector.ExecuteQueryWithLimits("EXEC dbo.usp_report", nil, ResultLimits{MaxRows: 2})
+	require.NoError(t, err)
+	assert.Len(t, result.Data, 2)
+	assert.Empty(t, result.ResultSets)
+	assert.True(t, result.Truncated)
+}
@@ -0,0 +1,218 @@
+package dbconnector
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ColumnMeta describes a result column as reported by the driver
+type ColumnMeta struct {
+	Name         string `json:"name"`
+	DatabaseType string `json:"database_type"`       // Driver type name, e.g. NUMERIC, VARCHAR, UUID
+	Nullable     *bool  `json:"nullable,omitempty"`  // Nil when the driver does not report it
+	Precision    *int64 `json:"precision,omitempty"` // Decimal precision, when applicable
+	Scale        *int64 `json:"scale,omitempty"`     // Decimal scale, when applicable
+	Length       *int64 `json:"length,omitempty"`    // Length of variable-length types, when applicable
+}
+
+// valueConverter turns a scanned driver value into a JSON-friendly value
+type valueConverter func(val interface{}) interface{}
+
+// columnMetaFromTypes builds column metadata from the driver column types
+func columnMetaFromTypes(types []*sql.ColumnType) []ColumnMeta {
+	meta := make([]ColumnMeta, len(types))
+	for i, ct := range types {
+		meta[i] = ColumnMeta{
+			Name:         ct.Name(),
+			DatabaseType: strings.ToUpper(ct.DatabaseTypeName()),
+		}
+		if nullable, ok := ct.Nullable(); ok {
+			meta[i].Nullable = &nullable
+		}
+		if precision, scale, ok := ct.DecimalSize(); ok {
+			meta[i].Precision = &precision
+			meta[i].Scale = &scale
+		}
+		if length, ok := ct.Length(); ok {
+			meta[i].Length = &length
+		}
+	}
+	return meta
+}
+
+// converterFor picks how values of a column are mapped for the connector's driver
+func (c *Connector) converterFor(databaseType string) valueConverter {
+	var converters map[string]valueConverter
+	switch c.config.Type {
+	case PostgreSQL:
+		converters = postgresConverters
+	case MySQL:
+		converters = mysqlConverters
+	case MSSQL:
+		converters = mssqlConverters
+	}
+
+	if conv, ok := converters[databaseType]; ok {
+		return conv
+	}
+	return convertText
+}
+
+var postgresConverters = map[string]valueConverter{
+	"NUMERIC": convertDecimal,
+	"INT2":    convertInteger,
+	"INT4":    convertInteger,
+	"INT8":    convertInteger,
+	"FLOAT4":  convertFloat,
+	"FLOAT8":  convertFloat,
+	"MONEY":   convertText, // Formatted with a currency symbol
+	"JSON":    convertJSON,
+	"JSONB":   convertJSON,
+	"BYTEA":   convertBinary,
+	"UUID":    convertText,
+}
+
+var mysqlConverters = map[string]valueConverter{
+	"DECIMAL":            convertDecimal,
+	"TINYINT":            convertInteger,
+	"SMALLINT":           convertInteger,
+	"MEDIUMINT":          convertInteger,
+	"INT":                convertInteger,
+	"BIGINT":             convertInteger,
+	"UNSIGNED TINYINT":   convertInteger,
+	"UNSIGNED SMALLINT":  convertInteger,
+	"UNSIGNED MEDIUMINT": convertInteger,
+	"UNSIGNED INT":       convertInteger,
+	"UNSIGNED BIGINT":    convertInteger,
+	"YEAR":               convertInteger,
+	"FLOAT":              convertFloat,
+	"DOUBLE":             convertFloat,
+	"JSON":               convertJSON,
+	"BINARY":             convertBinary,
+	"VARBINARY":          convertBinary,
+	"TINYBLOB":           convertBinary,
+	"BLOB":               convertBinary,
+	"MEDIUMBLOB":         convertBinary,
+	"LONGBLOB":           convertBinary,
+	"BIT":                convertBitField,
+	"GEOMETRY":           convertBinary,
+}
+
+var mssqlConverters = map[string]valueConverter{
+	"DECIMAL":          convertDecimal,
+	"NUMERIC":          convertDecimal,
+	"MONEY":            convertDecimal,
+	"SMALLMONEY":       convertDecimal,
+	"BINARY":           convertBinary,
+	"VARBINARY":        convertBinary,
+	"IMAGE":            convertBinary,
+	"UNIQUEIDENTIFIER": convertMSSQLUUID,
+}
+
+// convertText keeps textual values as strings
+func convertText(val interface{}) interface{} {
+	if b, ok := val.([]byte); ok {
+		return string(b)
+	}
+	return val
+}
+
+// convertDecimal keeps exact decimals as JSON numbers without going through float64
+func convertDecimal(val interface{}) interface{} {
+	s, ok := textValue(val)
+	if !ok {
+		return val
+	}
+	// NaN and Infinity have no JSON number form
+	if _, err := strconv.ParseFloat(s, 64); err != nil || strings.ContainsAny(s, "nNiI") {
+		return s
+	}
+	return json.Number(s)
+}
+
+func convertInteger(val interface{}) interface{} {
+	s, ok := textValue(val)
+	if !ok {
+		return val
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n
+	}
+	// Unsigned values beyond int64 stay exact
+	if _, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return json.Number(s)
+	}
+	return s
+}
+
+func convertFloat(val interface{}) interface{} {
+	s, ok := textValue(val)
+	if !ok {
+		return val
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !strings.ContainsAny(s, "nNiI") {
+		return f
+	}
+	return s
+}
+
+// convertJSON embeds JSON documents as-is instead of re-encoding them as strings
+func convertJSON(val interface{}) interface{} {
+	s, ok := textValue(val)
+	if !ok {
+		return val
+	}
+	if !json.Valid([]byte(s)) {
+		return s
+	}
+	return json.RawMessage(s)
+}
+
+// convertBinary encodes raw bytes as base64
+func convertBinary(val interface{}) interface{} {
+	if b, ok := val.([]byte); ok {
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	return val
+}
+
+// convertBitField reads a MySQL BIT(n) value as an unsigned integer
+func convertBitField(val interface{}) interface{} {
+	b, ok := val.([]byte)
+	if !ok || len(b) > 8 {
+		return convertBinary(val)
+	}
+	var n uint64
+	for _, x := range b {
+		n = n<<8 | uint64(x)
+	}
+	return n
+}
+
+// convertMSSQLUUID formats a UNIQUEIDENTIFIER, whose first three groups are
+// stored little-endian
+func convertMSSQLUUID(val interface{}) interface{} {
+	b, ok := val.([]byte)
+	if !ok || len(b) != 16 {
+		return convertText(val)
+	}
+	return fmt.Sprintf("%02X%02X%02X%02X-%02X%02X-%02X%02X-%02X%02X-%02X%02X%02X%02X%02X%02X",
+		b[3], b[2], b[1], b[0], b[5], b[4], b[7], b[6],
+		b[8], b[9], b[10], b[11], b[12], b[13], b[14], b[15])
+}
+
+// textValue returns the textual form of a value scanned as bytes or string
+func textValue(val interface{}) (string, bool) {
+	switch v := val.(type) {
+	case []byte:
+		return string(v), true
+	case string:
+		return v, true
+	default:
+		return "", false
+	}
+}
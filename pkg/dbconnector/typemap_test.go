@@ -0,0 +1,47 @@
+package dbconnector
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnector_converterFor(t *testing.T) {
+	tests := []struct {
+		dbType       DBType
+		databaseType string
+		input        interface{}
+		expected     interface{}
+	}{
+		{PostgreSQL, "NUMERIC", []byte("12345678901234567890.123"), json.Number("12345678901234567890.123")},
+		{PostgreSQL, "NUMERIC", []byte("NaN"), "NaN"},
+		{PostgreSQL, "JSONB", []byte(`{"a":1}`), json.RawMessage(`{"a":1}`)},
+		{PostgreSQL, "BYTEA", []byte{0xff, 0x00, 0x10}, "/wAQ"},
+		{PostgreSQL, "UUID", []byte("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"), "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"},
+		{PostgreSQL, "TEXT", []byte("hello"), "hello"},
+		{MySQL, "DECIMAL", []byte("10.50"), json.Number("10.50")},
+		{MySQL, "BIGINT", []byte("42"), int64(42)},
+		{MySQL, "UNSIGNED BIGINT", []byte("18446744073709551615"), json.Number("18446744073709551615")},
+		{MySQL, "DOUBLE", []byte("1.5"), 1.5},
+		{MySQL, "JSON", []byte(`[1,2]`), json.RawMessage(`[1,2]`)},
+		{MySQL, "BIT", []byte{0x01, 0x02}, uint64(258)},
+		{MySQL, "BLOB", []byte("hi"), "aGk="},
+		{MSSQL, "DECIMAL", []byte("3.14"), json.Number("3.14")},
+		{MSSQL, "UNIQUEIDENTIFIER",
+			[]byte{0x67, 0x45, 0x23, 0x01, 0xab, 0x89, 0xef, 0xcd, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+			"01234567-89AB-CDEF-0123-456789ABCDEF"},
+		// Values the driver already typed pass through
+		{MySQL, "BIGINT", int64(7), int64(7)},
+	}
+
+	for _, tt := range tests {
+		connector := NewConnector(&ConnectionConfig{Type: tt.dbType})
+		conv := connector.converterFor(tt.databaseType)
+		assert.Equal(t, tt.expected, conv(tt.input), "%s %s", tt.dbType, tt.databaseType)
+	}
+}
+
+func TestConvertJSON_Invalid(t *testing.T) {
+	assert.Equal(t, "{not json", convertJSON([]byte("{not json")))
+}
@@ -0,0 +1,239 @@
+// Package export streams query results as CSV, XLSX, Parquet or JSON Lines.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/dataweaver/pkg/dbconnector"
+)
+
+// Format is a file format results can be exported to
+type Format string
+
+const (
+	CSV     Format = "csv"
+	XLSX    Format = "xlsx"
+	Parquet Format = "parquet"
+	JSONL   Format = "jsonl"
+)
+
+// ChunkRows is how many rows are written between flushes of the output
+const ChunkRows = 1000
+
+// ParseFormat returns the format with a name, case-insensitively
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case CSV, XLSX, Parquet, JSONL:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported export format: %s", name)
+	}
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case XLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case Parquet:
+		return "application/vnd.apache.parquet"
+	case JSONL:
+		return "application/x-ndjson"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// Extension returns the file extension of the format, without the dot
+func (f Format) Extension() string {
+	return string(f)
+}
+
+// Writer encodes rows in one format
+type Writer interface {
+	// WriteRow writes one row, with values in column order
+	WriteRow(values []interface{}) error
+	// Flush writes buffered rows to the output
+	Flush() error
+	// Close writes any trailer of the format and flushes the output. It does
+	// not close the underlying writer.
+	Close() error
+}
+
+// NewWriter returns a writer of the format that writes to w
+func NewWriter(f Format, w io.Writer, columns []dbconnector.ColumnMeta) (Writer, error) {
+	switch f {
+	case CSV:
+		return newCSVWriter(w, columns)
+	case XLSX:
+		return newXLSXWriter(w, columns)
+	case Parquet:
+		return newParquetWriter(w, columns)
+	case JSONL:
+		return newJSONLWriter(w, columns), nil
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", f)
+	}
+}
+
+// Rows is a source of result rows, such as a dbconnector.RowIterator
+type Rows interface {
+	Next() bool
+	Values() []interface{}
+	Err() error
+}
+
+// Copy writes every row to w in the format and returns the number of rows
+// written. The output is flushed every ChunkRows rows, and so is w if it is
+// an http.Flusher, so results are sent in chunks instead of being buffered.
+func Copy(w io.Writer, f Format, columns []dbconnector.ColumnMeta, rows Rows) (int, error) {
+	writer, err := NewWriter(f, w, columns)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for rows.Next() {
+		if err := writer.WriteRow(rows.Values()); err != nil {
+			return count, err
+		}
+		count++
+
+		if count%ChunkRows == 0 {
+			if err := writer.Flush(); err != nil {
+				return count, err
+			}
+			if flusher, ok := w.(interface{ Flush() }); ok {
+				flusher.Flush()
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return count, err
+	}
+
+	return count, writer.Close()
+}
+
+// columnKind is the logical type a column is exported as
+type columnKind int
+
+const (
+	kindString columnKind = iota
+	kindInteger
+	kindFloat
+	kindDecimal
+	kindBoolean
+	kindDate
+	kindTimestamp
+	kindJSON
+)
+
+// maxDecimalPrecision is the largest precision exported as a typed decimal
+const maxDecimalPrecision = 38
+
+// column is a result column with the logical type it is exported as
+type column struct {
+	name      string
+	kind      columnKind
+	precision int // Decimal precision, 0 when unknown
+	scale     int // Decimal scale
+}
+
+// columnsOf classifies result columns by their database type
+func columnsOf(meta []dbconnector.ColumnMeta) []column {
+	columns := make([]column, len(meta))
+	for i, m := range meta {
+		columns[i] = column{name: m.Name, kind: kindOf(m.DatabaseType)}
+		switch {
+		case m.DatabaseType == "UNSIGNED BIGINT":
+			// Does not fit in a signed 64-bit integer
+			columns[i].kind = kindDecimal
+			columns[i].precision = 20
+		case columns[i].kind == kindDecimal && m.Precision != nil && m.Scale != nil:
+			precision, scale := int(*m.Precision), int(*m.Scale)
+			if precision > 0 && precision <= maxDecimalPrecision && scale >= 0 && scale <= precision {
+				columns[i].precision = precision
+				columns[i].scale = scale
+			}
+		}
+	}
+	return columns
+}
+
+func kindOf(databaseType string) columnKind {
+	if strings.HasPrefix(databaseType, "UNSIGNED ") {
+		return kindInteger
+	}
+	switch databaseType {
+	case "INT2", "INT4", "INT8", "INT", "INTEGER", "BIGINT", "SMALLINT", "TINYINT", "MEDIUMINT", "YEAR":
+		return kindInteger
+	case "FLOAT4", "FLOAT8", "FLOAT", "DOUBLE", "REAL":
+		return kindFloat
+	case "NUMERIC", "DECIMAL", "SMALLMONEY":
+		return kindDecimal
+	case "BOOL", "BOOLEAN":
+		return kindBoolean
+	case "DATE":
+		return kindDate
+	case "TIMESTAMP", "TIMESTAMPTZ", "DATETIME", "DATETIME2", "SMALLDATETIME", "DATETIMEOFFSET":
+		return kindTimestamp
+	case "JSON", "JSONB":
+		return kindJSON
+	default:
+		return kindString
+	}
+}
+
+// formatText renders a value as text for the text-based formats
+func formatText(val interface{}, kind columnKind) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case json.Number:
+		return string(v)
+	case json.RawMessage:
+		return string(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return formatFloat(v)
+	case float32:
+		return formatFloat(float64(v))
+	case time.Time:
+		if kind == kindDate {
+			return v.Format("2006-01-02")
+		}
+		return v.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
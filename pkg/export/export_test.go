@@ -0,0 +1,175 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/dataweaver/pkg/dbconnector"
+)
+
+type stubRows struct {
+	rows [][]interface{}
+	pos  int
+}
+
+func (r *stubRows) Next() bool {
+	r.pos++
+	return r.pos <= len(r.rows)
+}
+
+func (r *stubRows) Values() []interface{} { return r.rows[r.pos-1] }
+
+func (r *stubRows) Err() error { return nil }
+
+func int64Ptr(n int64) *int64 { return &n }
+
+func testColumns() []dbconnector.ColumnMeta {
+	return []dbconnector.ColumnMeta{
+		{Name: "id", DatabaseType: "INT8"},
+		{Name: "amount", DatabaseType: "NUMERIC", Precision: int64Ptr(10), Scale: int64Ptr(2)},
+		{Name: "created_at", DatabaseType: "TIMESTAMPTZ"},
+		{Name: "day", DatabaseType: "DATE"},
+		{Name: "name", DatabaseType: "TEXT"},
+	}
+}
+
+func testRows() *stubRows {
+	return &stubRows{rows: [][]interface{}{
+		{int64(1), json.Number("12.50"), time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), "a, \"b\""},
+		{int64(2), json.Number("-0.05"), nil, nil, nil},
+	}}
+}
+
+func TestCopy_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := Copy(&buf, CSV, testColumns(), testRows())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, "id,amount,created_at,day,name\n"+
+		"1,12.50,2024-03-01T12:00:00Z,2024-03-01,\"a, \"\"b\"\"\"\n"+
+		"2,-0.05,,,\n", buf.String())
+}
+
+func TestCopy_JSONL(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := Copy(&buf, JSONL, testColumns(), testRows())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, `{"id":1,"amount":12.50,"created_at":"2024-03-01T12:00:00Z","day":"2024-03-01","name":"a, \"b\""}`+"\n"+
+		`{"id":2,"amount":-0.05,"created_at":null,"day":null,"name":null}`+"\n", buf.String())
+}
+
+func TestCopy_XLSX(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := Copy(&buf, XLSX, testColumns(), testRows())
+	require.NoError(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+
+	require.Contains(t, files, "[Content_Types].xml")
+	assert.Contains(t, files["xl/styles.xml"], `formatCode="0.00"`)
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c t="inlineStr" s="1"><is><t xml:space="preserve">amount</t></is></c>`)
+	assert.Contains(t, sheet, `<c><v>1</v></c><c s="4"><v>12.50</v></c><c s="3"><v>45352.5</v></c><c s="2"><v>45352</v></c>`)
+	assert.Contains(t, sheet, `<t xml:space="preserve">a, &#34;b&#34;</t>`)
+	assert.True(t, strings.HasSuffix(sheet, `</sheetData></worksheet>`))
+}
+
+func TestCopy_Parquet(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := Copy(&buf, Parquet, testColumns(), testRows())
+	require.NoError(t, err)
+
+	f, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), f.NumRows())
+
+	schema := f.Schema()
+	amount, ok := schema.Lookup("amount")
+	require.True(t, ok)
+	assert.Equal(t, "DECIMAL(10,2)", amount.Node.Type().String())
+	createdAt, _ := schema.Lookup("created_at")
+	assert.Contains(t, createdAt.Node.Type().String(), "TIMESTAMP")
+	day, _ := schema.Lookup("day")
+	assert.Equal(t, "DATE", day.Node.Type().String())
+
+	rows := make([]parquet.Row, 2)
+	reader := parquet.NewReader(f)
+	n, _ := reader.ReadRows(rows)
+	require.Equal(t, 2, n)
+
+	assert.Equal(t, int64(1250), rows[0][amount.ColumnIndex].Int64())
+	assert.Equal(t, int64(-5), rows[1][amount.ColumnIndex].Int64())
+	assert.Equal(t, time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC).UnixMicro(), rows[0][createdAt.ColumnIndex].Int64())
+	assert.True(t, rows[1][createdAt.ColumnIndex].IsNull())
+}
+
+func TestParquet_DuplicateColumns(t *testing.T) {
+	var buf bytes.Buffer
+	columns := []dbconnector.ColumnMeta{{Name: "id", DatabaseType: "INT4"}, {Name: "id", DatabaseType: "INT4"}}
+	_, err := Copy(&buf, Parquet, columns, &stubRows{rows: [][]interface{}{{int64(1), int64(2)}}})
+	require.NoError(t, err)
+
+	f, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	_, ok := f.Schema().Lookup("id_2")
+	assert.True(t, ok)
+}
+
+func TestUnscaledDecimal(t *testing.T) {
+	tests := []struct {
+		in    string
+		scale int
+		want  string
+	}{
+		{"12.5", 2, "1250"},
+		{"-0.05", 2, "-5"},
+		{"1.005", 2, "101"},
+		{"-1.005", 2, "-101"},
+		{"12345678901234567890.12", 2, "1234567890123456789012"},
+	}
+	for _, tt := range tests {
+		got, err := unscaledDecimal(tt.in, tt.scale)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got.String(), tt.in)
+	}
+
+	_, err := unscaledDecimal("abc", 2)
+	assert.Error(t, err)
+}
+
+func TestTwosComplement(t *testing.T) {
+	assert.Equal(t, []byte{0x00, 0x01}, twosComplement(big.NewInt(1), 2))
+	assert.Equal(t, []byte{0xff, 0xff}, twosComplement(big.NewInt(-1), 2))
+	assert.Equal(t, []byte{0xff, 0x00}, twosComplement(big.NewInt(-256), 2))
+	assert.Equal(t, 16, decimalBytes(38))
+	assert.Equal(t, 9, decimalBytes(19))
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("XLSX")
+	require.NoError(t, err)
+	assert.Equal(t, XLSX, f)
+
+	_, err = ParseFormat("pdf")
+	assert.Error(t, err)
+}
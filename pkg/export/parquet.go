@@ -0,0 +1,252 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress/snappy"
+	"github.com/yourusername/dataweaver/pkg/dbconnector"
+)
+
+// parquetRowGroupRows is the smallest row group written on a flush. Parquet
+// readers work best with large row groups, so most flushes only buffer.
+const parquetRowGroupRows = 50000
+
+// parquetWriter writes rows as a Parquet file with one optional column per
+// result column. Parquet schemas order fields by name, so each row is
+// rearranged into schema order.
+type parquetWriter struct {
+	w        *parquet.Writer
+	columns  []column
+	index    []int // Schema column index of each result column
+	row      parquet.Row
+	buffered int
+}
+
+func newParquetWriter(w io.Writer, meta []dbconnector.ColumnMeta) (*parquetWriter, error) {
+	pw := &parquetWriter{columns: columnsOf(meta)}
+
+	// Parquet field names must be unique
+	names := make([]string, len(pw.columns))
+	group := make(parquet.Group, len(pw.columns))
+	for i, col := range pw.columns {
+		name := col.name
+		for n := 2; group[name] != nil; n++ {
+			name = col.name + "_" + strconv.Itoa(n)
+		}
+		names[i] = name
+		group[name] = parquet.Optional(parquetNode(col))
+	}
+
+	schema := parquet.NewSchema("results", group)
+	pw.index = make([]int, len(names))
+	for i, name := range names {
+		leaf, ok := schema.Lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("parquet column %s not found in schema", name)
+		}
+		pw.index[i] = leaf.ColumnIndex
+	}
+
+	pw.w = parquet.NewWriter(w, schema, parquet.Compression(&snappy.Codec{}))
+	pw.row = make(parquet.Row, len(pw.columns))
+	return pw, nil
+}
+
+// parquetNode maps a column kind to a Parquet type. Decimals of unknown
+// precision are written as strings to keep them exact.
+func parquetNode(col column) parquet.Node {
+	switch col.kind {
+	case kindInteger:
+		return parquet.Int(64)
+	case kindFloat:
+		return parquet.Leaf(parquet.DoubleType)
+	case kindDecimal:
+		if col.precision == 0 {
+			return parquet.String()
+		}
+		if col.precision <= 18 {
+			return parquet.Decimal(col.scale, col.precision, parquet.Int64Type)
+		}
+		return parquet.Decimal(col.scale, col.precision, parquet.FixedLenByteArrayType(decimalBytes(col.precision)))
+	case kindBoolean:
+		return parquet.Leaf(parquet.BooleanType)
+	case kindDate:
+		return parquet.Date()
+	case kindTimestamp:
+		return parquet.Timestamp(parquet.Microsecond)
+	case kindJSON:
+		return parquet.JSON()
+	default:
+		return parquet.String()
+	}
+}
+
+func (pw *parquetWriter) WriteRow(values []interface{}) error {
+	for i, val := range values {
+		v, err := parquetValue(val, pw.columns[i])
+		if err != nil {
+			return fmt.Errorf("column %s: %w", pw.columns[i].name, err)
+		}
+		index := pw.index[i]
+		if val == nil {
+			pw.row[index] = v.Level(0, 0, index)
+		} else {
+			pw.row[index] = v.Level(0, 1, index)
+		}
+	}
+	if _, err := pw.w.WriteRows([]parquet.Row{pw.row}); err != nil {
+		return err
+	}
+	pw.buffered++
+	return nil
+}
+
+func parquetValue(val interface{}, col column) (parquet.Value, error) {
+	if val == nil {
+		return parquet.NullValue(), nil
+	}
+
+	switch col.kind {
+	case kindInteger:
+		n, err := toInt64(val)
+		if err != nil {
+			return parquet.Value{}, err
+		}
+		return parquet.Int64Value(n), nil
+	case kindFloat:
+		f, err := toFloat64(val)
+		if err != nil {
+			return parquet.Value{}, err
+		}
+		return parquet.DoubleValue(f), nil
+	case kindDecimal:
+		if col.precision == 0 {
+			break
+		}
+		unscaled, err := unscaledDecimal(formatText(val, col.kind), col.scale)
+		if err != nil {
+			return parquet.Value{}, err
+		}
+		if col.precision <= 18 {
+			return parquet.Int64Value(unscaled.Int64()), nil
+		}
+		return parquet.FixedLenByteArrayValue(twosComplement(unscaled, decimalBytes(col.precision))), nil
+	case kindBoolean:
+		if b, ok := val.(bool); ok {
+			return parquet.BooleanValue(b), nil
+		}
+		return parquet.Value{}, fmt.Errorf("cannot write %T as boolean", val)
+	case kindDate, kindTimestamp:
+		t, ok := val.(time.Time)
+		if !ok {
+			break
+		}
+		if col.kind == kindDate {
+			wall := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+			return parquet.Int32Value(int32(wall.Unix() / 86400)), nil
+		}
+		return parquet.Int64Value(t.UnixMicro()), nil
+	}
+
+	// Strings, and values a typed column could not hold as such
+	if col.kind != kindString && col.kind != kindJSON && col.kind != kindDecimal {
+		return parquet.Value{}, fmt.Errorf("cannot write %T as %s", val, parquetNode(col).Type())
+	}
+	return parquet.ByteArrayValue([]byte(formatText(val, col.kind))), nil
+}
+
+func (pw *parquetWriter) Flush() error {
+	if pw.buffered < parquetRowGroupRows {
+		return nil
+	}
+	pw.buffered = 0
+	return pw.w.Flush()
+}
+
+func (pw *parquetWriter) Close() error {
+	return pw.w.Close()
+}
+
+func toInt64(val interface{}) (int64, error) {
+	switch v := val.(type) {
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case uint64:
+		if v > 1<<63-1 {
+			return 0, fmt.Errorf("%d overflows int64", v)
+		}
+		return int64(v), nil
+	case json.Number:
+		return v.Int64()
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	default:
+		return 0, fmt.Errorf("cannot write %T as integer", val)
+	}
+}
+
+func toFloat64(val interface{}) (float64, error) {
+	switch v := val.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("cannot write %T as double", val)
+	}
+}
+
+// unscaledDecimal parses a decimal and returns it multiplied by 10^scale,
+// rounding half away from zero any digits beyond the scale
+func unscaledDecimal(s string, scale int) (*big.Int, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal %q", s)
+	}
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)))
+
+	quo, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(int64(r.Sign())))
+	}
+	return quo, nil
+}
+
+// decimalBytes is the size of the smallest two's complement integer that
+// holds every unscaled value of a precision
+func decimalBytes(precision int) int {
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(precision)), nil)
+	return (limit.BitLen() + 1 + 7) / 8 // One bit for the sign
+}
+
+// twosComplement encodes n as a big-endian two's complement integer of size bytes
+func twosComplement(n *big.Int, size int) []byte {
+	b := make([]byte, size)
+	if n.Sign() >= 0 {
+		n.FillBytes(b)
+		return b
+	}
+	// -n == ^(n-1) for the positive n-1
+	m := new(big.Int).Neg(n)
+	m.Sub(m, big.NewInt(1)).FillBytes(b)
+	for i := range b {
+		b[i] = ^b[i]
+	}
+	return b
+}
@@ -0,0 +1,100 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/yourusername/dataweaver/pkg/dbconnector"
+)
+
+// csvWriter writes a header row followed by one record per row
+type csvWriter struct {
+	w       *csv.Writer
+	columns []column
+	record  []string
+}
+
+func newCSVWriter(w io.Writer, meta []dbconnector.ColumnMeta) (*csvWriter, error) {
+	cw := &csvWriter{
+		w:       csv.NewWriter(w),
+		columns: columnsOf(meta),
+		record:  make([]string, len(meta)),
+	}
+	for i, col := range cw.columns {
+		cw.record[i] = col.name
+	}
+	if err := cw.w.Write(cw.record); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvWriter) WriteRow(values []interface{}) error {
+	for i, val := range values {
+		cw.record[i] = formatText(val, cw.columns[i].kind)
+	}
+	return cw.w.Write(cw.record)
+}
+
+func (cw *csvWriter) Flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (cw *csvWriter) Close() error {
+	return cw.Flush()
+}
+
+// jsonlWriter writes one JSON object per line, with keys in column order
+type jsonlWriter struct {
+	w       *bufio.Writer
+	columns []column
+	keys    [][]byte // Encoded column names
+}
+
+func newJSONLWriter(w io.Writer, meta []dbconnector.ColumnMeta) *jsonlWriter {
+	jw := &jsonlWriter{
+		w:       bufio.NewWriter(w),
+		columns: columnsOf(meta),
+		keys:    make([][]byte, len(meta)),
+	}
+	for i, col := range jw.columns {
+		jw.keys[i], _ = json.Marshal(col.name)
+	}
+	return jw
+}
+
+func (jw *jsonlWriter) WriteRow(values []interface{}) error {
+	jw.w.WriteByte('{')
+	for i, val := range values {
+		if i > 0 {
+			jw.w.WriteByte(',')
+		}
+		jw.w.Write(jw.keys[i])
+		jw.w.WriteByte(':')
+
+		// Dates carry no time of day
+		if t, ok := val.(time.Time); ok && jw.columns[i].kind == kindDate {
+			val = t.Format("2006-01-02")
+		}
+		data, err := json.Marshal(val)
+		if err != nil {
+			// NaN and Infinity have no JSON number form
+			data, _ = json.Marshal(formatText(val, jw.columns[i].kind))
+		}
+		jw.w.Write(data)
+	}
+	jw.w.WriteByte('}')
+	return jw.w.WriteByte('\n')
+}
+
+func (jw *jsonlWriter) Flush() error {
+	return jw.w.Flush()
+}
+
+func (jw *jsonlWriter) Close() error {
+	return jw.w.Flush()
+}
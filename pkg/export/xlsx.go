@@ -0,0 +1,277 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/dataweaver/pkg/dbconnector"
+)
+
+// ErrTooManyRows is returned when a result does not fit in one worksheet
+var ErrTooManyRows = errors.New("result exceeds the 1048576 rows of an XLSX worksheet")
+
+// xlsxMaxRows is the number of rows in a worksheet, including the header
+const xlsxMaxRows = 1048576
+
+// Cell style indexes into the cellXfs of styles.xml. Decimal styles follow.
+const (
+	styleGeneral = iota
+	styleHeader
+	styleDate
+	styleTimestamp
+	styleDecimalBase
+)
+
+// xlsxWriter writes a single-sheet workbook. The sheet is the last entry of
+// the zip archive, so its rows can be written as they arrive.
+type xlsxWriter struct {
+	zw      *zip.Writer
+	w       *bufio.Writer
+	columns []column
+	styles  []int // Cell style of each column
+	rows    int
+}
+
+func newXLSXWriter(w io.Writer, meta []dbconnector.ColumnMeta) (*xlsxWriter, error) {
+	xw := &xlsxWriter{
+		zw:      zip.NewWriter(w),
+		columns: columnsOf(meta),
+	}
+
+	// Decimals are shown with their scale, dates and timestamps as such
+	var decimalFormats []string
+	xw.styles = make([]int, len(xw.columns))
+	for i, col := range xw.columns {
+		switch {
+		case col.kind == kindDate:
+			xw.styles[i] = styleDate
+		case col.kind == kindTimestamp:
+			xw.styles[i] = styleTimestamp
+		case col.kind == kindDecimal && col.scale > 0:
+			format := "0." + strings.Repeat("0", col.scale)
+			index := indexOf(decimalFormats, format)
+			if index < 0 {
+				index = len(decimalFormats)
+				decimalFormats = append(decimalFormats, format)
+			}
+			xw.styles[i] = styleDecimalBase + index
+		}
+	}
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles(decimalFormats)},
+	}
+	for _, part := range parts {
+		f, err := xw.zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := xw.zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	xw.w = bufio.NewWriter(sheet)
+	xw.w.WriteString(xml.Header)
+	xw.w.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	xw.w.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	xw.w.WriteString(`<sheetData>`)
+
+	// Header row
+	xw.w.WriteString(`<row>`)
+	for _, col := range xw.columns {
+		xw.writeString(col.name, styleHeader)
+	}
+	xw.w.WriteString(`</row>`)
+	xw.rows = 1
+
+	return xw, nil
+}
+
+func (xw *xlsxWriter) WriteRow(values []interface{}) error {
+	if xw.rows >= xlsxMaxRows {
+		return ErrTooManyRows
+	}
+	xw.rows++
+
+	xw.w.WriteString(`<row>`)
+	for i, val := range values {
+		xw.writeCell(val, xw.columns[i].kind, xw.styles[i])
+	}
+	_, err := xw.w.WriteString(`</row>`)
+	return err
+}
+
+func (xw *xlsxWriter) writeCell(val interface{}, kind columnKind, style int) {
+	switch v := val.(type) {
+	case nil:
+		xw.w.WriteString(`<c/>`)
+	case bool:
+		b := "0"
+		if v {
+			b = "1"
+		}
+		xw.w.WriteString(`<c t="b"><v>` + b + `</v></c>`)
+	case int64:
+		xw.writeNumber(strconv.FormatInt(v, 10), style)
+	case uint64:
+		xw.writeNumber(strconv.FormatUint(v, 10), style)
+	case float64:
+		xw.writeFloat(v, style)
+	case float32:
+		xw.writeFloat(float64(v), style)
+	case json.Number:
+		xw.writeNumber(string(v), style)
+	case time.Time:
+		serial, ok := excelSerial(v)
+		if !ok {
+			xw.writeString(formatText(v, kind), styleGeneral)
+			return
+		}
+		if style != styleDate {
+			style = styleTimestamp
+		}
+		xw.writeNumber(strconv.FormatFloat(serial, 'f', -1, 64), style)
+	default:
+		xw.writeString(formatText(val, kind), styleGeneral)
+	}
+}
+
+func (xw *xlsxWriter) writeFloat(f float64, style int) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		xw.writeString(formatFloat(f), styleGeneral)
+		return
+	}
+	xw.writeNumber(strconv.FormatFloat(f, 'g', -1, 64), style)
+}
+
+func (xw *xlsxWriter) writeNumber(n string, style int) {
+	if style == styleGeneral {
+		xw.w.WriteString(`<c><v>` + n + `</v></c>`)
+		return
+	}
+	fmt.Fprintf(xw.w, `<c s="%d"><v>%s</v></c>`, style, n)
+}
+
+func (xw *xlsxWriter) writeString(s string, style int) {
+	if style == styleGeneral {
+		xw.w.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+	} else {
+		fmt.Fprintf(xw.w, `<c t="inlineStr" s="%d"><is><t xml:space="preserve">`, style)
+	}
+	xml.EscapeText(xw.w, []byte(s))
+	xw.w.WriteString(`</t></is></c>`)
+}
+
+func (xw *xlsxWriter) Flush() error {
+	if err := xw.w.Flush(); err != nil {
+		return err
+	}
+	return xw.zw.Flush()
+}
+
+func (xw *xlsxWriter) Close() error {
+	xw.w.WriteString(`</sheetData></worksheet>`)
+	if err := xw.w.Flush(); err != nil {
+		return err
+	}
+	return xw.zw.Close()
+}
+
+// excelEpoch is day zero of Excel's 1900 date system, which counts the
+// nonexistent 29 February 1900; serials from March 1900 on are correct
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// excelSerial converts a time to an Excel date serial, in the time's own
+// zone since Excel has none. Times before March 1900 are not converted.
+func excelSerial(t time.Time) (float64, bool) {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	if wall.Year() < 1900 || (wall.Year() == 1900 && wall.Month() < time.March) || wall.Year() > 9999 {
+		return 0, false
+	}
+	days := wall.Sub(excelEpoch) / (24 * time.Hour)
+	rest := wall.Sub(excelEpoch) % (24 * time.Hour)
+	return float64(days) + float64(rest)/float64(24*time.Hour), true
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// xlsxStyles builds styles.xml with the fixed styles followed by one style
+// per decimal number format
+func xlsxStyles(decimalFormats []string) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+
+	fmt.Fprintf(&b, `<numFmts count="%d">`, 2+len(decimalFormats))
+	b.WriteString(`<numFmt numFmtId="164" formatCode="yyyy-mm-dd"/>`)
+	b.WriteString(`<numFmt numFmtId="165" formatCode="yyyy-mm-dd hh:mm:ss"/>`)
+	for i, format := range decimalFormats {
+		fmt.Fprintf(&b, `<numFmt numFmtId="%d" formatCode="%s"/>`, 166+i, format)
+	}
+	b.WriteString(`</numFmts>`)
+
+	b.WriteString(`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>`)
+	b.WriteString(`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>`)
+	b.WriteString(`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>`)
+	b.WriteString(`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>`)
+
+	fmt.Fprintf(&b, `<cellXfs count="%d">`, styleDecimalBase+len(decimalFormats))
+	b.WriteString(`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>`)
+	b.WriteString(`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>`)
+	b.WriteString(`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>`)
+	b.WriteString(`<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>`)
+	for i := range decimalFormats {
+		fmt.Fprintf(&b, `<xf numFmtId="%d" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>`, 166+i)
+	}
+	b.WriteString(`</cellXfs>`)
+
+	b.WriteString(`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>`)
+	b.WriteString(`</styleSheet>`)
+	return b.String()
+}
+
+const xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const xlsxRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="Results" sheetId="1" r:id="rId1"/></sheets>` +
+	`</workbook>`
+
+const xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
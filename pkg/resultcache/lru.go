@@ -0,0 +1,114 @@
+// Package resultcache keeps encoded query results in memory, bounded by their
+// total size and evicting the least recently used first.
+package resultcache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRU is a size-bounded cache of byte values with per-entry expiry. Entries
+// belong to a group so that everything cached for, say, one query can be
+// purged at once. It is safe for concurrent use.
+type LRU struct {
+	mu       sync.Mutex
+	maxBytes int64
+	size     int64
+	order    *list.List               // Front is the most recently used
+	items    map[string]*list.Element // Key -> element holding an *entry
+}
+
+type entry struct {
+	key       string
+	group     string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRU creates a cache holding at most maxBytes of values
+func NewLRU(maxBytes int64) *LRU {
+	return &LRU{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the value stored under key and when it expires, if it has not
+// expired by now
+func (c *LRU) Get(key string, now time.Time) ([]byte, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	e := el.Value.(*entry)
+	if !now.Before(e.expiresAt) {
+		c.remove(el)
+		return nil, time.Time{}, false
+	}
+	c.order.MoveToFront(el)
+	return e.value, e.expiresAt, true
+}
+
+// Set stores a value until expiresAt, evicting the least recently used
+// entries to make room. A value larger than the whole cache is not stored.
+func (c *LRU) Set(key, group string, value []byte, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+	if int64(len(value)) > c.maxBytes {
+		return
+	}
+
+	for c.size+int64(len(value)) > c.maxBytes {
+		c.remove(c.order.Back())
+	}
+
+	c.items[key] = c.order.PushFront(&entry{key: key, group: group, value: value, expiresAt: expiresAt})
+	c.size += int64(len(value))
+}
+
+// PurgeGroup removes every entry of a group and returns how many there were
+func (c *LRU) PurgeGroup(group string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	purged := 0
+	for el := c.order.Front(); el != nil; {
+		next := el.Next()
+		if el.Value.(*entry).group == group {
+			c.remove(el)
+			purged++
+		}
+		el = next
+	}
+	return purged
+}
+
+// Len returns the number of entries, including expired ones not yet evicted
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Size returns the total size of the stored values
+func (c *LRU) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+func (c *LRU) remove(el *list.Element) {
+	e := el.Value.(*entry)
+	c.order.Remove(el)
+	delete(c.items, e.key)
+	c.size -= int64(len(e.value))
+}
@@ -0,0 +1,83 @@
+package resultcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU_GetSet(t *testing.T) {
+	now := time.Now()
+	c := NewLRU(100)
+
+	c.Set("a", "q1", []byte("hello"), now.Add(time.Minute))
+	value, expiresAt, ok := c.Get("a", now)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("hello"), value)
+	assert.Equal(t, now.Add(time.Minute), expiresAt)
+
+	_, _, ok = c.Get("missing", now)
+	assert.False(t, ok)
+}
+
+func TestLRU_Expiry(t *testing.T) {
+	now := time.Now()
+	c := NewLRU(100)
+
+	c.Set("a", "q1", []byte("hello"), now.Add(time.Second))
+	_, _, ok := c.Get("a", now.Add(time.Second))
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+	assert.Equal(t, int64(0), c.Size())
+}
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Minute)
+	c := NewLRU(10)
+
+	c.Set("a", "q1", []byte("aaaa"), later)
+	c.Set("b", "q1", []byte("bbbb"), later)
+	_, _, _ = c.Get("a", now) // a is now more recent than b
+	c.Set("c", "q1", []byte("cccc"), later)
+
+	_, _, ok := c.Get("b", now)
+	assert.False(t, ok)
+	_, _, ok = c.Get("a", now)
+	assert.True(t, ok)
+	_, _, ok = c.Get("c", now)
+	assert.True(t, ok)
+	assert.Equal(t, int64(8), c.Size())
+}
+
+func TestLRU_ReplaceAndOversized(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Minute)
+	c := NewLRU(10)
+
+	c.Set("a", "q1", []byte("aaaa"), later)
+	c.Set("a", "q1", []byte("aaaaaa"), later)
+	assert.Equal(t, 1, c.Len())
+	assert.Equal(t, int64(6), c.Size())
+
+	c.Set("big", "q1", []byte("this is too large"), later)
+	_, _, ok := c.Get("big", now)
+	assert.False(t, ok)
+	assert.Equal(t, int64(6), c.Size())
+}
+
+func TestLRU_PurgeGroup(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Minute)
+	c := NewLRU(100)
+
+	c.Set("a", "q1", []byte("a"), later)
+	c.Set("b", "q2", []byte("b"), later)
+	c.Set("c", "q1", []byte("c"), later)
+
+	assert.Equal(t, 2, c.PurgeGroup("q1"))
+	assert.Equal(t, 1, c.Len())
+	_, _, ok := c.Get("b", now)
+	assert.True(t, ok)
+}
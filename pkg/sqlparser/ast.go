@@ -0,0 +1,531 @@
+package sqlparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Node is a statement or a parenthesized group within one. Parenthesized
+// groups nest as child nodes, so subqueries, CTE bodies and function
+// arguments each form their own subtree.
+type Node struct {
+	Open  *Token // Opening parenthesis; nil for a top-level statement
+	Close *Token // Closing parenthesis; nil for a statement or an unclosed group
+	Items []Item
+}
+
+// Item is either a significant token or a nested group
+type Item struct {
+	Token Token
+	Group *Node
+}
+
+// Kind returns the upper-cased leading keyword of the node, e.g. SELECT or
+// WITH. A node that starts with a group takes the kind of that group, so
+// "(SELECT 1) UNION (SELECT 2)" is a SELECT.
+func (n *Node) Kind() string {
+	if len(n.Items) == 0 {
+		return ""
+	}
+	first := n.Items[0]
+	if first.Group != nil {
+		return first.Group.Kind()
+	}
+	if first.Token.Type != TokenIdent {
+		return ""
+	}
+	return strings.ToUpper(first.Token.Text)
+}
+
+// firstToken returns the token where the node starts, for error positions
+func (n *Node) firstToken() Token {
+	if n.Open != nil {
+		return *n.Open
+	}
+	for _, item := range n.Items {
+		if item.Group != nil {
+			return item.Group.firstToken()
+		}
+		return item.Token
+	}
+	return Token{Line: 1, Column: 1}
+}
+
+// ValidationError reports a problem at a position in the SQL source
+type ValidationError struct {
+	Message string `json:"message"`
+	Offset  int    `json:"offset"` // Byte offset
+	Line    int    `json:"line"`   // 1-based
+	Column  int    `json:"column"` // 1-based
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s (line %d, column %d)", e.Message, e.Line, e.Column)
+}
+
+func errorAt(tok Token, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{
+		Message: fmt.Sprintf(format, args...),
+		Offset:  tok.Pos,
+		Line:    tok.Line,
+		Column:  tok.Column,
+	}
+}
+
+// ParseStatements splits SQL into statements on top-level semicolons and
+// builds the parenthesis tree of each. Comments and whitespace are dropped;
+// empty statements are skipped.
+func ParseStatements(sql string, dialect Dialect) ([]*Node, error) {
+	var statements []*Node
+	stack := []*Node{{}}
+
+	for _, tok := range Tokenize(sql, dialect) {
+		current := stack[len(stack)-1]
+
+		switch {
+		case tok.Type == TokenWhitespace || tok.Type == TokenComment:
+			continue
+
+		case tok.Type == TokenPunct && tok.Text == "(":
+			open := tok
+			group := &Node{Open: &open}
+			current.Items = append(current.Items, Item{Group: group})
+			stack = append(stack, group)
+
+		case tok.Type == TokenPunct && tok.Text == ")":
+			if len(stack) == 1 {
+				return nil, errorAt(tok, "unbalanced parentheses: unexpected )")
+			}
+			closing := tok
+			current.Close = &closing
+			stack = stack[:len(stack)-1]
+
+		case tok.Type == TokenPunct && tok.Text == ";" && len(stack) == 1:
+			if len(current.Items) > 0 {
+				statements = append(statements, current)
+			}
+			stack[0] = &Node{}
+
+		default:
+			current.Items = append(current.Items, Item{Token: tok})
+		}
+	}
+
+	if len(stack) > 1 {
+		return nil, errorAt(*stack[len(stack)-1].Open, "unbalanced parentheses: ( is never closed")
+	}
+	if len(stack[0].Items) > 0 {
+		statements = append(statements, stack[0])
+	}
+
+	return statements, nil
+}
+
+// readOnlyKinds are the statement kinds that can only read data
+var readOnlyKinds = map[string]bool{
+	"SELECT": true,
+	"WITH":   true,
+	"VALUES": true,
+	"TABLE":  true,
+}
+
+// DeniedFunctions are functions with side effects that read-only queries
+// may not call, keyed by lower-case name
+var DeniedFunctions = map[string]bool{
+	// PostgreSQL
+	"pg_terminate_backend":               true,
+	"pg_cancel_backend":                  true,
+	"pg_reload_conf":                     true,
+	"pg_rotate_logfile":                  true,
+	"pg_switch_wal":                      true,
+	"pg_create_restore_point":            true,
+	"pg_promote":                         true,
+	"pg_sleep":                           true,
+	"pg_sleep_for":                       true,
+	"pg_sleep_until":                     true,
+	"pg_advisory_lock":                   true,
+	"pg_advisory_xact_lock":              true,
+	"pg_try_advisory_lock":               true,
+	"pg_notify":                          true,
+	"pg_read_file":                       true,
+	"pg_read_binary_file":                true,
+	"pg_ls_dir":                          true,
+	"pg_stat_file":                       true,
+	"set_config":                         true,
+	"nextval":                            true,
+	"setval":                             true,
+	"lo_import":                          true,
+	"lo_export":                          true,
+	"lo_unlink":                          true,
+	"dblink":                             true,
+	"dblink_exec":                        true,
+	"pg_create_logical_replication_slot": true,
+	"pg_drop_replication_slot":           true,
+	// MySQL
+	"sleep":             true,
+	"benchmark":         true,
+	"get_lock":          true,
+	"release_lock":      true,
+	"release_all_locks": true,
+	"load_file":         true,
+	// SQL Server
+	"openrowset":     true,
+	"openquery":      true,
+	"opendatasource": true,
+}
+
+// DeniedPackagePrefixes are the prefixes of Oracle packages whose routines
+// may not be called from read-only queries, such as DBMS_LOCK.SLEEP or
+// UTL_HTTP.REQUEST. Routines without arguments can be called without
+// parentheses, so any reference to them is denied.
+var DeniedPackagePrefixes = []string{"dbms_", "utl_"}
+
+// deniedPackage returns the package of a qualified routine name if it is
+// one of DeniedPackagePrefixes
+func deniedPackage(name string) (string, bool) {
+	parts := strings.Split(name, ".")
+	if len(parts) < 2 {
+		return "", false
+	}
+	pkg := parts[len(parts)-2]
+	for _, prefix := range DeniedPackagePrefixes {
+		if strings.HasPrefix(strings.ToLower(pkg), prefix) {
+			return pkg, true
+		}
+	}
+	return "", false
+}
+
+// ReadOnlyOptions relaxes read-only validation for routines that have been
+// vetted not to modify data
+type ReadOnlyOptions struct {
+	// AllowedRoutines are the stored procedures and functions that may be
+	// called, matched case-insensitively against the name as written,
+	// including any schema, e.g. "dbo.usp_sales_report". Listed names may
+	// follow EXEC, EXECUTE or CALL, and listed functions are exempt from
+	// DeniedFunctions.
+	AllowedRoutines []string
+}
+
+// allows reports whether a routine name is on the allowlist
+func (o ReadOnlyOptions) allows(name string) bool {
+	for _, routine := range o.AllowedRoutines {
+		if strings.EqualFold(strings.TrimSpace(routine), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// callKinds are the statement kinds that invoke a stored procedure or function
+var callKinds = map[string]bool{
+	"EXEC":    true,
+	"EXECUTE": true,
+	"CALL":    true,
+}
+
+// ValidateReadOnlyStatements parses the SQL with the rules of the dialect and
+// checks that it is a single statement that cannot modify data: a query, or
+// a call to a routine allowed by opts. Optional blocks are validated as if
+// all of them were kept.
+func ValidateReadOnlyStatements(sql string, dialect Dialect, opts ReadOnlyOptions) error {
+	revealed := revealOptionalBlocks(sql, dialect)
+	for _, tok := range Tokenize(revealed, dialect) {
+		if isExecutableComment(tok, dialect) {
+			return errorAt(tok, "executable comments are not allowed")
+		}
+	}
+
+	statements, err := ParseStatements(revealed, dialect)
+	if err != nil {
+		return err
+	}
+	if len(statements) == 0 {
+		return errorAt(Token{Line: 1, Column: 1}, "SQL template cannot be empty")
+	}
+	if len(statements) > 1 {
+		return errorAt(statements[1].firstToken(), "multiple statements are not allowed")
+	}
+
+	stmt := statements[0]
+	if callKinds[stmt.Kind()] {
+		return validateCall(stmt, opts)
+	}
+	if !readOnlyKinds[stmt.Kind()] {
+		return errorAt(stmt.firstToken(), "only SELECT queries are allowed")
+	}
+	return validateQuery(stmt, opts)
+}
+
+// validateCall checks an EXEC, EXECUTE or CALL statement: the routine must
+// be on the allowlist and its arguments must be plain values
+func validateCall(stmt *Node, opts ReadOnlyOptions) error {
+	kind := stmt.Kind()
+	name, next := qualifiedName(stmt.Items, 1)
+	if name == "" {
+		// Dynamic SQL, a procedure name in a variable or a return status capture
+		return errorAt(stmt.firstToken(), "%s must name a procedure or function", kind)
+	}
+	if !opts.allows(name) {
+		return errorAt(stmt.Items[1].Token, "procedure or function %s is not on the allowlist", name)
+	}
+
+	args := stmt.Items[next:]
+	for _, item := range args {
+		// SQL Server runs statements that follow without a semicolon as well
+		if keyword, ok := keywordAt([]Item{item}, 0); ok && (writeKinds[keyword] || readOnlyKinds[keyword]) {
+			return errorAt(item.Token, "%s is not allowed in the arguments of %s", keyword, kind)
+		}
+	}
+	return validateItems(args, opts)
+}
+
+// qualifiedName reads a possibly schema-qualified name starting at index i,
+// joining its unquoted parts with dots. It returns "" if there is no name
+// there, and otherwise the name and the index just past it.
+func qualifiedName(items []Item, i int) (string, int) {
+	var parts []string
+	for {
+		if i >= len(items) || items[i].Group != nil {
+			return "", i
+		}
+		tok := items[i].Token
+		if tok.Type != TokenIdent && tok.Type != TokenQuotedIdent {
+			return "", i
+		}
+		parts = append(parts, tok.Value())
+		i++
+
+		if i < len(items) && items[i].Group == nil && isPunct(items[i].Token, ".") {
+			i++
+			continue
+		}
+		return strings.Join(parts, "."), i
+	}
+}
+
+// qualifiedNameEndingAt returns the possibly schema-qualified name whose
+// last part is at index i
+func qualifiedNameEndingAt(items []Item, i int) string {
+	parts := []string{items[i].Token.Value()}
+	for i >= 2 && items[i-1].Group == nil && isPunct(items[i-1].Token, ".") &&
+		items[i-2].Group == nil && (items[i-2].Token.Type == TokenIdent || items[i-2].Token.Type == TokenQuotedIdent) {
+		parts = append([]string{items[i-2].Token.Value()}, parts...)
+		i -= 2
+	}
+	return strings.Join(parts, ".")
+}
+
+// validateQuery checks a read-only query node and everything nested in it
+func validateQuery(n *Node, opts ReadOnlyOptions) error {
+	items := n.Items
+	if n.Kind() == "WITH" {
+		rest, err := validateCTEs(items, opts)
+		if err != nil {
+			return err
+		}
+		items = rest
+		if len(items) == 0 {
+			return errorAt(n.firstToken(), "WITH clause must be followed by a query")
+		}
+		main := &Node{Items: items}
+		if !readOnlyKinds[main.Kind()] {
+			return errorAt(main.firstToken(), "only SELECT queries are allowed")
+		}
+	}
+	return validateItems(items, opts)
+}
+
+// validateCTEs checks the common table expressions of a WITH clause and
+// returns the items of the main query that follows them
+func validateCTEs(items []Item, opts ReadOnlyOptions) ([]Item, error) {
+	i := 1 // Skip WITH
+	if i < len(items) && items[i].Token.IsKeyword("RECURSIVE") {
+		i++
+	}
+
+	for i < len(items) {
+		// name [(columns)] AS [NOT] [MATERIALIZED] (query)
+		name := items[i].Token
+		i++
+		if i < len(items) && items[i].Group != nil {
+			i++
+		}
+		if i >= len(items) || !items[i].Token.IsKeyword("AS") {
+			return nil, errorAt(name, "malformed WITH clause")
+		}
+		i++
+		if i < len(items) && items[i].Token.IsKeyword("NOT") {
+			i++
+		}
+		if i < len(items) && items[i].Token.IsKeyword("MATERIALIZED") {
+			i++
+		}
+		if i >= len(items) || items[i].Group == nil {
+			return nil, errorAt(name, "malformed WITH clause")
+		}
+
+		body := items[i].Group
+		if !readOnlyKinds[body.Kind()] {
+			return nil, errorAt(body.firstToken(), "data-modifying statement in WITH clause %q is not allowed", name.Value())
+		}
+		if err := validateQuery(body, opts); err != nil {
+			return nil, err
+		}
+		i++
+
+		if i < len(items) && items[i].Token.Type == TokenPunct && items[i].Token.Text == "," {
+			i++
+			continue
+		}
+		break
+	}
+
+	return items[i:], nil
+}
+
+// validateItems walks the tokens and groups of one nesting level
+func validateItems(items []Item, opts ReadOnlyOptions) error {
+	for i, item := range items {
+		if item.Group != nil {
+			group := item.Group
+			switch {
+			case readOnlyKinds[group.Kind()]:
+				if err := validateQuery(group, opts); err != nil {
+					return err
+				}
+			case writeKinds[group.Kind()]:
+				return errorAt(group.firstToken(), "%s is not allowed in a read-only query", group.Kind())
+			default:
+				if err := validateItems(group.Items, opts); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		tok := item.Token
+		if tok.Type != TokenIdent && tok.Type != TokenQuotedIdent {
+			continue
+		}
+
+		// Function call: name followed by an argument group
+		if i+1 < len(items) && items[i+1].Group != nil && DeniedFunctions[strings.ToLower(tok.Value())] &&
+			!opts.allows(qualifiedNameEndingAt(items, i)) {
+			return errorAt(tok, "function %s is not allowed in a read-only query", tok.Value())
+		}
+		// Package routine: the last part of a qualified name
+		if i+1 >= len(items) || items[i+1].Group != nil || !isPunct(items[i+1].Token, ".") {
+			name := qualifiedNameEndingAt(items, i)
+			if pkg, denied := deniedPackage(name); denied && !opts.allows(name) {
+				return errorAt(tok, "package %s is not allowed in a read-only query", pkg)
+			}
+		}
+		if tok.Type != TokenIdent {
+			continue
+		}
+
+		switch strings.ToUpper(tok.Text) {
+		case "INTO":
+			return errorAt(tok, "SELECT ... INTO is not allowed")
+		case "FOR":
+			if next, ok := keywordAt(items, i+1); ok {
+				switch next {
+				case "UPDATE", "SHARE", "NO", "KEY":
+					return errorAt(tok, "row locking clause FOR %s is not allowed", next)
+				}
+			}
+		case "LOCK":
+			if next, ok := keywordAt(items, i+1); ok && next == "IN" {
+				return errorAt(tok, "LOCK IN SHARE MODE is not allowed")
+			}
+		default:
+			if startsStatement(items, i) {
+				return errorAt(tok, "%s is not allowed in a read-only query", strings.ToUpper(tok.Text))
+			}
+		}
+	}
+	return nil
+}
+
+// statementKeywords start statements that have no place inside a query.
+// SQL Server runs a statement that follows a query even without a
+// semicolon, so these and writeKinds are rejected wherever a statement
+// could start in a query.
+var statementKeywords = map[string]bool{
+	"WAITFOR": true, "SHUTDOWN": true, "DBCC": true, "DECLARE": true, "SET": true,
+	"KILL": true, "BACKUP": true, "RESTORE": true, "RECONFIGURE": true, "CHECKPOINT": true,
+	"PRINT": true, "RAISERROR": true, "THROW": true, "BEGIN": true, "COMMIT": true,
+	"ROLLBACK": true, "DENY": true, "ENABLE": true, "DISABLE": true, "SETUSER": true,
+	"REVERT": true, "WRITETEXT": true, "UPDATETEXT": true,
+}
+
+// operandKeywords are followed by an expression or a name, never by the
+// start of a statement
+var operandKeywords = map[string]bool{
+	"SELECT": true, "DISTINCT": true, "ALL": true, "FROM": true, "JOIN": true, "ON": true,
+	"USING": true, "WHERE": true, "AND": true, "OR": true, "NOT": true, "BY": true,
+	"AS": true, "HAVING": true, "CASE": true, "WHEN": true, "THEN": true, "ELSE": true,
+	"IN": true, "IS": true, "LIKE": true, "ILIKE": true, "BETWEEN": true, "ANY": true,
+	"SOME": true, "EXISTS": true, "RETURNING": true, "PARTITION": true, "OVER": true,
+}
+
+// startsStatement reports whether the keyword at index i starts a statement
+// of its own, rather than being a function name such as REPLACE(...), part
+// of a qualified name, part of a clause such as CHARACTER SET, or a column
+// named like a keyword, such as begin in SELECT id, begin FROM t. Only a
+// keyword that follows a complete clause and is not part of an expression
+// is at the start of a statement.
+func startsStatement(items []Item, i int) bool {
+	keyword, ok := keywordAt(items, i)
+	if !ok || (!writeKinds[keyword] && !statementKeywords[keyword]) {
+		return false
+	}
+	if i+1 < len(items) && (items[i+1].Group != nil || isPunct(items[i+1].Token, ".")) {
+		return false
+	}
+	if i > 0 && items[i-1].Group == nil && isPunct(items[i-1].Token, ".") {
+		return false
+	}
+	// An operator or list separator on either side makes it an operand
+	if i > 0 && items[i-1].Group == nil && bindsOperand(items[i-1].Token) {
+		return false
+	}
+	if i+1 < len(items) && items[i+1].Group == nil && bindsOperand(items[i+1].Token) {
+		return false
+	}
+	if prev, ok := keywordAt(items, i-1); ok && operandKeywords[prev] {
+		return false
+	}
+
+	prev, _ := keywordAt(items, i-1)
+	next, _ := keywordAt(items, i+1)
+	switch {
+	case keyword == "SET" && prev == "CHARACTER":
+		return false
+	case keyword == "MERGE" && next == "JOIN": // SQL Server join hint
+		return false
+	}
+	return true
+}
+
+// bindsOperand reports whether a neighbouring token makes a keyword an operand:
+// any punctuation but *, which ends a select list
+func bindsOperand(tok Token) bool {
+	return (tok.Type == TokenPunct && tok.Text != "*") || tok.Type == TokenCast
+}
+
+// writeKinds are statement kinds that modify data or schema
+var writeKinds = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true, "UPSERT": true,
+	"REPLACE": true, "DROP": true, "TRUNCATE": true, "ALTER": true, "CREATE": true,
+	"GRANT": true, "REVOKE": true, "EXEC": true, "EXECUTE": true, "CALL": true,
+}
+
+// keywordAt returns the upper-cased keyword at index i, if there is one
+func keywordAt(items []Item, i int) (string, bool) {
+	if i < 0 || i >= len(items) || items[i].Group != nil || items[i].Token.Type != TokenIdent {
+		return "", false
+	}
+	return strings.ToUpper(items[i].Token.Text), true
+}
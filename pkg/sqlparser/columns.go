@@ -0,0 +1,227 @@
+package sqlparser
+
+import "strings"
+
+// TableRef is a table named in a FROM or JOIN clause
+type TableRef struct {
+	Schema string `json:"schema,omitempty"`
+	Name   string `json:"name"`
+	Alias  string `json:"alias,omitempty"`
+}
+
+// ColumnRef is a column a parameter is compared against
+type ColumnRef struct {
+	Qualifier string `json:"qualifier,omitempty"` // Table name or alias written before the column, if any
+	Column    string `json:"column"`
+}
+
+// clauseKeywords end a table reference; they can never be a table alias
+var clauseKeywords = map[string]bool{
+	"WHERE": true, "JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "FULL": true,
+	"CROSS": true, "OUTER": true, "NATURAL": true, "ON": true, "USING": true, "GROUP": true,
+	"ORDER": true, "HAVING": true, "LIMIT": true, "OFFSET": true, "FETCH": true, "UNION": true,
+	"INTERSECT": true, "EXCEPT": true, "WINDOW": true, "FOR": true, "LATERAL": true, "AS": true,
+	"SELECT": true, "FROM": true, "WITH": true, "INTO": true, "TABLESAMPLE": true,
+}
+
+// significantTokens drops whitespace and comments
+func significantTokens(sql string, dialect Dialect) []Token {
+	var tokens []Token
+	for _, tok := range Tokenize(sql, dialect) {
+		if tok.Type != TokenWhitespace && tok.Type != TokenComment {
+			tokens = append(tokens, tok)
+		}
+	}
+	return tokens
+}
+
+func isName(tok Token) bool {
+	return tok.Type == TokenIdent || tok.Type == TokenQuotedIdent
+}
+
+func isPunct(tok Token, text string) bool {
+	return tok.Type == TokenPunct && tok.Text == text
+}
+
+// ReferencedTables returns the tables named in FROM and JOIN clauses, in
+// order of appearance. Subqueries in FROM are skipped; their own FROM
+// clauses are found on their own.
+func ReferencedTables(sql string, dialect Dialect) []TableRef {
+	tokens := significantTokens(revealOptionalBlocks(sql, dialect), dialect)
+
+	var tables []TableRef
+	for i := 0; i < len(tokens); i++ {
+		if !tokens[i].IsKeyword("FROM") && !tokens[i].IsKeyword("JOIN") {
+			continue
+		}
+		// FROM a, b JOIN c: read a comma-separated list of references
+		j := i + 1
+		for {
+			ref, next, ok := readTableRef(tokens, j)
+			if !ok {
+				break
+			}
+			tables = append(tables, ref)
+			if next < len(tokens) && isPunct(tokens[next], ",") {
+				j = next + 1
+				continue
+			}
+			break
+		}
+	}
+	return tables
+}
+
+// readTableRef reads "[schema.]name [[AS] alias]" starting at i
+func readTableRef(tokens []Token, i int) (TableRef, int, bool) {
+	if i >= len(tokens) || !isName(tokens[i]) || clauseKeywords[strings.ToUpper(tokens[i].Text)] {
+		return TableRef{}, i, false
+	}
+
+	parts := []string{tokens[i].Value()}
+	i++
+	for i+1 < len(tokens) && isPunct(tokens[i], ".") && isName(tokens[i+1]) {
+		parts = append(parts, tokens[i+1].Value())
+		i += 2
+	}
+	// A name followed by ( is a table function, not a table
+	if i < len(tokens) && isPunct(tokens[i], "(") {
+		return TableRef{}, i, false
+	}
+
+	ref := TableRef{Name: parts[len(parts)-1]}
+	if len(parts) > 1 {
+		ref.Schema = parts[len(parts)-2]
+	}
+
+	if i < len(tokens) && tokens[i].IsKeyword("AS") {
+		i++
+	}
+	if i < len(tokens) && isName(tokens[i]) && !clauseKeywords[strings.ToUpper(tokens[i].Text)] {
+		ref.Alias = tokens[i].Value()
+		i++
+	}
+	return ref, i, true
+}
+
+// ParameterColumns maps each parameter that is compared directly against a
+// column to that column. Recognized forms are "col <op> :p", ":p <op> col",
+// "col [NOT] IN (:p)", "col [NOT] LIKE :p" and "col BETWEEN :a AND :b".
+// The first comparison found for a parameter wins.
+func ParameterColumns(sql string, dialect Dialect) map[string]ColumnRef {
+	tokens := significantTokens(revealOptionalBlocks(sql, dialect), dialect)
+
+	result := make(map[string]ColumnRef)
+	for i, tok := range tokens {
+		if tok.Type != TokenParam {
+			continue
+		}
+		name := tok.Value()
+		if _, ok := result[name]; ok {
+			continue
+		}
+		if ref, ok := columnBefore(tokens, i); ok {
+			result[name] = ref
+		} else if ref, ok := columnAfter(tokens, i); ok {
+			result[name] = ref
+		}
+	}
+	return result
+}
+
+// columnBefore finds the column in "col <op> :p" and its IN, LIKE and BETWEEN forms
+func columnBefore(tokens []Token, i int) (ColumnRef, bool) {
+	j := i - 1
+
+	switch {
+	case j >= 0 && isPunct(tokens[j], "(") && j-1 >= 0 && tokens[j-1].IsKeyword("IN"):
+		j -= 2
+	case j >= 0 && (tokens[j].IsKeyword("LIKE") || tokens[j].IsKeyword("ILIKE") || tokens[j].IsKeyword("BETWEEN")):
+		j--
+	case j >= 2 && tokens[j].IsKeyword("AND") && tokens[j-1].Type == TokenParam && tokens[j-2].IsKeyword("BETWEEN"):
+		// Upper bound of BETWEEN
+		j -= 3
+	default:
+		// Comparison operator made of one or more of = < > !
+		start := j
+		for j >= 0 && tokens[j].Type == TokenPunct && strings.Contains("=<>!", tokens[j].Text) {
+			j--
+		}
+		if j == start {
+			return ColumnRef{}, false
+		}
+		return columnEndingAt(tokens, j)
+	}
+
+	if j >= 0 && tokens[j].IsKeyword("NOT") {
+		j--
+	}
+	return columnEndingAt(tokens, j)
+}
+
+// columnAfter finds the column in ":p <op> col"
+func columnAfter(tokens []Token, i int) (ColumnRef, bool) {
+	j := i + 1
+	start := j
+	for j < len(tokens) && tokens[j].Type == TokenPunct && strings.Contains("=<>!", tokens[j].Text) {
+		j++
+	}
+	if j == start || j >= len(tokens) || !isName(tokens[j]) {
+		return ColumnRef{}, false
+	}
+
+	ref := ColumnRef{Column: tokens[j].Value()}
+	if j+2 < len(tokens) && isPunct(tokens[j+1], ".") && isName(tokens[j+2]) {
+		ref = ColumnRef{Qualifier: ref.Column, Column: tokens[j+2].Value()}
+	}
+	if clauseKeywords[strings.ToUpper(ref.Column)] {
+		return ColumnRef{}, false
+	}
+	return ref, true
+}
+
+// columnEndingAt reads "[qualifier.]column" whose last token is at j
+func columnEndingAt(tokens []Token, j int) (ColumnRef, bool) {
+	if j < 0 || !isName(tokens[j]) || clauseKeywords[strings.ToUpper(tokens[j].Text)] {
+		return ColumnRef{}, false
+	}
+	ref := ColumnRef{Column: tokens[j].Value()}
+	if j-2 >= 0 && isPunct(tokens[j-1], ".") && isName(tokens[j-2]) {
+		ref.Qualifier = tokens[j-2].Value()
+	}
+	return ref, true
+}
+
+// ParameterTypeForColumn maps a database column type, as reported by schema
+// introspection, to a parameter type and JSON Schema format
+func ParameterTypeForColumn(columnType string) (string, string) {
+	t := strings.ToLower(strings.TrimSpace(columnType))
+	if idx := strings.Index(t, "("); idx >= 0 {
+		t = strings.TrimSpace(t[:idx])
+	}
+	t = strings.TrimSuffix(t, " unsigned")
+
+	switch t {
+	case "int", "int2", "int4", "int8", "integer", "bigint", "smallint", "tinyint", "mediumint",
+		"serial", "bigserial", "smallserial", "year":
+		return "integer", ""
+	case "numeric", "decimal", "real", "float", "float4", "float8", "double", "double precision",
+		"money", "smallmoney", "number", "binary_float", "binary_double":
+		return "number", ""
+	case "boolean", "bool", "bit":
+		return "boolean", ""
+	case "date":
+		return "date", "date"
+	case "uuid", "uniqueidentifier":
+		return "string", "uuid"
+	}
+
+	switch {
+	case strings.HasPrefix(t, "timestamp"), strings.HasPrefix(t, "datetime"), t == "smalldatetime":
+		return "datetime", "date-time"
+	case strings.HasPrefix(t, "time"):
+		return "string", "time"
+	default:
+		return "string", ""
+	}
+}
@@ -0,0 +1,149 @@
+package sqlparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReferencedTables(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		dialect  Dialect
+		expected []TableRef
+	}{
+		{
+			name:     "Single table",
+			sql:      "SELECT * FROM users WHERE id = :id",
+			expected: []TableRef{{Name: "users"}},
+		},
+		{
+			name: "Schema, aliases and joins",
+			sql:  "SELECT * FROM sales.orders AS o JOIN customers c ON c.id = o.customer_id LEFT JOIN regions ON true",
+			expected: []TableRef{
+				{Schema: "sales", Name: "orders", Alias: "o"},
+				{Name: "customers", Alias: "c"},
+				{Name: "regions"},
+			},
+		},
+		{
+			name:     "Comma list",
+			sql:      "SELECT * FROM a x, b WHERE x.id = b.id",
+			expected: []TableRef{{Name: "a", Alias: "x"}, {Name: "b"}},
+		},
+		{
+			name:     "Subquery and table function are skipped",
+			sql:      "SELECT * FROM (SELECT id FROM items) i, generate_series(1, 3)",
+			expected: []TableRef{{Name: "items"}},
+		},
+		{
+			name:     "Quoted identifiers",
+			sql:      "SELECT * FROM [dbo].[Order Items] oi",
+			dialect:  DialectSQLServer,
+			expected: []TableRef{{Schema: "dbo", Name: "Order Items", Alias: "oi"}},
+		},
+		{
+			name:     "Optional block",
+			sql:      "SELECT * FROM t /*[ JOIN u ON u.id = t.uid ]*/",
+			expected: []TableRef{{Name: "t"}, {Name: "u"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ReferencedTables(tt.sql, tt.dialect)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestParameterColumns(t *testing.T) {
+	sql := `SELECT * FROM orders o
+		WHERE o.customer_id = :customer
+		  AND :min_total <= total
+		  AND status NOT IN (:statuses)
+		  AND o.note LIKE :pattern
+		  AND created_at BETWEEN :from AND :to
+		  AND amount >= :amount
+		  AND lower(name) = :name
+		  /*[ AND region <> :region ]*/`
+
+	expected := map[string]ColumnRef{
+		"customer":  {Qualifier: "o", Column: "customer_id"},
+		"min_total": {Column: "total"},
+		"statuses":  {Column: "status"},
+		"pattern":   {Qualifier: "o", Column: "note"},
+		"from":      {Column: "created_at"},
+		"to":        {Column: "created_at"},
+		"amount":    {Column: "amount"},
+		"region":    {Column: "region"},
+	}
+
+	result := ParameterColumns(sql, DialectGeneric)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, result)
+	}
+}
+
+func TestParameterTypeForColumn(t *testing.T) {
+	tests := []struct {
+		columnType     string
+		expectedType   string
+		expectedFormat string
+	}{
+		{"integer", "integer", ""},
+		{"int(11) unsigned", "integer", ""},
+		{"BIGINT", "integer", ""},
+		{"numeric(10,2)", "number", ""},
+		{"double precision", "number", ""},
+		{"money", "number", ""},
+		{"boolean", "boolean", ""},
+		{"bit", "boolean", ""},
+		{"date", "date", "date"},
+		{"timestamp with time zone", "datetime", "date-time"},
+		{"datetime2", "datetime", "date-time"},
+		{"datetimeoffset", "datetime", "date-time"},
+		{"time without time zone", "string", "time"},
+		{"uuid", "string", "uuid"},
+		{"uniqueidentifier", "string", "uuid"},
+		{"character varying(255)", "string", ""},
+		{"interval", "string", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.columnType, func(t *testing.T) {
+			typ, format := ParameterTypeForColumn(tt.columnType)
+			if typ != tt.expectedType || format != tt.expectedFormat {
+				t.Errorf("Expected %s/%s, got %s/%s", tt.expectedType, tt.expectedFormat, typ, format)
+			}
+		})
+	}
+}
+
+func TestInferParameterType(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"user_id", "number"},
+		{"total_amount", "number"},
+		{"orderTotal", "number"},
+		{"start_date", "date"},
+		{"createdAt", "date"},
+		{"is_active", "boolean"},
+		{"is_deleted", "boolean"},
+		{"status", "string"},
+		{"customer_name", "string"},
+		{"token", "string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := inferParameterType(tt.name); result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
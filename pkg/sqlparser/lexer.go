@@ -0,0 +1,398 @@
+package sqlparser
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Dialect selects the lexical rules of a database engine
+type Dialect string
+
+const (
+	DialectGeneric    Dialect = "generic"
+	DialectPostgreSQL Dialect = "postgresql"
+	DialectMySQL      Dialect = "mysql"
+	DialectSQLServer  Dialect = "sqlserver"
+	DialectOracle     Dialect = "oracle"
+)
+
+// DialectFor maps a datasource or connector type name to a Dialect
+func DialectFor(dbType string) Dialect {
+	switch strings.ToLower(dbType) {
+	case "postgresql", "postgres":
+		return DialectPostgreSQL
+	case "mysql":
+		return DialectMySQL
+	case "sqlserver", "mssql":
+		return DialectSQLServer
+	case "oracle":
+		return DialectOracle
+	default:
+		return DialectGeneric
+	}
+}
+
+// TokenType classifies a lexical token
+type TokenType int
+
+const (
+	TokenWhitespace  TokenType = iota
+	TokenComment               // -- line, /* block */ and MySQL # comments
+	TokenString                // '...', E'...', N'...', $tag$...$tag$ and MySQL "..."
+	TokenQuotedIdent           // "...", `...` and SQL Server [...]
+	TokenIdent                 // Keywords and bare identifiers
+	TokenNumber
+	TokenParam       // Named parameter, :name
+	TokenPlaceholder // Positional placeholder, ? or $1
+	TokenVariable    // @name and @@name
+	TokenCast        // Postgres ::
+	TokenPunct       // Any other operator or punctuation character
+)
+
+// Token is a lexical unit of a SQL statement
+type Token struct {
+	Type   TokenType
+	Text   string // Source text, including quotes and prefixes
+	Pos    int    // Byte offset in the source
+	Line   int    // 1-based line
+	Column int    // 1-based column, in characters
+}
+
+// Value returns the meaningful part of a token: the name of a parameter or
+// variable, or the unquoted text of a quoted identifier
+func (t Token) Value() string {
+	switch t.Type {
+	case TokenParam:
+		return t.Text[1:]
+	case TokenVariable:
+		return strings.TrimLeft(t.Text, "@")
+	case TokenQuotedIdent:
+		if len(t.Text) < 2 {
+			return t.Text
+		}
+		inner := t.Text[1 : len(t.Text)-1]
+		switch t.Text[0] {
+		case '"':
+			return strings.ReplaceAll(inner, `""`, `"`)
+		case '`':
+			return strings.ReplaceAll(inner, "``", "`")
+		case '[':
+			return strings.ReplaceAll(inner, "]]", "]")
+		}
+		return inner
+	default:
+		return t.Text
+	}
+}
+
+// IsKeyword reports whether the token is the given keyword, case-insensitively
+func (t Token) IsKeyword(keyword string) bool {
+	return t.Type == TokenIdent && strings.EqualFold(t.Text, keyword)
+}
+
+// Tokenize splits SQL into tokens following the rules of the dialect.
+// Unterminated strings and comments run to the end of the input.
+func Tokenize(sql string, dialect Dialect) []Token {
+	tokens, _ := tokenize(sql, dialect)
+	return tokens
+}
+
+// tokenize is Tokenize that also returns the string, quoted identifier or
+// block comment left open at the end of the input, if any
+func tokenize(sql string, dialect Dialect) ([]Token, *Token) {
+	l := &lexer{src: sql, dialect: dialect, line: 1, col: 1}
+	var tokens []Token
+	var unclosed *Token
+	for l.pos < len(l.src) {
+		tok := l.next()
+		if l.unclosed {
+			unclosed = &tok
+		}
+		tokens = append(tokens, tok)
+	}
+	return tokens, unclosed
+}
+
+type lexer struct {
+	src      string
+	dialect  Dialect
+	pos      int
+	line     int
+	col      int
+	unclosed bool // The last token ran to the end of the input unclosed
+}
+
+func (l *lexer) peek(offset int) byte {
+	if l.pos+offset < len(l.src) {
+		return l.src[l.pos+offset]
+	}
+	return 0
+}
+
+func (l *lexer) next() Token {
+	start, line, col := l.pos, l.line, l.col
+	typ := l.scan()
+	// Track line and column over the consumed text
+	for _, r := range l.src[start:l.pos] {
+		if r == '\n' {
+			l.line++
+			l.col = 1
+		} else {
+			l.col++
+		}
+	}
+	return Token{Type: typ, Text: l.src[start:l.pos], Pos: start, Line: line, Column: col}
+}
+
+// scan consumes one token and returns its type
+func (l *lexer) scan() TokenType {
+	c := l.peek(0)
+
+	switch {
+	case isSpace(c):
+		for l.pos < len(l.src) && isSpace(l.src[l.pos]) {
+			l.pos++
+		}
+		return TokenWhitespace
+
+	case c == '-' && l.peek(1) == '-', c == '#' && l.dialect == DialectMySQL:
+		l.skipLine()
+		return TokenComment
+
+	case c == '/' && l.peek(1) == '*':
+		l.skipBlockComment()
+		return TokenComment
+
+	case c == '\'':
+		l.skipQuoted('\'', l.dialect == DialectMySQL)
+		return TokenString
+
+	case c == '"':
+		// MySQL treats double quotes as strings unless ANSI_QUOTES is set
+		if l.dialect == DialectMySQL {
+			l.skipQuoted('"', true)
+			return TokenString
+		}
+		l.skipQuoted('"', false)
+		return TokenQuotedIdent
+
+	case c == '`' && l.dialect != DialectSQLServer:
+		l.skipQuoted('`', false)
+		return TokenQuotedIdent
+
+	case c == '[' && l.dialect == DialectSQLServer:
+		l.skipQuoted(']', false)
+		return TokenQuotedIdent
+
+	case isStringPrefix(c) && l.peek(1) == '\'':
+		// E'...' (backslash escapes), N'...', X'...', B'...'
+		backslash := l.dialect == DialectMySQL || c == 'E' || c == 'e'
+		l.pos++
+		l.skipQuoted('\'', backslash)
+		return TokenString
+
+	case c == '$':
+		return l.scanDollar()
+
+	case c == ':':
+		if l.peek(1) == ':' {
+			l.pos += 2
+			return TokenCast
+		}
+		if isWordChar(l.peek(1)) {
+			l.pos++
+			l.skipWord()
+			return TokenParam
+		}
+		l.pos++
+		return TokenPunct
+
+	case c == '?':
+		l.pos++
+		return TokenPlaceholder
+
+	case c == '@':
+		l.pos++
+		if l.peek(0) == '@' {
+			l.pos++
+		}
+		l.skipWord()
+		return TokenVariable
+
+	case isDigit(c), c == '.' && isDigit(l.peek(1)):
+		l.skipNumber()
+		return TokenNumber
+
+	case isIdentStart(c), c == '#' && l.dialect == DialectSQLServer:
+		l.pos++
+		l.skipWord()
+		return TokenIdent
+
+	case c >= utf8.RuneSelf:
+		r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		if unicode.IsLetter(r) {
+			l.pos += size
+			l.skipWord()
+			return TokenIdent
+		}
+		l.pos += size
+		return TokenPunct
+
+	default:
+		l.pos++
+		return TokenPunct
+	}
+}
+
+func (l *lexer) skipLine() {
+	for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+		l.pos++
+	}
+}
+
+// isExecutableComment reports whether the token is a MySQL /*! ... */ or
+// MariaDB /*M! ... */ comment, whose body the server runs as SQL
+func isExecutableComment(tok Token, dialect Dialect) bool {
+	return dialect == DialectMySQL && tok.Type == TokenComment &&
+		(strings.HasPrefix(tok.Text, "/*!") || strings.HasPrefix(tok.Text, "/*M!"))
+}
+
+// skipBlockComment consumes a /* */ comment. Postgres comments nest.
+func (l *lexer) skipBlockComment() {
+	depth := 0
+	for l.pos < len(l.src) {
+		if l.peek(0) == '/' && l.peek(1) == '*' {
+			depth++
+			l.pos += 2
+			if l.dialect != DialectPostgreSQL && depth > 1 {
+				depth = 1
+			}
+			continue
+		}
+		if l.peek(0) == '*' && l.peek(1) == '/' {
+			depth--
+			l.pos += 2
+			if depth == 0 {
+				return
+			}
+			continue
+		}
+		l.pos++
+	}
+	l.unclosed = true
+}
+
+// skipQuoted consumes a quoted run starting at the opening quote. A doubled
+// closing quote is an escaped quote; backslash escapes are honoured if asked.
+func (l *lexer) skipQuoted(closing byte, backslash bool) {
+	l.pos++ // opening quote
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if backslash && c == '\\' {
+			l.pos += 2
+			continue
+		}
+		l.pos++
+		if c == closing {
+			if l.peek(0) == closing {
+				l.pos++
+				continue
+			}
+			return
+		}
+	}
+	if l.pos > len(l.src) {
+		l.pos = len(l.src)
+	}
+	l.unclosed = true
+}
+
+// scanDollar handles $1 placeholders and Postgres $tag$ ... $tag$ strings
+func (l *lexer) scanDollar() TokenType {
+	if isDigit(l.peek(1)) {
+		l.pos++
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.pos++
+		}
+		return TokenPlaceholder
+	}
+
+	if l.dialect == DialectPostgreSQL || l.dialect == DialectGeneric {
+		// Opening tag: $ [identifier] $
+		end := l.pos + 1
+		for end < len(l.src) && isWordChar(l.src[end]) && !(end == l.pos+1 && isDigit(l.src[end])) {
+			end++
+		}
+		if end < len(l.src) && l.src[end] == '$' {
+			tag := l.src[l.pos : end+1]
+			l.pos = end + 1
+			if idx := strings.Index(l.src[l.pos:], tag); idx >= 0 {
+				l.pos += idx + len(tag)
+			} else {
+				l.pos = len(l.src)
+				l.unclosed = true
+			}
+			return TokenString
+		}
+	}
+
+	l.pos++
+	return TokenPunct
+}
+
+func (l *lexer) skipWord() {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if isWordChar(c) || c == '$' || c == '#' {
+			l.pos++
+			continue
+		}
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				l.pos += size
+				continue
+			}
+		}
+		return
+	}
+}
+
+func (l *lexer) skipNumber() {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case isDigit(c), c == '.':
+			l.pos++
+		case (c == 'e' || c == 'E') && (isDigit(l.peek(1)) || ((l.peek(1) == '+' || l.peek(1) == '-') && isDigit(l.peek(2)))):
+			l.pos += 2
+		default:
+			return
+		}
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isWordChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+func isStringPrefix(c byte) bool {
+	switch c {
+	case 'E', 'e', 'N', 'n', 'X', 'x', 'B', 'b':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,217 @@
+package sqlparser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	sql := "SELECT a::int, 'x''y' -- note\nFROM \"t\" WHERE id = :id"
+	tokens := Tokenize(sql, DialectPostgreSQL)
+
+	var got []TokenType
+	for _, tok := range tokens {
+		if tok.Type != TokenWhitespace {
+			got = append(got, tok.Type)
+		}
+	}
+	expected := []TokenType{
+		TokenIdent, TokenIdent, TokenCast, TokenIdent, TokenPunct, TokenString, TokenComment,
+		TokenIdent, TokenQuotedIdent, TokenIdent, TokenIdent, TokenPunct, TokenParam,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Tokenize() types = %v, want %v", got, expected)
+	}
+
+	last := tokens[len(tokens)-1]
+	if last.Line != 2 || last.Column != 21 || last.Value() != "id" {
+		t.Errorf("Expected id at 2:21, got %q at %d:%d", last.Value(), last.Line, last.Column)
+	}
+}
+
+func TestParameterTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		dialect  Dialect
+		expected []string
+	}{
+		{
+			name:     "Colon inside string literal",
+			sql:      "SELECT * FROM shifts WHERE start_time = '10:30' AND id = :id",
+			dialect:  DialectGeneric,
+			expected: []string{"id"},
+		},
+		{
+			name:     "Line comment",
+			sql:      "SELECT * FROM users -- filter by :ignored\nWHERE id = :id",
+			dialect:  DialectGeneric,
+			expected: []string{"id"},
+		},
+		{
+			name:     "Block comment",
+			sql:      "SELECT /* :ignored */ * FROM users WHERE id = :id",
+			dialect:  DialectGeneric,
+			expected: []string{"id"},
+		},
+		{
+			name:     "Nested Postgres block comment",
+			sql:      "SELECT /* outer /* :inner */ :still_comment */ :id",
+			dialect:  DialectPostgreSQL,
+			expected: []string{"id"},
+		},
+		{
+			name:     "Postgres cast",
+			sql:      "SELECT * FROM orders WHERE created_at::date = :day AND total > :min::numeric",
+			dialect:  DialectPostgreSQL,
+			expected: []string{"day", "min"},
+		},
+		{
+			name:     "Dollar-quoted string",
+			sql:      "SELECT $body$ it's :not_a_param $body$, $$ :nor_this $$ WHERE id = :id",
+			dialect:  DialectPostgreSQL,
+			expected: []string{"id"},
+		},
+		{
+			name:     "Quoted identifier",
+			sql:      `SELECT "weird:name" FROM t WHERE id = :id`,
+			dialect:  DialectPostgreSQL,
+			expected: []string{"id"},
+		},
+		{
+			name:     "MySQL hash comment and backslash escape",
+			sql:      "SELECT 'it\\'s :x' FROM t # :y\nWHERE id = :id",
+			dialect:  DialectMySQL,
+			expected: []string{"id"},
+		},
+		{
+			name:     "MySQL assignment",
+			sql:      "SELECT @n := :start",
+			dialect:  DialectMySQL,
+			expected: []string{"start"},
+		},
+		{
+			name:     "SQL Server bracket identifier",
+			sql:      "SELECT [col:1] FROM t WHERE id = :id",
+			dialect:  DialectSQLServer,
+			expected: []string{"id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, tok := range ParameterTokens(tt.sql, tt.dialect) {
+				names = append(names, tok.Value())
+			}
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("ParameterTokens() = %v, want %v", names, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindValues(t *testing.T) {
+	tests := []struct {
+		name         string
+		sql          string
+		dialect      Dialect
+		params       map[string]interface{}
+		expectedSQL  string
+		expectedArgs []interface{}
+		expectedErr  error
+	}{
+		{
+			name:         "Prefix names are not clobbered",
+			sql:          "SELECT * FROM t WHERE id = :id OR parent_id = :id_parent",
+			dialect:      DialectPostgreSQL,
+			params:       map[string]interface{}{"id": 1, "id_parent": 2},
+			expectedSQL:  "SELECT * FROM t WHERE id = $1 OR parent_id = $2",
+			expectedArgs: []interface{}{1, 2},
+		},
+		{
+			name:         "Repeated names reuse numbered placeholders",
+			sql:          "SELECT * FROM t WHERE a >= :d AND b >= :d AND c = :e",
+			dialect:      DialectSQLServer,
+			params:       map[string]interface{}{"d": "x", "e": "y"},
+			expectedSQL:  "SELECT * FROM t WHERE a >= @p1 AND b >= @p1 AND c = @p2",
+			expectedArgs: []interface{}{"x", "y"},
+		},
+		{
+			name:         "Repeated names need one argument per question mark",
+			sql:          "SELECT * FROM t WHERE a >= :d AND b >= :d",
+			dialect:      DialectMySQL,
+			params:       map[string]interface{}{"d": "x"},
+			expectedSQL:  "SELECT * FROM t WHERE a >= ? AND b >= ?",
+			expectedArgs: []interface{}{"x", "x"},
+		},
+		{
+			name:         "Casts and literals untouched",
+			sql:          "SELECT '10:30'::time, :t::date",
+			dialect:      DialectPostgreSQL,
+			params:       map[string]interface{}{"t": "2024-01-01"},
+			expectedSQL:  "SELECT '10:30'::time, $1::date",
+			expectedArgs: []interface{}{"2024-01-01"},
+		},
+		{
+			name:         "Array expands per element",
+			sql:          "SELECT * FROM t WHERE status IN (:statuses) AND id = :id OR status IN (:statuses)",
+			dialect:      DialectPostgreSQL,
+			params:       map[string]interface{}{"statuses": []interface{}{"a", "b"}, "id": 7},
+			expectedSQL:  "SELECT * FROM t WHERE status IN ($1, $2) AND id = $3 OR status IN ($1, $2)",
+			expectedArgs: []interface{}{"a", "b", 7},
+		},
+		{
+			name:         "Typed slice expands with question marks",
+			sql:          "SELECT * FROM t WHERE id IN (:ids)",
+			dialect:      DialectMySQL,
+			params:       map[string]interface{}{"ids": []int{1, 2, 3}},
+			expectedSQL:  "SELECT * FROM t WHERE id IN (?, ?, ?)",
+			expectedArgs: []interface{}{1, 2, 3},
+		},
+		{
+			name:         "Byte slices are scalars",
+			sql:          "SELECT * FROM t WHERE hash = :hash",
+			dialect:      DialectMySQL,
+			params:       map[string]interface{}{"hash": []byte{1, 2}},
+			expectedSQL:  "SELECT * FROM t WHERE hash = ?",
+			expectedArgs: []interface{}{[]byte{1, 2}},
+		},
+		{
+			name:        "Empty array",
+			sql:         "SELECT * FROM t WHERE id IN (:ids)",
+			dialect:     DialectPostgreSQL,
+			params:      map[string]interface{}{"ids": []interface{}{}},
+			expectedErr: ErrEmptyArrayParameter,
+		},
+		{
+			name:        "Array too long",
+			sql:         "SELECT * FROM t WHERE id IN (:ids)",
+			dialect:     DialectPostgreSQL,
+			params:      map[string]interface{}{"ids": make([]interface{}, MaxArrayParameterLength+1)},
+			expectedErr: ErrArrayParameterTooLong,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := BindValues(tt.sql, tt.dialect, tt.params)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("BindValues() error = %v, want %v", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindValues() unexpected error: %v", err)
+			}
+			if sql != tt.expectedSQL {
+				t.Errorf("BindValues() sql = %v, want %v", sql, tt.expectedSQL)
+			}
+			if !reflect.DeepEqual(args, tt.expectedArgs) {
+				t.Errorf("BindValues() args = %v, want %v", args, tt.expectedArgs)
+			}
+		})
+	}
+}
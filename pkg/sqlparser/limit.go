@@ -0,0 +1,404 @@
+package sqlparser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// limitedResultAlias names the derived table when a query has to be wrapped
+const limitedResultAlias = "limited_result"
+
+// InjectRowLimit caps the number of rows a read-only query returns, using the
+// limit syntax of the dialect: LIMIT for PostgreSQL, MySQL and generic SQL,
+// TOP or OFFSET ... FETCH for SQL Server and FETCH FIRST for Oracle.
+//
+// An existing limit that is already at most limit is kept; a larger one is
+// lowered. Limits the rewriter cannot read, such as a parameter, are kept and
+// the query is wrapped in an outer query that applies limit instead, provided
+// its columns are named and unique; otherwise the query is returned unchanged
+// and left to the caller's in-memory row limit. SQL that is not a single
+// read-only query is returned unchanged.
+func InjectRowLimit(sql string, dialect Dialect, limit int) string {
+	if limit <= 0 {
+		return sql
+	}
+
+	q, ok := scanLimitQuery(sql, dialect)
+	if !ok {
+		return sql
+	}
+
+	switch dialect {
+	case DialectSQLServer:
+		return q.injectSQLServer(limit)
+	case DialectOracle:
+		return q.injectFetchFirst(limit)
+	default:
+		return q.injectLimit(limit)
+	}
+}
+
+// limitQuery is a query reduced to what the limit rewriter needs: the
+// significant tokens outside any parentheses and where the query ends
+type limitQuery struct {
+	sql     string
+	dialect Dialect
+	first   Token   // First significant token
+	top     []Token // Significant top-level tokens, without a trailing semicolon
+	end     int     // Byte offset just past the last significant token
+}
+
+func scanLimitQuery(sql string, dialect Dialect) (*limitQuery, bool) {
+	q := &limitQuery{sql: sql, dialect: dialect}
+
+	depth := 0
+	seen := false
+	terminated := false
+	for _, tok := range Tokenize(sql, dialect) {
+		if tok.Type == TokenWhitespace || tok.Type == TokenComment {
+			continue
+		}
+		if terminated {
+			// Anything after the closing semicolon is a second statement
+			return nil, false
+		}
+
+		if !seen {
+			seen = true
+			q.first = tok
+			if !isPunct(tok, "(") && !readOnlyKinds[strings.ToUpper(tok.Text)] {
+				return nil, false
+			}
+		}
+
+		switch {
+		case isPunct(tok, "("):
+			depth++
+		case isPunct(tok, ")"):
+			depth--
+			if depth < 0 {
+				return nil, false
+			}
+		case depth == 0 && isPunct(tok, ";"):
+			terminated = true
+			continue
+		case depth == 0:
+			q.top = append(q.top, tok)
+		}
+		q.end = tok.Pos + len(tok.Text)
+	}
+
+	if !seen || depth != 0 {
+		return nil, false
+	}
+	return q, true
+}
+
+// keyword returns the index of the first top-level keyword, or -1
+func (q *limitQuery) keyword(word string) int {
+	for i, tok := range q.top {
+		if tok.IsKeyword(word) {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasSetOperation reports whether the query combines several queries
+func (q *limitQuery) hasSetOperation() bool {
+	return q.keyword("UNION") >= 0 || q.keyword("INTERSECT") >= 0 || q.keyword("EXCEPT") >= 0 || q.keyword("MINUS") >= 0
+}
+
+// body returns the query without its trailing semicolon and comments
+func (q *limitQuery) body() string {
+	return q.sql[:q.end]
+}
+
+// appendClause adds a clause after the last significant token, ahead of any
+// trailing comment or semicolon
+func (q *limitQuery) appendClause(clause string) string {
+	return q.sql[:q.end] + " " + clause + q.sql[q.end:]
+}
+
+// replaceToken swaps the text of one token for another
+func (q *limitQuery) replaceToken(tok Token, text string) string {
+	return q.sql[:tok.Pos] + text + q.sql[tok.Pos+len(tok.Text):]
+}
+
+// lowerCount keeps a numeric row count that is within limit and lowers one
+// that is not. It reports false when the count is not a plain integer.
+func (q *limitQuery) lowerCount(tok Token, limit int) (string, bool) {
+	if tok.Type != TokenNumber {
+		return "", false
+	}
+	n, err := strconv.Atoi(tok.Text)
+	if err != nil {
+		return "", false
+	}
+	if n <= limit {
+		return q.sql, true
+	}
+	return q.replaceToken(tok, strconv.Itoa(limit)), true
+}
+
+// fetchCount finds a FETCH FIRST|NEXT [n] ROW|ROWS clause and returns the
+// count token, or ok false when there is no such clause. A nil count means
+// the count was omitted, which is one row.
+func (q *limitQuery) fetchCount() (count *Token, ok bool) {
+	i := q.keyword("FETCH")
+	if i < 0 || i+1 >= len(q.top) {
+		return nil, false
+	}
+	if !q.top[i+1].IsKeyword("FIRST") && !q.top[i+1].IsKeyword("NEXT") {
+		return nil, false
+	}
+	if i+2 < len(q.top) && !q.top[i+2].IsKeyword("ROW") && !q.top[i+2].IsKeyword("ROWS") {
+		return &q.top[i+2], true
+	}
+	return nil, true
+}
+
+// injectLimit handles dialects with LIMIT n
+func (q *limitQuery) injectLimit(limit int) string {
+	if i := q.keyword("LIMIT"); i >= 0 {
+		if i+1 >= len(q.top) {
+			return q.sql
+		}
+		count := q.top[i+1]
+		// MySQL: LIMIT offset, count
+		if i+3 < len(q.top) && isPunct(q.top[i+2], ",") {
+			count = q.top[i+3]
+		}
+		if count.IsKeyword("ALL") {
+			return q.replaceToken(count, strconv.Itoa(limit))
+		}
+		if sql, ok := q.lowerCount(count, limit); ok {
+			return sql
+		}
+		return q.wrap(limit)
+	}
+
+	// PostgreSQL also accepts the standard FETCH FIRST clause
+	if count, ok := q.fetchCount(); ok {
+		if count == nil {
+			return q.sql
+		}
+		if sql, ok := q.lowerCount(*count, limit); ok {
+			return sql
+		}
+		return q.wrap(limit)
+	}
+
+	return q.appendClause(fmt.Sprintf("LIMIT %d", limit))
+}
+
+// injectFetchFirst handles Oracle, which has FETCH FIRST n ROWS ONLY
+func (q *limitQuery) injectFetchFirst(limit int) string {
+	if count, ok := q.fetchCount(); ok {
+		if count == nil {
+			return q.sql
+		}
+		if sql, ok := q.lowerCount(*count, limit); ok {
+			return sql
+		}
+		return q.wrap(limit)
+	}
+	return q.appendClause(fmt.Sprintf("FETCH FIRST %d ROWS ONLY", limit))
+}
+
+// injectSQLServer uses SELECT TOP for simple queries and OFFSET ... FETCH
+// where TOP cannot apply to the whole result
+func (q *limitQuery) injectSQLServer(limit int) string {
+	// OFFSET n ROWS [FETCH NEXT m ROWS ONLY]
+	if q.keyword("OFFSET") >= 0 {
+		if count, ok := q.fetchCount(); ok {
+			if count == nil {
+				return q.sql
+			}
+			if sql, ok := q.lowerCount(*count, limit); ok {
+				return sql
+			}
+			return q.wrap(limit)
+		}
+		return q.appendClause(fmt.Sprintf("FETCH NEXT %d ROWS ONLY", limit))
+	}
+
+	// TOP on the first branch would only limit that branch, so combined
+	// queries are limited through ORDER BY ... OFFSET or a wrapping query
+	if q.hasSetOperation() || isPunct(q.first, "(") {
+		if q.keyword("ORDER") >= 0 {
+			return q.appendClause(fmt.Sprintf("OFFSET 0 ROWS FETCH NEXT %d ROWS ONLY", limit))
+		}
+		return q.wrap(limit)
+	}
+
+	// The main SELECT is the first top-level one; CTE bodies are nested
+	sel := q.keyword("SELECT")
+	if sel < 0 {
+		return q.sql
+	}
+	i := sel + 1
+	if i < len(q.top) && (q.top[i].IsKeyword("DISTINCT") || q.top[i].IsKeyword("ALL")) {
+		i++
+	}
+
+	if i < len(q.top) && q.top[i].IsKeyword("TOP") {
+		if count, ok := q.topCount(q.top[i]); ok {
+			if sql, ok := q.lowerCount(count, limit); ok {
+				return sql
+			}
+		}
+		return q.wrap(limit)
+	}
+
+	after := q.top[i-1]
+	pos := after.Pos + len(after.Text)
+	return q.sql[:pos] + fmt.Sprintf(" TOP (%d)", limit) + q.sql[pos:]
+}
+
+// topCount reads the count of TOP n or TOP (n). It reports false for
+// TOP ... PERCENT and for counts that are expressions.
+func (q *limitQuery) topCount(top Token) (Token, bool) {
+	offset := top.Pos + len(top.Text)
+	tokens := significantTokens(q.sql[offset:], q.dialect)
+
+	next := 1
+	if len(tokens) > 0 && isPunct(tokens[0], "(") {
+		if len(tokens) < 3 || !isPunct(tokens[2], ")") {
+			return Token{}, false
+		}
+		tokens = tokens[1:]
+		next = 2
+	}
+	if len(tokens) == 0 || (len(tokens) > next && tokens[next].IsKeyword("PERCENT")) {
+		return Token{}, false
+	}
+
+	count := tokens[0]
+	count.Pos += offset
+	return count, true
+}
+
+// wrap applies the limit in an outer query around the original one. A
+// derived table needs named, unique columns, so a query whose columns cannot
+// be shown to have them is left to the in-memory row limit, as are queries
+// starting with WITH on SQL Server, which does not allow a WITH clause in a
+// derived table.
+func (q *limitQuery) wrap(limit int) string {
+	if !q.hasUniqueColumnNames() {
+		return q.sql
+	}
+
+	body := q.body()
+	switch q.dialect {
+	case DialectSQLServer:
+		if q.first.IsKeyword("WITH") {
+			return q.sql
+		}
+		return fmt.Sprintf("SELECT TOP (%d) * FROM (\n%s\n) AS %s", limit, body, limitedResultAlias)
+	case DialectOracle:
+		return fmt.Sprintf("SELECT * FROM (\n%s\n) %s FETCH FIRST %d ROWS ONLY", body, limitedResultAlias, limit)
+	default:
+		return fmt.Sprintf("SELECT * FROM (\n%s\n) AS %s LIMIT %d", body, limitedResultAlias, limit)
+	}
+}
+
+// unnamedKeywords are values that give a column no name of their own on at
+// least one engine
+var unnamedKeywords = map[string]bool{
+	"NULL": true, "TRUE": true, "FALSE": true, "CURRENT_DATE": true, "CURRENT_TIME": true,
+	"CURRENT_TIMESTAMP": true, "CURRENT_USER": true, "SESSION_USER": true, "SYSTEM_USER": true,
+	"USER": true, "LOCALTIME": true, "LOCALTIMESTAMP": true, "SYSDATE": true, "SYSTIMESTAMP": true,
+}
+
+// hasUniqueColumnNames reports whether every result column of the query, as
+// named by the select list of its first branch, has a name and no two names
+// are the same. A lone * counts as unique when it reads a single base table.
+func (q *limitQuery) hasUniqueColumnNames() bool {
+	statements, err := ParseStatements(q.body(), q.dialect)
+	if err != nil || len(statements) != 1 {
+		return false
+	}
+	items := statements[0].Items
+	withCTEs := len(items) > 0 && items[0].Token.IsKeyword("WITH")
+	if withCTEs {
+		items = (&lineageBuilder{ctes: make(map[string][]LineageColumn)}).ctesOf(items, nil)
+	}
+	items = splitBranches(items)[0]
+
+	selectAt, fromAt, listEnd, fromEnd := -1, -1, len(items), len(items)
+	for i := range items {
+		kw, ok := keywordAt(items, i)
+		if !ok {
+			continue
+		}
+		switch {
+		case kw == "SELECT" && selectAt < 0:
+			selectAt = i
+		case (kw == "FROM" || kw == "INTO") && selectAt >= 0 && listEnd == len(items):
+			listEnd = i
+			if kw == "FROM" {
+				fromAt = i
+			}
+		case kw == "FROM" && fromAt < 0:
+			fromAt = i
+		case fromClauseEnd[kw] && fromAt >= 0 && fromEnd == len(items):
+			fromEnd = i
+		}
+	}
+	if selectAt != 0 {
+		return false
+	}
+
+	list := splitList(items[skipSelectModifiers(items, selectAt+1):listEnd])
+	if len(list) == 1 && len(list[0]) == 1 && list[0][0].Group == nil && isPunct(list[0][0].Token, "*") {
+		// A CTE may itself produce duplicate names
+		return !withCTEs && fromAt >= 0 && isSingleTable(items[fromAt+1:fromEnd])
+	}
+
+	seen := make(map[string]bool, len(list))
+	for _, expr := range list {
+		name, ok := selectItemName(expr, q.dialect)
+		if !ok || seen[strings.ToUpper(name)] {
+			return false
+		}
+		seen[strings.ToUpper(name)] = true
+	}
+	return true
+}
+
+// selectItemName returns the name of the column a select list expression
+// yields: its alias, or the column of a plain column reference. Other
+// expressions without an alias are named differently by each engine, or not
+// at all.
+func selectItemName(expr []Item, dialect Dialect) (string, bool) {
+	// SQL Server: alias = expression
+	if dialect == DialectSQLServer && len(expr) >= 3 && expr[0].Group == nil && isName(expr[0].Token) &&
+		expr[1].Group == nil && isPunct(expr[1].Token, "=") {
+		return expr[0].Token.Value(), true
+	}
+	if alias, _ := splitAlias(expr); alias != "" {
+		return alias, true
+	}
+
+	parts, next := readDottedName(expr, 0)
+	if parts == nil || next != len(expr) {
+		return "", false
+	}
+	if last := expr[len(expr)-1].Token; len(parts) == 1 && last.Type == TokenIdent && unnamedKeywords[strings.ToUpper(last.Text)] {
+		return "", false
+	}
+	return parts[len(parts)-1], true
+}
+
+// isSingleTable reports whether a FROM clause names exactly one table, with
+// an optional alias
+func isSingleTable(items []Item) bool {
+	parts, next := readDottedName(items, 0)
+	if parts == nil {
+		return false
+	}
+	_, renamed, next := readAlias(items, next)
+	return renamed == nil && next == len(items)
+}
@@ -0,0 +1,232 @@
+package sqlparser
+
+import (
+	"testing"
+)
+
+func TestInjectRowLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		dialect  Dialect
+		expected string
+	}{
+		// LIMIT dialects
+		{
+			name:     "Append LIMIT",
+			sql:      "SELECT * FROM users",
+			dialect:  DialectPostgreSQL,
+			expected: "SELECT * FROM users LIMIT 101",
+		},
+		{
+			name:     "Append before semicolon and trailing comment",
+			sql:      "SELECT * FROM users; -- all users",
+			dialect:  DialectPostgreSQL,
+			expected: "SELECT * FROM users LIMIT 101; -- all users",
+		},
+		{
+			name:     "Smaller LIMIT is kept",
+			sql:      "SELECT * FROM users LIMIT 10 OFFSET 5",
+			dialect:  DialectPostgreSQL,
+			expected: "SELECT * FROM users LIMIT 10 OFFSET 5",
+		},
+		{
+			name:     "Larger LIMIT is lowered",
+			sql:      "SELECT * FROM users LIMIT 5000",
+			dialect:  DialectPostgreSQL,
+			expected: "SELECT * FROM users LIMIT 101",
+		},
+		{
+			name:     "LIMIT ALL is replaced",
+			sql:      "SELECT * FROM users LIMIT ALL",
+			dialect:  DialectPostgreSQL,
+			expected: "SELECT * FROM users LIMIT 101",
+		},
+		{
+			name:     "MySQL offset and count",
+			sql:      "SELECT * FROM users LIMIT 20, 500",
+			dialect:  DialectMySQL,
+			expected: "SELECT * FROM users LIMIT 20, 101",
+		},
+		{
+			name:     "Parameter LIMIT is wrapped",
+			sql:      "SELECT * FROM users LIMIT :n",
+			dialect:  DialectPostgreSQL,
+			expected: "SELECT * FROM (\nSELECT * FROM users LIMIT :n\n) AS limited_result LIMIT 101",
+		},
+		{
+			name:     "Nested LIMIT is ignored",
+			sql:      "SELECT * FROM (SELECT * FROM users LIMIT 5000) u",
+			dialect:  DialectMySQL,
+			expected: "SELECT * FROM (SELECT * FROM users LIMIT 5000) u LIMIT 101",
+		},
+		{
+			name:     "FETCH FIRST in PostgreSQL",
+			sql:      "SELECT * FROM users FETCH FIRST 500 ROWS ONLY",
+			dialect:  DialectPostgreSQL,
+			expected: "SELECT * FROM users FETCH FIRST 101 ROWS ONLY",
+		},
+		{
+			name:     "Not a query",
+			sql:      "DELETE FROM users",
+			dialect:  DialectPostgreSQL,
+			expected: "DELETE FROM users",
+		},
+		{
+			name:     "Multiple statements",
+			sql:      "SELECT 1; SELECT 2",
+			dialect:  DialectPostgreSQL,
+			expected: "SELECT 1; SELECT 2",
+		},
+		// SQL Server
+		{
+			name:     "Insert TOP",
+			sql:      "SELECT DISTINCT name FROM users ORDER BY name",
+			dialect:  DialectSQLServer,
+			expected: "SELECT DISTINCT TOP (101) name FROM users ORDER BY name",
+		},
+		{
+			name:     "TOP after CTE",
+			sql:      "WITH a AS (SELECT TOP 5000 * FROM users) SELECT * FROM a",
+			dialect:  DialectSQLServer,
+			expected: "WITH a AS (SELECT TOP 5000 * FROM users) SELECT TOP (101) * FROM a",
+		},
+		{
+			name:     "Smaller TOP is kept",
+			sql:      "SELECT TOP (10) * FROM users",
+			dialect:  DialectSQLServer,
+			expected: "SELECT TOP (10) * FROM users",
+		},
+		{
+			name:     "Larger TOP is lowered",
+			sql:      "SELECT TOP 5000 * FROM users",
+			dialect:  DialectSQLServer,
+			expected: "SELECT TOP 101 * FROM users",
+		},
+		{
+			name:     "TOP PERCENT is wrapped",
+			sql:      "SELECT TOP 50 PERCENT * FROM users",
+			dialect:  DialectSQLServer,
+			expected: "SELECT TOP (101) * FROM (\nSELECT TOP 50 PERCENT * FROM users\n) AS limited_result",
+		},
+		{
+			name:     "OFFSET without FETCH",
+			sql:      "SELECT * FROM users ORDER BY id OFFSET 10 ROWS",
+			dialect:  DialectSQLServer,
+			expected: "SELECT * FROM users ORDER BY id OFFSET 10 ROWS FETCH NEXT 101 ROWS ONLY",
+		},
+		{
+			name:     "Larger FETCH is lowered",
+			sql:      "SELECT * FROM users ORDER BY id OFFSET 0 ROWS FETCH NEXT 500 ROWS ONLY",
+			dialect:  DialectSQLServer,
+			expected: "SELECT * FROM users ORDER BY id OFFSET 0 ROWS FETCH NEXT 101 ROWS ONLY",
+		},
+		{
+			name:     "UNION with ORDER BY",
+			sql:      "SELECT id FROM a UNION SELECT id FROM b ORDER BY id",
+			dialect:  DialectSQLServer,
+			expected: "SELECT id FROM a UNION SELECT id FROM b ORDER BY id OFFSET 0 ROWS FETCH NEXT 101 ROWS ONLY",
+		},
+		{
+			name:     "UNION without ORDER BY",
+			sql:      "SELECT id FROM a UNION SELECT id FROM b",
+			dialect:  DialectSQLServer,
+			expected: "SELECT TOP (101) * FROM (\nSELECT id FROM a UNION SELECT id FROM b\n) AS limited_result",
+		},
+		// Oracle
+		{
+			name:     "Append FETCH FIRST",
+			sql:      "SELECT * FROM users",
+			dialect:  DialectOracle,
+			expected: "SELECT * FROM users FETCH FIRST 101 ROWS ONLY",
+		},
+		{
+			name:     "Smaller FETCH FIRST is kept",
+			sql:      "SELECT * FROM users FETCH FIRST 10 ROWS ONLY",
+			dialect:  DialectOracle,
+			expected: "SELECT * FROM users FETCH FIRST 10 ROWS ONLY",
+		},
+		// Queries are only wrapped when their columns are named and unique
+		{
+			name:     "Aliased columns are wrapped",
+			sql:      "SELECT u.id, COUNT(*) AS orders, MAX(o.total) largest FROM users u JOIN orders o ON o.user_id = u.id GROUP BY u.id LIMIT :n",
+			dialect:  DialectPostgreSQL,
+			expected: "SELECT * FROM (\nSELECT u.id, COUNT(*) AS orders, MAX(o.total) largest FROM users u JOIN orders o ON o.user_id = u.id GROUP BY u.id LIMIT :n\n) AS limited_result LIMIT 101",
+		},
+		{
+			name:     "Unnamed column is not wrapped in PostgreSQL",
+			sql:      "SELECT COUNT(*) FROM users LIMIT :n",
+			dialect:  DialectPostgreSQL,
+			expected: "SELECT COUNT(*) FROM users LIMIT :n",
+		},
+		{
+			name:     "Duplicate columns are not wrapped in PostgreSQL",
+			sql:      "SELECT a.id, b.id FROM a JOIN b ON b.a_id = a.id LIMIT :n",
+			dialect:  DialectPostgreSQL,
+			expected: "SELECT a.id, b.id FROM a JOIN b ON b.a_id = a.id LIMIT :n",
+		},
+		{
+			name:     "Star over a join is not wrapped",
+			sql:      "SELECT * FROM a JOIN b ON b.a_id = a.id LIMIT :n",
+			dialect:  DialectPostgreSQL,
+			expected: "SELECT * FROM a JOIN b ON b.a_id = a.id LIMIT :n",
+		},
+		{
+			name:     "Unnamed column is not wrapped in MySQL",
+			sql:      "SELECT NULL, name FROM users LIMIT :n",
+			dialect:  DialectMySQL,
+			expected: "SELECT NULL, name FROM users LIMIT :n",
+		},
+		{
+			name:     "Duplicate columns are not wrapped in MySQL",
+			sql:      "SELECT a.id, b.ID FROM a, b LIMIT :n",
+			dialect:  DialectMySQL,
+			expected: "SELECT a.id, b.ID FROM a, b LIMIT :n",
+		},
+		{
+			name:     "Unnamed columns are not wrapped in SQL Server",
+			sql:      "SELECT COUNT(*) FROM a UNION ALL SELECT COUNT(*) FROM b",
+			dialect:  DialectSQLServer,
+			expected: "SELECT COUNT(*) FROM a UNION ALL SELECT COUNT(*) FROM b",
+		},
+		{
+			name:     "Duplicate columns are not wrapped in SQL Server",
+			sql:      "SELECT TOP 10 PERCENT a.id, b.id FROM a JOIN b ON b.a_id = a.id",
+			dialect:  DialectSQLServer,
+			expected: "SELECT TOP 10 PERCENT a.id, b.id FROM a JOIN b ON b.a_id = a.id",
+		},
+		{
+			name:     "SQL Server alias assignment is wrapped",
+			sql:      "SELECT total = COUNT(*) FROM a UNION ALL SELECT COUNT(*) FROM b",
+			dialect:  DialectSQLServer,
+			expected: "SELECT TOP (101) * FROM (\nSELECT total = COUNT(*) FROM a UNION ALL SELECT COUNT(*) FROM b\n) AS limited_result",
+		},
+		{
+			name:     "Unnamed column is not wrapped in Oracle",
+			sql:      "SELECT id, CASE WHEN active = 1 THEN 'y' END FROM users FETCH FIRST :n ROWS ONLY",
+			dialect:  DialectOracle,
+			expected: "SELECT id, CASE WHEN active = 1 THEN 'y' END FROM users FETCH FIRST :n ROWS ONLY",
+		},
+		{
+			name:     "Duplicate columns are not wrapped in Oracle",
+			sql:      "SELECT a.id, b.id FROM a JOIN b ON b.a_id = a.id FETCH FIRST :n ROWS ONLY",
+			dialect:  DialectOracle,
+			expected: "SELECT a.id, b.id FROM a JOIN b ON b.a_id = a.id FETCH FIRST :n ROWS ONLY",
+		},
+		{
+			name:     "Named columns are wrapped in Oracle",
+			sql:      "SELECT a.id, b.id AS b_id FROM a JOIN b ON b.a_id = a.id FETCH FIRST :n ROWS ONLY",
+			dialect:  DialectOracle,
+			expected: "SELECT * FROM (\nSELECT a.id, b.id AS b_id FROM a JOIN b ON b.a_id = a.id FETCH FIRST :n ROWS ONLY\n) limited_result FETCH FIRST 101 ROWS ONLY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := InjectRowLimit(tt.sql, tt.dialect, 101)
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,754 @@
+package sqlparser
+
+import "strings"
+
+// Lineage describes which tables and columns a query template reads
+type Lineage struct {
+	Tables  []LineageTable  `json:"tables"`  // Base tables read anywhere in the query; CTE names are resolved away
+	Columns []LineageColumn `json:"columns"` // Result columns of the outermost query
+	Filters []LineageFilter `json:"filters"` // Columns compared against parameters
+}
+
+// LineageTable is a base table read by a query
+type LineageTable struct {
+	Schema string `json:"schema,omitempty"`
+	Name   string `json:"name"`
+}
+
+// LineageSource is a base table column. Table is empty when the column
+// could not be tied to a table, and Column is * when every column of the
+// table is read.
+type LineageSource struct {
+	Schema string `json:"schema,omitempty"`
+	Table  string `json:"table,omitempty"`
+	Column string `json:"column"`
+}
+
+// LineageColumn is a result column and the base table columns its value
+// comes from
+type LineageColumn struct {
+	Name    string          `json:"name"`
+	Sources []LineageSource `json:"sources"`
+	Derived bool            `json:"derived,omitempty"` // Computed by an expression rather than passed through
+}
+
+// LineageFilter ties a parameter to a column it is compared against
+type LineageFilter struct {
+	Parameter string        `json:"parameter"`
+	Column    LineageSource `json:"column"`
+}
+
+// exprKeywords are words inside expressions that are never column names
+var exprKeywords = map[string]bool{
+	"CASE": true, "WHEN": true, "THEN": true, "ELSE": true, "END": true, "AND": true, "OR": true,
+	"NOT": true, "NULL": true, "IS": true, "IN": true, "LIKE": true, "ILIKE": true, "BETWEEN": true,
+	"TRUE": true, "FALSE": true, "DISTINCT": true, "AS": true, "CAST": true, "INTERVAL": true,
+	"OVER": true, "PARTITION": true, "BY": true, "ORDER": true, "ASC": true, "DESC": true,
+	"EXISTS": true, "ANY": true, "ALL": true, "SOME": true, "COLLATE": true, "FILTER": true,
+	"WITHIN": true, "ROWS": true, "RANGE": true, "PRECEDING": true, "FOLLOWING": true,
+	"UNBOUNDED": true, "CURRENT": true, "ROW": true, "NULLS": true, "FIRST": true, "LAST": true,
+	"ESCAPE": true, "SIMILAR": true, "TO": true, "AT": true, "TIME": true, "ZONE": true,
+	"DATE": true, "TIMESTAMP": true, "BOTH": true, "LEADING": true, "TRAILING": true,
+	"FROM": true, "WHERE": true, "ON": true, "USING": true,
+}
+
+// setOperators separate the branches of a compound query
+var setOperators = map[string]bool{"UNION": true, "INTERSECT": true, "EXCEPT": true, "MINUS": true}
+
+// ExtractLineage reports the base tables a template reads, where each result
+// column comes from and which columns its parameters filter on. Columns are
+// traced through CTEs and derived tables. The analysis is syntactic: an
+// unqualified column in a query over several tables is only tied to a table
+// when a derived table or CTE is known to produce it. Optional blocks are
+// analyzed as if all of them were kept.
+func ExtractLineage(sql string, dialect Dialect) *Lineage {
+	revealed := revealOptionalBlocks(sql, dialect)
+	lineage := &Lineage{
+		Tables:  []LineageTable{},
+		Columns: []LineageColumn{},
+		Filters: []LineageFilter{},
+	}
+
+	statements, err := ParseStatements(revealed, dialect)
+	if err != nil || len(statements) == 0 {
+		return lineage
+	}
+
+	b := &lineageBuilder{ctes: make(map[string][]LineageColumn)}
+	if columns := b.query(statements[0].Items, nil); columns != nil {
+		lineage.Columns = columns
+	}
+	if b.tables != nil {
+		lineage.Tables = b.tables
+	}
+	lineage.Filters = b.filters(significantTokens(revealed, dialect))
+	return lineage
+}
+
+// lineageBuilder collects lineage while walking the parenthesis tree
+type lineageBuilder struct {
+	ctes   map[string][]LineageColumn // Result columns of each CTE, by lower-case name
+	tables []LineageTable
+	ranges []scopeRange
+}
+
+// lineageScope holds the tables and derived tables a query's FROM clause
+// brings into scope
+type lineageScope struct {
+	parent  *lineageScope
+	entries []scopeEntry
+}
+
+type scopeEntry struct {
+	name    string          // Alias, or the table name when there is none
+	table   *LineageTable   // Base table; nil for derived tables and CTEs
+	columns []LineageColumn // Result columns of a derived table or CTE
+}
+
+// scopeRange is the span of source a scope covers, used to resolve the
+// columns parameters are compared against
+type scopeRange struct {
+	start, end int
+	scope      *lineageScope
+}
+
+// query analyzes a query with optional WITH clause and set operations and
+// returns its result columns
+func (b *lineageBuilder) query(items []Item, parent *lineageScope) []LineageColumn {
+	if len(items) > 0 && items[0].Token.IsKeyword("WITH") {
+		items = b.ctesOf(items, parent)
+	}
+
+	var columns []LineageColumn
+	for n, branch := range splitBranches(items) {
+		var branchColumns []LineageColumn
+		if len(branch) > 0 && branch[0].Group != nil && isQueryKind(branch[0].Group.Kind()) {
+			branchColumns = b.query(branch[0].Group.Items, parent)
+			b.nested(branch[1:], parent)
+		} else {
+			branchColumns = b.selectBranch(branch, parent)
+		}
+
+		// Later branches add sources to the columns of the first, by position
+		if n == 0 {
+			columns = branchColumns
+			continue
+		}
+		for i := range columns {
+			if i < len(branchColumns) {
+				columns[i].Sources = appendSources(columns[i].Sources, branchColumns[i].Sources...)
+				columns[i].Derived = columns[i].Derived || branchColumns[i].Derived
+			}
+		}
+	}
+	return columns
+}
+
+// ctesOf records the result columns of each CTE and returns the items of
+// the main query
+func (b *lineageBuilder) ctesOf(items []Item, parent *lineageScope) []Item {
+	i := 1
+	if i < len(items) && items[i].Token.IsKeyword("RECURSIVE") {
+		i++
+	}
+	for i < len(items) {
+		name := items[i].Token
+		i++
+		var renamed []string
+		if i < len(items) && items[i].Group != nil {
+			renamed = groupNames(items[i].Group)
+			i++
+		}
+		if i >= len(items) || !items[i].Token.IsKeyword("AS") {
+			return items[i:]
+		}
+		i++
+		for i < len(items) && (items[i].Token.IsKeyword("NOT") || items[i].Token.IsKeyword("MATERIALIZED")) {
+			i++
+		}
+		if i >= len(items) || items[i].Group == nil {
+			return items[i:]
+		}
+		columns := b.query(items[i].Group.Items, parent)
+		b.ctes[strings.ToLower(name.Value())] = renameColumns(columns, renamed)
+		i++
+
+		if i < len(items) && isPunct(items[i].Token, ",") && items[i].Group == nil {
+			i++
+			continue
+		}
+		break
+	}
+	return items[i:]
+}
+
+// splitBranches splits a compound query at its set operators
+func splitBranches(items []Item) [][]Item {
+	var branches [][]Item
+	start := 0
+	for i := range items {
+		if kw, ok := keywordAt(items, i); ok && setOperators[kw] {
+			branches = append(branches, items[start:i])
+			start = i + 1
+			if next, ok := keywordAt(items, start); ok && (next == "ALL" || next == "DISTINCT") {
+				start++
+			}
+		}
+	}
+	return append(branches, items[start:])
+}
+
+// selectBranch analyzes one SELECT and returns its result columns
+func (b *lineageBuilder) selectBranch(items []Item, parent *lineageScope) []LineageColumn {
+	scope := &lineageScope{parent: parent}
+	if start, end, ok := itemsRange(items); ok {
+		b.ranges = append(b.ranges, scopeRange{start: start, end: end, scope: scope})
+	}
+
+	selectAt, fromAt, fromEnd := -1, -1, len(items)
+	for i := range items {
+		kw, ok := keywordAt(items, i)
+		if !ok {
+			continue
+		}
+		switch {
+		case kw == "SELECT" && selectAt < 0:
+			selectAt = i
+		case kw == "FROM" && selectAt >= 0 && fromAt < 0:
+			fromAt = i
+		case (fromClauseEnd[kw] || kw == "INTO") && fromAt >= 0 && fromEnd == len(items):
+			fromEnd = i
+		}
+	}
+	if selectAt < 0 {
+		b.nested(items, parent)
+		return nil
+	}
+
+	// The FROM clause comes first so the select list can be resolved
+	listEnd := len(items)
+	if fromAt >= 0 {
+		listEnd = fromAt
+		b.fromClause(items[fromAt+1:fromEnd], scope)
+		b.nested(items[fromEnd:], scope)
+	}
+	for i := selectAt + 1; i < listEnd; i++ {
+		if kw, ok := keywordAt(items, i); ok && kw == "INTO" {
+			listEnd = i
+			break
+		}
+	}
+
+	var columns []LineageColumn
+	for _, expr := range splitList(items[skipSelectModifiers(items, selectAt+1):listEnd]) {
+		columns = append(columns, b.selectItem(expr, scope)...)
+	}
+	return columns
+}
+
+// skipSelectModifiers skips DISTINCT [ON (...)], ALL and TOP n after SELECT
+func skipSelectModifiers(items []Item, i int) int {
+	for i < len(items) {
+		kw, ok := keywordAt(items, i)
+		switch {
+		case ok && kw == "DISTINCT":
+			i++
+			if next, ok := keywordAt(items, i); ok && next == "ON" && i+1 < len(items) && items[i+1].Group != nil {
+				i += 2
+			}
+		case ok && kw == "ALL":
+			i++
+		case ok && kw == "TOP":
+			i += 2
+			if next, ok := keywordAt(items, i); ok && next == "PERCENT" {
+				i++
+			}
+			if next, ok := keywordAt(items, i); ok && next == "WITH" {
+				i += 2
+			}
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// fromClause brings the tables and derived tables of a FROM clause into
+// scope, and walks subqueries in its join conditions
+func (b *lineageBuilder) fromClause(items []Item, scope *lineageScope) {
+	expectRef := true
+	for i := 0; i < len(items); i++ {
+		item := items[i]
+		if !expectRef {
+			switch {
+			case item.Group != nil:
+				b.nested([]Item{item}, scope)
+			case item.Token.IsKeyword("JOIN"), isPunct(item.Token, ","):
+				expectRef = true
+			}
+			continue
+		}
+
+		switch {
+		case item.Token.IsKeyword("LATERAL") || item.Token.IsKeyword("ONLY"):
+			continue
+		case item.Group != nil && isQueryKind(item.Group.Kind()):
+			columns := b.query(item.Group.Items, scope.parent)
+			alias, renamed, next := readAlias(items, i+1)
+			scope.entries = append(scope.entries, scopeEntry{name: alias, columns: renameColumns(columns, renamed)})
+			i = next - 1
+		case item.Group != nil:
+			// Parenthesized joins
+			b.fromClause(item.Group.Items, scope)
+		case isName(item.Token):
+			parts, next := readDottedName(items, i)
+			if next < len(items) && items[next].Group != nil {
+				// Table function; its arguments may hold subqueries
+				b.nested(items[next:next+1], scope)
+				_, _, next = readAlias(items, next+1)
+			} else {
+				entry := b.tableEntry(parts)
+				alias, renamed, after := readAlias(items, next)
+				if alias != "" {
+					entry.name = alias
+				}
+				entry.columns = renameColumns(entry.columns, renamed)
+				scope.entries = append(scope.entries, entry)
+				next = after
+			}
+			i = next - 1
+		}
+		expectRef = false
+	}
+}
+
+// tableEntry makes a scope entry for a named table, which is either a CTE
+// or a base table
+func (b *lineageBuilder) tableEntry(parts []string) scopeEntry {
+	name := parts[len(parts)-1]
+	if len(parts) == 1 {
+		if columns, ok := b.ctes[strings.ToLower(name)]; ok {
+			return scopeEntry{name: name, columns: columns}
+		}
+	}
+
+	table := LineageTable{Name: name}
+	if len(parts) > 1 {
+		table.Schema = parts[len(parts)-2]
+	}
+	b.addTable(table)
+	return scopeEntry{name: name, table: &table}
+}
+
+func (b *lineageBuilder) addTable(table LineageTable) {
+	for _, t := range b.tables {
+		if strings.EqualFold(t.Schema, table.Schema) && strings.EqualFold(t.Name, table.Name) {
+			return
+		}
+	}
+	b.tables = append(b.tables, table)
+}
+
+// nested walks items for subqueries outside the select list and FROM
+// clause, so their tables and parameters are seen
+func (b *lineageBuilder) nested(items []Item, scope *lineageScope) {
+	for _, item := range items {
+		if item.Group == nil {
+			continue
+		}
+		if isQueryKind(item.Group.Kind()) {
+			b.query(item.Group.Items, scope)
+		} else {
+			b.nested(item.Group.Items, scope)
+		}
+	}
+}
+
+// selectItem returns the result columns of one select list expression;
+// a * expands to one column per table in scope
+func (b *lineageBuilder) selectItem(expr []Item, scope *lineageScope) []LineageColumn {
+	if len(expr) == 0 {
+		return nil
+	}
+
+	// * and qualifier.*
+	last := expr[len(expr)-1]
+	if last.Group == nil && isPunct(last.Token, "*") {
+		var columns []LineageColumn
+		qualifier := ""
+		if len(expr) >= 3 && isPunct(expr[len(expr)-2].Token, ".") {
+			qualifier = expr[len(expr)-3].Token.Value()
+		}
+		for _, entry := range scope.entries {
+			if qualifier != "" && !strings.EqualFold(entry.name, qualifier) {
+				continue
+			}
+			if entry.table != nil {
+				columns = append(columns, LineageColumn{
+					Name:    "*",
+					Sources: []LineageSource{{Schema: entry.table.Schema, Table: entry.table.Name, Column: "*"}},
+				})
+				continue
+			}
+			columns = append(columns, copyColumns(entry.columns)...)
+		}
+		return columns
+	}
+
+	name, body := splitAlias(expr)
+	column := LineageColumn{Sources: b.columnSources(body, scope)}
+
+	parts, next := readDottedName(body, 0)
+	simple := len(parts) > 0 && next == len(body) && !exprKeywords[strings.ToUpper(parts[len(parts)-1])]
+	switch {
+	case simple:
+		if name == "" {
+			name = parts[len(parts)-1]
+		}
+		// A column passed through a derived table keeps its derived flag
+		column.Derived = b.derivedColumn(parts, scope)
+	default:
+		column.Derived = true
+		if name == "" {
+			name = "?column?"
+			if len(body) >= 2 && isName(body[0].Token) && body[0].Group == nil && body[1].Group != nil {
+				name = strings.ToLower(body[0].Token.Value())
+			}
+		}
+	}
+	column.Name = name
+	if column.Sources == nil {
+		column.Sources = []LineageSource{}
+	}
+	return []LineageColumn{column}
+}
+
+// splitAlias separates "expr [AS] alias" into the alias and the expression
+func splitAlias(expr []Item) (string, []Item) {
+	n := len(expr)
+	if n < 2 || expr[n-1].Group != nil || !isName(expr[n-1].Token) {
+		return "", expr
+	}
+	alias := expr[n-1].Token
+	if expr[n-2].Group == nil && expr[n-2].Token.IsKeyword("AS") {
+		return alias.Value(), expr[:n-2]
+	}
+	if exprKeywords[strings.ToUpper(alias.Text)] && alias.Type == TokenIdent {
+		return "", expr
+	}
+
+	// Without AS, the alias must follow a complete operand
+	prev := expr[n-2]
+	if prev.Group != nil {
+		return alias.Value(), expr[:n-1]
+	}
+	switch prev.Token.Type {
+	case TokenIdent:
+		if exprKeywords[strings.ToUpper(prev.Token.Text)] && !prev.Token.IsKeyword("END") {
+			return "", expr
+		}
+		return alias.Value(), expr[:n-1]
+	case TokenQuotedIdent, TokenNumber, TokenString, TokenParam:
+		return alias.Value(), expr[:n-1]
+	}
+	return "", expr
+}
+
+// derivedColumn reports whether a column reference resolves to a computed
+// column of a derived table or CTE
+func (b *lineageBuilder) derivedColumn(parts []string, scope *lineageScope) bool {
+	ref := columnRefOf(parts)
+	for s := scope; s != nil; s = s.parent {
+		for _, entry := range s.entries {
+			if entry.table != nil || (ref.Qualifier != "" && !strings.EqualFold(entry.name, ref.Qualifier)) {
+				continue
+			}
+			for _, c := range entry.columns {
+				if strings.EqualFold(c.Name, ref.Column) {
+					return c.Derived
+				}
+			}
+		}
+		if len(s.entries) > 0 {
+			return false
+		}
+	}
+	return false
+}
+
+// columnSources returns the base table columns an expression reads
+func (b *lineageBuilder) columnSources(expr []Item, scope *lineageScope) []LineageSource {
+	var sources []LineageSource
+	for i := 0; i < len(expr); i++ {
+		item := expr[i]
+		if item.Group != nil {
+			if isQueryKind(item.Group.Kind()) {
+				for _, column := range b.query(item.Group.Items, scope) {
+					sources = appendSources(sources, column.Sources...)
+				}
+			} else {
+				sources = appendSources(sources, b.columnSources(item.Group.Items, scope)...)
+			}
+			continue
+		}
+
+		tok := item.Token
+		if !isName(tok) || (tok.Type == TokenIdent && exprKeywords[strings.ToUpper(tok.Text)]) {
+			continue
+		}
+		// Type names after AS and ::, and date parts before FROM, as in
+		// CAST(x AS int), x::date and EXTRACT(YEAR FROM x)
+		if i > 0 && expr[i-1].Group == nil && (expr[i-1].Token.IsKeyword("AS") || expr[i-1].Token.Type == TokenCast) {
+			continue
+		}
+		parts, next := readDottedName(expr, i)
+		i = next - 1
+		if next < len(expr) && (expr[next].Group != nil || expr[next].Token.IsKeyword("FROM")) {
+			continue
+		}
+		sources = appendSources(sources, b.resolve(columnRefOf(parts), scope)...)
+	}
+	return sources
+}
+
+// resolve ties a column reference to base table columns through the scope
+func (b *lineageBuilder) resolve(ref ColumnRef, scope *lineageScope) []LineageSource {
+	for s := scope; s != nil; s = s.parent {
+		var candidates []scopeEntry
+		for _, entry := range s.entries {
+			if ref.Qualifier != "" {
+				if strings.EqualFold(entry.name, ref.Qualifier) ||
+					(entry.table != nil && strings.EqualFold(entry.table.Name, ref.Qualifier)) {
+					return entrySources(entry, ref.Column)
+				}
+				continue
+			}
+			candidates = append(candidates, entry)
+		}
+		if ref.Qualifier != "" || len(candidates) == 0 {
+			continue
+		}
+		if len(candidates) == 1 {
+			return entrySources(candidates[0], ref.Column)
+		}
+
+		// Among several tables, only a derived table known to produce the
+		// column can claim it
+		var matches []LineageSource
+		found := 0
+		for _, entry := range candidates {
+			if entry.table == nil && findColumn(entry.columns, ref.Column) != nil {
+				matches = entrySources(entry, ref.Column)
+				found++
+			}
+		}
+		if found == 1 {
+			return matches
+		}
+		return []LineageSource{{Column: ref.Column}}
+	}
+	return []LineageSource{{Column: ref.Column}}
+}
+
+// entrySources returns the base table columns behind a column of an entry
+func entrySources(entry scopeEntry, column string) []LineageSource {
+	if entry.table != nil {
+		return []LineageSource{{Schema: entry.table.Schema, Table: entry.table.Name, Column: column}}
+	}
+	if c := findColumn(entry.columns, column); c != nil {
+		return c.Sources
+	}
+	// A column of a derived table that selects * from one table
+	for _, c := range entry.columns {
+		if c.Name == "*" && len(c.Sources) == 1 {
+			source := c.Sources[0]
+			source.Column = column
+			return []LineageSource{source}
+		}
+	}
+	return []LineageSource{{Column: column}}
+}
+
+func findColumn(columns []LineageColumn, name string) *LineageColumn {
+	for i := range columns {
+		if strings.EqualFold(columns[i].Name, name) {
+			return &columns[i]
+		}
+	}
+	return nil
+}
+
+// filters resolves the column each parameter is compared against in the
+// scope of the innermost query around the parameter
+func (b *lineageBuilder) filters(tokens []Token) []LineageFilter {
+	filters := []LineageFilter{}
+	seen := make(map[LineageFilter]bool)
+	for i, tok := range tokens {
+		if tok.Type != TokenParam {
+			continue
+		}
+		ref, ok := columnBefore(tokens, i)
+		if !ok {
+			ref, ok = columnAfter(tokens, i)
+		}
+		if !ok {
+			continue
+		}
+		for _, source := range b.resolve(ref, b.scopeAt(tok.Pos)) {
+			filter := LineageFilter{Parameter: tok.Value(), Column: source}
+			if !seen[filter] {
+				seen[filter] = true
+				filters = append(filters, filter)
+			}
+		}
+	}
+	return filters
+}
+
+// scopeAt returns the scope of the innermost query around a position
+func (b *lineageBuilder) scopeAt(pos int) *lineageScope {
+	var best *scopeRange
+	for i := range b.ranges {
+		r := &b.ranges[i]
+		if pos >= r.start && pos <= r.end && (best == nil || r.end-r.start < best.end-best.start) {
+			best = r
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return best.scope
+}
+
+// itemsRange returns the source span of a list of items
+func itemsRange(items []Item) (int, int, bool) {
+	if len(items) == 0 {
+		return 0, 0, false
+	}
+	start := items[0].Token.Pos
+	if items[0].Group != nil {
+		start = items[0].Group.firstToken().Pos
+	}
+	last := items[len(items)-1]
+	end := last.Token.Pos + len(last.Token.Text)
+	if last.Group != nil {
+		if last.Group.Close == nil {
+			return start, int(^uint(0) >> 1), true
+		}
+		end = last.Group.Close.Pos
+	}
+	return start, end, true
+}
+
+// splitList splits items at top-level commas
+func splitList(items []Item) [][]Item {
+	var list [][]Item
+	start := 0
+	for i, item := range items {
+		if item.Group == nil && isPunct(item.Token, ",") {
+			list = append(list, items[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(items) {
+		list = append(list, items[start:])
+	}
+	return list
+}
+
+// readDottedName reads "name(.name)*" starting at i
+func readDottedName(items []Item, i int) ([]string, int) {
+	if i >= len(items) || items[i].Group != nil || !isName(items[i].Token) {
+		return nil, i
+	}
+	parts := []string{items[i].Token.Value()}
+	i++
+	for i+1 < len(items) && items[i].Group == nil && isPunct(items[i].Token, ".") &&
+		items[i+1].Group == nil && isName(items[i+1].Token) {
+		parts = append(parts, items[i+1].Token.Value())
+		i += 2
+	}
+	return parts, i
+}
+
+// readAlias reads "[AS] alias [(column, ...)]" starting at i
+func readAlias(items []Item, i int) (string, []string, int) {
+	if i < len(items) && items[i].Group == nil && items[i].Token.IsKeyword("AS") {
+		i++
+	}
+	if i >= len(items) || items[i].Group != nil || !isName(items[i].Token) ||
+		clauseKeywords[strings.ToUpper(items[i].Token.Text)] || fromClauseEnd[strings.ToUpper(items[i].Token.Text)] {
+		return "", nil, i
+	}
+	alias := items[i].Token.Value()
+	i++
+	var renamed []string
+	if i < len(items) && items[i].Group != nil {
+		renamed = groupNames(items[i].Group)
+		i++
+	}
+	return alias, renamed, i
+}
+
+// groupNames returns the names in a parenthesized column list
+func groupNames(group *Node) []string {
+	var names []string
+	for _, item := range group.Items {
+		if item.Group == nil && isName(item.Token) {
+			names = append(names, item.Token.Value())
+		}
+	}
+	return names
+}
+
+// renameColumns applies a column alias list to result columns
+func renameColumns(columns []LineageColumn, names []string) []LineageColumn {
+	if len(names) == 0 {
+		return columns
+	}
+	renamed := copyColumns(columns)
+	for i := range renamed {
+		if i < len(names) {
+			renamed[i].Name = names[i]
+		}
+	}
+	return renamed
+}
+
+func copyColumns(columns []LineageColumn) []LineageColumn {
+	copied := make([]LineageColumn, len(columns))
+	for i, c := range columns {
+		c.Sources = append([]LineageSource(nil), c.Sources...)
+		copied[i] = c
+	}
+	return copied
+}
+
+// columnRefOf turns "[schema.]table.column" into a column reference
+func columnRefOf(parts []string) ColumnRef {
+	ref := ColumnRef{Column: parts[len(parts)-1]}
+	if len(parts) > 1 {
+		ref.Qualifier = parts[len(parts)-2]
+	}
+	return ref
+}
+
+func appendSources(sources []LineageSource, more ...LineageSource) []LineageSource {
+	for _, source := range more {
+		duplicate := false
+		for _, s := range sources {
+			if s == source {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+func isQueryKind(kind string) bool {
+	return kind == "SELECT" || kind == "WITH" || kind == "VALUES"
+}
@@ -0,0 +1,137 @@
+package sqlparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractLineage(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		expected *Lineage
+	}{
+		{
+			name: "Joins, aliases and filters",
+			sql: "SELECT o.id, o.total * 2 AS doubled, c.name customer FROM sales.orders o " +
+				"JOIN customers c ON c.id = o.customer_id WHERE o.status = :status AND c.region IN (:regions)",
+			expected: &Lineage{
+				Tables: []LineageTable{{Schema: "sales", Name: "orders"}, {Name: "customers"}},
+				Columns: []LineageColumn{
+					{Name: "id", Sources: []LineageSource{{Schema: "sales", Table: "orders", Column: "id"}}},
+					{Name: "doubled", Sources: []LineageSource{{Schema: "sales", Table: "orders", Column: "total"}}, Derived: true},
+					{Name: "customer", Sources: []LineageSource{{Table: "customers", Column: "name"}}},
+				},
+				Filters: []LineageFilter{
+					{Parameter: "status", Column: LineageSource{Schema: "sales", Table: "orders", Column: "status"}},
+					{Parameter: "regions", Column: LineageSource{Table: "customers", Column: "region"}},
+				},
+			},
+		},
+		{
+			name: "Through a CTE",
+			sql: "WITH recent AS (SELECT id, created_at AS at_time FROM orders WHERE created_at > :since) " +
+				"SELECT r.id, r.at_time, count(*) FROM recent r GROUP BY r.id",
+			expected: &Lineage{
+				Tables: []LineageTable{{Name: "orders"}},
+				Columns: []LineageColumn{
+					{Name: "id", Sources: []LineageSource{{Table: "orders", Column: "id"}}},
+					{Name: "at_time", Sources: []LineageSource{{Table: "orders", Column: "created_at"}}},
+					{Name: "count", Sources: []LineageSource{}, Derived: true},
+				},
+				Filters: []LineageFilter{
+					{Parameter: "since", Column: LineageSource{Table: "orders", Column: "created_at"}},
+				},
+			},
+		},
+		{
+			name: "Star over a derived table",
+			sql:  "SELECT * FROM (SELECT id, upper(name) AS n FROM users) u WHERE u.id = :id",
+			expected: &Lineage{
+				Tables: []LineageTable{{Name: "users"}},
+				Columns: []LineageColumn{
+					{Name: "id", Sources: []LineageSource{{Table: "users", Column: "id"}}},
+					{Name: "n", Sources: []LineageSource{{Table: "users", Column: "name"}}, Derived: true},
+				},
+				Filters: []LineageFilter{
+					{Parameter: "id", Column: LineageSource{Table: "users", Column: "id"}},
+				},
+			},
+		},
+		{
+			name: "Star over a table",
+			sql:  "SELECT * FROM users WHERE email = :email",
+			expected: &Lineage{
+				Tables: []LineageTable{{Name: "users"}},
+				Columns: []LineageColumn{
+					{Name: "*", Sources: []LineageSource{{Table: "users", Column: "*"}}},
+				},
+				Filters: []LineageFilter{
+					{Parameter: "email", Column: LineageSource{Table: "users", Column: "email"}},
+				},
+			},
+		},
+		{
+			name: "Union branches",
+			sql:  "SELECT id FROM a UNION ALL SELECT id FROM b",
+			expected: &Lineage{
+				Tables: []LineageTable{{Name: "a"}, {Name: "b"}},
+				Columns: []LineageColumn{
+					{Name: "id", Sources: []LineageSource{{Table: "a", Column: "id"}, {Table: "b", Column: "id"}}},
+				},
+				Filters: []LineageFilter{},
+			},
+		},
+		{
+			name: "Correlated subquery and optional block",
+			sql: "SELECT name, (SELECT max(amount) FROM payments p WHERE p.user_id = u.id) AS last_payment " +
+				"FROM users u WHERE id = :id /*[ AND email = :email ]*/",
+			expected: &Lineage{
+				Tables: []LineageTable{{Name: "users"}, {Name: "payments"}},
+				Columns: []LineageColumn{
+					{Name: "name", Sources: []LineageSource{{Table: "users", Column: "name"}}},
+					{Name: "last_payment", Sources: []LineageSource{{Table: "payments", Column: "amount"}}, Derived: true},
+				},
+				Filters: []LineageFilter{
+					{Parameter: "id", Column: LineageSource{Table: "users", Column: "id"}},
+					{Parameter: "email", Column: LineageSource{Table: "users", Column: "email"}},
+				},
+			},
+		},
+		{
+			name: "Type names and date parts are not columns",
+			sql:  "SELECT CAST(price AS int) AS price, EXTRACT(YEAR FROM created_at) y, sold::date FROM items",
+			expected: &Lineage{
+				Tables: []LineageTable{{Name: "items"}},
+				Columns: []LineageColumn{
+					{Name: "price", Sources: []LineageSource{{Table: "items", Column: "price"}}, Derived: true},
+					{Name: "y", Sources: []LineageSource{{Table: "items", Column: "created_at"}}, Derived: true},
+					{Name: "?column?", Sources: []LineageSource{{Table: "items", Column: "sold"}}, Derived: true},
+				},
+				Filters: []LineageFilter{},
+			},
+		},
+		{
+			name: "Ambiguous unqualified column",
+			sql:  "SELECT a.id FROM a JOIN b ON a.id = b.a_id WHERE name = :name",
+			expected: &Lineage{
+				Tables: []LineageTable{{Name: "a"}, {Name: "b"}},
+				Columns: []LineageColumn{
+					{Name: "id", Sources: []LineageSource{{Table: "a", Column: "id"}}},
+				},
+				Filters: []LineageFilter{
+					{Parameter: "name", Column: LineageSource{Column: "name"}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ExtractLineage(tt.sql, DialectPostgreSQL)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+}
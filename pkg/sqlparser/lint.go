@@ -0,0 +1,420 @@
+package sqlparser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity ranks a lint finding
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// DefaultLargeTableRows is the row count from which a table counts as large
+// when the configuration does not set one
+const DefaultLargeTableRows = 100000
+
+// UnknownTableRows is the row count of a table without statistics. The
+// missing_where rule treats such tables as large.
+const UnknownTableRows int64 = -1
+
+// LintFinding is a best-practice problem found in a SQL template. Line and
+// Column are 1-based; they are zero for findings that have no location in
+// the SQL, such as a declared parameter the template never uses.
+type LintFinding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Offset   int      `json:"offset"`
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+}
+
+// LintRule is a check run by Lint
+type LintRule struct {
+	ID          string
+	Description string
+	Severity    Severity // Default severity
+	check       func(l *linter)
+}
+
+// LintRules are the available rules, in the order they run
+var LintRules = []LintRule{
+	{
+		ID:          "select_star",
+		Description: "SELECT * makes a tool's output change whenever the table changes",
+		Severity:    SeverityWarning,
+		check:       lintSelectStar,
+	},
+	{
+		ID:          "missing_where",
+		Description: "Query on a large table without a WHERE clause",
+		Severity:    SeverityWarning,
+		check:       lintMissingWhere,
+	},
+	{
+		ID:          "implicit_cross_join",
+		Description: "Tables listed with commas in FROM are cross joined unless a WHERE condition relates them",
+		Severity:    SeverityWarning,
+		check:       lintImplicitCrossJoin,
+	},
+	{
+		ID:          "non_sargable_predicate",
+		Description: "A function or expression on the column compared to a parameter prevents index use",
+		Severity:    SeverityWarning,
+		check:       lintNonSargable,
+	},
+	{
+		ID:          "order_by_without_limit",
+		Description: "ORDER BY without a limit sorts the whole result",
+		Severity:    SeverityInfo,
+		check:       lintOrderByWithoutLimit,
+	},
+	{
+		ID:          "unused_parameter",
+		Description: "Declared parameter is not used in the SQL template",
+		Severity:    SeverityWarning,
+		check:       lintUnusedParameters,
+	},
+	{
+		ID:          "undeclared_parameter",
+		Description: "SQL template uses a parameter that is not declared",
+		Severity:    SeverityError,
+		check:       lintUndeclaredParameters,
+	},
+}
+
+// LintRuleConfig overrides the defaults of one rule
+type LintRuleConfig struct {
+	Enabled  *bool    // nil keeps the rule enabled
+	Severity Severity // Empty keeps the rule's default severity
+}
+
+// LintConfig selects and tunes the lint rules
+type LintConfig struct {
+	Rules          map[string]LintRuleConfig // Keyed by rule ID
+	LargeTableRows int64                     // Threshold for missing_where; 0 means DefaultLargeTableRows
+}
+
+// LintOptions carries the configuration and what is known about the
+// template's context
+type LintOptions struct {
+	Config LintConfig
+	// DeclaredParameters are the parameter names defined for the query. The
+	// parameter rules are skipped when it is nil.
+	DeclaredParameters []string
+	// TableRows holds approximate row counts keyed by lower-case table name
+	// or schema.table, or UnknownTableRows for tables never analyzed. The
+	// missing_where rule is skipped when it is empty.
+	TableRows map[string]int64
+}
+
+// Lint runs the enabled rules on a SQL template and returns the findings in
+// source order. Optional blocks are linted as if they were kept. SQL that
+// does not parse yields no findings; validation reports it instead.
+func Lint(sql string, dialect Dialect, opts LintOptions) []LintFinding {
+	revealed := revealOptionalBlocks(sql, dialect)
+	statements, err := ParseStatements(revealed, dialect)
+	if err != nil || len(statements) == 0 {
+		return nil
+	}
+
+	l := &linter{
+		dialect:    dialect,
+		opts:       opts,
+		statements: statements,
+		tokens:     significantTokens(revealed, dialect),
+		findings:   []LintFinding{},
+	}
+
+	for _, rule := range LintRules {
+		cfg := opts.Config.Rules[rule.ID]
+		if cfg.Enabled != nil && !*cfg.Enabled {
+			continue
+		}
+		l.rule = rule
+		if cfg.Severity != "" {
+			l.rule.Severity = cfg.Severity
+		}
+		rule.check(l)
+	}
+
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		return l.findings[i].Offset < l.findings[j].Offset
+	})
+	return l.findings
+}
+
+// linter holds the parsed template while the rules run
+type linter struct {
+	dialect    Dialect
+	opts       LintOptions
+	statements []*Node
+	tokens     []Token // Significant tokens of the whole template
+	rule       LintRule
+	findings   []LintFinding
+}
+
+func (l *linter) report(tok *Token, format string, args ...interface{}) {
+	finding := LintFinding{
+		Rule:     l.rule.ID,
+		Severity: l.rule.Severity,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	if tok != nil {
+		finding.Offset = tok.Pos
+		finding.Line = tok.Line
+		finding.Column = tok.Column
+	}
+	l.findings = append(l.findings, finding)
+}
+
+// eachLevel calls fn with the items of every statement and nested group,
+// outermost first
+func (l *linter) eachLevel(fn func(items []Item, outermost bool)) {
+	var walk func(n *Node, outermost bool)
+	walk = func(n *Node, outermost bool) {
+		fn(n.Items, outermost)
+		for _, item := range n.Items {
+			if item.Group != nil {
+				walk(item.Group, false)
+			}
+		}
+	}
+	for _, stmt := range l.statements {
+		walk(stmt, true)
+	}
+}
+
+// levelTokens flattens one level of items into tokens, standing in for each
+// nested group with its opening parenthesis
+func levelTokens(items []Item) []Token {
+	tokens := make([]Token, 0, len(items))
+	for _, item := range items {
+		if item.Group != nil {
+			if item.Group.Open != nil {
+				tokens = append(tokens, *item.Group.Open)
+			}
+			continue
+		}
+		tokens = append(tokens, item.Token)
+	}
+	return tokens
+}
+
+// indexOf returns the index of the first keyword in tokens, or -1
+func indexOf(tokens []Token, keyword string) int {
+	for i, tok := range tokens {
+		if tok.IsKeyword(keyword) {
+			return i
+		}
+	}
+	return -1
+}
+
+// fromClauseEnd are the keywords that close a FROM clause
+var fromClauseEnd = map[string]bool{
+	"WHERE": true, "GROUP": true, "HAVING": true, "ORDER": true, "LIMIT": true, "OFFSET": true,
+	"FETCH": true, "UNION": true, "INTERSECT": true, "EXCEPT": true, "MINUS": true, "WINDOW": true,
+	"FOR": true,
+}
+
+func lintSelectStar(l *linter) {
+	l.eachLevel(func(items []Item, _ bool) {
+		tokens := levelTokens(items)
+		inSelectList := false
+		for i, tok := range tokens {
+			switch {
+			case tok.IsKeyword("SELECT"):
+				inSelectList = true
+			case tok.IsKeyword("FROM"):
+				inSelectList = false
+			case inSelectList && isPunct(tok, "*") && i > 0:
+				// A * after a value is multiplication
+				prev := tokens[i-1]
+				if prev.IsKeyword("SELECT") || prev.IsKeyword("DISTINCT") || prev.IsKeyword("ALL") ||
+					isPunct(prev, ",") || isPunct(prev, ".") ||
+					(i > 1 && tokens[i-2].IsKeyword("TOP")) {
+					star := tok
+					l.report(&star, "avoid SELECT *; list the columns the tool returns")
+				}
+			}
+		}
+	})
+}
+
+func lintMissingWhere(l *linter) {
+	if len(l.opts.TableRows) == 0 {
+		return
+	}
+	threshold := l.opts.Config.LargeTableRows
+	if threshold <= 0 {
+		threshold = DefaultLargeTableRows
+	}
+
+	l.eachLevel(func(items []Item, _ bool) {
+		tokens := levelTokens(items)
+		if indexOf(tokens, "FROM") < 0 || indexOf(tokens, "WHERE") >= 0 {
+			return
+		}
+		for i, tok := range tokens {
+			if !tok.IsKeyword("FROM") && !tok.IsKeyword("JOIN") {
+				continue
+			}
+			j := i + 1
+			for {
+				ref, next, ok := readTableRef(tokens, j)
+				if !ok {
+					break
+				}
+				rows, ok := l.tableRows(ref)
+				switch {
+				case !ok:
+				case rows < 0:
+					l.report(&tokens[j], "%s has no row count statistics and the query has no WHERE clause", ref.Name)
+				case rows >= threshold:
+					l.report(&tokens[j], "%s has about %d rows and the query has no WHERE clause", ref.Name, rows)
+				}
+				if next < len(tokens) && isPunct(tokens[next], ",") {
+					j = next + 1
+					continue
+				}
+				break
+			}
+		}
+	})
+}
+
+// tableRows looks up the row count of a table, preferring a schema match,
+// and reports whether the table is known
+func (l *linter) tableRows(ref TableRef) (int64, bool) {
+	if ref.Schema != "" {
+		if rows, ok := l.opts.TableRows[strings.ToLower(ref.Schema+"."+ref.Name)]; ok {
+			return rows, true
+		}
+	}
+	rows, ok := l.opts.TableRows[strings.ToLower(ref.Name)]
+	return rows, ok
+}
+
+func lintImplicitCrossJoin(l *linter) {
+	l.eachLevel(func(items []Item, _ bool) {
+		tokens := levelTokens(items)
+		from := indexOf(tokens, "FROM")
+		if from < 0 {
+			return
+		}
+		for i := from + 1; i < len(tokens); i++ {
+			tok := tokens[i]
+			if tok.Type == TokenIdent && fromClauseEnd[strings.ToUpper(tok.Text)] {
+				return
+			}
+			if !isPunct(tok, ",") || i+1 >= len(tokens) {
+				continue
+			}
+			// LATERAL subqueries and table functions depend on the tables
+			// before them, so the comma is not a cross join
+			next := tokens[i+1]
+			if next.IsKeyword("LATERAL") || (i+2 < len(tokens) && isName(next) && isPunct(tokens[i+2], "(")) {
+				continue
+			}
+			comma := tok
+			l.report(&comma, "implicit cross join; use an explicit JOIN ... ON")
+		}
+	})
+}
+
+func lintNonSargable(l *linter) {
+	tokens := l.tokens
+	for i, tok := range tokens {
+		if tok.Type != TokenParam {
+			continue
+		}
+
+		// Skip back over the comparison operator
+		j := i - 1
+		for j >= 0 && tokens[j].Type == TokenPunct && strings.Contains("=<>!", tokens[j].Text) {
+			j--
+		}
+		if j == i-1 {
+			// LIKE '%' || :p can only be answered by scanning
+			if j >= 3 && isPunct(tokens[j], "|") && isPunct(tokens[j-1], "|") &&
+				tokens[j-2].Type == TokenString && strings.HasPrefix(tokens[j-2].Text, "'%") &&
+				(tokens[j-3].IsKeyword("LIKE") || tokens[j-3].IsKeyword("ILIKE")) {
+				l.report(&tokens[j-2], "leading wildcard in LIKE with %s prevents index use", tok.Text)
+			}
+			continue
+		}
+		if j < 0 {
+			continue
+		}
+
+		switch {
+		case isPunct(tokens[j], ")"):
+			l.report(&tokens[j], "function or expression on the column compared to %s prevents index use", tok.Text)
+		case j >= 1 && (isName(tokens[j]) || tokens[j].Type == TokenNumber) &&
+			tokens[j-1].Type == TokenPunct && strings.Contains("+-*/%|", tokens[j-1].Text):
+			l.report(&tokens[j], "arithmetic on the column compared to %s prevents index use", tok.Text)
+		}
+	}
+}
+
+func lintOrderByWithoutLimit(l *linter) {
+	l.eachLevel(func(items []Item, outermost bool) {
+		if !outermost {
+			return
+		}
+		tokens := levelTokens(items)
+		order := indexOf(tokens, "ORDER")
+		if order < 0 {
+			return
+		}
+		for _, keyword := range []string{"LIMIT", "FETCH", "TOP"} {
+			if indexOf(tokens, keyword) >= 0 {
+				return
+			}
+		}
+		l.report(&tokens[order], "ORDER BY without a row limit sorts the entire result")
+	})
+}
+
+func lintUnusedParameters(l *linter) {
+	if l.opts.DeclaredParameters == nil {
+		return
+	}
+	used := make(map[string]bool)
+	for _, tok := range l.tokens {
+		if tok.Type == TokenParam {
+			used[tok.Value()] = true
+		}
+	}
+	for _, name := range l.opts.DeclaredParameters {
+		if !used[name] {
+			l.report(nil, "parameter %s is declared but not used in the SQL template", name)
+		}
+	}
+}
+
+func lintUndeclaredParameters(l *linter) {
+	if l.opts.DeclaredParameters == nil {
+		return
+	}
+	declared := make(map[string]bool)
+	for _, name := range l.opts.DeclaredParameters {
+		declared[name] = true
+	}
+	reported := make(map[string]bool)
+	for i, tok := range l.tokens {
+		name := tok.Value()
+		if tok.Type != TokenParam || declared[name] || reported[name] {
+			continue
+		}
+		reported[name] = true
+		l.report(&l.tokens[i], "parameter %s is used but not declared", name)
+	}
+}
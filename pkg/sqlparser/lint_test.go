@@ -0,0 +1,117 @@
+package sqlparser
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// lintRules returns rule:line:column for each finding
+func lintRules(findings []LintFinding) []string {
+	result := []string{}
+	for _, f := range findings {
+		result = append(result, fmt.Sprintf("%s:%d:%d", f.Rule, f.Line, f.Column))
+	}
+	return result
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		opts     LintOptions
+		expected []string
+	}{
+		{
+			name:     "Clean query",
+			sql:      "SELECT id, name FROM users WHERE id = :id",
+			expected: []string{},
+		},
+		{
+			name:     "SELECT star",
+			sql:      "SELECT u.*, count(*), 2 * 3 FROM users u WHERE id IN (SELECT * FROM x)",
+			expected: []string{"select_star:1:10", "select_star:1:62"},
+		},
+		{
+			name:     "Implicit cross join",
+			sql:      "SELECT a.id FROM a, b WHERE a.id = b.id",
+			expected: []string{"implicit_cross_join:1:19"},
+		},
+		{
+			name:     "Table function is not a cross join",
+			sql:      "SELECT a.id FROM a, unnest(a.tags) t",
+			expected: []string{},
+		},
+		{
+			name: "Non-sargable predicates",
+			sql:  "SELECT id FROM users\nWHERE lower(email) = :email\n  AND age + 1 > :age\n  AND name LIKE '%' || :name",
+			expected: []string{
+				"non_sargable_predicate:2:18",
+				"non_sargable_predicate:3:13",
+				"non_sargable_predicate:4:17",
+			},
+		},
+		{
+			name:     "ORDER BY without limit",
+			sql:      "SELECT id FROM users WHERE id > :id ORDER BY id",
+			expected: []string{"order_by_without_limit:1:37"},
+		},
+		{
+			name:     "ORDER BY with limit",
+			sql:      "SELECT id FROM users WHERE id > :id ORDER BY id LIMIT 10",
+			expected: []string{},
+		},
+		{
+			name:     "Missing WHERE on a large table",
+			sql:      "SELECT id FROM public.events e JOIN users u ON u.id = e.user_id",
+			opts:     LintOptions{TableRows: map[string]int64{"public.events": 5000000, "users": 10}},
+			expected: []string{"missing_where:1:16"},
+		},
+		{
+			name:     "Missing WHERE on a table without statistics",
+			sql:      "SELECT id FROM audit",
+			opts:     LintOptions{TableRows: map[string]int64{"users": 10, "audit": UnknownTableRows}},
+			expected: []string{"missing_where:1:16"},
+		},
+		{
+			name:     "Missing WHERE on a table not in the catalog",
+			sql:      "WITH recent AS (SELECT id FROM users WHERE id > 10) SELECT id FROM recent",
+			opts:     LintOptions{TableRows: map[string]int64{"users": UnknownTableRows}},
+			expected: []string{},
+		},
+		{
+			name: "Declared and used parameters differ",
+			sql:  "SELECT id FROM users WHERE id = :id AND name = :name",
+			opts: LintOptions{DeclaredParameters: []string{"id", "region"}},
+			expected: []string{
+				"unused_parameter:0:0",
+				"undeclared_parameter:1:48",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := lintRules(Lint(tt.sql, DialectGeneric, tt.opts))
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestLint_Config(t *testing.T) {
+	disabled := false
+	opts := LintOptions{Config: LintConfig{Rules: map[string]LintRuleConfig{
+		"select_star":            {Enabled: &disabled},
+		"order_by_without_limit": {Severity: SeverityError},
+	}}}
+
+	findings := Lint("SELECT * FROM users ORDER BY id", DialectGeneric, opts)
+	if len(findings) != 1 {
+		t.Fatalf("Expected 1 finding, got %v", findings)
+	}
+	if findings[0].Rule != "order_by_without_limit" || findings[0].Severity != SeverityError {
+		t.Errorf("Expected order_by_without_limit as error, got %s as %s", findings[0].Rule, findings[0].Severity)
+	}
+}
@@ -0,0 +1,128 @@
+package sqlparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParameterStyle is the way parameters are written in pasted SQL
+type ParameterStyle string
+
+const (
+	ParameterStyleNone     ParameterStyle = "none"     // No parameters
+	ParameterStyleColon    ParameterStyle = "colon"    // :name, the canonical form
+	ParameterStyleAt       ParameterStyle = "at"       // @name, as in SQL Server tools
+	ParameterStyleDollar   ParameterStyle = "dollar"   // $1, as in psql
+	ParameterStyleQuestion ParameterStyle = "question" // ?, as in JDBC and MySQL tools
+	ParameterStyleMixed    ParameterStyle = "mixed"    // More than one of the above
+)
+
+// NormalizeParameters rewrites alternative parameter styles to the canonical
+// :name form and reports the style it found. Only parameter tokens change;
+// strings, comments and quoted identifiers are left as they are.
+//
+//   - @name becomes :name. @@name system variables are kept, as is @name for
+//     MySQL, where it is a user variable, and the argument name in an EXEC
+//     proc @name = value call.
+//   - $n becomes :pn, so repeated numbers stay one parameter. SQL Server
+//     keeps $ for money literals.
+//   - Each ? becomes the next of :p1, :p2, ... PostgreSQL keeps ? as the
+//     jsonb key-exists operator.
+//
+// Generated names never collide with names already in the SQL, so
+// normalizing canonical SQL returns it unchanged.
+func NormalizeParameters(sql string, dialect Dialect) (string, ParameterStyle) {
+	tokens := Tokenize(sql, dialect)
+
+	// Names already taken by :name and @name parameters
+	taken := make(map[string]bool)
+	for i, tok := range tokens {
+		if tok.Type == TokenParam || (isAtParameter(tok, dialect) && !isArgumentName(tokens, i)) {
+			taken[tok.Value()] = true
+		}
+	}
+
+	styles := make(map[ParameterStyle]bool)
+	dollarNames := make(map[string]string)
+	questions := 0
+
+	var b strings.Builder
+	b.Grow(len(sql))
+
+	for i, tok := range tokens {
+		switch {
+		case tok.Type == TokenParam:
+			styles[ParameterStyleColon] = true
+			b.WriteString(tok.Text)
+
+		case isAtParameter(tok, dialect) && !isArgumentName(tokens, i):
+			styles[ParameterStyleAt] = true
+			b.WriteString(":" + tok.Value())
+
+		case tok.Type == TokenPlaceholder && strings.HasPrefix(tok.Text, "$") && dialect != DialectSQLServer:
+			styles[ParameterStyleDollar] = true
+			name, ok := dollarNames[tok.Text]
+			if !ok {
+				name = freshName("p"+tok.Text[1:], taken)
+				dollarNames[tok.Text] = name
+			}
+			b.WriteString(":" + name)
+
+		case tok.Type == TokenPlaceholder && tok.Text == "?" && dialect != DialectPostgreSQL:
+			styles[ParameterStyleQuestion] = true
+			questions++
+			b.WriteString(":" + freshName(fmt.Sprintf("p%d", questions), taken))
+
+		default:
+			b.WriteString(tok.Text)
+		}
+	}
+
+	switch len(styles) {
+	case 0:
+		return sql, ParameterStyleNone
+	case 1:
+		for style := range styles {
+			return b.String(), style
+		}
+	}
+	return b.String(), ParameterStyleMixed
+}
+
+// isAtParameter reports whether a token is an @name parameter in the dialect
+func isAtParameter(tok Token, dialect Dialect) bool {
+	return tok.Type == TokenVariable && dialect != DialectMySQL &&
+		!strings.HasPrefix(tok.Text, "@@") && len(tok.Text) > 1
+}
+
+// isArgumentName reports whether the token at index i names a procedure
+// argument, as @name in EXEC proc @name = value, rather than a parameter
+func isArgumentName(tokens []Token, i int) bool {
+	next := i + 1
+	for next < len(tokens) && (tokens[next].Type == TokenWhitespace || tokens[next].Type == TokenComment) {
+		next++
+	}
+	if next >= len(tokens) || !isPunct(tokens[next], "=") {
+		return false
+	}
+
+	// The statement must start with EXEC, EXECUTE or CALL
+	var first Token
+	for j := i - 1; j >= 0 && !isPunct(tokens[j], ";"); j-- {
+		if tokens[j].Type != TokenWhitespace && tokens[j].Type != TokenComment {
+			first = tokens[j]
+		}
+	}
+	return first.Type == TokenIdent && callKinds[strings.ToUpper(first.Text)]
+}
+
+// freshName returns base, or base with a numeric suffix if base is taken,
+// and marks the result as taken
+func freshName(base string, taken map[string]bool) string {
+	name := base
+	for i := 2; taken[name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	taken[name] = true
+	return name
+}
@@ -0,0 +1,97 @@
+package sqlparser
+
+import (
+	"testing"
+)
+
+func TestNormalizeParameters(t *testing.T) {
+	tests := []struct {
+		name          string
+		sql           string
+		dialect       Dialect
+		expected      string
+		expectedStyle ParameterStyle
+	}{
+		{
+			name:          "Canonical SQL is unchanged",
+			sql:           "SELECT * FROM t WHERE id = :id AND note = '@x ? $1'",
+			dialect:       DialectGeneric,
+			expected:      "SELECT * FROM t WHERE id = :id AND note = '@x ? $1'",
+			expectedStyle: ParameterStyleColon,
+		},
+		{
+			name:          "No parameters",
+			sql:           "SELECT 1",
+			dialect:       DialectPostgreSQL,
+			expected:      "SELECT 1",
+			expectedStyle: ParameterStyleNone,
+		},
+		{
+			name:          "SQL Server @name",
+			sql:           "SELECT * FROM [Orders] WHERE CustomerId = @customerId AND @@ROWCOUNT > 0 -- @ignored",
+			dialect:       DialectSQLServer,
+			expected:      "SELECT * FROM [Orders] WHERE CustomerId = :customerId AND @@ROWCOUNT > 0 -- @ignored",
+			expectedStyle: ParameterStyleAt,
+		},
+		{
+			name:          "SQL Server procedure argument names are kept",
+			sql:           "EXEC dbo.usp_sales_report @year = @year, @region = 'EU'",
+			dialect:       DialectSQLServer,
+			expected:      "EXEC dbo.usp_sales_report @year = :year, @region = 'EU'",
+			expectedStyle: ParameterStyleAt,
+		},
+		{
+			name:          "MySQL user variables are kept",
+			sql:           "SELECT * FROM t WHERE a = @a AND b = ?",
+			dialect:       DialectMySQL,
+			expected:      "SELECT * FROM t WHERE a = @a AND b = :p1",
+			expectedStyle: ParameterStyleQuestion,
+		},
+		{
+			name:          "psql $n",
+			sql:           "SELECT * FROM t WHERE a = $1 AND b > $2::int OR c = $1",
+			dialect:       DialectPostgreSQL,
+			expected:      "SELECT * FROM t WHERE a = :p1 AND b > :p2::int OR c = :p1",
+			expectedStyle: ParameterStyleDollar,
+		},
+		{
+			name:          "PostgreSQL ? is the jsonb operator",
+			sql:           "SELECT * FROM t WHERE data ? 'key' AND id = $1",
+			dialect:       DialectPostgreSQL,
+			expected:      "SELECT * FROM t WHERE data ? 'key' AND id = :p1",
+			expectedStyle: ParameterStyleDollar,
+		},
+		{
+			name:          "Question marks number by position",
+			sql:           "SELECT * FROM t WHERE a = ? AND b = ?",
+			dialect:       DialectMySQL,
+			expected:      "SELECT * FROM t WHERE a = :p1 AND b = :p2",
+			expectedStyle: ParameterStyleQuestion,
+		},
+		{
+			name:          "Generated names avoid existing ones",
+			sql:           "SELECT * FROM t WHERE a = :p1 AND b = ?",
+			dialect:       DialectGeneric,
+			expected:      "SELECT * FROM t WHERE a = :p1 AND b = :p1_2",
+			expectedStyle: ParameterStyleMixed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, style := NormalizeParameters(tt.sql, tt.dialect)
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+			if style != tt.expectedStyle {
+				t.Errorf("Expected style %s, got %s", tt.expectedStyle, style)
+			}
+
+			// Normalizing again changes nothing
+			again, _ := NormalizeParameters(result, tt.dialect)
+			if again != result {
+				t.Errorf("Expected normalization to be stable, got %q", again)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package sqlparser
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ParameterTokens returns every named parameter token in the SQL, in order.
+// Parameters inside strings, comments and quoted identifiers are ignored, as
+// is the type name after a Postgres :: cast.
+func ParameterTokens(sql string, dialect Dialect) []Token {
+	var params []Token
+	for _, tok := range Tokenize(sql, dialect) {
+		if tok.Type == TokenParam {
+			params = append(params, tok)
+		}
+	}
+	return params
+}
+
+// MaxArrayParameterLength caps how many values an array parameter may expand to
+const MaxArrayParameterLength = 1000
+
+var (
+	ErrEmptyArrayParameter   = errors.New("array parameter must not be empty")
+	ErrArrayParameterTooLong = fmt.Errorf("array parameter exceeds %d values", MaxArrayParameterLength)
+)
+
+// BindValues rewrites named parameters to the positional placeholders of the
+// dialect and returns the arguments in placeholder order. Array values expand
+// to one placeholder per element, so "IN (:ids)" binds every id separately.
+// Numbered placeholders ($1, @p1) are reused for repeated names; ? placeholders
+// take one argument per use. Parameters missing from params bind as NULL.
+func BindValues(sql string, dialect Dialect, params map[string]interface{}) (string, []interface{}, error) {
+	tokens := Tokenize(sql, dialect)
+
+	var b strings.Builder
+	b.Grow(len(sql))
+
+	var args []interface{}
+	bound := make(map[string]string) // name -> placeholders, for numbered styles
+
+	for _, tok := range tokens {
+		if tok.Type != TokenParam {
+			b.WriteString(tok.Text)
+			continue
+		}
+
+		name := tok.Value()
+		if placeholders, ok := bound[name]; ok {
+			b.WriteString(placeholders)
+			continue
+		}
+
+		values := []interface{}{params[name]}
+		if list, ok := listValues(params[name]); ok {
+			if len(list) == 0 {
+				return "", nil, fmt.Errorf("%w: %s", ErrEmptyArrayParameter, name)
+			}
+			if len(list) > MaxArrayParameterLength {
+				return "", nil, fmt.Errorf("%w: %s", ErrArrayParameterTooLong, name)
+			}
+			values = list
+		}
+
+		placeholders := make([]string, len(values))
+		for i := range values {
+			switch dialect {
+			case DialectPostgreSQL:
+				placeholders[i] = fmt.Sprintf("$%d", len(args)+i+1)
+			case DialectSQLServer:
+				placeholders[i] = fmt.Sprintf("@p%d", len(args)+i+1)
+			default:
+				placeholders[i] = "?"
+			}
+		}
+		args = append(args, values...)
+
+		joined := strings.Join(placeholders, ", ")
+		if dialect == DialectPostgreSQL || dialect == DialectSQLServer {
+			bound[name] = joined
+		}
+		b.WriteString(joined)
+	}
+
+	return b.String(), args, nil
+}
+
+// listValues returns the elements of a slice or array value. Byte slices are
+// scalar values, not lists.
+func listValues(val interface{}) ([]interface{}, bool) {
+	if val == nil {
+		return nil, false
+	}
+	if list, ok := val.([]interface{}); ok {
+		return list, true
+	}
+	if _, ok := val.([]byte); ok {
+		return nil, false
+	}
+
+	rv := reflect.ValueOf(val)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	list := make([]interface{}, rv.Len())
+	for i := range list {
+		list[i] = rv.Index(i).Interface()
+	}
+	return list, true
+}
+
+// isListParameter reports whether the parameter token at index i of tokens is
+// the whole content of an IN (...) list
+func isListParameter(tokens []Token, i int) bool {
+	prev := significantBefore(tokens, i)
+	if prev < 0 || tokens[prev].Text != "(" {
+		return false
+	}
+	in := significantBefore(tokens, prev)
+	if in < 0 || !tokens[in].IsKeyword("IN") {
+		return false
+	}
+	next := significantAfter(tokens, i)
+	return next >= 0 && tokens[next].Text == ")"
+}
+
+func significantBefore(tokens []Token, i int) int {
+	for j := i - 1; j >= 0; j-- {
+		if tokens[j].Type != TokenWhitespace && tokens[j].Type != TokenComment {
+			return j
+		}
+	}
+	return -1
+}
+
+func significantAfter(tokens []Token, i int) int {
+	for j := i + 1; j < len(tokens); j++ {
+		if tokens[j].Type != TokenWhitespace && tokens[j].Type != TokenComment {
+			return j
+		}
+	}
+	return -1
+}
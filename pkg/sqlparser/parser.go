@@ -2,60 +2,78 @@ package sqlparser
 
 import (
 	"fmt"
-	"regexp"
 	"strings"
+	"unicode"
 )
 
 // ParameterInfo represents information about a SQL parameter
 type ParameterInfo struct {
 	Name     string `json:"name"`
-	Type     string `json:"type"` // string, number, boolean, date
+	Type     string `json:"type"`            // string, number, boolean, date, array
+	Items    string `json:"items,omitempty"` // Element type of an array parameter
+	Optional bool   `json:"optional"`        // Only used inside optional blocks
 	Position int    `json:"position"`
 }
 
 // ExtractParameters extracts all named parameters from a SQL template
 // Supports :paramName syntax
 func ExtractParameters(sql string) []string {
-	re := regexp.MustCompile(`:(\w+)`)
-	matches := re.FindAllStringSubmatch(sql, -1)
-
 	// Use map to track unique parameters and preserve order
 	seen := make(map[string]bool)
 	var params []string
 
-	for _, match := range matches {
-		if len(match) > 1 {
-			paramName := match[1]
-			if !seen[paramName] {
-				seen[paramName] = true
-				params = append(params, paramName)
-			}
+	for _, tok := range ParameterTokens(sql, DialectGeneric) {
+		paramName := tok.Value()
+		if !seen[paramName] {
+			seen[paramName] = true
+			params = append(params, paramName)
 		}
 	}
 
 	return params
 }
 
-// ExtractParametersWithInfo extracts parameters with additional metadata
+// ExtractParametersWithInfo extracts parameters with additional metadata.
+// Parameters that only appear inside optional blocks are marked optional.
 func ExtractParametersWithInfo(sql string) []ParameterInfo {
-	re := regexp.MustCompile(`:(\w+)`)
-	matches := re.FindAllStringSubmatchIndex(sql, -1)
-
-	seen := make(map[string]bool)
+	index := make(map[string]int)
 	var params []ParameterInfo
-	position := 0
-
-	for _, match := range matches {
-		if len(match) >= 4 {
-			paramName := sql[match[2]:match[3]]
-			if !seen[paramName] {
-				seen[paramName] = true
-				position++
-				params = append(params, ParameterInfo{
-					Name:     paramName,
-					Type:     inferParameterType(paramName),
-					Position: position,
-				})
+
+	add := func(tokens []Token, i int, optional bool) {
+		paramName := tokens[i].Value()
+		if idx, ok := index[paramName]; ok {
+			// Used outside an optional block anywhere makes it required
+			if !optional {
+				params[idx].Optional = false
+			}
+			return
+		}
+		index[paramName] = len(params)
+		info := ParameterInfo{
+			Name:     paramName,
+			Type:     inferParameterType(paramName),
+			Optional: optional,
+			Position: len(params) + 1,
+		}
+		// A parameter that makes up a whole IN (...) list takes a list of values
+		if isListParameter(tokens, i) {
+			info.Items = info.Type
+			info.Type = "array"
+		}
+		params = append(params, info)
+	}
+
+	tokens := Tokenize(sql, DialectGeneric)
+	for i, tok := range tokens {
+		switch {
+		case tok.Type == TokenParam:
+			add(tokens, i, false)
+		case isOptionalBlock(tok):
+			inner := Tokenize(optionalBlockBody(tok), DialectGeneric)
+			for j := range inner {
+				if inner[j].Type == TokenParam {
+					add(inner, j, true)
+				}
 			}
 		}
 	}
@@ -63,71 +81,88 @@ func ExtractParametersWithInfo(sql string) []ParameterInfo {
 	return params
 }
 
-// inferParameterType attempts to infer parameter type from its name
+// inferParameterType guesses a parameter type from the words of its name.
+// It is the fallback when the parameter cannot be matched to a column in the
+// data source schema.
 func inferParameterType(name string) string {
-	nameLower := strings.ToLower(name)
-
-	// Date-related patterns
-	datePatterns := []string{"date", "time", "created", "updated", "start", "end", "from", "to", "birth", "expire"}
-	for _, pattern := range datePatterns {
-		if strings.Contains(nameLower, pattern) {
-			return "date"
+	words := nameWords(name)
+	has := func(patterns ...string) bool {
+		for _, word := range words {
+			for _, pattern := range patterns {
+				if word == pattern {
+					return true
+				}
+			}
 		}
+		return false
+	}
+
+	switch {
+	// Boolean prefixes are checked first: is_deleted is a flag, not a date
+	case len(words) > 1 && (words[0] == "is" || words[0] == "has" || words[0] == "can"):
+		return "boolean"
+	case has("date", "time", "timestamp", "created", "updated", "start", "end", "from", "to", "birth", "birthday", "expire", "expires", "since", "until", "before", "after"):
+		return "date"
+	case has("id", "count", "num", "number", "amount", "total", "price", "qty", "quantity", "age", "year", "month", "day", "limit", "offset", "page", "size"):
+		return "number"
+	case has("active", "enabled", "disabled", "flag"):
+		return "boolean"
+	default:
+		return "string"
 	}
+}
 
-	// Number-related patterns
-	numberPatterns := []string{"id", "count", "num", "amount", "price", "qty", "quantity", "age", "year", "month", "day", "limit", "offset", "page", "size"}
-	for _, pattern := range numberPatterns {
-		if strings.Contains(nameLower, pattern) {
-			return "number"
+// nameWords splits a parameter name into lower-case words on underscores,
+// hyphens and camelCase boundaries, e.g. "orderTotal_amount" becomes
+// [order total amount]
+func nameWords(name string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = current[:0]
 		}
 	}
 
-	// Boolean-related patterns
-	boolPatterns := []string{"is_", "has_", "can_", "active", "enabled", "disabled", "flag", "status"}
-	for _, pattern := range boolPatterns {
-		if strings.Contains(nameLower, pattern) {
-			return "boolean"
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-':
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) ||
+			(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))):
+			flush()
 		}
+		current = append(current, r)
 	}
+	flush()
 
-	// Default to string
-	return "string"
+	return words
 }
 
 // ReplaceParameters replaces named parameters with positional placeholders
 // Returns the converted SQL, ordered parameter values, and any error
+// Array values expand to one placeholder per element, and optional blocks
+// are kept or dropped depending on their parameters
 func ReplaceParameters(sql string, params map[string]interface{}, dbType string) (string, []interface{}, error) {
 	if params == nil || len(params) == 0 {
 		return sql, nil, nil
 	}
 
-	re := regexp.MustCompile(`:(\w+)`)
-	matches := re.FindAllStringSubmatch(sql, -1)
+	dialect := DialectFor(dbType)
+	sql = ExpandOptionalBlocks(sql, dialect, params)
 
-	if len(matches) == 0 {
+	names := ExtractParameters(sql)
+	if len(names) == 0 {
 		return sql, nil, nil
 	}
 
-	// Track unique parameters in order
-	paramOrder := make([]string, 0)
-	seen := make(map[string]bool)
-	for _, match := range matches {
-		paramName := match[1]
-		if !seen[paramName] {
-			seen[paramName] = true
-			paramOrder = append(paramOrder, paramName)
-		}
-	}
-
-	// Build args in order and validate all parameters are provided
-	args := make([]interface{}, 0, len(paramOrder))
+	// Validate all parameters are provided
 	missingParams := make([]string, 0)
-
-	for _, name := range paramOrder {
-		if val, ok := params[name]; ok {
-			args = append(args, val)
-		} else {
+	for _, name := range names {
+		if _, ok := params[name]; !ok {
 			missingParams = append(missingParams, name)
 		}
 	}
@@ -136,116 +171,63 @@ func ReplaceParameters(sql string, params map[string]interface{}, dbType string)
 		return "", nil, fmt.Errorf("missing required parameters: %s", strings.Join(missingParams, ", "))
 	}
 
-	// Replace named parameters with positional placeholders
-	convertedSQL := sql
-	for i, name := range paramOrder {
-		var placeholder string
-		switch strings.ToLower(dbType) {
-		case "postgresql", "postgres":
-			placeholder = fmt.Sprintf("$%d", i+1)
-		case "mysql", "oracle":
-			placeholder = "?"
-		case "sqlserver", "mssql":
-			placeholder = fmt.Sprintf("@p%d", i+1)
-		default:
-			placeholder = "?"
-		}
-		// Replace all occurrences of this named parameter
-		convertedSQL = strings.ReplaceAll(convertedSQL, ":"+name, placeholder)
-	}
-
-	return convertedSQL, args, nil
+	return BindValues(sql, dialect, params)
 }
 
-// ValidateReadOnlySQL validates that the SQL is a read-only query (SELECT only)
+// ValidateReadOnlySQL validates that the SQL is a single read-only query.
+// Errors carry the offending position as a *ValidationError.
 func ValidateReadOnlySQL(sql string) error {
-	// Normalize the SQL: trim whitespace and convert to uppercase for checking
-	normalized := strings.TrimSpace(strings.ToUpper(sql))
-
-	// Remove leading comments
-	normalized = removeComments(normalized)
-
-	// Check if it starts with SELECT or WITH (for CTEs)
-	if !strings.HasPrefix(normalized, "SELECT") && !strings.HasPrefix(normalized, "WITH") {
-		return fmt.Errorf("only SELECT queries are allowed")
-	}
-
-	// Check for dangerous keywords that shouldn't be in a read-only query
-	dangerousKeywords := []string{
-		"INSERT ", "UPDATE ", "DELETE ", "DROP ", "TRUNCATE ",
-		"ALTER ", "CREATE ", "GRANT ", "REVOKE ", "EXEC ",
-		"EXECUTE ", "INTO ", // INTO can be used with SELECT INTO
-	}
-
-	for _, keyword := range dangerousKeywords {
-		if strings.Contains(normalized, keyword) {
-			// Special case: INTO is allowed in subqueries, but not as SELECT INTO
-			if keyword == "INTO " && !strings.Contains(normalized, "SELECT ") {
-				continue
-			}
-			// Check if INTO appears right after SELECT (SELECT INTO pattern)
-			if keyword == "INTO " {
-				selectIntoPattern := regexp.MustCompile(`SELECT\s+.*?\s+INTO\s+`)
-				if !selectIntoPattern.MatchString(normalized) {
-					continue
-				}
-			}
-			return fmt.Errorf("forbidden keyword detected: %s", strings.TrimSpace(keyword))
-		}
-	}
-
-	return nil
-}
-
-// removeComments removes SQL comments from the beginning of the query
-func removeComments(sql string) string {
-	result := sql
-
-	// Remove single-line comments (-- comment)
-	singleLineComment := regexp.MustCompile(`^--.*?(\n|$)`)
-	for singleLineComment.MatchString(result) {
-		result = singleLineComment.ReplaceAllString(result, "")
-		result = strings.TrimSpace(result)
-	}
-
-	// Remove multi-line comments (/* comment */)
-	multiLineComment := regexp.MustCompile(`^/\*.*?\*/`)
-	for multiLineComment.MatchString(result) {
-		result = multiLineComment.ReplaceAllString(result, "")
-		result = strings.TrimSpace(result)
-	}
-
-	return strings.TrimSpace(result)
+	return ValidateReadOnlyStatements(sql, DialectGeneric, ReadOnlyOptions{})
 }
 
-// ValidateSQLSyntax performs basic SQL syntax validation
-func ValidateSQLSyntax(sql string) error {
+// ValidateSQLSyntax performs basic SQL syntax validation with the lexing
+// rules of the dialect: strings, quoted identifiers and comments must be
+// closed and parentheses balanced. Optional blocks are checked as if all of
+// them were kept. Positioned errors are returned as *ValidationError.
+func ValidateSQLSyntax(sql string, dialect Dialect) error {
 	if strings.TrimSpace(sql) == "" {
 		return fmt.Errorf("SQL template cannot be empty")
 	}
 
-	// Check for unbalanced parentheses
-	openCount := strings.Count(sql, "(")
-	closeCount := strings.Count(sql, ")")
-	if openCount != closeCount {
-		return fmt.Errorf("unbalanced parentheses: %d opening, %d closing", openCount, closeCount)
+	revealed := revealOptionalBlocks(sql, dialect)
+	tokens, unclosed := tokenize(revealed, dialect)
+	if unclosed != nil {
+		switch unclosed.Type {
+		case TokenComment:
+			return errorAt(*unclosed, "unterminated comment")
+		case TokenQuotedIdent:
+			return errorAt(*unclosed, "unterminated quoted identifier")
+		default:
+			return errorAt(*unclosed, "unterminated string literal")
+		}
 	}
 
-	// Check for unbalanced quotes
-	singleQuotes := strings.Count(sql, "'")
-	if singleQuotes%2 != 0 {
-		return fmt.Errorf("unbalanced single quotes")
+	if _, err := ParseStatements(revealed, dialect); err != nil {
+		return err
 	}
 
-	// Check for common SQL syntax issues
-	normalizedSQL := strings.ToUpper(strings.TrimSpace(sql))
-
-	// Must have at least a SELECT keyword for read-only queries
-	if !strings.Contains(normalizedSQL, "SELECT") && !strings.Contains(normalizedSQL, "WITH") {
-		return fmt.Errorf("SQL must contain SELECT statement")
+	// Must have at least a SELECT keyword for read-only queries, unless it
+	// calls a routine; whether that routine is allowed is checked separately
+	for _, tok := range tokens {
+		if tok.IsKeyword("SELECT") || tok.IsKeyword("WITH") {
+			return nil
+		}
+	}
+	if isCallStatement(tokens) {
+		return nil
 	}
+	return fmt.Errorf("SQL must contain SELECT statement")
+}
 
-	return nil
+// isCallStatement reports whether the tokens start with EXEC, EXECUTE or CALL
+func isCallStatement(tokens []Token) bool {
+	for _, tok := range tokens {
+		if tok.Type == TokenWhitespace || tok.Type == TokenComment {
+			continue
+		}
+		return tok.Type == TokenIdent && callKinds[strings.ToUpper(tok.Text)]
+	}
+	return false
 }
 
 // CountParameters counts the number of unique parameters in a SQL template
@@ -36,6 +36,11 @@ func TestExtractParameters(t *testing.T) {
 			sql:      "SELECT p.name, SUM(o.amount) FROM products p JOIN orders o ON p.id = o.product_id WHERE o.created_at BETWEEN :start_date AND :end_date AND p.category_id = :category_id GROUP BY p.name",
 			expected: []string{"start_date", "end_date", "category_id"},
 		},
+		{
+			name:     "Ignores literals, comments and casts",
+			sql:      "SELECT created_at::date FROM events /* :skip */ WHERE slot = '10:30' AND id = :id -- :skip",
+			expected: []string{"id"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -72,6 +77,20 @@ func TestExtractParametersWithInfo(t *testing.T) {
 	}
 }
 
+func TestExtractParametersWithInfo_Array(t *testing.T) {
+	result := ExtractParametersWithInfo("SELECT * FROM orders WHERE status IN ( :statuses ) AND region = :region")
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 parameters, got %d", len(result))
+	}
+	if result[0].Type != "array" || result[0].Items != "string" {
+		t.Errorf("Expected statuses to be an array of string, got %s of %s", result[0].Type, result[0].Items)
+	}
+	if result[1].Type != "string" || result[1].Items != "" {
+		t.Errorf("Expected region to be a string, got %s", result[1].Type)
+	}
+}
+
 func TestReplaceParameters(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -113,6 +132,14 @@ func TestReplaceParameters(t *testing.T) {
 			expectedSQL: "",
 			expectedErr: true,
 		},
+		{
+			name:        "Prefix parameter names",
+			sql:         "SELECT * FROM users WHERE id = :id OR id IN (SELECT unnest(:id_list))",
+			params:      map[string]interface{}{"id": 1, "id_list": "{1,2}"},
+			dbType:      "postgresql",
+			expectedSQL: "SELECT * FROM users WHERE id = $1 OR id IN (SELECT unnest($2))",
+			expectedErr: false,
+		},
 		{
 			name:        "No parameters",
 			sql:         "SELECT * FROM users",
@@ -188,6 +215,71 @@ func TestValidateReadOnlySQL(t *testing.T) {
 			sql:         "CREATE TABLE test (id INT)",
 			expectError: true,
 		},
+		{
+			name:        "Column names containing keywords",
+			sql:         "SELECT created_into, last_update FROM audit WHERE deleted = false",
+			expectError: false,
+		},
+		{
+			name:        "Keywords inside literals and comments",
+			sql:         "SELECT 'DELETE FROM users' AS note /* DROP TABLE x */ FROM t",
+			expectError: false,
+		},
+		{
+			name:        "Trailing semicolon",
+			sql:         "SELECT 1;",
+			expectError: false,
+		},
+		{
+			name:        "Subquery and function arguments",
+			sql:         "SELECT SUBSTRING(name FROM 1 FOR 3), (SELECT COUNT(*) FROM orders) FROM users",
+			expectError: false,
+		},
+		{
+			name:        "Multiple statements",
+			sql:         "SELECT 1;\n\tdrop\ntable x",
+			expectError: true,
+		},
+		{
+			name:        "Writable CTE",
+			sql:         "WITH x AS (\ndelete\nfrom users RETURNING *) SELECT * FROM x",
+			expectError: true,
+		},
+		{
+			name:        "Writable CTE after read-only CTE",
+			sql:         "WITH a AS (SELECT 1), b AS MATERIALIZED (INSERT INTO t VALUES (1) RETURNING *) SELECT * FROM a, b",
+			expectError: true,
+		},
+		{
+			name:        "SELECT INTO",
+			sql:         "SELECT * INTO backup_users FROM users",
+			expectError: true,
+		},
+		{
+			name:        "FOR UPDATE",
+			sql:         "SELECT * FROM users WHERE id = 1 FOR UPDATE",
+			expectError: true,
+		},
+		{
+			name:        "FOR SHARE in subquery",
+			sql:         "SELECT * FROM (SELECT * FROM users FOR SHARE) u",
+			expectError: true,
+		},
+		{
+			name:        "Denylisted function",
+			sql:         "SELECT pg_terminate_backend(pid) FROM pg_stat_activity",
+			expectError: true,
+		},
+		{
+			name:        "Denylisted qualified function",
+			sql:         "SELECT pg_catalog.pg_sleep(10)",
+			expectError: true,
+		},
+		{
+			name:        "Data-modifying subquery",
+			sql:         "SELECT * FROM (DELETE FROM users RETURNING *) d",
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -200,6 +292,130 @@ func TestValidateReadOnlySQL(t *testing.T) {
 	}
 }
 
+func TestValidateReadOnlySQL_Position(t *testing.T) {
+	err := ValidateReadOnlySQL("SELECT *\nFROM users\nWHERE id = 1 FOR UPDATE")
+
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected *ValidationError, got %T", err)
+	}
+	if verr.Line != 3 || verr.Column != 14 {
+		t.Errorf("Expected error at 3:14, got %d:%d", verr.Line, verr.Column)
+	}
+}
+
+func TestValidateReadOnlyStatements_MySQL(t *testing.T) {
+	// In MySQL, # starts a comment and double quotes delimit strings
+	if err := ValidateReadOnlyStatements("SELECT \"a;b\" FROM t # ; DROP TABLE t", DialectMySQL, ReadOnlyOptions{}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := ValidateReadOnlyStatements("SELECT * FROM t LOCK IN SHARE MODE", DialectMySQL, ReadOnlyOptions{}); err == nil {
+		t.Error("Expected LOCK IN SHARE MODE to be rejected")
+	}
+	if err := ValidateReadOnlyStatements("SELECT id INTO OUTFILE '/tmp/x' FROM t", DialectMySQL, ReadOnlyOptions{}); err == nil {
+		t.Error("Expected INTO OUTFILE to be rejected")
+	}
+
+	// MySQL runs the body of /*! */ and MariaDB /*M! */ comments as SQL
+	for _, sql := range []string{
+		"SELECT * FROM t /*! INTO OUTFILE '/tmp/x' */",
+		"SELECT 1 /*!, SLEEP(100) */",
+		"SELECT 1 /*M!100000 , SLEEP(100) */",
+	} {
+		if err := ValidateReadOnlyStatements(sql, DialectMySQL, ReadOnlyOptions{}); err == nil {
+			t.Errorf("Expected executable comment in %q to be rejected", sql)
+		}
+	}
+	if err := ValidateReadOnlyStatements("SELECT /*+ MAX_EXECUTION_TIME(1000) */ 1 /* note */", DialectMySQL, ReadOnlyOptions{}); err != nil {
+		t.Errorf("Unexpected error for hint and plain comment: %v", err)
+	}
+	if err := ValidateReadOnlyStatements("SELECT 1 /*! not special here */", DialectPostgreSQL, ReadOnlyOptions{}); err != nil {
+		t.Errorf("Unexpected error outside MySQL: %v", err)
+	}
+}
+
+func TestValidateReadOnlyStatements_SQLServer(t *testing.T) {
+	// SQL Server runs a statement that follows a query without a semicolon
+	rejected := []string{
+		"SELECT 1 DELETE FROM users",
+		"SELECT * FROM t WHERE id = 1 DROP TABLE t",
+		"SELECT 1 UPDATE t SET a = 1",
+		"SELECT 1\nSHUTDOWN",
+		"SELECT 1 WAITFOR DELAY '00:10'",
+		"SELECT 1 EXEC dbo.usp_purge",
+		"SELECT * FROM (SELECT 1 AS a) s DECLARE @x INT",
+		"SELECT begin, [end] FROM t BEGIN TRAN",
+		"SELECT * FROM t WHERE x IN (1, 2) SET NOCOUNT ON",
+	}
+	for _, sql := range rejected {
+		if err := ValidateReadOnlyStatements(sql, DialectSQLServer, ReadOnlyOptions{}); err == nil {
+			t.Errorf("Expected %q to be rejected", sql)
+		}
+	}
+
+	// Function names, qualified names and clauses that share a keyword
+	allowed := []string{
+		"SELECT REPLACE(name, 'a', 'b'), TRUNCATE(price, 2) FROM t",
+		"SELECT o.[update], o.delete FROM orders o",
+		"SELECT * FROM a INNER MERGE JOIN b ON a.id = b.id",
+		"SELECT CAST(name AS CHAR CHARACTER SET utf8) FROM t",
+		// Columns named like statement keywords
+		"SELECT id, begin, set FROM periods WHERE begin < :d ORDER BY begin",
+		"SELECT update AS updated, delete FROM flags",
+		"SELECT p.id FROM periods p WHERE p.id = 1 AND begin >= commit",
+	}
+	for _, sql := range allowed {
+		if err := ValidateReadOnlyStatements(sql, DialectSQLServer, ReadOnlyOptions{}); err != nil {
+			t.Errorf("Unexpected error for %q: %v", sql, err)
+		}
+	}
+}
+
+func TestValidateReadOnlyStatements_AllowedRoutines(t *testing.T) {
+	opts := ReadOnlyOptions{AllowedRoutines: []string{"dbo.usp_sales_report", "refresh_totals", "dblink"}}
+
+	tests := []struct {
+		name        string
+		sql         string
+		dialect     Dialect
+		expectError bool
+	}{
+		{"Allowlisted procedure", "EXEC dbo.usp_sales_report :year, :region", DialectSQLServer, false},
+		{"Quoted and differently cased name", "EXECUTE [DBO].[usp_Sales_Report] @year = :year", DialectSQLServer, false},
+		{"CALL with arguments", "CALL refresh_totals(:from, :to);", DialectMySQL, false},
+		{"Procedure not on the allowlist", "EXEC dbo.usp_purge_orders", DialectSQLServer, true},
+		{"Schema must match as written", "EXEC sales.usp_sales_report", DialectSQLServer, true},
+		{"Dynamic SQL", "EXEC ('DELETE FROM orders')", DialectSQLServer, true},
+		{"Procedure name in a variable", "EXEC @proc", DialectSQLServer, true},
+		{"Return status capture", "EXEC @rc = dbo.usp_sales_report", DialectSQLServer, true},
+		{"Statement following the call", "EXEC dbo.usp_sales_report 2024 DELETE FROM orders", DialectSQLServer, true},
+		{"Second statement", "EXEC dbo.usp_sales_report; DROP TABLE orders", DialectSQLServer, true},
+		{"Allowlisted denied function", "SELECT * FROM dblink('conn', 'SELECT 1') AS t(a int)", DialectPostgreSQL, false},
+		{"Other denied function", "SELECT pg_sleep(1)", DialectPostgreSQL, true},
+		{"Linked server query", "SELECT * FROM OPENQUERY(remote, 'SELECT 1')", DialectSQLServer, true},
+		{"Ad hoc remote rowset", "SELECT * FROM OpenRowSet('SQLNCLI', 'Server=x;', 'SELECT 1') AS r", DialectSQLServer, true},
+		{"Ad hoc data source", "SELECT * FROM OPENDATASOURCE('SQLNCLI', 'Data Source=x').db.dbo.t", DialectSQLServer, true},
+		{"Oracle package call", "SELECT DBMS_LOCK.SLEEP(10) FROM dual", DialectOracle, true},
+		{"Oracle schema-qualified package", "SELECT sys.utl_http.request('http://x') FROM dual", DialectOracle, true},
+		{"Oracle package routine without arguments", "SELECT DBMS_PIPE.RECEIVE_MESSAGE FROM dual", DialectOracle, true},
+		{"Oracle column that is not a package", "SELECT d.dbms_flag FROM dual d", DialectOracle, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateReadOnlyStatements(tt.sql, tt.dialect, opts)
+			if (err != nil) != tt.expectError {
+				t.Errorf("ValidateReadOnlyStatements() error = %v, wantErr %v", err, tt.expectError)
+			}
+		})
+	}
+
+	// Without an allowlist, calls stay blocked
+	if err := ValidateReadOnlyStatements("EXEC dbo.usp_sales_report", DialectSQLServer, ReadOnlyOptions{}); err == nil {
+		t.Error("Expected EXEC to be rejected without an allowlist")
+	}
+}
+
 func TestValidateSQLSyntax(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -236,11 +452,41 @@ func TestValidateSQLSyntax(t *testing.T) {
 			sql:         "DESCRIBE users",
 			expectError: true,
 		},
+		{
+			name:        "Procedure call",
+			sql:         "EXEC dbo.usp_sales_report :year",
+			expectError: false,
+		},
+		{
+			name:        "Parenthesis inside a literal",
+			sql:         "SELECT '(' AS open_paren, 'it''s (' FROM t",
+			expectError: false,
+		},
+		{
+			name:        "Quotes and parentheses in comments",
+			sql:         "SELECT 1 -- don't (\n/* ) */",
+			expectError: false,
+		},
+		{
+			name:        "SELECT only inside a literal",
+			sql:         "DESCRIBE 'SELECT'",
+			expectError: true,
+		},
+		{
+			name:        "Unterminated comment",
+			sql:         "SELECT 1 /* note",
+			expectError: true,
+		},
+		{
+			name:        "Unbalanced parentheses in optional block",
+			sql:         "SELECT * FROM t WHERE 1 = 1 /*[ AND (a = :a ]*/",
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := ValidateSQLSyntax(tt.sql)
+			err := ValidateSQLSyntax(tt.sql, DialectGeneric)
 			if (err != nil) != tt.expectError {
 				t.Errorf("ValidateSQLSyntax() error = %v, wantErr %v", err, tt.expectError)
 			}
@@ -0,0 +1,88 @@
+package sqlparser
+
+import "strings"
+
+// Optional blocks wrap a clause in a marked comment, e.g.
+//
+//	SELECT * FROM orders WHERE 1 = 1 /*[ AND region = :region ]*/
+//
+// The clause is kept only when every parameter inside it is provided and not
+// null. Because the block is a comment, the template stays valid SQL and
+// parameters inside it are not required.
+const (
+	optionalBlockOpen  = "/*["
+	optionalBlockClose = "]*/"
+)
+
+// isOptionalBlock reports whether a token is an optional block comment
+func isOptionalBlock(tok Token) bool {
+	return tok.Type == TokenComment &&
+		len(tok.Text) >= len(optionalBlockOpen)+len(optionalBlockClose) &&
+		strings.HasPrefix(tok.Text, optionalBlockOpen) &&
+		strings.HasSuffix(tok.Text, optionalBlockClose)
+}
+
+// optionalBlockBody returns the SQL inside an optional block comment
+func optionalBlockBody(tok Token) string {
+	return tok.Text[len(optionalBlockOpen) : len(tok.Text)-len(optionalBlockClose)]
+}
+
+// ExpandOptionalBlocks keeps the optional blocks whose parameters are all
+// present and not null, and drops the rest
+func ExpandOptionalBlocks(sql string, dialect Dialect, params map[string]interface{}) string {
+	if !strings.Contains(sql, optionalBlockOpen) {
+		return sql
+	}
+
+	var b strings.Builder
+	b.Grow(len(sql))
+
+	for _, tok := range Tokenize(sql, dialect) {
+		if !isOptionalBlock(tok) {
+			b.WriteString(tok.Text)
+			continue
+		}
+
+		body := optionalBlockBody(tok)
+		keep := true
+		for _, param := range ParameterTokens(body, dialect) {
+			if params[param.Value()] == nil {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			b.WriteString(body)
+		} else {
+			b.WriteString(" ")
+		}
+	}
+
+	return b.String()
+}
+
+// revealOptionalBlocks turns every optional block into plain SQL, replacing
+// the markers with spaces so that token positions are unchanged. Validation
+// runs on this form so that clauses inside blocks are checked too.
+func revealOptionalBlocks(sql string, dialect Dialect) string {
+	if !strings.Contains(sql, optionalBlockOpen) {
+		return sql
+	}
+
+	blank := strings.Repeat(" ", len(optionalBlockOpen))
+
+	var b strings.Builder
+	b.Grow(len(sql))
+
+	for _, tok := range Tokenize(sql, dialect) {
+		if isOptionalBlock(tok) {
+			b.WriteString(blank)
+			b.WriteString(optionalBlockBody(tok))
+			b.WriteString(blank)
+			continue
+		}
+		b.WriteString(tok.Text)
+	}
+
+	return b.String()
+}
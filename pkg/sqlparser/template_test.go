@@ -0,0 +1,79 @@
+package sqlparser
+
+import (
+	"testing"
+)
+
+func TestExpandOptionalBlocks(t *testing.T) {
+	sql := "SELECT * FROM orders WHERE 1 = 1 /*[ AND region = :region ]*/ /*[ AND status IN (:statuses) ]*/ /* note */"
+
+	tests := []struct {
+		name     string
+		params   map[string]interface{}
+		expected string
+	}{
+		{
+			name:     "All blocks kept",
+			params:   map[string]interface{}{"region": "EU", "statuses": []interface{}{"open"}},
+			expected: "SELECT * FROM orders WHERE 1 = 1  AND region = :region   AND status IN (:statuses)  /* note */",
+		},
+		{
+			name:     "Absent parameter drops its block",
+			params:   map[string]interface{}{"region": "EU"},
+			expected: "SELECT * FROM orders WHERE 1 = 1  AND region = :region    /* note */",
+		},
+		{
+			name:     "Null parameter drops its block",
+			params:   map[string]interface{}{"region": nil},
+			expected: "SELECT * FROM orders WHERE 1 = 1     /* note */",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ExpandOptionalBlocks(sql, DialectPostgreSQL, tt.params)
+			if result != tt.expected {
+				t.Errorf("ExpandOptionalBlocks() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtractParametersWithInfo_Optional(t *testing.T) {
+	sql := "SELECT * FROM orders WHERE customer_id = :customer_id /*[ AND region = :region ]*/ /*[ AND customer_id <> :customer_id ]*/"
+	result := ExtractParametersWithInfo(sql)
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 parameters, got %d", len(result))
+	}
+	if result[0].Name != "customer_id" || result[0].Optional {
+		t.Errorf("Expected customer_id to be required")
+	}
+	if result[1].Name != "region" || !result[1].Optional {
+		t.Errorf("Expected region to be optional")
+	}
+
+	// Only required parameters must be supplied
+	if err := ValidateParameters(sql, map[string]interface{}{"customer_id": 1}); err != nil {
+		t.Errorf("ValidateParameters() unexpected error: %v", err)
+	}
+}
+
+func TestValidateReadOnlySQL_OptionalBlocks(t *testing.T) {
+	if err := ValidateReadOnlySQL("SELECT * FROM t WHERE 1 = 1 /*[ AND a = :a ]*/"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := ValidateReadOnlySQL("SELECT * FROM t /*[ ; DROP TABLE t ]*/"); err == nil {
+		t.Error("Expected statement hidden in an optional block to be rejected")
+	}
+}
+
+func TestReplaceParameters_OptionalBlocks(t *testing.T) {
+	sql, args, err := ReplaceParameters("SELECT * FROM t WHERE a = :a /*[ AND b = :b ]*/", map[string]interface{}{"a": 1}, "postgresql")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sql != "SELECT * FROM t WHERE a = $1  " || len(args) != 1 {
+		t.Errorf("ReplaceParameters() = %q %v", sql, args)
+	}
+}